@@ -1,12 +1,18 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/jmgilman/sow/cli/internal/cmdutil"
+	"github.com/jmgilman/sow/cli/internal/hooks"
+	"github.com/jmgilman/sow/cli/internal/verify"
+	"github.com/jmgilman/sow/libs/config"
 	"github.com/jmgilman/sow/libs/project"
 	"github.com/jmgilman/sow/libs/project/state"
+	projschema "github.com/jmgilman/sow/libs/schemas/project"
 	
 	"github.com/jmgilman/sow/cli/internal/sow"
 	"github.com/spf13/cobra"
@@ -20,6 +26,7 @@ import (
 //	sow advance [event]            # Fire explicit event
 //	sow advance --list             # List available transitions
 //	sow advance --dry-run [event]  # Validate without executing
+//	sow advance --undo             # Undo the last transition
 //
 // This command examines the current phase and state, determines the appropriate
 // transition event, validates prerequisites via guards, and advances the state
@@ -37,11 +44,31 @@ The advance command:
 4. Fires the event if guards pass
 5. Saves the updated state
 
+Shell hooks configured in .sow/config.yaml run around the transition:
+pre_advance, on_exit (of the current state), and on_enter (of the target
+state) run before the new state is saved, and any of them can veto the
+transition by exiting non-zero. post_advance runs afterwards. Hooks receive
+SOW_PROJECT_NAME, SOW_PROJECT_TYPE, SOW_PROJECT_BRANCH, SOW_PHASE,
+SOW_EVENT, SOW_FROM_STATE, SOW_TO_STATE, and SOW_HOOK, and their output is
+recorded in .sow/project/log.md.
+
+Verification commands configured per phase in .sow/config.yaml (verify:)
+run before any transition that completes the phase. A failing command
+blocks the transition; results and output paths are recorded in the
+phase's metadata.verification either way.
+
+The project state is snapshotted before every transition, so a transition
+fired by mistake can be undone with --undo. Each undo restores the previous
+snapshot; use 'sow project rollback' to go back several steps at once.
+
 Flags:
   --list     List available transitions without executing
   --dry-run  Validate transition without executing (requires event argument)
+  --undo     Restore the state from before the last transition
 
-Guards may prevent transitions. Common guard failures:
+Guards may prevent transitions. A blocked transition lists each unmet
+condition (for example "task 020 not complete (in_progress)"), as do
+--dry-run and --list. Common guard failures:
 - Planning → Implementation: task_list output not approved
 - Implementation Planning → Executing: tasks not approved (metadata.tasks_approved)
 - Implementation Executing → Review: not all tasks completed
@@ -51,7 +78,8 @@ Examples:
   sow advance                    # Auto-determine next event
   sow advance finalize           # Fire explicit event
   sow advance --list             # Show available transitions
-  sow advance --dry-run finalize # Validate before executing`,
+  sow advance --dry-run finalize # Validate before executing
+  sow advance --undo             # Undo the last transition`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Validate flags and arguments
@@ -62,6 +90,20 @@ Examples:
 			// Get context
 			ctx := cmdutil.GetContext(cmd.Context())
 
+			listFlag, _ := cmd.Flags().GetBool("list")
+			dryRunFlag, _ := cmd.Flags().GetBool("dry-run")
+			undoFlag, _ := cmd.Flags().GetBool("undo")
+
+			// Lock project state for an undo. Transitions take the lock
+			// themselves once their hooks have run (see lockTransition).
+			if undoFlag {
+				lock, err := cmdutil.LockProject(cmd.Context(), ctx)
+				if err != nil {
+					return err
+				}
+				defer func() { _ = lock.Release() }()
+			}
+
 			// Load project using SDK
 			proj, err := cmdutil.LoadProject(cmd.Context(), ctx)
 			if err != nil {
@@ -71,14 +113,17 @@ Examples:
 			// Get current state
 			currentState := proj.Statechart.Current_state
 
+			// Check for undo mode
+			if undoFlag {
+				return undoTransition(cmd, ctx, proj)
+			}
+
 			// Check for list mode
-			listFlag, _ := cmd.Flags().GetBool("list")
 			if listFlag {
 				return listAvailableTransitions(cmd, proj, currentState)
 			}
 
 			// Check for dry-run mode
-			dryRunFlag, _ := cmd.Flags().GetBool("dry-run")
 			if dryRunFlag {
 				// Get event argument (validation ensures it exists)
 				event := args[0]
@@ -97,13 +142,14 @@ Examples:
 			}
 
 			// Auto-determination mode: no flags, no event argument
-			return executeAutoTransition(cmd, proj, currentState)
+			return executeAutoTransition(cmd, ctx, proj, currentState)
 		},
 	}
 
 	// Add flags
 	cmd.Flags().Bool("list", false, "List available transitions without executing")
 	cmd.Flags().Bool("dry-run", false, "Validate transition without executing")
+	cmd.Flags().Bool("undo", false, "Restore the state from before the last transition")
 
 	return cmd
 }
@@ -113,6 +159,7 @@ Examples:
 func validateAdvanceFlags(cmd *cobra.Command, args []string) error {
 	listFlag, _ := cmd.Flags().GetBool("list")
 	dryRunFlag, _ := cmd.Flags().GetBool("dry-run")
+	undoFlag, _ := cmd.Flags().GetBool("undo")
 
 	// Get event argument if provided
 	var event string
@@ -133,6 +180,10 @@ func validateAdvanceFlags(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("--dry-run requires an event argument")
 	}
 
+	if undoFlag && (listFlag || dryRunFlag || event != "") {
+		return fmt.Errorf("cannot use --undo with other flags or an event argument")
+	}
+
 	return nil
 }
 
@@ -145,6 +196,7 @@ func validateAdvanceFlags(cmd *cobra.Command, args []string) error {
 // - Save fails (I/O error).
 func executeAutoTransition(
 	cmd *cobra.Command,
+	ctx *sow.Context,
 	proj *state.Project,
 	currentState string,
 ) error {
@@ -156,8 +208,14 @@ func executeAutoTransition(
 		return fmt.Errorf("invalid project configuration")
 	}
 
-	// Build project machine for current state (returns *project.Machine, not raw *stateless.StateMachine)
-	machine := config.BuildProjectMachine(proj, project.State(currentState))
+	transitionHooks, err := newAdvanceHooks(cmd, ctx)
+	if err != nil {
+		return err
+	}
+	verification, err := newPhaseVerification(cmd, ctx)
+	if err != nil {
+		return err
+	}
 
 	// Determine which event to fire from current state
 	event, err := config.DetermineEvent(proj)
@@ -166,32 +224,108 @@ func executeAutoTransition(
 		return enhanceAutoTransitionError(err, proj, currentState)
 	}
 
-	// Fire the event with automatic phase status updates
-	if err := config.FireWithPhaseUpdates(machine, event, proj); err != nil {
-		// Provide helpful error messages based on error type
-		if strings.Contains(err.Error(), "cannot fire event") {
-			return fmt.Errorf("transition blocked: %w\n\nCheck that all prerequisites for this state transition are met", err)
-		}
-		return fmt.Errorf("failed to advance: %w", err)
+	// Report unmet guard conditions, verify the phase being completed, then
+	// give the configured hooks a chance to veto the transition. These run
+	// before the state lock is taken: verification may take long, and hooks
+	// may run sow commands that need the lock.
+	if guard := config.EvaluateGuard(project.State(currentState), event, proj); !guard.Passed() {
+		return guardBlockedError(config, project.State(currentState), event, guard)
+	}
+	if err := verification.run(cmd, config, currentState, event); err != nil {
+		verification.recordFailure(cmd, ctx, err)
+		return err
+	}
+	if err := transitionHooks.before(cmd, config, proj, currentState, event); err != nil {
+		return err
+	}
+
+	lock, proj, err := lockTransition(cmd, ctx, proj)
+	if err != nil {
+		return err
 	}
+	defer func() { _ = lock.Release() }()
+
+	// Fire and save. The project may have changed while the lock was not
+	// held, and the save is retried after a concurrent write, so the event
+	// and guard are checked again against the state being saved. It must still be
+	// the state we started from so that an advance is never applied twice.
+	var before *projschema.ProjectState
+	var guard project.GuardResult
+	proj, err = cmdutil.UpdateProject(cmd.Context(), proj, func(p *state.Project) error {
+		if p.Statechart.Current_state != currentState {
+			return fmt.Errorf("project advanced to %s by another process", p.Statechart.Current_state)
+		}
+		before = p.Snapshot()
+
+		next, err := config.DetermineEvent(p)
+		if err != nil {
+			return enhanceAutoTransitionError(err, p, currentState)
+		}
+		if next != event {
+			return fmt.Errorf("project changed while preparing the transition: next event is now %s instead of %s", next, event)
+		}
+		guard = config.EvaluateGuard(project.State(currentState), event, p)
+		if !guard.Passed() {
+			return guardBlockedError(config, project.State(currentState), event, guard)
+		}
 
-	// Sync machine state to project state before saving
-	proj.Statechart.Current_state = string(machine.State())
+		// Record the passing verification
+		verification.record(p)
 
-	// Save updated state
-	if err := proj.Save(cmd.Context()); err != nil {
-		return fmt.Errorf("failed to save state: %w", err)
+		// Build project machine for current state and fire the event with
+		// automatic phase status updates
+		machine := config.BuildProjectMachine(p, project.State(currentState))
+		if err := config.FireWithPhaseUpdates(machine, event, p); err != nil {
+			return fmt.Errorf("failed to advance: %w", err)
+		}
+
+		// Sync machine state to project state before saving
+		p.Statechart.Current_state = string(machine.State())
+
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
+	// Snapshot the previous state and record the transition, then release
+	// the lock for the post_advance hooks
+	recordAdvance(cmd, config, currentState, event, before, guard, proj)
+	_ = lock.Release()
+
 	// Display new state
 	newState := proj.Statechart.Current_state
 	fmt.Printf("Advanced to: %s\n", newState)
 
+	transitionHooks.after(cmd, config, proj, currentState, event)
+
 	return nil
 }
 
+// lockTransition takes the state lock for firing a transition and reloads
+// the project, which may have changed while the lock was not held. Without
+// a sow context (as in unit tests) nothing is locked and proj is returned.
+// The returned lock is safe to release when nil.
+func lockTransition(cmd *cobra.Command, ctx *sow.Context, proj *state.Project) (*state.Lock, *state.Project, error) {
+	if ctx == nil {
+		return nil, proj, nil
+	}
+
+	lock, err := cmdutil.LockProject(cmd.Context(), ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	reloaded, err := cmdutil.LoadProject(cmd.Context(), ctx)
+	if err != nil {
+		_ = lock.Release()
+		return nil, nil, fmt.Errorf("failed to load project: %w", err)
+	}
+	return lock, reloaded, nil
+}
+
 // listAvailableTransitions displays all available transitions from the current state.
-// Shows both permitted and blocked transitions with guard status.
+// Shows both permitted and blocked transitions with guard status, and the
+// unmet conditions of blocked transitions.
 func listAvailableTransitions(
 	_ *cobra.Command,
 	proj *state.Project,
@@ -257,6 +391,12 @@ func listAvailableTransitions(
 			fmt.Printf("    Requires: %s\n", transition.GuardDesc)
 		}
 
+		// Explain why a blocked transition cannot fire
+		if blocked {
+			result := config.EvaluateGuard(project.State(currentState), transition.Event, proj)
+			printUnmetConditions("    ", result)
+		}
+
 		fmt.Println()
 	}
 
@@ -274,6 +414,21 @@ func enhanceAutoTransitionError(err error, proj *state.Project, currentState str
 		return fmt.Errorf("cannot advance from state %s: %w", currentState, err)
 	}
 
+	// A branching state whose discriminator matched no path lists what its
+	// branches are waiting for
+	var branchErr *project.ErrBranchNotFound
+	if errors.As(err, &branchErr) {
+		if result := branchUnmetConditions(config, project.State(currentState), proj); !result.Passed() {
+			var msg strings.Builder
+			msg.WriteString(fmt.Sprintf("cannot advance from state %s: no branch can be taken\n\n", currentState))
+			msg.WriteString("Unmet conditions:\n")
+			for _, unmet := range result.Unmet {
+				msg.WriteString(fmt.Sprintf("  - %s\n", unmet))
+			}
+			return fmt.Errorf("%s", strings.TrimSuffix(msg.String(), "\n"))
+		}
+	}
+
 	// Check if this is a terminal state (no transitions configured)
 	transitions := config.GetAvailableTransitions(project.State(currentState))
 	if len(transitions) == 0 {
@@ -314,7 +469,7 @@ func enhanceAutoTransitionError(err error, proj *state.Project, currentState str
 //
 // Side effects: NONE - this function never modifies project state.
 func validateTransition(
-	_ *sow.Context,
+	ctx *sow.Context,
 	proj *state.Project,
 	currentState string,
 	event string,
@@ -351,6 +506,7 @@ func validateTransition(
 			fmt.Printf("Guard description: %s\n", guardDesc)
 		}
 		fmt.Println("Current status: Guard not satisfied")
+		printUnmetConditions("", projectConfig.EvaluateGuard(project.State(currentState), project.Event(event), proj))
 		fmt.Println()
 		fmt.Println("Fix the guard condition, then try again.")
 
@@ -367,12 +523,36 @@ func validateTransition(
 		fmt.Printf("Description: %s\n", description)
 	}
 
+	// Verification commands are not run by a dry run, only listed
+	printVerifyCommands(ctx, projectConfig.CompletedPhase(project.State(currentState), project.Event(event)))
+
 	fmt.Println()
 	fmt.Printf("To execute: sow advance %s\n", event)
 
 	return nil
 }
 
+// printVerifyCommands lists the verification commands configured for a
+// phase. Nothing is printed without a phase, a sow context, or commands.
+func printVerifyCommands(ctx *sow.Context, phase string) {
+	if phase == "" || ctx == nil {
+		return
+	}
+	repoConfig, err := config.LoadRepoConfig(ctx.FS())
+	if err != nil {
+		return
+	}
+	commands := config.GetVerifyCommands(repoConfig, phase)
+	if len(commands) == 0 {
+		return
+	}
+
+	fmt.Printf("\nVerification of the %s phase will run first:\n", phase)
+	for _, command := range commands {
+		fmt.Printf("  - %s\n", command)
+	}
+}
+
 // executeExplicitTransition validates and executes an explicit event transition.
 // This is the core implementation of explicit event mode (sow advance [event]).
 //
@@ -418,62 +598,415 @@ func executeExplicitTransition(
 		return fmt.Errorf("event not configured")
 	}
 
-	// Build project machine for current state (returns *project.Machine, not raw *stateless.StateMachine)
-	machine := config.BuildProjectMachine(proj, typedState)
-	err := config.FireWithPhaseUpdates(machine, typedEvent, proj)
+	transitionHooks, err := newAdvanceHooks(cmd, ctx)
+	if err != nil {
+		return err
+	}
+	verification, err := newPhaseVerification(cmd, ctx)
 	if err != nil {
-		// Enhanced error handling for guard failures
-		return enhanceTransitionError(err, typedState, typedEvent, targetState, config)
+		return err
+	}
+
+	// Report unmet guard conditions, verify the phase being completed, then
+	// give the configured hooks a chance to veto the transition, all before
+	// the state lock is taken
+	if guard := config.EvaluateGuard(typedState, typedEvent, proj); !guard.Passed() {
+		return guardBlockedError(config, typedState, typedEvent, guard)
+	}
+	if err := verification.run(cmd, config, currentState, typedEvent); err != nil {
+		verification.recordFailure(cmd, ctx, err)
+		return err
+	}
+	if err := transitionHooks.before(cmd, config, proj, currentState, typedEvent); err != nil {
+		return err
 	}
 
-	// Sync machine state to project state (Save() does this, but we need it before Save())
-	// This is required because the machine's internal state has changed
-	proj.Statechart.Current_state = string(machine.State())
+	lock, proj, err := lockTransition(cmd, ctx, proj)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+
+	// The project may have changed while the lock was not held, and the
+	// save is retried after a concurrent write, so the guard is checked
+	// again against the state being saved
+	var before *projschema.ProjectState
+	var guard project.GuardResult
+	fire := func(p *state.Project) error {
+		if p.Statechart.Current_state != currentState {
+			return fmt.Errorf("project advanced to %s by another process", p.Statechart.Current_state)
+		}
+		before = p.Snapshot()
 
-	// Save updated state to disk
+		guard = config.EvaluateGuard(typedState, typedEvent, p)
+		if !guard.Passed() {
+			return guardBlockedError(config, typedState, typedEvent, guard)
+		}
+
+		// Record the passing verification
+		verification.record(p)
+
+		// Build project machine for current state (returns *project.Machine, not raw *stateless.StateMachine)
+		machine := config.BuildProjectMachine(p, typedState)
+		if err := config.FireWithPhaseUpdates(machine, typedEvent, p); err != nil {
+			return fmt.Errorf("failed to advance: %w", err)
+		}
+
+		// Sync machine state to project state (Save() does this, but we need it before Save())
+		// This is required because the machine's internal state has changed
+		p.Statechart.Current_state = string(machine.State())
+
+		return nil
+	}
+
+	// Fire and save updated state to disk
 	// Note: In production, ctx is always set via Load()
 	// In unit tests, proj may not have ctx, so we skip Save()
-	if ctx != nil {
-		if err := proj.Save(cmd.Context()); err != nil {
-			return fmt.Errorf("failed to save state: %w", err)
+	if ctx == nil {
+		if err := fire(proj); err != nil {
+			return err
+		}
+	} else {
+		if proj, err = cmdutil.UpdateProject(cmd.Context(), proj, fire); err != nil {
+			return err
 		}
+
+		// Snapshot the previous state and record the transition
+		recordAdvance(cmd, config, currentState, typedEvent, before, guard, proj)
 	}
 
+	// Release the lock for the post_advance hooks
+	_ = lock.Release()
+
 	// Display new state
 	newState := proj.Statechart.Current_state
 	fmt.Printf("Advanced to: %s\n", newState)
 
+	transitionHooks.after(cmd, config, proj, currentState, typedEvent)
+
 	return nil
 }
 
-// enhanceTransitionError enhances error messages for transition failures.
-// Detects guard failures and provides helpful context and next steps.
-func enhanceTransitionError(
-	err error,
+// guardBlockedError describes a transition blocked by its guard, listing
+// each unmet condition so the orchestrator knows what to fix.
+func guardBlockedError(
+	config *project.ProjectTypeConfig,
 	currentState project.State,
 	event project.Event,
-	targetState project.State,
+	result project.GuardResult,
+) error {
+	var msg strings.Builder
+	msg.WriteString("transition blocked by guard")
+	if desc := config.GetGuardDescription(currentState, event); desc != "" {
+		msg.WriteString(": " + desc)
+	}
+	msg.WriteString("\n\n")
+
+	msg.WriteString(fmt.Sprintf("Current state: %s\n", currentState))
+	msg.WriteString(fmt.Sprintf("Event: %s\n", event))
+	msg.WriteString(fmt.Sprintf("Target state: %s\n\n", config.GetTargetState(currentState, event)))
+
+	msg.WriteString("Unmet conditions:\n")
+	for _, unmet := range result.Unmet {
+		msg.WriteString(fmt.Sprintf("  - %s\n", unmet))
+	}
+
+	msg.WriteString(fmt.Sprintf("\nUse 'sow advance --dry-run %s' to validate prerequisites.", event))
+
+	return fmt.Errorf("%s", msg.String())
+}
+
+// branchUnmetConditions merges the unmet conditions of every transition out
+// of a branching state, without repeats. It explains why the discriminator
+// matched no branch, e.g. because the review has no assessment yet.
+func branchUnmetConditions(config *project.ProjectTypeConfig, from project.State, p *state.Project) project.GuardResult {
+	var result project.GuardResult
+	seen := make(map[string]bool)
+	for _, transition := range config.GetAvailableTransitions(from) {
+		for _, unmet := range config.EvaluateGuard(from, transition.Event, p).Unmet {
+			if !seen[unmet] {
+				seen[unmet] = true
+				result.Addf("%s", unmet)
+			}
+		}
+	}
+	return result
+}
+
+// printUnmetConditions prints the unmet conditions of a guard result, one
+// per line, prefixed with indent. Nothing is printed for a passing result.
+func printUnmetConditions(indent string, result project.GuardResult) {
+	if result.Passed() {
+		return
+	}
+	fmt.Printf("%sUnmet conditions:\n", indent)
+	for _, unmet := range result.Unmet {
+		fmt.Printf("%s  - %s\n", indent, unmet)
+	}
+}
+
+// advanceHooks runs the shell hooks from .sow/config.yaml around a single
+// transition.
+type advanceHooks struct {
+	runner *hooks.Runner
+}
+
+// newAdvanceHooks loads the configured hooks. Without a sow context (as in
+// unit tests) no hooks run.
+func newAdvanceHooks(cmd *cobra.Command, ctx *sow.Context) (*advanceHooks, error) {
+	if ctx == nil {
+		return &advanceHooks{}, nil
+	}
+	runner, err := hooks.FromContext(ctx, cmd.OutOrStdout())
+	if err != nil {
+		return nil, err
+	}
+	return &advanceHooks{runner: runner}, nil
+}
+
+// before runs the pre_advance, on_exit, and on_enter hooks. A failing hook
+// vetoes the transition. Callers run them without holding the state lock.
+func (h *advanceHooks) before(
+	cmd *cobra.Command,
+	config *project.ProjectTypeConfig,
+	p *state.Project,
+	fromState string,
+	event project.Event,
+) error {
+	if h.runner == nil {
+		return nil
+	}
+
+	err := h.runner.Before(cmd.Context(), hookTransition(config, p, fromState, event))
+	var hookErr *hooks.Error
+	if errors.As(err, &hookErr) {
+		return hookVetoError(hookErr)
+	}
+	return err
+}
+
+// after runs the post_advance hooks. The transition has already been
+// saved, so failures are reported as warnings.
+func (h *advanceHooks) after(
+	cmd *cobra.Command,
+	config *project.ProjectTypeConfig,
+	p *state.Project,
+	fromState string,
+	event project.Event,
+) {
+	if h.runner == nil {
+		return
+	}
+
+	err := h.runner.After(cmd.Context(), hookTransition(config, p, fromState, event))
+	if err == nil {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	var hookErr *hooks.Error
+	if errors.As(err, &hookErr) && hookErr.Output != "" {
+		fmt.Fprintf(os.Stderr, "%s\n", strings.TrimRight(hookErr.Output, "\n"))
+	}
+}
+
+// phaseVerification runs the verification commands from .sow/config.yaml
+// for the phase a transition completes.
+type phaseVerification struct {
+	runner *verify.Runner
+	result *verify.Result
+}
+
+// newPhaseVerification loads the configured verification commands. Without
+// a sow context (as in unit tests) nothing is verified.
+func newPhaseVerification(cmd *cobra.Command, ctx *sow.Context) (*phaseVerification, error) {
+	if ctx == nil {
+		return &phaseVerification{}, nil
+	}
+	runner, err := verify.FromContext(ctx, cmd.OutOrStdout())
+	if err != nil {
+		return nil, err
+	}
+	return &phaseVerification{runner: runner}, nil
+}
+
+// run verifies the phase completed by firing event from fromState, if it
+// has verification commands. Callers run it without holding the state
+// lock. A failing result is returned as a *verificationFailedError; a
+// passing one is kept for record.
+func (v *phaseVerification) run(
+	cmd *cobra.Command,
 	config *project.ProjectTypeConfig,
+	fromState string,
+	event project.Event,
 ) error {
-	// Check if this is a guard failure
-	if strings.Contains(err.Error(), "guard condition is not met") {
-		guardDesc := config.GetGuardDescription(currentState, event)
+	if v.runner == nil {
+		return nil
+	}
+	phase := config.CompletedPhase(project.State(fromState), event)
+	if phase == "" || len(v.runner.Commands(phase)) == 0 {
+		return nil
+	}
 
-		var msg strings.Builder
-		msg.WriteString(fmt.Sprintf("Transition blocked: %s\n\n", guardDesc))
-		msg.WriteString(fmt.Sprintf("Current state: %s\n", currentState))
-		msg.WriteString(fmt.Sprintf("Event: %s\n", event))
-		msg.WriteString(fmt.Sprintf("Target state: %s\n\n", targetState))
+	result, err := v.runner.Run(cmd.Context(), phase)
+	if err != nil {
+		return fmt.Errorf("failed to verify %s phase: %w", phase, err)
+	}
+	v.result = result
 
-		if guardDesc != "" {
-			msg.WriteString(fmt.Sprintf("The guard condition is not satisfied. %s\n\n", guardDesc))
+	if !result.Passed {
+		return &verificationFailedError{result: result}
+	}
+	return nil
+}
+
+// record stores a passing verification in the phase metadata of p, the
+// project the transition is fired on. Nothing is recorded if no phase was
+// verified.
+func (v *phaseVerification) record(p *state.Project) {
+	if v.result != nil && v.result.Passed {
+		recordVerification(p, v.result)
+	}
+}
+
+// recordFailure records a failed verification in the phase metadata. The
+// transition was refused, so the result is saved on its own under the state
+// lock. err is the error that refused the transition; other errors are
+// ignored.
+func (v *phaseVerification) recordFailure(cmd *cobra.Command, ctx *sow.Context, err error) {
+	var failed *verificationFailedError
+	if !errors.As(err, &failed) || ctx == nil {
+		return
+	}
+
+	lock, proj, lockErr := lockTransition(cmd, ctx, nil)
+	if lockErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record verification results: %v\n", lockErr)
+		return
+	}
+	defer func() { _ = lock.Release() }()
+
+	_, saveErr := cmdutil.UpdateProject(cmd.Context(), proj, func(p *state.Project) error {
+		recordVerification(p, failed.result)
+		return nil
+	})
+	if saveErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record verification results: %v\n", saveErr)
+	}
+}
+
+// recordVerification stores a verification result in its phase's metadata.
+func recordVerification(p *state.Project, result *verify.Result) {
+	phase, exists := p.Phases[result.Phase]
+	if !exists {
+		return
+	}
+	if phase.Metadata == nil {
+		phase.Metadata = make(map[string]interface{})
+	}
+	phase.Metadata[verify.MetadataKey] = result.Metadata()
+	p.Phases[result.Phase] = phase
+}
+
+// verificationFailedError reports a transition refused because a
+// verification command of the completed phase failed.
+type verificationFailedError struct {
+	result *verify.Result
+}
+
+// Error lists each verification command with its outcome.
+func (e *verificationFailedError) Error() string {
+	var msg strings.Builder
+	msg.WriteString(fmt.Sprintf("transition blocked: %s phase verification failed\n\n", e.result.Phase))
+	for _, c := range e.result.Commands {
+		if c.Passed {
+			msg.WriteString(fmt.Sprintf("  ✓ %s\n", c.Command))
+			continue
 		}
+		if c.TimedOut {
+			msg.WriteString(fmt.Sprintf("  ✗ %s (timed out)\n", c.Command))
+		} else {
+			msg.WriteString(fmt.Sprintf("  ✗ %s (exit code %d)\n", c.Command, c.ExitCode))
+		}
+		msg.WriteString(fmt.Sprintf("    Output: %s\n", c.Output))
+	}
+	msg.WriteString(fmt.Sprintf("\nResults are recorded in the %s phase metadata (%s).", e.result.Phase, verify.MetadataKey))
+	return msg.String()
+}
 
-		msg.WriteString(fmt.Sprintf("Use 'sow advance --dry-run %s' to validate prerequisites.", event))
+// hookTransition describes a transition to the hooks.
+func hookTransition(
+	config *project.ProjectTypeConfig,
+	p *state.Project,
+	fromState string,
+	event project.Event,
+) hooks.Transition {
+	return hooks.Transition{
+		Project: p.Name,
+		Type:    p.Type,
+		Branch:  p.Branch,
+		Phase:   config.GetPhaseForState(fromState),
+		Event:   string(event),
+		From:    fromState,
+		To:      string(config.GetTargetState(project.State(fromState), event)),
+	}
+}
 
-		return fmt.Errorf("%s", msg.String())
+// hookVetoError describes a transition vetoed by a failing hook, including
+// the hook's output.
+func hookVetoError(err *hooks.Error) error {
+	var msg strings.Builder
+	msg.WriteString(fmt.Sprintf("transition vetoed by %s hook: %s\n\n", err.Point, err.Command))
+	msg.WriteString(fmt.Sprintf("Hook failed: %v\n", err.Err))
+	if output := strings.TrimRight(err.Output, "\n"); output != "" {
+		msg.WriteString("\n" + output + "\n")
 	}
+	msg.WriteString("\nHook output is recorded in .sow/" + hooks.LogPath)
+
+	return fmt.Errorf("%s", msg.String())
+}
 
-	// Other error types - use default wrapping
-	return fmt.Errorf("failed to advance: %w", err)
+// recordAdvance saves a snapshot of the state from before a completed
+// transition and records the transition in the project journal, with each
+// condition of the guard evaluated for it, so the history shows why the
+// transition was allowed.
+func recordAdvance(
+	cmd *cobra.Command,
+	config *project.ProjectTypeConfig,
+	fromState string,
+	event project.Event,
+	before *projschema.ProjectState,
+	guard project.GuardResult,
+	proj *state.Project,
+) {
+	entry := state.JournalEntry{
+		Kind:     state.JournalAdvance,
+		Phase:    config.GetPhaseForState(fromState),
+		Event:    string(event),
+		From:     fromState,
+		To:       proj.Statechart.Current_state,
+		Snapshot: cmdutil.SaveSnapshot(cmd.Context(), before, string(event)),
+		Revision: proj.Revision,
+	}
+	for _, condition := range guard.Met {
+		entry.Guards = append(entry.Guards, state.GuardResult{Description: condition, Passed: true})
+	}
+	for _, condition := range guard.Unmet {
+		entry.Guards = append(entry.Guards, state.GuardResult{Description: condition, Passed: false})
+	}
+	cmdutil.AppendJournal(cmd.Context(), entry)
+}
+
+// undoTransition restores the project to the snapshot taken before the most
+// recent transition. The machine is rebuilt at the restored state and the
+// rollback is recorded in the project journal.
+func undoTransition(cmd *cobra.Command, ctx *sow.Context, proj *state.Project) error {
+	currentState := proj.Statechart.Current_state
+
+	proj, snap, err := cmdutil.RollbackProject(cmd.Context(), ctx, proj, 0)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Undid %s: %s → %s\n", snap.Event, currentState, proj.Statechart.Current_state)
+	return nil
 }
@@ -19,13 +19,23 @@ has specific capabilities and prompts tailored to its role.
 Commands:
   list      List available agents
   spawn     Spawn an agent to execute a task
-  resume    Resume a paused agent session`,
+  run       Run all ready tasks in parallel worktrees
+  resume    Resume a paused agent session
+  logs      Show or follow the output of an agent session
+  stats     Report agent cost, tokens, and activity
+  ps        List running agents and orphaned tasks
+  kill      Stop a running agent`,
 	}
 
 	// Add subcommands
 	cmd.AddCommand(newListCmd())
 	cmd.AddCommand(newSpawnCmd())
+	cmd.AddCommand(newRunCmd())
 	cmd.AddCommand(newResumeCmd())
+	cmd.AddCommand(newLogsCmd())
+	cmd.AddCommand(newStatsCmd())
+	cmd.AddCommand(newPsCmd())
+	cmd.AddCommand(newKillCmd())
 
 	return cmd
 }
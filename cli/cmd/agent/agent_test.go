@@ -62,6 +62,7 @@ func TestNewAgentCmd_HasSubcommands(t *testing.T) {
 	expectedSubcmds := map[string]bool{
 		"list":                    false,
 		"spawn [task-id]":         false,
+		"run":                     false,
 		"resume [task-id] <prompt>": false,
 	}
 
@@ -0,0 +1,125 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/jmgilman/sow/cli/internal/agents"
+	"github.com/jmgilman/sow/cli/internal/agents/logformat"
+	"github.com/jmgilman/sow/cli/internal/budget"
+	"github.com/jmgilman/sow/cli/internal/cmdutil"
+	"github.com/jmgilman/sow/libs/project/state"
+	"github.com/spf13/cobra"
+)
+
+// checkBudget refuses to start an agent once the project, or the task of
+// phaseName when taskID is set, has reached its budget in .sow/config.yaml.
+// Nearly spent budgets are reported as warnings on stderr.
+func checkBudget(cmd *cobra.Command, proj *state.Project, phaseName, taskID string) error {
+	return checkProjectBudget(cmd.Context(), cmd.ErrOrStderr(), proj, phaseName, taskID)
+}
+
+// checkProjectBudget is checkBudget reporting warnings on errOut.
+func checkProjectBudget(ctx context.Context, errOut io.Writer, proj *state.Project, phaseName, taskID string) error {
+	sowCtx := cmdutil.GetContext(ctx)
+	b, err := budget.FromContext(sowCtx)
+	if err != nil {
+		return err
+	}
+	if !b.Enabled() {
+		return nil
+	}
+
+	outputDir := filepath.Join(sowCtx.RepoRoot(), ".sow", "project", "agent-outputs")
+	report, err := buildStatsReport(proj, outputDir)
+	if err != nil {
+		return fmt.Errorf("failed to compute agent spend: %w", err)
+	}
+
+	var task budget.Spend
+	for _, s := range report.Sessions {
+		if taskID != "" && s.Phase == phaseName && s.Task == taskID {
+			task = spendOf(s.Stats)
+		}
+	}
+
+	warnings, err := b.Check(spendOf(report.Total), taskID, task)
+	for _, warning := range warnings {
+		_, _ = fmt.Fprintf(errOut, "Warning: %s\n", warning)
+	}
+	return err
+}
+
+// withBudgetRetries returns a context under which an execution policy only
+// retries a failed agent while the budget checked by checkBudget allows
+// it. The project is reloaded for each check, since the failed attempt and
+// other agents have spent since the agent started.
+func withBudgetRetries(ctx context.Context, errOut io.Writer, phaseName, taskID string) context.Context {
+	return agents.WithRetryCheck(ctx, func() error {
+		proj, err := cmdutil.LoadProject(ctx, cmdutil.GetContext(ctx))
+		if err != nil {
+			return fmt.Errorf("failed to load project: %w", err)
+		}
+		return checkProjectBudget(ctx, errOut, proj, phaseName, taskID)
+	})
+}
+
+// recordSpend records agent spend in the metadata of phaseName, or of every
+// in-progress phase for taskless sessions (empty phaseName), when a budget
+// is configured. Failing to record is reported on errOut but does not fail
+// the agent.
+func recordSpend(ctx context.Context, errOut io.Writer, phaseName string) {
+	if err := recordPhaseSpend(ctx, phaseName); err != nil {
+		_, _ = fmt.Fprintf(errOut, "Warning: failed to record agent spend: %v\n", err)
+	}
+}
+
+// recordPhaseSpend stores the budget totals in phase metadata under the
+// state lock.
+func recordPhaseSpend(ctx context.Context, phaseName string) error {
+	sowCtx := cmdutil.GetContext(ctx)
+	b, err := budget.FromContext(sowCtx)
+	if err != nil || !b.Enabled() {
+		return err
+	}
+
+	lock, err := cmdutil.LockProject(ctx, sowCtx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+
+	proj, err := cmdutil.LoadProject(ctx, sowCtx)
+	if err != nil {
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+	outputDir := filepath.Join(sowCtx.RepoRoot(), ".sow", "project", "agent-outputs")
+	report, err := buildStatsReport(proj, outputDir)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	_, err = cmdutil.UpdateProject(ctx, proj, func(p *state.Project) error {
+		for name, phase := range p.Phases {
+			if name != phaseName && (phaseName != "" || phase.Status != "in_progress") {
+				continue
+			}
+			if phase.Metadata == nil {
+				phase.Metadata = make(map[string]interface{})
+			}
+			phase.Metadata[budget.MetadataKey] = b.Metadata(spendOf(report.Phases[name]), spendOf(report.Total), now)
+			p.Phases[name] = phase
+		}
+		return nil
+	})
+	return err
+}
+
+// spendOf returns the spend counted against budgets: cost and all tokens.
+func spendOf(stats logformat.Stats) budget.Spend {
+	return budget.Spend{CostUSD: stats.CostUSD, Tokens: stats.Tokens()}
+}
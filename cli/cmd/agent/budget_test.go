@@ -0,0 +1,190 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmgilman/sow/cli/internal/agents"
+	"github.com/jmgilman/sow/cli/internal/cmdutil"
+	"github.com/jmgilman/sow/libs/schemas"
+	"github.com/jmgilman/sow/libs/schemas/project"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// budgetTest is a project with task 010 (session session-010) whose
+// spawned agents write spawnLog as their session's raw log.
+type budgetTest struct {
+	cmd     *cobra.Command
+	stderr  bytes.Buffer
+	tmpDir  string
+	spawned bool
+}
+
+// setupBudgetTest creates a budgetTest with the given .sow/config.yaml and
+// raw session logs keyed by session ID.
+func setupBudgetTest(t *testing.T, configYAML string, logs map[string]string, spawnLog string) *budgetTest {
+	t.Helper()
+	now := time.Now()
+	sowCtx, tmpDir, cleanup := setupTestProject(t, []project.TaskState{{
+		Id:             "010",
+		Name:           "Test Task",
+		Phase:          "implementation",
+		Status:         "pending",
+		Iteration:      1,
+		Assigned_agent: "implementer",
+		Created_at:     now,
+		Updated_at:     now,
+		Session_id:     "session-010",
+		Inputs:         []project.ArtifactState{},
+		Outputs:        []project.ArtifactState{},
+	}})
+	t.Cleanup(cleanup)
+
+	if err := os.WriteFile(filepath.Join(tmpDir, ".sow", "config.yaml"), []byte(configYAML), 0644); err != nil {
+		t.Fatalf("failed to write config.yaml: %v", err)
+	}
+	outputDir := filepath.Join(tmpDir, ".sow", "project", "agent-outputs")
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	for id, content := range logs {
+		if err := os.WriteFile(filepath.Join(outputDir, id+".json"), []byte(content), 0644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+
+	bt := &budgetTest{cmd: newSpawnCmd(), tmpDir: tmpDir}
+	mockRegistry := agents.NewExecutorRegistry()
+	mockRegistry.RegisterNamed("claude-code", &agents.MockExecutor{
+		SpawnFunc: func(_ context.Context, _ *agents.Agent, _ string, sessionID string) error {
+			bt.spawned = true
+			return os.WriteFile(filepath.Join(outputDir, sessionID+".json"), []byte(spawnLog), 0644)
+		},
+	})
+	originalLoadRegistry := loadExecutorRegistry
+	t.Cleanup(func() { loadExecutorRegistry = originalLoadRegistry })
+	loadExecutorRegistry = func(_ *schemas.UserConfig, _ string) (*agents.ExecutorRegistry, error) {
+		return mockRegistry, nil
+	}
+
+	bt.cmd.SetErr(&bt.stderr)
+	bt.cmd.SetContext(cmdutil.WithContext(context.Background(), sowCtx))
+	return bt
+}
+
+// TestRunSpawn_TaskBudgetExceeded verifies a task whose session has spent
+// its budget is not spawned again.
+func TestRunSpawn_TaskBudgetExceeded(t *testing.T) {
+	bt := setupBudgetTest(t,
+		"budget:\n  task:\n    usd: 1\n",
+		map[string]string{"session-010": `{"type":"result","total_cost_usd":1.25,"num_turns":3}` + "\n"},
+		"")
+
+	err := runSpawn(bt.cmd, []string{"010"}, "implementation", "", "")
+	if err == nil || !strings.Contains(err.Error(), "agent budget exceeded: task 010 has spent $1.25 of its $1.00 budget") {
+		t.Fatalf("runSpawn() error = %v, want task budget exceeded", err)
+	}
+	if bt.spawned {
+		t.Error("agent was spawned despite the spent budget")
+	}
+}
+
+// TestRunSpawn_BudgetWarningAndRecordedSpend verifies a nearly spent
+// budget is reported, and the spend after the run is recorded in the
+// phase metadata.
+func TestRunSpawn_BudgetWarningAndRecordedSpend(t *testing.T) {
+	bt := setupBudgetTest(t,
+		"budget:\n  project:\n    usd: 10\n",
+		map[string]string{"old-session": `{"type":"result","total_cost_usd":8.5,"num_turns":3}` + "\n"},
+		`{"type":"result","total_cost_usd":0.5,"num_turns":1,"usage":{"input_tokens":100,"output_tokens":20}}`+"\n")
+
+	if err := runSpawn(bt.cmd, []string{"010"}, "implementation", "", ""); err != nil {
+		t.Fatalf("runSpawn() error = %v", err)
+	}
+	if !bt.spawned {
+		t.Fatal("agent was not spawned")
+	}
+	if want := "Warning: project has spent $8.50 of its $10.00 budget (85%)"; !strings.Contains(bt.stderr.String(), want) {
+		t.Errorf("stderr = %q, want %q", bt.stderr.String(), want)
+	}
+
+	stateData, err := os.ReadFile(filepath.Join(bt.tmpDir, ".sow", "project", "state.yaml"))
+	if err != nil {
+		t.Fatalf("failed to read state: %v", err)
+	}
+	var saved project.ProjectState
+	if err := yaml.Unmarshal(stateData, &saved); err != nil {
+		t.Fatalf("failed to unmarshal state: %v", err)
+	}
+	recorded, ok := saved.Phases["implementation"].Metadata["budget"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("phase metadata = %v, want budget", saved.Phases["implementation"].Metadata)
+	}
+	if recorded["phase_cost_usd"] != 0.5 || recorded["project_cost_usd"] != 9 || recorded["project_remaining_usd"] != 1 {
+		t.Errorf("recorded budget = %v", recorded)
+	}
+	if recorded["phase_tokens"] != 120 {
+		t.Errorf("phase_tokens = %v, want 120", recorded["phase_tokens"])
+	}
+}
+
+// TestRunSpawn_NoBudget verifies spend is not recorded without a budget.
+func TestRunSpawn_NoBudget(t *testing.T) {
+	bt := setupBudgetTest(t, "", nil, `{"type":"result","total_cost_usd":0.5}`+"\n")
+
+	if err := runSpawn(bt.cmd, []string{"010"}, "implementation", "", ""); err != nil {
+		t.Fatalf("runSpawn() error = %v", err)
+	}
+	stateData, err := os.ReadFile(filepath.Join(bt.tmpDir, ".sow", "project", "state.yaml"))
+	if err != nil {
+		t.Fatalf("failed to read state: %v", err)
+	}
+	if strings.Contains(string(stateData), "budget") {
+		t.Errorf("state records spend without a budget:\n%s", stateData)
+	}
+}
+
+// TestRunParallel_BudgetExceeded verifies 'sow agent run' does not start
+// tasks once the project budget is spent.
+func TestRunParallel_BudgetExceeded(t *testing.T) {
+	sowCtx, tmpDir, cleanup := setupTestProject(t, []project.TaskState{runTestTask("010")})
+	defer cleanup()
+
+	if err := os.WriteFile(filepath.Join(tmpDir, ".sow", "config.yaml"), []byte("budget:\n  project:\n    usd: 1\n"), 0644); err != nil {
+		t.Fatalf("failed to write config.yaml: %v", err)
+	}
+	outputDir := filepath.Join(tmpDir, ".sow", "project", "agent-outputs")
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, "old-session.json"), []byte(`{"type":"result","total_cost_usd":1.25}`+"\n"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	spawned := false
+	exec := &agents.MockExecutor{
+		SpawnFunc: func(_ context.Context, _ *agents.Agent, _ string, _ string) error {
+			spawned = true
+			return nil
+		},
+	}
+	out, err := executeRun(t, sowCtx, exec, 1)
+	if err == nil {
+		t.Fatalf("expected error for spent budget, got output:\n%s", out)
+	}
+	if spawned {
+		t.Error("agent was spawned despite the spent budget")
+	}
+	if !strings.Contains(out, "failed: agent budget exceeded: project has spent $1.25 of its $1.00 budget") {
+		t.Errorf("expected budget failure in summary, got:\n%s", out)
+	}
+	if status := loadRunTasks(t, sowCtx)["010"].Status; status != "pending" {
+		t.Errorf("expected task 010 to stay pending, got %s", status)
+	}
+}
@@ -0,0 +1,121 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmgilman/sow/cli/internal/cmdutil"
+	"github.com/jmgilman/sow/cli/internal/runs"
+	"github.com/spf13/cobra"
+)
+
+// defaultKillGrace is how long kill waits for an agent to exit before
+// killing it.
+const defaultKillGrace = 10 * time.Second
+
+// newKillCmd creates the kill subcommand.
+func newKillCmd() *cobra.Command {
+	var phase string
+	var force bool
+	var grace time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "kill <task-id|agent|session-id>",
+		Short: "Stop a running agent",
+		Long: `Stop a running agent and every process it started.
+
+The agent is looked up by task ID (in every phase, or in --phase), by agent
+name for taskless sessions, or by session ID, as listed by 'sow agent ps'.
+Its process group is asked to exit (SIGTERM) and killed if it is still
+running after --grace. With --force it is killed right away.
+
+The task keeps its status and session, so it can be continued with
+'sow agent resume' or spawned again. Killing an agent whose process has
+already exited clears its record.
+
+Examples:
+  # Stop the agent working on task 010
+  sow agent kill 010
+
+  # Kill a stuck taskless planner immediately
+  sow agent kill planner --force`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if force {
+				grace = 0
+			}
+			return runKill(cmd, args[0], phase, grace)
+		},
+	}
+
+	cmd.Flags().StringVar(&phase, "phase", "", "Phase of the task (defaults to searching every phase)")
+	cmd.Flags().BoolVar(&force, "force", false, "Kill the agent without waiting for it to exit")
+	cmd.Flags().DurationVar(&grace, "grace", defaultKillGrace, "How long to wait for the agent to exit before killing it")
+
+	return cmd
+}
+
+// runKill implements the kill command logic.
+func runKill(cmd *cobra.Command, target, phase string, grace time.Duration) error {
+	// Get sow context
+	ctx := cmdutil.GetContext(cmd.Context())
+
+	// Check if sow is initialized
+	if !ctx.IsInitialized() {
+		return fmt.Errorf("sow not initialized. Run 'sow init' first")
+	}
+
+	proj, err := cmdutil.LoadProject(cmd.Context(), ctx)
+	if err != nil {
+		if strings.Contains(err.Error(), "no such file") {
+			return fmt.Errorf("no active project found")
+		}
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+
+	// Resolve the session, accepting a recorded session ID as well
+	dir := runs.Dir(ctx.RepoRoot())
+	sessionID, err := resolveLogSession(proj, target, phase)
+	if err != nil {
+		run, findErr := runs.Find(dir, target)
+		if findErr != nil || run == nil || phase != "" {
+			return err
+		}
+		sessionID = target
+	}
+
+	run, err := runs.Find(dir, sessionID)
+	if err != nil {
+		return err
+	}
+	if run == nil {
+		return fmt.Errorf("no running agent for %s", target)
+	}
+
+	out := cmd.OutOrStdout()
+	if !run.Alive() {
+		if err := runs.Remove(dir, *run); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(out, "Agent of %s (pid %d) had already exited; cleared its record\n", describeRun(*run), run.PID)
+		return nil
+	}
+
+	if err := run.Terminate(grace); err != nil {
+		return err
+	}
+	if err := runs.Remove(dir, *run); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(out, "Stopped agent of %s (pid %d)\n", describeRun(*run), run.PID)
+	return nil
+}
+
+// describeRun names a run's task or taskless agent for messages.
+func describeRun(run runs.Run) string {
+	if run.Task != "" {
+		return "task " + run.Task
+	}
+	return run.Agent
+}
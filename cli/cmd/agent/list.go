@@ -17,7 +17,15 @@ Agents are roles that can be spawned by the orchestrator to perform
 specialized tasks. Each agent has specific capabilities and prompts
 tailored to its role.
 
-The list shows each agent's name and description.`,
+Custom agents are declared in .sow/agents/<name>.md: YAML front matter
+with a description (and optionally capabilities), followed by the agent
+prompt. They are marked with their definition file.
+
+The list shows each agent's name, description, and permission profile:
+whether it is read-only (may only edit files under .sow/), the tools it
+is allowed or denied, and the shell commands it may run. Executors
+translate the profile into their CLI flags; yolo mode does not bypass
+a restricted profile.`,
 		RunE: runList,
 	}
 }
@@ -31,9 +39,20 @@ func runList(cmd *cobra.Command, _ []string) error {
 		return agentList[i].Name < agentList[j].Name
 	})
 
+	// Pad names to a common column, widened for long custom agent names
+	width := 14
+	for _, agent := range agentList {
+		width = max(width, len(agent.Name)+2)
+	}
+
 	cmd.Println("Available agents:")
 	for _, agent := range agentList {
-		cmd.Printf("  %-14s%s\n", agent.Name, agent.Description)
+		if agent.Source != "" {
+			cmd.Printf("  %-*s%s (custom: .sow/%s)\n", width, agent.Name, agent.Description, agent.Source)
+		} else {
+			cmd.Printf("  %-*s%s\n", width, agent.Name, agent.Description)
+		}
+		cmd.Printf("  %-*spermissions: %s\n", width, "", agent.Permissions)
 	}
 
 	return nil
@@ -132,6 +132,33 @@ func TestRunList_HasHeader(t *testing.T) {
 	}
 }
 
+// TestRunList_ShowsPermissions verifies each agent's permission profile
+// is shown.
+func TestRunList_ShowsPermissions(t *testing.T) {
+	cmd := &cobra.Command{}
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+
+	err := runList(cmd, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := buf.String()
+
+	expectedProfiles := []string{
+		"permissions: unrestricted",
+		"permissions: read-only; shell: sow *, git diff *, git log *",
+		"permissions: read-only; shell: sow *",
+	}
+
+	for _, profile := range expectedProfiles {
+		if !strings.Contains(output, profile) {
+			t.Errorf("expected output to contain %q", profile)
+		}
+	}
+}
+
 // TestRunList_FormatsWithAlignment verifies output uses consistent alignment.
 func TestRunList_FormatsWithAlignment(t *testing.T) {
 	cmd := &cobra.Command{}
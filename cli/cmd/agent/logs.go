@@ -0,0 +1,326 @@
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jmgilman/sow/cli/internal/agents/logformat"
+	"github.com/jmgilman/sow/cli/internal/cmdutil"
+	"github.com/jmgilman/sow/libs/project/state"
+	"github.com/spf13/cobra"
+)
+
+// logPollInterval is how often a followed log is checked for new output.
+const logPollInterval = 200 * time.Millisecond
+
+// logsOptions holds the flags of the logs command.
+type logsOptions struct {
+	phase   string
+	follow  bool
+	raw     bool
+	since   string
+	filters []string
+}
+
+// newLogsCmd creates the logs subcommand.
+func newLogsCmd() *cobra.Command {
+	var opts logsOptions
+
+	cmd := &cobra.Command{
+		Use:   "logs <task-id|agent>",
+		Short: "Show the output of an agent session",
+		Long: `Show the output of an agent session.
+
+The session is looked up by task ID (in every phase, or in --phase), or
+by agent name for taskless sessions started with 'sow agent spawn --agent'.
+Its output in .sow/project/agent-outputs/ is rendered the same way as the
+session's .log file.
+
+Flags:
+  -f, --follow  Keep printing output as the agent writes it (Ctrl-C to stop)
+  --raw         Print the stream-json events instead of rendering them
+  --since       Only show runs started after a duration ago (10m) or an
+                RFC 3339 time; followed output is always shown
+  --filter      Only show some kinds of output: text (assistant text and
+                final result), tools (tool calls and results), errors
+                (failed tool calls and sessions); repeatable
+
+Output of command executors that log plain text is printed as is and
+cannot be filtered.
+
+Examples:
+  # Follow the agent working on task 010
+  sow agent logs 010 -f
+
+  # Show the tool calls of the last hour
+  sow agent logs 010 --filter tools --since 1h
+
+  # Show the errors of the planner's taskless session
+  sow agent logs planner --filter errors`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLogs(cmd, args[0], opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.phase, "phase", "", "Phase of the task (defaults to searching every phase)")
+	cmd.Flags().BoolVarP(&opts.follow, "follow", "f", false, "Follow the log as it grows")
+	cmd.Flags().BoolVar(&opts.raw, "raw", false, "Print raw stream-json events")
+	cmd.Flags().StringVar(&opts.since, "since", "", "Only show runs started since a duration ago or an RFC 3339 time")
+	cmd.Flags().StringSliceVar(&opts.filters, "filter", nil, "Only show text, tools, or errors")
+
+	return cmd
+}
+
+// runLogs implements the logs command logic.
+func runLogs(cmd *cobra.Command, target string, opts logsOptions) error {
+	filter, err := logformat.ParseFilter(opts.filters)
+	if err != nil {
+		return err
+	}
+	since, err := parseSince(opts.since, time.Now())
+	if err != nil {
+		return err
+	}
+
+	// Get sow context
+	ctx := cmdutil.GetContext(cmd.Context())
+
+	// Check if sow is initialized
+	if !ctx.IsInitialized() {
+		return fmt.Errorf("sow not initialized. Run 'sow init' first")
+	}
+
+	proj, err := cmdutil.LoadProject(cmd.Context(), ctx)
+	if err != nil {
+		if strings.Contains(err.Error(), "no such file") {
+			return fmt.Errorf("no active project found")
+		}
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+
+	sessionID, err := resolveLogSession(proj, target, opts.phase)
+	if err != nil {
+		return err
+	}
+
+	runCtx := cmd.Context()
+	if opts.follow {
+		var stop context.CancelFunc
+		runCtx, stop = signal.NotifyContext(runCtx, os.Interrupt, syscall.SIGTERM)
+		defer stop()
+	}
+
+	outputDir := filepath.Join(ctx.RepoRoot(), ".sow", "project", "agent-outputs")
+	path, plain, err := findSessionLog(runCtx, outputDir, sessionID, opts.follow)
+	if err != nil || path == "" {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if plain {
+		if !filter.IsZero() || !since.IsZero() {
+			return fmt.Errorf("session %s has a plain text log, which cannot be filtered (--filter and --since need stream-json output)", sessionID)
+		}
+		return tailLog(runCtx, path, opts.follow, func(line []byte, _ bool) error {
+			_, err := fmt.Fprintf(out, "%s\n", line)
+			return err
+		})
+	}
+
+	formatter := logformat.NewFormatter()
+	formatter.Filter = filter
+	printer := &logPrinter{out: out, raw: opts.raw, since: since, formatter: formatter}
+	return tailLog(runCtx, path, opts.follow, printer.print)
+}
+
+// parseSince parses --since: a duration before now, or an RFC 3339 time.
+// An empty value returns the zero time.
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q (want a duration such as 10m or an RFC 3339 time)", value)
+}
+
+// resolveLogSession returns the session ID of a task, or of an agent's
+// taskless session. Tasks are looked up in phase, or in every phase when
+// phase is empty.
+func resolveLogSession(proj *state.Project, target, phase string) (string, error) {
+	phaseNames := []string{phase}
+	if phase == "" {
+		phaseNames = make([]string, 0, len(proj.Phases))
+		for name := range proj.Phases {
+			phaseNames = append(phaseNames, name)
+		}
+		sort.Strings(phaseNames)
+	} else if _, ok := proj.Phases[phase]; !ok {
+		return "", fmt.Errorf("phase %s not found", phase)
+	}
+
+	var found []string
+	sessionID := ""
+	for _, name := range phaseNames {
+		for _, task := range proj.Phases[name].Tasks {
+			if task.Id == target {
+				found = append(found, name)
+				sessionID = task.Session_id
+			}
+		}
+	}
+
+	switch {
+	case len(found) > 1:
+		return "", fmt.Errorf("task %s exists in phases %s; specify --phase", target, strings.Join(found, ", "))
+	case len(found) == 1 && sessionID == "":
+		return "", fmt.Errorf("task %s has no session (spawn it first with 'sow agent spawn %s')", target, target)
+	case len(found) == 1:
+		return sessionID, nil
+	case phase != "":
+		return "", fmt.Errorf("task %s not found in phase %s", target, phase)
+	}
+
+	if sessionID, ok := proj.Agent_sessions[target]; ok && sessionID != "" {
+		return sessionID, nil
+	}
+	return "", fmt.Errorf("no task or agent session named %s", target)
+}
+
+// findSessionLog returns the log of a session in outputDir: the raw
+// stream-json events, or the plain text log of executors that write no
+// events (plain is then true). When following, it waits for the log to
+// appear and returns an empty path if ctx ends first.
+func findSessionLog(ctx context.Context, outputDir, sessionID string, follow bool) (path string, plain bool, err error) {
+	rawPath := filepath.Join(outputDir, sessionID+".json")
+	logPath := filepath.Join(outputDir, sessionID+".log")
+	for {
+		if _, err := os.Stat(rawPath); err == nil {
+			return rawPath, false, nil
+		}
+		if _, err := os.Stat(logPath); err == nil {
+			return logPath, true, nil
+		}
+		if !follow {
+			return "", false, fmt.Errorf("no output recorded for session %s", sessionID)
+		}
+		select {
+		case <-ctx.Done():
+			return "", false, nil
+		case <-time.After(logPollInterval):
+		}
+	}
+}
+
+// tailLog calls handle with each line of the file at path. With follow,
+// it then waits for more lines until ctx ends; handle is told whether a
+// line was written after the end of the file was first reached.
+func tailLog(ctx context.Context, path string, follow bool, handle func(line []byte, live bool) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open log: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	reader := bufio.NewReader(file)
+	var pending []byte
+	live := false
+	for {
+		chunk, err := reader.ReadBytes('\n')
+		pending = append(pending, chunk...)
+		if err == nil {
+			if err := handle(bytes.TrimRight(pending, "\r\n"), live); err != nil {
+				return err
+			}
+			pending = pending[:0]
+			continue
+		}
+		if !errors.Is(err, io.EOF) {
+			return fmt.Errorf("failed to read log: %w", err)
+		}
+
+		// Without follow, a final line without newline is complete;
+		// when following, it is still being written.
+		if !follow {
+			if len(pending) > 0 {
+				return handle(pending, live)
+			}
+			return nil
+		}
+		live = true
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(logPollInterval):
+		}
+	}
+}
+
+// logPrinter prints the lines of a stream-json log.
+type logPrinter struct {
+	out       io.Writer
+	raw       bool
+	since     time.Time
+	formatter *logformat.Formatter
+
+	// runStart is the start of the run the current line belongs to.
+	runStart time.Time
+}
+
+// print prints one line of the log, if selected by --since and --filter.
+// Lines that are not events, such as an agent's stderr, are kept unless
+// a filter is set.
+func (p *logPrinter) print(line []byte, live bool) error {
+	if len(bytes.TrimSpace(line)) == 0 {
+		return nil
+	}
+
+	event, err := logformat.ParseEvent(line)
+	if err != nil {
+		if !p.formatter.Filter.IsZero() || !p.selected(live) {
+			return nil
+		}
+		_, err = fmt.Fprintf(p.out, "%s\n", line)
+		return err
+	}
+
+	if start, ok := event.RunStart(); ok {
+		p.runStart = start
+	}
+	if !p.selected(live) {
+		return nil
+	}
+
+	if p.raw {
+		if p.formatter.Filter.Apply(event) == nil {
+			return nil
+		}
+		_, err = fmt.Fprintf(p.out, "%s\n", line)
+		return err
+	}
+	_, err = io.WriteString(p.out, p.formatter.Format(event))
+	return err
+}
+
+// selected reports whether --since selects the current line: it belongs
+// to a run started at or after since, or was written while following.
+// Lines before the first run marker have no known time.
+func (p *logPrinter) selected(live bool) bool {
+	return p.since.IsZero() || live || (!p.runStart.IsZero() && !p.runStart.Before(p.since))
+}
@@ -0,0 +1,186 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jmgilman/sow/cli/internal/cmdutil"
+	"github.com/jmgilman/sow/libs/project/state"
+	"github.com/jmgilman/sow/libs/schemas/project"
+)
+
+// TestParseSince verifies durations and RFC 3339 times are accepted.
+func TestParseSince(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	got, err := parseSince("90m", now)
+	if err != nil || !got.Equal(now.Add(-90*time.Minute)) {
+		t.Errorf("parseSince(90m) = %v, %v", got, err)
+	}
+	got, err = parseSince("2025-05-01T00:00:00Z", now)
+	if err != nil || !got.Equal(time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("parseSince(time) = %v, %v", got, err)
+	}
+	if got, err := parseSince("", now); err != nil || !got.IsZero() {
+		t.Errorf("parseSince(\"\") = %v, %v; want zero time", got, err)
+	}
+	for _, value := range []string{"yesterday", "-5m"} {
+		if _, err := parseSince(value, now); err == nil {
+			t.Errorf("parseSince(%q) should fail", value)
+		}
+	}
+}
+
+// TestResolveLogSession verifies sessions are found by task ID or by
+// agent name.
+func TestResolveLogSession(t *testing.T) {
+	proj := &state.Project{ProjectState: project.ProjectState{
+		Phases: map[string]project.PhaseState{
+			"implementation": {Tasks: []project.TaskState{
+				{Id: "010", Session_id: "session-010"},
+				{Id: "020"},
+				{Id: "030", Session_id: "session-impl-030"},
+			}},
+			"review": {Tasks: []project.TaskState{
+				{Id: "030", Session_id: "session-review-030"},
+			}},
+		},
+		Agent_sessions: map[string]string{"planner": "session-planner"},
+	}}
+
+	tests := []struct {
+		target  string
+		phase   string
+		want    string
+		wantErr string
+	}{
+		{target: "010", want: "session-010"},
+		{target: "planner", want: "session-planner"},
+		{target: "030", phase: "review", want: "session-review-030"},
+		{target: "030", wantErr: "task 030 exists in phases implementation, review; specify --phase"},
+		{target: "020", wantErr: "task 020 has no session"},
+		{target: "010", phase: "review", wantErr: "task 010 not found in phase review"},
+		{target: "010", phase: "design", wantErr: "phase design not found"},
+		{target: "architect", wantErr: "no task or agent session named architect"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target+"/"+tt.phase, func(t *testing.T) {
+			got, err := resolveLogSession(proj, tt.target, tt.phase)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("resolveLogSession() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("resolveLogSession() = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+// TestTailLog_Follow verifies followed output includes lines appended
+// after the end of the file, marked live, and only complete lines.
+func TestTailLog_Follow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+	if err := os.WriteFile(path, []byte("one\n"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	var mu sync.Mutex
+	var lines []string
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- tailLog(ctx, path, true, func(line []byte, live bool) error {
+			mu.Lock()
+			defer mu.Unlock()
+			text := string(line)
+			if live {
+				text += " (live)"
+			}
+			lines = append(lines, text)
+			if len(lines) == 2 {
+				cancel()
+			}
+			return nil
+		})
+	}()
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	defer func() { _ = file.Close() }()
+	time.Sleep(2 * logPollInterval)
+	_, _ = file.WriteString("tw")
+	time.Sleep(2 * logPollInterval)
+	_, _ = file.WriteString("o\n")
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("tailLog() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		cancel()
+		t.Fatal("tailLog() did not return after the second line")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if want := []string{"one", "two (live)"}; strings.Join(lines, "|") != strings.Join(want, "|") {
+		t.Errorf("lines = %q, want %q", lines, want)
+	}
+}
+
+// TestRunLogs_PlainLog verifies plain text logs are printed as is and
+// cannot be filtered.
+func TestRunLogs_PlainLog(t *testing.T) {
+	now := time.Now()
+	sowCtx, tmpDir, cleanup := setupTestProject(t, []project.TaskState{{
+		Id:             "010",
+		Name:           "Test task",
+		Phase:          "implementation",
+		Status:         "in_progress",
+		Iteration:      1,
+		Assigned_agent: "implementer",
+		Created_at:     now,
+		Updated_at:     now,
+		Session_id:     "session-010",
+		Inputs:         []project.ArtifactState{},
+		Outputs:        []project.ArtifactState{},
+	}})
+	defer cleanup()
+
+	outputDir := filepath.Join(tmpDir, ".sow", "project", "agent-outputs")
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, "session-010.log"), []byte("Applied edit to main.go\n"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	cmd := newLogsCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetContext(cmdutil.WithContext(context.Background(), sowCtx))
+
+	if err := runLogs(cmd, "010", logsOptions{}); err != nil {
+		t.Fatalf("runLogs() error = %v", err)
+	}
+	if out.String() != "Applied edit to main.go\n" {
+		t.Errorf("output = %q", out.String())
+	}
+
+	err := runLogs(cmd, "010", logsOptions{filters: []string{"errors"}})
+	if err == nil || !strings.Contains(err.Error(), "plain text log") {
+		t.Errorf("runLogs() with filter error = %v, want plain text error", err)
+	}
+}
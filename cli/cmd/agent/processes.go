@@ -0,0 +1,61 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/jmgilman/sow/cli/internal/agents"
+	"github.com/jmgilman/sow/cli/internal/cmdutil"
+	"github.com/jmgilman/sow/cli/internal/runs"
+	"github.com/jmgilman/sow/cli/internal/sow"
+)
+
+// withRunRecord returns a context under which each agent process started
+// for run's session is recorded in .sow/project/run/ while it runs (see
+// package runs). Failing to record a run is passed to warn but does not
+// fail the agent.
+func withRunRecord(ctx context.Context, run runs.Run, warn func(error)) context.Context {
+	dir := runs.Dir(cmdutil.GetContext(ctx).RepoRoot())
+	return agents.WithProcessObserver(ctx, func(pid int, startedAt time.Time) func() {
+		started := run
+		started.PID = pid
+		started.PGID = pid
+		started.StartedAt = startedAt
+		started.Owner = os.Getpid()
+		started.OwnerStartedAt, _ = agents.ProcessStartTime(started.Owner)
+		if err := runs.Write(dir, started); err != nil {
+			warn(fmt.Errorf("failed to record agent process %d: %w", pid, err))
+			return nil
+		}
+		return func() {
+			if err := runs.Remove(dir, started); err != nil {
+				warn(fmt.Errorf("failed to remove record of agent process %d: %w", pid, err))
+			}
+		}
+	})
+}
+
+// checkNotRunning refuses to start an agent on a session whose recorded
+// agent is still running. target names the session on the command line,
+// for the hint to stop it.
+func checkNotRunning(ctx *sow.Context, sessionID, target string) error {
+	run, err := runs.Find(runs.Dir(ctx.RepoRoot()), sessionID)
+	if err != nil {
+		return err
+	}
+	if run != nil && run.Alive() {
+		return fmt.Errorf("session %s already has a running agent (pid %d, started %s)\nStop it with 'sow agent kill %s'",
+			sessionID, run.PID, run.StartedAt.Format(time.RFC3339), target)
+	}
+	return nil
+}
+
+// warnTo returns a warn function for withRunRecord that reports on errOut.
+func warnTo(errOut io.Writer) func(error) {
+	return func(err error) {
+		_, _ = fmt.Fprintf(errOut, "Warning: %v\n", err)
+	}
+}
@@ -0,0 +1,230 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmgilman/sow/cli/internal/agents"
+	"github.com/jmgilman/sow/cli/internal/cmdutil"
+	"github.com/jmgilman/sow/cli/internal/runs"
+	"github.com/jmgilman/sow/cli/internal/sow"
+	"github.com/jmgilman/sow/libs/schemas"
+	"github.com/jmgilman/sow/libs/schemas/project"
+	"github.com/spf13/cobra"
+)
+
+// setupProcessesTest creates a project whose task 010 (session
+// session-010) is in progress, and returns a command for it.
+func setupProcessesTest(t *testing.T, cmd *cobra.Command) (*sow.Context, *bytes.Buffer) {
+	t.Helper()
+	now := time.Now()
+	sowCtx, _, cleanup := setupTestProject(t, []project.TaskState{{
+		Id:             "010",
+		Name:           "Test Task",
+		Phase:          "implementation",
+		Status:         "in_progress",
+		Iteration:      1,
+		Assigned_agent: "implementer",
+		Created_at:     now,
+		Updated_at:     now,
+		Session_id:     "session-010",
+		Inputs:         []project.ArtifactState{},
+		Outputs:        []project.ArtifactState{},
+	}})
+	t.Cleanup(cleanup)
+
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stdout)
+	cmd.SetContext(cmdutil.WithContext(context.Background(), sowCtx))
+	return sowCtx, &stdout
+}
+
+// exitedPID returns the PID of a process that has exited.
+func exitedPID(t *testing.T) int {
+	t.Helper()
+	cmd := exec.Command("true")
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("failed to run true: %v", err)
+	}
+	return cmd.Process.Pid
+}
+
+// writeRun records a run of the project's task 010, started when the
+// process pid did.
+func writeRun(t *testing.T, sowCtx *sow.Context, pid int) runs.Run {
+	t.Helper()
+	startedAt, ok := agents.ProcessStartTime(pid)
+	if !ok {
+		startedAt = time.Now()
+	}
+	run := runs.Run{
+		PID:       pid,
+		PGID:      pid,
+		StartedAt: startedAt,
+		Session:   "session-010",
+		Phase:     "implementation",
+		Task:      "010",
+		Agent:     "implementer",
+		Owner:     os.Getpid(),
+	}
+	if err := runs.Write(runs.Dir(sowCtx.RepoRoot()), run); err != nil {
+		t.Fatalf("runs.Write() error = %v", err)
+	}
+	return run
+}
+
+// TestRunPs_Running verifies a live run is listed as running.
+func TestRunPs_Running(t *testing.T) {
+	cmd := newPsCmd()
+	sowCtx, stdout := setupProcessesTest(t, cmd)
+	writeRun(t, sowCtx, os.Getpid())
+
+	if err := runPs(cmd, "text"); err != nil {
+		t.Fatalf("runPs() error = %v", err)
+	}
+	output := stdout.String()
+	for _, want := range []string{"TASK", "implementation/010", "implementer", "session-010", "running"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "Orphaned") {
+		t.Errorf("running task reported as orphaned:\n%s", output)
+	}
+}
+
+// TestRunPs_Orphaned verifies an in-progress task whose agent exited
+// without sow seeing it is reported as orphaned.
+func TestRunPs_Orphaned(t *testing.T) {
+	cmd := newPsCmd()
+	sowCtx, stdout := setupProcessesTest(t, cmd)
+	writeRun(t, sowCtx, exitedPID(t))
+
+	if err := runPs(cmd, "text"); err != nil {
+		t.Fatalf("runPs() error = %v", err)
+	}
+	output := stdout.String()
+	for _, want := range []string{"exited", "Orphaned tasks", "implementation/010  session session-010"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+}
+
+// TestRunPs_Empty verifies the message without recorded runs.
+func TestRunPs_Empty(t *testing.T) {
+	cmd := newPsCmd()
+	_, stdout := setupProcessesTest(t, cmd)
+
+	if err := runPs(cmd, "text"); err != nil {
+		t.Fatalf("runPs() error = %v", err)
+	}
+	if stdout.String() != "No running agents\n" {
+		t.Errorf("output = %q, want %q", stdout.String(), "No running agents\n")
+	}
+}
+
+// TestRunKill_Exited verifies killing an agent that already exited clears
+// its record.
+func TestRunKill_Exited(t *testing.T) {
+	cmd := newKillCmd()
+	sowCtx, stdout := setupProcessesTest(t, cmd)
+	run := writeRun(t, sowCtx, exitedPID(t))
+
+	if err := runKill(cmd, "010", "", time.Second); err != nil {
+		t.Fatalf("runKill() error = %v", err)
+	}
+	if !strings.Contains(stdout.String(), "had already exited") {
+		t.Errorf("output = %q, want already exited", stdout.String())
+	}
+	if found, _ := runs.Find(runs.Dir(sowCtx.RepoRoot()), run.Session); found != nil {
+		t.Error("record not removed")
+	}
+}
+
+// TestRunKill_NotRunning verifies the error for a task without a recorded
+// run.
+func TestRunKill_NotRunning(t *testing.T) {
+	cmd := newKillCmd()
+	setupProcessesTest(t, cmd)
+
+	err := runKill(cmd, "010", "", time.Second)
+	if err == nil || !strings.Contains(err.Error(), "no running agent for 010") {
+		t.Errorf("runKill() error = %v, want no running agent", err)
+	}
+}
+
+// TestRunSpawn_AlreadyRunning verifies a session whose agent still runs is
+// not spawned again.
+func TestRunSpawn_AlreadyRunning(t *testing.T) {
+	cmd := newSpawnCmd()
+	sowCtx, _ := setupProcessesTest(t, cmd)
+	writeRun(t, sowCtx, os.Getpid())
+
+	spawned := false
+	mockRegistry := agents.NewExecutorRegistry()
+	mockRegistry.RegisterNamed("claude-code", &agents.MockExecutor{
+		SpawnFunc: func(_ context.Context, _ *agents.Agent, _ string, _ string) error {
+			spawned = true
+			return nil
+		},
+	})
+	originalLoadRegistry := loadExecutorRegistry
+	t.Cleanup(func() { loadExecutorRegistry = originalLoadRegistry })
+	loadExecutorRegistry = func(_ *schemas.UserConfig, _ string) (*agents.ExecutorRegistry, error) {
+		return mockRegistry, nil
+	}
+
+	err := runSpawn(cmd, []string{"010"}, "implementation", "", "")
+	if err == nil || !strings.Contains(err.Error(), "session session-010 already has a running agent") {
+		t.Fatalf("runSpawn() error = %v, want already running", err)
+	}
+	if !strings.Contains(err.Error(), "sow agent kill 010") {
+		t.Errorf("error = %v, want kill hint", err)
+	}
+	if spawned {
+		t.Error("agent was spawned despite the running session")
+	}
+}
+
+// TestRunSpawn_ReusedPID verifies a record whose PID now belongs to a
+// process started at another time does not block spawning.
+func TestRunSpawn_ReusedPID(t *testing.T) {
+	cmd := newSpawnCmd()
+	sowCtx, _ := setupProcessesTest(t, cmd)
+	run := writeRun(t, sowCtx, os.Getpid())
+	if _, ok := agents.ProcessStartTime(run.PID); !ok {
+		t.Skip("process start times are not available on this platform")
+	}
+	run.StartedAt = run.StartedAt.Add(-time.Hour)
+	if err := runs.Write(runs.Dir(sowCtx.RepoRoot()), run); err != nil {
+		t.Fatalf("runs.Write() error = %v", err)
+	}
+
+	spawned := false
+	mockRegistry := agents.NewExecutorRegistry()
+	mockRegistry.RegisterNamed("claude-code", &agents.MockExecutor{
+		SpawnFunc: func(_ context.Context, _ *agents.Agent, _ string, _ string) error {
+			spawned = true
+			return nil
+		},
+	})
+	originalLoadRegistry := loadExecutorRegistry
+	t.Cleanup(func() { loadExecutorRegistry = originalLoadRegistry })
+	loadExecutorRegistry = func(_ *schemas.UserConfig, _ string) (*agents.ExecutorRegistry, error) {
+		return mockRegistry, nil
+	}
+
+	if err := runSpawn(cmd, []string{"010"}, "implementation", "", ""); err != nil {
+		t.Fatalf("runSpawn() error = %v", err)
+	}
+	if !spawned {
+		t.Error("agent was not spawned")
+	}
+}
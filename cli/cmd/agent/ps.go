@@ -0,0 +1,161 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jmgilman/sow/cli/internal/cmdutil"
+	"github.com/jmgilman/sow/cli/internal/runs"
+	"github.com/spf13/cobra"
+)
+
+// Statuses of a recorded run.
+const (
+	runStatusRunning  = "running"  // The agent and the sow process waiting for it run
+	runStatusDetached = "detached" // The agent runs, but the sow process that started it is gone
+	runStatusExited   = "exited"   // The agent is gone without sow seeing it exit
+)
+
+// newPsCmd creates the ps subcommand.
+func newPsCmd() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "ps",
+		Short: "List running agents",
+		Long: `List the agent processes started by sow in this project.
+
+Every agent process started by 'sow agent spawn', 'resume', or 'run' is
+recorded in .sow/project/run/ until it exits. Each record is checked for
+liveness:
+
+  running   The agent is running
+  detached  The agent is running, but the sow process that started it is
+            gone (e.g. the orchestrator crashed); stop it with 'sow agent kill'
+  exited    The agent is gone without sow seeing it exit
+
+In-progress tasks whose agent exited this way are reported as orphaned:
+no agent is working on them anymore. Continue them with 'sow agent resume'
+or 'sow agent spawn', or clear the record with 'sow agent kill'.
+
+Examples:
+  sow agent ps
+  sow agent ps --format json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runPs(cmd, format)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: text, json")
+
+	return cmd
+}
+
+// psRun is a recorded run with its liveness, as listed by ps.
+type psRun struct {
+	runs.Run
+	Status string `json:"status"`
+}
+
+// psOrphan is an orphaned task, as listed by ps.
+type psOrphan struct {
+	Phase   string `json:"phase"`
+	Task    string `json:"task"`
+	Session string `json:"session"`
+	PID     int    `json:"pid"`
+}
+
+// runPs implements the ps command logic.
+func runPs(cmd *cobra.Command, format string) error {
+	if format != "text" && format != "json" {
+		return fmt.Errorf("unknown format: %s (valid: text, json)", format)
+	}
+
+	// Get sow context
+	ctx := cmdutil.GetContext(cmd.Context())
+
+	// Check if sow is initialized
+	if !ctx.IsInitialized() {
+		return fmt.Errorf("sow not initialized. Run 'sow init' first")
+	}
+
+	proj, err := cmdutil.LoadProject(cmd.Context(), ctx)
+	if err != nil {
+		if strings.Contains(err.Error(), "no such file") {
+			return fmt.Errorf("no active project found")
+		}
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+
+	recorded, err := runs.List(runs.Dir(ctx.RepoRoot()))
+	if err != nil {
+		return err
+	}
+	listed := make([]psRun, 0, len(recorded))
+	for _, run := range recorded {
+		listed = append(listed, psRun{Run: run, Status: runStatus(run)})
+	}
+	orphans := make([]psOrphan, 0)
+	for _, o := range runs.FindOrphans(proj, recorded) {
+		orphans = append(orphans, psOrphan{Phase: o.Phase, Task: o.Task, Session: o.Run.Session, PID: o.Run.PID})
+	}
+
+	out := cmd.OutOrStdout()
+	if format == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(map[string]interface{}{"runs": listed, "orphans": orphans}); err != nil {
+			return fmt.Errorf("failed to encode runs: %w", err)
+		}
+		return nil
+	}
+	return writePsText(out, listed, orphans)
+}
+
+// runStatus returns the liveness of a recorded run.
+func runStatus(run runs.Run) string {
+	switch {
+	case !run.Alive():
+		return runStatusExited
+	case run.Detached():
+		return runStatusDetached
+	default:
+		return runStatusRunning
+	}
+}
+
+// writePsText writes the runs as a table followed by the orphaned tasks.
+func writePsText(out io.Writer, listed []psRun, orphans []psOrphan) error {
+	if len(listed) == 0 {
+		_, _ = fmt.Fprintln(out, "No running agents")
+	} else {
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "TASK\tAGENT\tSESSION\tPID\tSTARTED\tSTATUS")
+		for _, run := range listed {
+			task := "-"
+			if run.Task != "" {
+				task = run.Phase + "/" + run.Task
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n",
+				task, run.Agent, run.Session, run.PID, run.StartedAt.Local().Format(time.DateTime), run.Status)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if len(orphans) == 0 {
+		return nil
+	}
+	_, _ = fmt.Fprintf(out, "\nOrphaned tasks (in progress, agent process gone):\n")
+	for _, o := range orphans {
+		_, _ = fmt.Fprintf(out, "  %s/%s  session %s  pid %d\n", o.Phase, o.Task, o.Session, o.PID)
+	}
+	_, err := fmt.Fprintf(out, "Continue them with 'sow agent resume <task-id> <prompt>' or 'sow agent spawn <task-id>',\nor clear the record with 'sow agent kill <task-id>'.\n")
+	return err
+}
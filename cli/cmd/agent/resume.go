@@ -1,14 +1,15 @@
 package agent
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 	"strings"
 
 	"github.com/jmgilman/sow/cli/internal/agents"
 	"github.com/jmgilman/sow/cli/internal/cmdutil"
+	"github.com/jmgilman/sow/cli/internal/runs"
 	"github.com/jmgilman/sow/libs/project/state"
-	"github.com/jmgilman/sow/libs/config"
 	"github.com/spf13/cobra"
 )
 
@@ -39,6 +40,7 @@ TASKLESS MODE (with --agent):
 Prerequisites:
   - The session must have been previously created with 'sow agent spawn'
   - The executor must support session resumption
+  - The project and task must not have spent their budget in .sow/config.yaml
 
 The resume command is typically used in this workflow:
   1. Orchestrator spawns worker -> Worker executes -> Worker pauses
@@ -63,6 +65,20 @@ Examples:
 	return cmd
 }
 
+// ResumeTask resumes the session of a task of phase with prompt, as
+// 'sow agent resume <task-id> <prompt> --phase <phase>' does, and blocks
+// until the agent exits.
+func ResumeTask(cmd *cobra.Command, phase, taskID, prompt string) error {
+	return runResume(cmd, []string{taskID, prompt}, phase, "")
+}
+
+// ResumeAgent resumes the session of an agent spawned without a task with
+// prompt, as 'sow agent resume --agent <name> <prompt>' does, and blocks
+// until the agent exits.
+func ResumeAgent(cmd *cobra.Command, agentName, prompt string) error {
+	return runResume(cmd, []string{prompt}, "", agentName)
+}
+
 // runResume implements the resume command logic.
 func runResume(cmd *cobra.Command, args []string, explicitPhase, agentFlag string) error {
 	// Get sow context
@@ -103,7 +119,7 @@ func runResume(cmd *cobra.Command, args []string, explicitPhase, agentFlag strin
 	}
 
 	// Load user config for executor settings
-	userConfig, err := config.LoadUserConfig(ctx.FS())
+	userConfig, err := loadUserConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load user config: %w", err)
 	}
@@ -118,15 +134,7 @@ func runResume(cmd *cobra.Command, args []string, explicitPhase, agentFlag strin
 	}
 
 	// Get bindings for executor lookup
-	var bindings *struct {
-		Orchestrator *string `json:"orchestrator,omitempty"`
-		Implementer  *string `json:"implementer,omitempty"`
-		Architect    *string `json:"architect,omitempty"`
-		Reviewer     *string `json:"reviewer,omitempty"`
-		Planner      *string `json:"planner,omitempty"`
-		Researcher   *string `json:"researcher,omitempty"`
-		Decomposer   *string `json:"decomposer,omitempty"`
-	}
+	var bindings map[string]string
 	if userConfig != nil && userConfig.Agents != nil {
 		bindings = userConfig.Agents.Bindings
 	}
@@ -138,15 +146,7 @@ func runResume(cmd *cobra.Command, args []string, explicitPhase, agentFlag strin
 }
 
 // runResumeWithTask handles resuming a session for a specific task.
-func runResumeWithTask(cmd *cobra.Command, proj *state.Project, taskID, prompt, explicitPhase string, executorRegistry *agents.ExecutorRegistry, bindings *struct {
-	Orchestrator *string `json:"orchestrator,omitempty"`
-	Implementer  *string `json:"implementer,omitempty"`
-	Architect    *string `json:"architect,omitempty"`
-	Reviewer     *string `json:"reviewer,omitempty"`
-	Planner      *string `json:"planner,omitempty"`
-	Researcher   *string `json:"researcher,omitempty"`
-	Decomposer   *string `json:"decomposer,omitempty"`
-}) error {
+func runResumeWithTask(cmd *cobra.Command, proj *state.Project, taskID, prompt, explicitPhase string, executorRegistry *agents.ExecutorRegistry, bindings map[string]string) error {
 	// Resolve which phase to use
 	phaseName, err := resolveTaskPhase(proj, explicitPhase)
 	if err != nil {
@@ -194,8 +194,27 @@ func runResumeWithTask(cmd *cobra.Command, proj *state.Project, taskID, prompt,
 		return fmt.Errorf("executor does not support session resumption")
 	}
 
-	// Resume session
-	if err := executor.Resume(cmd.Context(), sessionID, prompt); err != nil {
+	// Refuse to continue once the project or task budget is spent
+	if err := checkBudget(cmd, proj, phaseName, taskID); err != nil {
+		return err
+	}
+
+	// Refuse to resume a session whose agent is still running
+	if err := checkNotRunning(cmdutil.GetContext(cmd.Context()), sessionID, taskID); err != nil {
+		return err
+	}
+
+	// Resume session under the agent's permission profile
+	resumeCtx, err := withAgentPermissions(cmd.Context(), agentName, taskID)
+	if err != nil {
+		return err
+	}
+	resumeCtx = withTaskAttempts(resumeCtx, cmd.ErrOrStderr(), phaseName, taskID)
+	resumeCtx = withBudgetRetries(resumeCtx, cmd.ErrOrStderr(), phaseName, taskID)
+	resumeCtx = withRunRecord(resumeCtx, runs.Run{Session: sessionID, Phase: phaseName, Task: taskID, Agent: agentName}, warnTo(cmd.ErrOrStderr()))
+	err = executor.Resume(resumeCtx, sessionID, prompt)
+	recordSpend(context.WithoutCancel(cmd.Context()), cmd.ErrOrStderr(), phaseName)
+	if err != nil {
 		return fmt.Errorf("resume failed: %w", err)
 	}
 
@@ -203,15 +222,7 @@ func runResumeWithTask(cmd *cobra.Command, proj *state.Project, taskID, prompt,
 }
 
 // runResumeTaskless handles resuming a session for a taskless agent.
-func runResumeTaskless(cmd *cobra.Command, proj *state.Project, agentName, prompt string, executorRegistry *agents.ExecutorRegistry, bindings *struct {
-	Orchestrator *string `json:"orchestrator,omitempty"`
-	Implementer  *string `json:"implementer,omitempty"`
-	Architect    *string `json:"architect,omitempty"`
-	Reviewer     *string `json:"reviewer,omitempty"`
-	Planner      *string `json:"planner,omitempty"`
-	Researcher   *string `json:"researcher,omitempty"`
-	Decomposer   *string `json:"decomposer,omitempty"`
-}) error {
+func runResumeTaskless(cmd *cobra.Command, proj *state.Project, agentName, prompt string, executorRegistry *agents.ExecutorRegistry, bindings map[string]string) error {
 	// Look up session ID from project's agent_sessions
 	if proj.Agent_sessions == nil {
 		return fmt.Errorf("no session found for agent %s (spawn first with 'sow agent spawn --agent %s')", agentName, agentName)
@@ -233,10 +244,40 @@ func runResumeTaskless(cmd *cobra.Command, proj *state.Project, agentName, promp
 		return fmt.Errorf("executor does not support session resumption")
 	}
 
-	// Resume session
-	if err := executor.Resume(cmd.Context(), sessionID, prompt); err != nil {
+	// Refuse to continue once the project budget is spent
+	if err := checkBudget(cmd, proj, "", ""); err != nil {
+		return err
+	}
+
+	// Refuse to resume a session whose agent is still running
+	if err := checkNotRunning(cmdutil.GetContext(cmd.Context()), sessionID, agentName); err != nil {
+		return err
+	}
+
+	// Resume session under the agent's permission profile
+	resumeCtx, err := withAgentPermissions(cmd.Context(), agentName, "")
+	if err != nil {
+		return err
+	}
+	resumeCtx = withBudgetRetries(resumeCtx, cmd.ErrOrStderr(), "", "")
+	resumeCtx = withRunRecord(resumeCtx, runs.Run{Session: sessionID, Agent: agentName}, warnTo(cmd.ErrOrStderr()))
+	err = executor.Resume(resumeCtx, sessionID, prompt)
+	recordSpend(context.WithoutCancel(cmd.Context()), cmd.ErrOrStderr(), "")
+	if err != nil {
 		return fmt.Errorf("resume failed: %w", err)
 	}
 
 	return nil
 }
+
+// withAgentPermissions returns ctx carrying the permission profile of the
+// named agent, for executors to apply when resuming its session. taskID
+// is only used in the error for an unknown agent.
+func withAgentPermissions(ctx context.Context, agentName, taskID string) (context.Context, error) {
+	agentRegistry := agents.NewAgentRegistry()
+	agent, err := agentRegistry.Get(agentName)
+	if err != nil {
+		return nil, buildAgentNotFoundError(agentName, taskID, agentRegistry)
+	}
+	return agents.WithPermissions(ctx, agent.Permissions), nil
+}
@@ -0,0 +1,529 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmgilman/sow/cli/internal/agents"
+	"github.com/jmgilman/sow/cli/internal/cmdutil"
+	"github.com/jmgilman/sow/cli/internal/runs"
+	"github.com/jmgilman/sow/cli/internal/sow"
+	"github.com/jmgilman/sow/libs/exec"
+	"github.com/jmgilman/sow/libs/git"
+	"github.com/jmgilman/sow/libs/project/state"
+	"github.com/jmgilman/sow/libs/schemas/project"
+	"github.com/spf13/cobra"
+)
+
+// worktreeMetadataKey is the task metadata key recording the worktree a
+// task ran in and the outcome of merging it back.
+const worktreeMetadataKey = "worktree"
+
+// Merge outcomes recorded under the "merge" field of a task's worktree
+// metadata.
+const (
+	mergePending  = "pending"
+	mergeMerged   = "merged"
+	mergeConflict = "conflict"
+)
+
+// newRunCmd creates the run subcommand.
+func newRunCmd() *cobra.Command {
+	var phase string
+	var parallel int
+
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Run all ready tasks in parallel worktrees",
+		Long: `Run all ready tasks concurrently, each in its own git worktree.
+
+Every pending task whose dependencies are done (see 'sow task next') is
+started with its assigned agent. Each task gets a branch named
+<project-branch>-task-<id>, created from the last commit of the project
+branch and checked out in .sow/worktrees/. The agent works in that
+worktree while its sow commands update the shared project state.
+
+When an agent finishes and its task is marked needs_review or completed,
+the task's work is committed on its branch and merged into the project
+branch. The worktree and branch are then removed. A merge that conflicts is
+aborted and the task is flagged in its metadata (worktree.merge: conflict);
+its worktree is kept so the conflict can be resolved by hand.
+
+Tasks that become ready while the run is in progress are not started; run
+the command again to pick them up.
+
+When a budget is configured in .sow/config.yaml, tasks are not started and
+failed agents are not retried once the project or task has spent it (see
+'sow agent stats').
+
+Examples:
+  # Run ready tasks one at a time
+  sow agent run
+
+  # Run up to three tasks at once
+  sow agent run --parallel 3`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runParallel(cmd, phase, parallel)
+		},
+	}
+
+	cmd.Flags().StringVar(&phase, "phase", "", "Target phase (defaults to smart resolution)")
+	cmd.Flags().IntVar(&parallel, "parallel", 1, "Maximum number of tasks to run at once")
+
+	return cmd
+}
+
+// parallelJob is a ready task resolved to the agent and executor that run it.
+type parallelJob struct {
+	task     project.TaskState
+	agent    *agents.Agent
+	executor agents.Executor
+	branch   string
+	worktree string
+}
+
+// parallelResult is the outcome of running one task.
+type parallelResult struct {
+	job    *parallelJob
+	status string
+	merge  string
+	err    error
+}
+
+// parallelRun holds what the tasks of one run share.
+type parallelRun struct {
+	sowCtx *sow.Context
+	phase  string
+	out    io.Writer
+	errOut io.Writer
+
+	// mu serializes state updates, git operations on the project
+	// checkout, and output.
+	mu sync.Mutex
+}
+
+// runParallel implements the run command logic.
+func runParallel(cmd *cobra.Command, explicitPhase string, parallel int) error {
+	if parallel < 1 {
+		return fmt.Errorf("--parallel must be at least 1")
+	}
+
+	// Get sow context
+	ctx := cmdutil.GetContext(cmd.Context())
+
+	// Check if sow is initialized
+	if !ctx.IsInitialized() {
+		return fmt.Errorf("sow not initialized. Run 'sow init' first")
+	}
+
+	// Load project state
+	proj, err := cmdutil.LoadProject(cmd.Context(), ctx)
+	if err != nil {
+		if strings.Contains(err.Error(), "no such file") {
+			return fmt.Errorf("no active project found")
+		}
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+
+	// Resolve which phase to use
+	phaseName, err := resolveTaskPhase(proj, explicitPhase)
+	if err != nil {
+		return err
+	}
+	phaseState, exists := proj.Phases[phaseName]
+	if !exists {
+		return fmt.Errorf("phase not found: %s", phaseName)
+	}
+
+	ready, err := state.ReadyTasks(phaseState.Tasks)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if len(ready) == 0 {
+		_, _ = fmt.Fprintf(out, "No tasks ready in %s phase.\n", phaseName)
+		return nil
+	}
+
+	// Load user config for executor settings
+	userConfig, err := loadUserConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load user config: %w", err)
+	}
+
+	// Compute output directory for agent logs
+	outputDir := filepath.Join(ctx.RepoRoot(), ".sow", "project", "agent-outputs")
+
+	// Create executor registry from user config
+	executorRegistry, err := loadExecutorRegistry(userConfig, outputDir)
+	if err != nil {
+		return fmt.Errorf("failed to load executor registry: %w", err)
+	}
+
+	// Resolve every task's agent and executor before starting any of them
+	agentRegistry := agents.NewAgentRegistry()
+	jobs := make([]*parallelJob, 0, len(ready))
+	for _, task := range ready {
+		agent, err := agentRegistry.Get(task.Assigned_agent)
+		if err != nil {
+			return buildAgentNotFoundError(task.Assigned_agent, task.Id, agentRegistry)
+		}
+
+		var executor agents.Executor
+		if userConfig != nil && userConfig.Agents != nil {
+			executor, err = executorRegistry.GetAgentExecutor(agent.Name, userConfig.Agents.Bindings)
+		} else {
+			executor, err = executorRegistry.GetAgentExecutor(agent.Name, nil)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get executor for agent %s: %w", agent.Name, err)
+		}
+		if err := executor.ValidateAvailability(); err != nil {
+			return fmt.Errorf("executor not available: %w", err)
+		}
+
+		branch := taskBranch(proj.Branch, task.Id)
+		jobs = append(jobs, &parallelJob{
+			task:     task,
+			agent:    agent,
+			executor: executor,
+			branch:   branch,
+			worktree: git.WorktreePath(ctx.MainRepoRoot(), branch),
+		})
+	}
+
+	_, _ = fmt.Fprintf(out, "Running %d ready task(s) in %s phase, up to %d at a time\n", len(jobs), phaseName, parallel)
+
+	run := &parallelRun{sowCtx: ctx, phase: phaseName, out: out, errOut: cmd.ErrOrStderr()}
+	results := make([]parallelResult, len(jobs))
+	slots := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i, job := range jobs {
+		wg.Add(1)
+		go func(i int, job *parallelJob) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			results[i] = run.runTask(cmd.Context(), job)
+		}(i, job)
+	}
+	wg.Wait()
+
+	return run.summarize(results)
+}
+
+// taskBranch returns the name of the branch a task runs on.
+func taskBranch(projectBranch, taskID string) string {
+	return fmt.Sprintf("%s-task-%s", projectBranch, taskID)
+}
+
+// runTask runs one task in its worktree and merges the result back.
+func (r *parallelRun) runTask(ctx context.Context, job *parallelJob) parallelResult {
+	result := parallelResult{job: job, status: job.task.Status}
+
+	// Refuse to start once the budget is spent, including by the tasks
+	// already run, or while the task's session has a running agent
+	if err := r.checkStart(ctx, job); err != nil {
+		result.err = err
+		return result
+	}
+
+	r.mu.Lock()
+	err := git.EnsureWorktree(r.sowCtx.Git(), r.sowCtx.RepoRoot(), job.worktree, job.branch)
+	r.mu.Unlock()
+	if err != nil {
+		result.err = fmt.Errorf("create worktree: %w", err)
+		return result
+	}
+
+	// Mark the task started and persist its session before spawning
+	// (crash recovery)
+	var sessionID string
+	err = r.updateTask(ctx, job.task.Id, func(task *project.TaskState) {
+		now := time.Now()
+		task.Status = "in_progress"
+		if task.Started_at.IsZero() {
+			task.Started_at = now
+		}
+		task.Updated_at = now
+		if task.Session_id == "" {
+			task.Session_id = uuid.New().String()
+		}
+		sessionID = task.Session_id
+		r.setWorktreeMetadata(task, job, mergePending)
+	})
+	if err != nil {
+		result.err = err
+		return result
+	}
+	r.printf("[%s] %s: started %s in %s\n", job.task.Id, job.task.Name, job.agent.Name, r.relPath(job.worktree))
+
+	spawnCtx := agents.WithWorkspace(ctx, job.worktree, sow.RepoRootEnv+"="+r.sowCtx.RepoRoot())
+	spawnCtx = agents.WithAttemptRecorder(spawnCtx, func(attempt agents.Attempt) {
+		err := r.updateTask(context.WithoutCancel(ctx), job.task.Id, func(task *project.TaskState) {
+			task.Metadata = agents.AppendAttempt(task.Metadata, attempt)
+		})
+		if err != nil {
+			r.printf("[%s] warning: failed to record attempt %d: %v\n", job.task.Id, attempt.Number, err)
+		}
+	})
+	spawnCtx = withBudgetRetries(spawnCtx, r.stderr(), r.phase, job.task.Id)
+	spawnCtx = withRunRecord(spawnCtx, runs.Run{Session: sessionID, Phase: r.phase, Task: job.task.Id, Agent: job.agent.Name}, func(err error) {
+		r.printf("[%s] warning: %v\n", job.task.Id, err)
+	})
+	prompt := buildWorktreeTaskPrompt(job.task.Id, r.phase, r.sowCtx.RepoRoot())
+	err = job.executor.Spawn(spawnCtx, job.agent, prompt, sessionID)
+	recordSpend(context.WithoutCancel(ctx), r.stderr(), r.phase)
+	if err != nil {
+		result.err = fmt.Errorf("spawn failed: %w", err)
+		result.status = "in_progress"
+		return result
+	}
+
+	// The agent reports its outcome through the task status
+	result.status, err = r.taskStatus(ctx, job.task.Id)
+	if err != nil {
+		result.err = err
+		return result
+	}
+	if result.status != "needs_review" && result.status != "completed" {
+		return result
+	}
+
+	result.merge, err = r.mergeTask(ctx, job)
+	if err != nil {
+		result.err = err
+		return result
+	}
+	result.err = r.updateTask(ctx, job.task.Id, func(task *project.TaskState) {
+		task.Updated_at = time.Now()
+		r.setWorktreeMetadata(task, job, result.merge)
+	})
+	return result
+}
+
+// checkStart refuses to start a task once the project or task budget is
+// spent, or while an agent recorded for the task's session still runs. The
+// project is reloaded, since tasks that ran before have spent since the
+// run started.
+func (r *parallelRun) checkStart(ctx context.Context, job *parallelJob) error {
+	proj, err := cmdutil.LoadProject(ctx, r.sowCtx)
+	if err != nil {
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+	if err := checkProjectBudget(ctx, r.stderr(), proj, r.phase, job.task.Id); err != nil {
+		return err
+	}
+	if job.task.Session_id == "" {
+		return nil
+	}
+	return checkNotRunning(r.sowCtx, job.task.Session_id, job.task.Id)
+}
+
+// mergeTask commits any work left uncommitted in the task's worktree and
+// merges its branch into the project branch. On a clean merge the worktree
+// and branch are removed; on a conflict the merge is aborted and both are
+// kept. Returns the merge outcome, or an error if the merge failed for any
+// other reason.
+func (r *parallelRun) mergeTask(ctx context.Context, job *parallelJob) (string, error) {
+	taskGit := exec.NewLocalExecutor("git", exec.WithDir(job.worktree))
+	if _, _, err := taskGit.RunContext(ctx, "add", "-A"); err != nil {
+		return "", fmt.Errorf("stage task work: %w", err)
+	}
+	if _, _, err := taskGit.RunContext(ctx, "diff", "--cached", "--quiet"); err != nil {
+		message := fmt.Sprintf("Task %s: %s", job.task.Id, job.task.Name)
+		if _, stderr, err := taskGit.RunContext(ctx, "commit", "-m", message); err != nil {
+			return "", fmt.Errorf("commit task work: %w: %s", err, strings.TrimSpace(stderr))
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	projectGit := exec.NewLocalExecutor("git", exec.WithDir(r.sowCtx.RepoRoot()))
+	message := fmt.Sprintf("Merge task %s: %s", job.task.Id, job.task.Name)
+	if _, stderr, err := projectGit.RunContext(ctx, "merge", "--no-ff", "-m", message, job.branch); err != nil {
+		if !mergeConflicted(ctx, projectGit) {
+			return "", fmt.Errorf("merge task branch: %w: %s", err, strings.TrimSpace(stderr))
+		}
+		if _, stderr, err := projectGit.RunContext(ctx, "merge", "--abort"); err != nil {
+			return "", fmt.Errorf("abort conflicting merge: %w: %s", err, strings.TrimSpace(stderr))
+		}
+		return mergeConflict, nil
+	}
+
+	if _, stderr, err := projectGit.RunContext(ctx, "worktree", "remove", "--force", job.worktree); err != nil {
+		return "", fmt.Errorf("remove worktree: %w: %s", err, strings.TrimSpace(stderr))
+	}
+	if _, stderr, err := projectGit.RunContext(ctx, "branch", "-d", job.branch); err != nil {
+		return "", fmt.Errorf("delete task branch: %w: %s", err, strings.TrimSpace(stderr))
+	}
+	return mergeMerged, nil
+}
+
+// mergeConflicted reports whether a failed merge stopped on conflicts: the
+// merge is still in progress and has unmerged paths. Any other failure, such
+// as local changes the merge would overwrite, leaves no merge to resolve.
+func mergeConflicted(ctx context.Context, git exec.Executor) bool {
+	if _, _, err := git.RunContext(ctx, "rev-parse", "-q", "--verify", "MERGE_HEAD"); err != nil {
+		return false
+	}
+	unmerged, _, err := git.RunContext(ctx, "diff", "--name-only", "--diff-filter=U")
+	return err == nil && strings.TrimSpace(unmerged) != ""
+}
+
+// updateTask applies mutate to a task of the run's phase and saves the
+// project under the state lock.
+func (r *parallelRun) updateTask(ctx context.Context, taskID string, mutate func(*project.TaskState)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	lock, err := cmdutil.LockProject(ctx, r.sowCtx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+
+	proj, err := cmdutil.LoadProject(ctx, r.sowCtx)
+	if err != nil {
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+	_, err = cmdutil.UpdateProject(ctx, proj, func(p *state.Project) error {
+		phase := p.Phases[r.phase]
+		for i := range phase.Tasks {
+			if phase.Tasks[i].Id == taskID {
+				mutate(&phase.Tasks[i])
+				p.Phases[r.phase] = phase
+				return nil
+			}
+		}
+		return fmt.Errorf("task not found: %s", taskID)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update task %s: %w", taskID, err)
+	}
+	return nil
+}
+
+// taskStatus reads the current status of a task of the run's phase.
+func (r *parallelRun) taskStatus(ctx context.Context, taskID string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	proj, err := cmdutil.LoadProject(ctx, r.sowCtx)
+	if err != nil {
+		return "", fmt.Errorf("failed to load project: %w", err)
+	}
+	for _, task := range proj.Phases[r.phase].Tasks {
+		if task.Id == taskID {
+			return task.Status, nil
+		}
+	}
+	return "", fmt.Errorf("task not found: %s", taskID)
+}
+
+// setWorktreeMetadata records the task's branch, worktree, and merge
+// outcome in its metadata.
+func (r *parallelRun) setWorktreeMetadata(task *project.TaskState, job *parallelJob, merge string) {
+	if task.Metadata == nil {
+		task.Metadata = make(map[string]interface{})
+	}
+	task.Metadata[worktreeMetadataKey] = map[string]interface{}{
+		"branch": job.branch,
+		"path":   r.relPath(job.worktree),
+		"merge":  merge,
+	}
+}
+
+// relPath returns path relative to the main repository root, for output
+// and metadata.
+func (r *parallelRun) relPath(path string) string {
+	if rel, err := filepath.Rel(r.sowCtx.MainRepoRoot(), path); err == nil {
+		return rel
+	}
+	return path
+}
+
+// stderr returns a writer for warnings that does not interleave them with
+// other tasks' output.
+func (r *parallelRun) stderr() io.Writer {
+	return &lockedWriter{mu: &r.mu, w: r.errOut}
+}
+
+// lockedWriter serializes writes to w with mu.
+type lockedWriter struct {
+	mu *sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
+
+// printf writes a progress line without interleaving it with other tasks'.
+func (r *parallelRun) printf(format string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, _ = fmt.Fprintf(r.out, format, args...)
+}
+
+// summarize prints the outcome of every task and returns an error if any
+// task failed, did not finish, or could not be merged.
+func (r *parallelRun) summarize(results []parallelResult) error {
+	_, _ = fmt.Fprintln(r.out, "\nSummary:")
+
+	unmerged := 0
+	for _, result := range results {
+		job := result.job
+		line := fmt.Sprintf("  [%s] %s: ", job.task.Id, job.task.Name)
+		switch {
+		case result.err != nil:
+			line += fmt.Sprintf("failed: %v", result.err)
+		case result.merge == mergeMerged:
+			line += fmt.Sprintf("%s, merged", result.status)
+		case result.merge == mergeConflict:
+			line += fmt.Sprintf("%s, merge conflict; resolve by merging %s (worktree kept at %s)",
+				result.status, job.branch, r.relPath(job.worktree))
+		default:
+			line += fmt.Sprintf("not finished (status %s); worktree kept at %s", result.status, r.relPath(job.worktree))
+		}
+		_, _ = fmt.Fprintln(r.out, line)
+
+		if result.merge != mergeMerged {
+			unmerged++
+		}
+	}
+
+	if unmerged > 0 {
+		return fmt.Errorf("%d of %d task(s) were not merged", unmerged, len(results))
+	}
+	return nil
+}
+
+// buildWorktreeTaskPrompt creates the prompt for an agent running a task in
+// its own worktree. Task files are read from the project checkout, since
+// the worktree only has what was committed when it was created.
+func buildWorktreeTaskPrompt(taskID, phaseName, repoRoot string) string {
+	return fmt.Sprintf(`Execute task %s.
+
+Task location: %s
+
+Read state.yaml for task metadata, description.md for requirements,
+and feedback/ for any corrections from previous iterations.
+
+You are working in a git worktree dedicated to this task, on its own branch.
+Make all code changes in the current directory. Other tasks run at the same
+time in their own worktrees; your branch is merged into the project branch
+when you set the task status to needs_review.
+`, taskID, filepath.Join(repoRoot, ".sow", "project", "phases", phaseName, "tasks", taskID)+string(filepath.Separator))
+}
@@ -0,0 +1,351 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmgilman/sow/cli/internal/agents"
+	"github.com/jmgilman/sow/cli/internal/cmdutil"
+	"github.com/jmgilman/sow/cli/internal/sow"
+	"github.com/jmgilman/sow/libs/git"
+	"github.com/jmgilman/sow/libs/project/state"
+	"github.com/jmgilman/sow/libs/schemas"
+	"github.com/jmgilman/sow/libs/schemas/project"
+)
+
+// runTestTask returns a pending implementation task for run tests.
+func runTestTask(id string, dependsOn ...string) project.TaskState {
+	now := time.Now()
+	return project.TaskState{
+		Id:             id,
+		Name:           "Task " + id,
+		Phase:          "implementation",
+		Status:         "pending",
+		Iteration:      1,
+		Assigned_agent: "implementer",
+		Created_at:     now,
+		Updated_at:     now,
+		Depends_on:     append([]string{}, dependsOn...),
+		Inputs:         []project.ArtifactState{},
+		Outputs:        []project.ArtifactState{},
+	}
+}
+
+// promptTaskID extracts the task ID from a task prompt.
+var promptTaskID = regexp.MustCompile(`Execute task (\d{3})`)
+
+// mockTaskExecutor returns a mock executor whose agents write the file
+// returned by work into the task's worktree and set the task to status.
+func mockTaskExecutor(t *testing.T, sowCtx *sow.Context, status string, work func(taskID string) (string, string)) *agents.MockExecutor {
+	t.Helper()
+	return &agents.MockExecutor{
+		SpawnFunc: func(ctx context.Context, _ *agents.Agent, prompt string, _ string) error {
+			match := promptTaskID.FindStringSubmatch(prompt)
+			if match == nil {
+				t.Errorf("prompt does not name a task:\n%s", prompt)
+				return nil
+			}
+			taskID := match[1]
+
+			if work != nil {
+				name, content := work(taskID)
+				worktree := git.WorktreePath(sowCtx.MainRepoRoot(), taskBranch("feat/test", taskID))
+				if err := os.WriteFile(filepath.Join(worktree, name), []byte(content), 0644); err != nil {
+					t.Errorf("failed to write task work: %v", err)
+				}
+			}
+
+			// Like the sow commands an agent runs, update under the state lock
+			lock, err := cmdutil.LockProject(ctx, sowCtx)
+			if err != nil {
+				t.Errorf("failed to lock project: %v", err)
+				return nil
+			}
+			defer func() { _ = lock.Release() }()
+
+			proj, err := cmdutil.LoadProject(ctx, sowCtx)
+			if err != nil {
+				t.Errorf("failed to load project: %v", err)
+				return nil
+			}
+			_, err = cmdutil.UpdateProject(ctx, proj, func(p *state.Project) error {
+				phase := p.Phases["implementation"]
+				for i := range phase.Tasks {
+					if phase.Tasks[i].Id == taskID {
+						phase.Tasks[i].Status = status
+					}
+				}
+				p.Phases["implementation"] = phase
+				return nil
+			})
+			if err != nil {
+				t.Errorf("failed to set task status: %v", err)
+			}
+			return nil
+		},
+	}
+}
+
+// executeRun runs the run command with exec as the executor and returns its
+// output and error.
+func executeRun(t *testing.T, sowCtx *sow.Context, exec agents.Executor, parallel int) (string, error) {
+	t.Helper()
+
+	mockRegistry := agents.NewExecutorRegistry()
+	mockRegistry.RegisterNamed("claude-code", exec)
+
+	originalLoadRegistry := loadExecutorRegistry
+	t.Cleanup(func() { loadExecutorRegistry = originalLoadRegistry })
+	loadExecutorRegistry = func(_ *schemas.UserConfig, _ string) (*agents.ExecutorRegistry, error) {
+		return mockRegistry, nil
+	}
+
+	cmd := newRunCmd()
+	cmd.SetContext(cmdutil.WithContext(context.Background(), sowCtx))
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+
+	err := runParallel(cmd, "implementation", parallel)
+	return out.String(), err
+}
+
+// loadRunTasks loads the implementation tasks of the test project by ID.
+func loadRunTasks(t *testing.T, sowCtx *sow.Context) map[string]project.TaskState {
+	t.Helper()
+	proj, err := cmdutil.LoadProject(context.Background(), sowCtx)
+	if err != nil {
+		t.Fatalf("failed to load project: %v", err)
+	}
+	tasks := make(map[string]project.TaskState)
+	for _, task := range proj.Phases["implementation"].Tasks {
+		tasks[task.Id] = task
+	}
+	return tasks
+}
+
+// worktreeMerge returns the merge outcome recorded in a task's metadata.
+func worktreeMerge(task project.TaskState) string {
+	worktree, _ := task.Metadata[worktreeMetadataKey].(map[string]interface{})
+	merge, _ := worktree["merge"].(string)
+	return merge
+}
+
+// TestRunParallel_MergesFinishedTasks verifies ready tasks run in their own
+// worktrees and are merged back once they finish.
+func TestRunParallel_MergesFinishedTasks(t *testing.T) {
+	sowCtx, tmpDir, cleanup := setupTestProject(t, []project.TaskState{
+		runTestTask("010"),
+		runTestTask("020"),
+		runTestTask("030", "010"),
+	})
+	defer cleanup()
+
+	exec := mockTaskExecutor(t, sowCtx, "needs_review", func(taskID string) (string, string) {
+		return taskID + ".txt", "work for " + taskID + "\n"
+	})
+	out, err := executeRun(t, sowCtx, exec, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, out)
+	}
+
+	for _, id := range []string{"010", "020"} {
+		content, err := os.ReadFile(filepath.Join(tmpDir, id+".txt"))
+		if err != nil {
+			t.Errorf("expected work of task %s to be merged: %v", id, err)
+		} else if string(content) != "work for "+id+"\n" {
+			t.Errorf("unexpected content for task %s: %q", id, content)
+		}
+
+		worktree := git.WorktreePath(tmpDir, taskBranch("feat/test", id))
+		if _, err := os.Stat(worktree); !os.IsNotExist(err) {
+			t.Errorf("expected worktree of task %s to be removed", id)
+		}
+	}
+
+	tasks := loadRunTasks(t, sowCtx)
+	for _, id := range []string{"010", "020"} {
+		if tasks[id].Status != "needs_review" {
+			t.Errorf("expected task %s status needs_review, got %s", id, tasks[id].Status)
+		}
+		if tasks[id].Session_id == "" {
+			t.Errorf("expected task %s to have a session ID", id)
+		}
+		if tasks[id].Started_at.IsZero() {
+			t.Errorf("expected task %s to have a start time", id)
+		}
+		if merge := worktreeMerge(tasks[id]); merge != mergeMerged {
+			t.Errorf("expected task %s merge %q, got %q", id, mergeMerged, merge)
+		}
+	}
+
+	// Task 030 waits on 010 and is not started
+	if tasks["030"].Status != "pending" {
+		t.Errorf("expected task 030 to stay pending, got %s", tasks["030"].Status)
+	}
+	if !strings.Contains(out, "Running 2 ready task(s) in implementation phase") {
+		t.Errorf("expected run header, got:\n%s", out)
+	}
+	if !strings.Contains(out, "[010] Task 010: needs_review, merged") {
+		t.Errorf("expected summary for task 010, got:\n%s", out)
+	}
+}
+
+// TestRunParallel_FlagsMergeConflicts verifies a conflicting merge is aborted
+// and the task is flagged with its worktree kept.
+func TestRunParallel_FlagsMergeConflicts(t *testing.T) {
+	sowCtx, tmpDir, cleanup := setupTestProject(t, []project.TaskState{
+		runTestTask("010"),
+		runTestTask("020"),
+	})
+	defer cleanup()
+
+	exec := mockTaskExecutor(t, sowCtx, "needs_review", func(taskID string) (string, string) {
+		return "shared.txt", "written by " + taskID + "\n"
+	})
+	out, err := executeRun(t, sowCtx, exec, 2)
+	if err == nil {
+		t.Fatalf("expected error for unmerged task, got output:\n%s", out)
+	}
+	if !strings.Contains(err.Error(), "1 of 2 task(s) were not merged") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	tasks := loadRunTasks(t, sowCtx)
+	merged, conflicted := 0, ""
+	for id, task := range tasks {
+		switch worktreeMerge(task) {
+		case mergeMerged:
+			merged++
+		case mergeConflict:
+			conflicted = id
+		}
+	}
+	if merged != 1 || conflicted == "" {
+		t.Fatalf("expected one merged and one conflicted task, got %v", tasks)
+	}
+
+	worktree := git.WorktreePath(tmpDir, taskBranch("feat/test", conflicted))
+	if _, err := os.Stat(worktree); err != nil {
+		t.Errorf("expected worktree of conflicted task to be kept: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".git", "MERGE_HEAD")); !os.IsNotExist(err) {
+		t.Error("expected conflicting merge to be aborted")
+	}
+	if !strings.Contains(out, "merge conflict") {
+		t.Errorf("expected conflict in summary, got:\n%s", out)
+	}
+}
+
+// TestRunParallel_ReportsMergeFailures verifies a merge that fails without
+// conflicts is reported as an error rather than flagged as a conflict.
+func TestRunParallel_ReportsMergeFailures(t *testing.T) {
+	sowCtx, tmpDir, cleanup := setupTestProject(t, []project.TaskState{runTestTask("010")})
+	defer cleanup()
+
+	// An untracked file in the project checkout that the merge would overwrite
+	if err := os.WriteFile(filepath.Join(tmpDir, "shared.txt"), []byte("local\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	exec := mockTaskExecutor(t, sowCtx, "needs_review", func(taskID string) (string, string) {
+		return "shared.txt", "written by " + taskID + "\n"
+	})
+	out, err := executeRun(t, sowCtx, exec, 1)
+	if err == nil {
+		t.Fatalf("expected error for failed merge, got output:\n%s", out)
+	}
+
+	tasks := loadRunTasks(t, sowCtx)
+	if merge := worktreeMerge(tasks["010"]); merge == mergeConflict {
+		t.Errorf("expected failed merge not to be flagged as a conflict")
+	}
+	if !strings.Contains(out, "failed: merge task branch") {
+		t.Errorf("expected merge failure in summary, got:\n%s", out)
+	}
+	if strings.Contains(out, "merge conflict") {
+		t.Errorf("expected no conflict in summary, got:\n%s", out)
+	}
+}
+
+// TestRunParallel_LeavesUnfinishedTasks verifies tasks whose agent did not
+// finish are not merged.
+func TestRunParallel_LeavesUnfinishedTasks(t *testing.T) {
+	sowCtx, tmpDir, cleanup := setupTestProject(t, []project.TaskState{runTestTask("010")})
+	defer cleanup()
+
+	exec := mockTaskExecutor(t, sowCtx, "in_progress", nil)
+	out, err := executeRun(t, sowCtx, exec, 1)
+	if err == nil {
+		t.Fatalf("expected error for unfinished task, got output:\n%s", out)
+	}
+
+	tasks := loadRunTasks(t, sowCtx)
+	if tasks["010"].Status != "in_progress" {
+		t.Errorf("expected task 010 in_progress, got %s", tasks["010"].Status)
+	}
+	if merge := worktreeMerge(tasks["010"]); merge != mergePending {
+		t.Errorf("expected merge %q, got %q", mergePending, merge)
+	}
+	if _, err := os.Stat(git.WorktreePath(tmpDir, taskBranch("feat/test", "010"))); err != nil {
+		t.Errorf("expected worktree to be kept: %v", err)
+	}
+	if !strings.Contains(out, "not finished (status in_progress)") {
+		t.Errorf("expected unfinished task in summary, got:\n%s", out)
+	}
+}
+
+// TestRunParallel_NoReadyTasks verifies nothing runs without ready tasks.
+func TestRunParallel_NoReadyTasks(t *testing.T) {
+	waiting := runTestTask("020", "010")
+	blocked := runTestTask("010")
+	blocked.Status = "in_progress"
+	sowCtx, _, cleanup := setupTestProject(t, []project.TaskState{blocked, waiting})
+	defer cleanup()
+
+	exec := &agents.MockExecutor{
+		SpawnFunc: func(context.Context, *agents.Agent, string, string) error {
+			t.Error("expected no agent to be spawned")
+			return nil
+		},
+	}
+	out, err := executeRun(t, sowCtx, exec, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "No tasks ready in implementation phase.") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+// TestRunParallel_RejectsInvalidParallel verifies --parallel must be positive.
+func TestRunParallel_RejectsInvalidParallel(t *testing.T) {
+	sowCtx, _, cleanup := setupTestProject(t, []project.TaskState{runTestTask("010")})
+	defer cleanup()
+
+	_, err := executeRun(t, sowCtx, &agents.MockExecutor{}, 0)
+	if err == nil || !strings.Contains(err.Error(), "--parallel must be at least 1") {
+		t.Errorf("expected --parallel error, got: %v", err)
+	}
+}
+
+// TestBuildWorktreeTaskPrompt verifies the prompt points at the task files
+// in the project checkout.
+func TestBuildWorktreeTaskPrompt(t *testing.T) {
+	prompt := buildWorktreeTaskPrompt("010", "implementation", "/repo")
+
+	if !strings.Contains(prompt, "Execute task 010.") {
+		t.Error("expected prompt to name the task")
+	}
+	if !strings.Contains(prompt, "/repo/.sow/project/phases/implementation/tasks/010/") {
+		t.Errorf("expected absolute task location, got:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, "worktree") {
+		t.Error("expected prompt to mention the worktree")
+	}
+}
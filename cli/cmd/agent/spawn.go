@@ -1,14 +1,18 @@
 package agent
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"path/filepath"
 	"sort"
 	"strings"
 
 	"github.com/google/uuid"
+	"github.com/jmgilman/go/fs/billy"
 	"github.com/jmgilman/sow/cli/internal/agents"
 	"github.com/jmgilman/sow/cli/internal/cmdutil"
+	"github.com/jmgilman/sow/cli/internal/runs"
 	"github.com/jmgilman/sow/libs/config"
 	"github.com/jmgilman/sow/libs/project/state"
 	"github.com/jmgilman/sow/libs/schemas"
@@ -24,6 +28,13 @@ var loadExecutorRegistry = func(userConfig *schemas.UserConfig, outputDir string
 	return agents.LoadExecutorRegistry(userConfig, outputDir)
 }
 
+// loadUserConfig loads the user configuration (~/.config/sow/config.yaml).
+// It lives outside the repository, so it is read from the local filesystem
+// rather than the .sow filesystem of the context.
+func loadUserConfig() (*schemas.UserConfig, error) {
+	return config.LoadUserConfig(billy.NewLocal())
+}
+
 // newSpawnCmd creates the spawn subcommand.
 func newSpawnCmd() *cobra.Command {
 	var phase string
@@ -56,6 +67,11 @@ Session IDs are persisted before spawning to support crash recovery. For task
 mode, session IDs are stored in the task state. For taskless mode, session IDs
 are stored in the project's agent_sessions map.
 
+When a budget is configured in .sow/config.yaml, spawning is refused once the
+project or task has spent it (see 'sow agent stats'), and a warning is shown
+when it is nearly spent. A failed agent is not retried once the budget is
+spent.
+
 Examples:
   # Task mode: spawn agent for task 010
   sow agent spawn 010
@@ -81,6 +97,20 @@ Examples:
 	return cmd
 }
 
+// SpawnTask spawns the agent assigned to a task of phase, as
+// 'sow agent spawn <task-id> --phase <phase>' does, and blocks until the
+// agent exits.
+func SpawnTask(cmd *cobra.Command, phase, taskID string) error {
+	return runSpawn(cmd, []string{taskID}, phase, "", "")
+}
+
+// SpawnAgent spawns an agent without a task, as
+// 'sow agent spawn --agent <name> --prompt <prompt>' does, and blocks until
+// the agent exits.
+func SpawnAgent(cmd *cobra.Command, agentName, prompt string) error {
+	return runSpawn(cmd, nil, "", agentName, prompt)
+}
+
 // runSpawn implements the spawn command logic.
 func runSpawn(cmd *cobra.Command, args []string, explicitPhase, agentFlag, customPrompt string) error {
 	// Get sow context
@@ -110,7 +140,7 @@ func runSpawn(cmd *cobra.Command, args []string, explicitPhase, agentFlag, custo
 	}
 
 	// Load user config for executor settings
-	userConfig, err := config.LoadUserConfig(ctx.FS())
+	userConfig, err := loadUserConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load user config: %w", err)
 	}
@@ -125,15 +155,7 @@ func runSpawn(cmd *cobra.Command, args []string, explicitPhase, agentFlag, custo
 	}
 
 	// Get bindings for executor lookup
-	var bindings *struct {
-		Orchestrator *string `json:"orchestrator,omitempty"`
-		Implementer  *string `json:"implementer,omitempty"`
-		Architect    *string `json:"architect,omitempty"`
-		Reviewer     *string `json:"reviewer,omitempty"`
-		Planner      *string `json:"planner,omitempty"`
-		Researcher   *string `json:"researcher,omitempty"`
-		Decomposer   *string `json:"decomposer,omitempty"`
-	}
+	var bindings map[string]string
 	if userConfig != nil && userConfig.Agents != nil {
 		bindings = userConfig.Agents.Bindings
 	}
@@ -145,15 +167,7 @@ func runSpawn(cmd *cobra.Command, args []string, explicitPhase, agentFlag, custo
 }
 
 // runSpawnWithTask handles spawning an agent for a specific task.
-func runSpawnWithTask(cmd *cobra.Command, proj *state.Project, taskID, explicitPhase, agentOverride, customPrompt string, executorRegistry *agents.ExecutorRegistry, bindings *struct {
-	Orchestrator *string `json:"orchestrator,omitempty"`
-	Implementer  *string `json:"implementer,omitempty"`
-	Architect    *string `json:"architect,omitempty"`
-	Reviewer     *string `json:"reviewer,omitempty"`
-	Planner      *string `json:"planner,omitempty"`
-	Researcher   *string `json:"researcher,omitempty"`
-	Decomposer   *string `json:"decomposer,omitempty"`
-}) error {
+func runSpawnWithTask(cmd *cobra.Command, proj *state.Project, taskID, explicitPhase, agentOverride, customPrompt string, executorRegistry *agents.ExecutorRegistry, bindings map[string]string) error {
 	// Resolve which phase to use
 	phaseName, err := resolveTaskPhase(proj, explicitPhase)
 	if err != nil {
@@ -200,23 +214,48 @@ func runSpawnWithTask(cmd *cobra.Command, proj *state.Project, taskID, explicitP
 		return fmt.Errorf("failed to get executor for agent %s: %w", agentName, err)
 	}
 
+	// Refuse to start once the project or task budget is spent
+	if err := checkBudget(cmd, proj, phaseName, taskID); err != nil {
+		return err
+	}
+
 	// Handle session ID
 	sessionID := task.Session_id
 	if sessionID == "" {
 		// Generate new session ID
-		sessionID = uuid.New().String()
-		task.Session_id = sessionID
-
-		// Update phase with modified task
-		phaseState.Tasks[taskIndex] = *task
-		proj.Phases[phaseName] = phaseState
-
-		// CRITICAL: Save before spawning (crash recovery)
-		if err := proj.Save(cmd.Context()); err != nil {
+		// CRITICAL: Save before spawning (crash recovery). If another
+		// process assigned a session in the meantime, use theirs instead.
+		// The lock is released before spawning so the agent can update state.
+		lock, err := cmdutil.LockProject(cmd.Context(), cmdutil.GetContext(cmd.Context()))
+		if err != nil {
+			return err
+		}
+		_, err = cmdutil.UpdateProject(cmd.Context(), proj, func(p *state.Project) error {
+			phase := p.Phases[phaseName]
+			for i := range phase.Tasks {
+				if phase.Tasks[i].Id != taskID {
+					continue
+				}
+				if phase.Tasks[i].Session_id == "" {
+					phase.Tasks[i].Session_id = uuid.New().String()
+				}
+				sessionID = phase.Tasks[i].Session_id
+				p.Phases[phaseName] = phase
+				return nil
+			}
+			return fmt.Errorf("task not found: %s", taskID)
+		})
+		_ = lock.Release()
+		if err != nil {
 			return fmt.Errorf("failed to save session ID: %w", err)
 		}
 	}
 
+	// Refuse to start a second agent on the session
+	if err := checkNotRunning(cmdutil.GetContext(cmd.Context()), sessionID, taskID); err != nil {
+		return err
+	}
+
 	// Build task prompt with optional custom prompt
 	prompt := buildTaskPrompt(taskID, phaseName)
 	if customPrompt != "" {
@@ -227,23 +266,61 @@ func runSpawnWithTask(cmd *cobra.Command, proj *state.Project, taskID, explicitP
 	if err := executor.ValidateAvailability(); err != nil {
 		return fmt.Errorf("executor not available: %w", err)
 	}
-	if err := executor.Spawn(cmd.Context(), agent, prompt, sessionID); err != nil {
+	spawnCtx := withTaskAttempts(cmd.Context(), cmd.ErrOrStderr(), phaseName, taskID)
+	spawnCtx = withBudgetRetries(spawnCtx, cmd.ErrOrStderr(), phaseName, taskID)
+	spawnCtx = withRunRecord(spawnCtx, runs.Run{Session: sessionID, Phase: phaseName, Task: taskID, Agent: agentName}, warnTo(cmd.ErrOrStderr()))
+	err = executor.Spawn(spawnCtx, agent, prompt, sessionID)
+	recordSpend(context.WithoutCancel(cmd.Context()), cmd.ErrOrStderr(), phaseName)
+	if err != nil {
 		return fmt.Errorf("spawn failed: %w", err)
 	}
 
 	return nil
 }
 
+// withTaskAttempts returns a context under which each attempt of an agent
+// run is appended to the metadata of the task (see agents.WithAttemptRecorder).
+// Failing to record an attempt is reported on errOut but does not fail
+// the agent.
+func withTaskAttempts(ctx context.Context, errOut io.Writer, phaseName, taskID string) context.Context {
+	return agents.WithAttemptRecorder(ctx, func(attempt agents.Attempt) {
+		// Record even when the run was canceled
+		if err := recordTaskAttempt(context.WithoutCancel(ctx), phaseName, taskID, attempt); err != nil {
+			_, _ = fmt.Fprintf(errOut, "Warning: failed to record attempt %d of task %s: %v\n", attempt.Number, taskID, err)
+		}
+	})
+}
+
+// recordTaskAttempt appends attempt to the metadata of a task under the
+// state lock.
+func recordTaskAttempt(ctx context.Context, phaseName, taskID string, attempt agents.Attempt) error {
+	sowCtx := cmdutil.GetContext(ctx)
+	lock, err := cmdutil.LockProject(ctx, sowCtx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+
+	proj, err := cmdutil.LoadProject(ctx, sowCtx)
+	if err != nil {
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+	_, err = cmdutil.UpdateProject(ctx, proj, func(p *state.Project) error {
+		phase := p.Phases[phaseName]
+		for i := range phase.Tasks {
+			if phase.Tasks[i].Id == taskID {
+				phase.Tasks[i].Metadata = agents.AppendAttempt(phase.Tasks[i].Metadata, attempt)
+				p.Phases[phaseName] = phase
+				return nil
+			}
+		}
+		return fmt.Errorf("task not found: %s", taskID)
+	})
+	return err
+}
+
 // runSpawnTaskless handles spawning an agent without a task.
-func runSpawnTaskless(cmd *cobra.Command, proj *state.Project, agentName, customPrompt string, executorRegistry *agents.ExecutorRegistry, bindings *struct {
-	Orchestrator *string `json:"orchestrator,omitempty"`
-	Implementer  *string `json:"implementer,omitempty"`
-	Architect    *string `json:"architect,omitempty"`
-	Reviewer     *string `json:"reviewer,omitempty"`
-	Planner      *string `json:"planner,omitempty"`
-	Researcher   *string `json:"researcher,omitempty"`
-	Decomposer   *string `json:"decomposer,omitempty"`
-}) error {
+func runSpawnTaskless(cmd *cobra.Command, proj *state.Project, agentName, customPrompt string, executorRegistry *agents.ExecutorRegistry, bindings map[string]string) error {
 	// Look up agent by name
 	agentRegistry := agents.NewAgentRegistry()
 	agent, err := agentRegistry.Get(agentName)
@@ -257,23 +334,42 @@ func runSpawnTaskless(cmd *cobra.Command, proj *state.Project, agentName, custom
 		return fmt.Errorf("failed to get executor for agent %s: %w", agentName, err)
 	}
 
-	// Initialize agent_sessions map if nil
-	if proj.Agent_sessions == nil {
-		proj.Agent_sessions = make(map[string]string)
+	// Refuse to start once the project budget is spent
+	if err := checkBudget(cmd, proj, "", ""); err != nil {
+		return err
 	}
 
 	// Handle session ID: use existing or generate new
 	sessionID := proj.Agent_sessions[agentName]
 	if sessionID == "" {
-		sessionID = uuid.New().String()
-		proj.Agent_sessions[agentName] = sessionID
-
-		// CRITICAL: Save before spawning (crash recovery)
-		if err := proj.Save(cmd.Context()); err != nil {
+		// CRITICAL: Save before spawning (crash recovery). If another
+		// process assigned a session in the meantime, use theirs instead.
+		// The lock is released before spawning so the agent can update state.
+		lock, err := cmdutil.LockProject(cmd.Context(), cmdutil.GetContext(cmd.Context()))
+		if err != nil {
+			return err
+		}
+		_, err = cmdutil.UpdateProject(cmd.Context(), proj, func(p *state.Project) error {
+			if p.Agent_sessions == nil {
+				p.Agent_sessions = make(map[string]string)
+			}
+			if p.Agent_sessions[agentName] == "" {
+				p.Agent_sessions[agentName] = uuid.New().String()
+			}
+			sessionID = p.Agent_sessions[agentName]
+			return nil
+		})
+		_ = lock.Release()
+		if err != nil {
 			return fmt.Errorf("failed to save session ID: %w", err)
 		}
 	}
 
+	// Refuse to start a second agent on the session
+	if err := checkNotRunning(cmdutil.GetContext(cmd.Context()), sessionID, agentName); err != nil {
+		return err
+	}
+
 	// Build prompt: just custom prompt for taskless spawn
 	// The executor will prepend the agent template
 	prompt := customPrompt
@@ -285,7 +381,11 @@ func runSpawnTaskless(cmd *cobra.Command, proj *state.Project, agentName, custom
 	if err := executor.ValidateAvailability(); err != nil {
 		return fmt.Errorf("executor not available: %w", err)
 	}
-	if err := executor.Spawn(cmd.Context(), agent, prompt, sessionID); err != nil {
+	spawnCtx := withBudgetRetries(cmd.Context(), cmd.ErrOrStderr(), "", "")
+	spawnCtx = withRunRecord(spawnCtx, runs.Run{Session: sessionID, Agent: agentName}, warnTo(cmd.ErrOrStderr()))
+	err = executor.Spawn(spawnCtx, agent, prompt, sessionID)
+	recordSpend(context.WithoutCancel(cmd.Context()), cmd.ErrOrStderr(), "")
+	if err != nil {
 		return fmt.Errorf("spawn failed: %w", err)
 	}
 
@@ -0,0 +1,386 @@
+package agent
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jmgilman/sow/cli/internal/agents/logformat"
+	"github.com/jmgilman/sow/cli/internal/cmdutil"
+	"github.com/jmgilman/sow/libs/project/state"
+	"github.com/spf13/cobra"
+)
+
+// newStatsCmd creates the stats subcommand.
+func newStatsCmd() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Report agent cost, tokens, and activity",
+		Long: `Report agent cost, tokens, and activity from the session logs.
+
+Every raw session log in .sow/project/agent-outputs/ is read and its
+stream-json events are aggregated per session (task), agent, phase, and
+for the whole project:
+
+  cost        Summed total_cost_usd of the runs' result events
+  tokens      Input, output, and cache tokens
+  turns       Summed turns of the runs
+  tool calls  Calls by tool name, and tool results that were errors
+  runs        Agent runs that finished, and how many of them failed
+  wall time   Summed duration of the runs
+
+Sessions are attributed using the project state: task sessions to their
+task, phase, and agent, taskless sessions to their agent. Logs of sessions
+the state no longer references count towards the project total only.
+
+Examples:
+  sow agent stats
+  sow agent stats --format json
+  sow agent stats --format csv > spend.csv`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runStats(cmd, format)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: text, json, csv")
+
+	return cmd
+}
+
+// runStats implements the stats command logic.
+func runStats(cmd *cobra.Command, format string) error {
+	switch format {
+	case "text", "json", "csv":
+	default:
+		return fmt.Errorf("unknown format: %s (valid: text, json, csv)", format)
+	}
+
+	// Get sow context
+	ctx := cmdutil.GetContext(cmd.Context())
+
+	// Check if sow is initialized
+	if !ctx.IsInitialized() {
+		return fmt.Errorf("sow not initialized. Run 'sow init' first")
+	}
+
+	proj, err := cmdutil.LoadProject(cmd.Context(), ctx)
+	if err != nil {
+		if strings.Contains(err.Error(), "no such file") {
+			return fmt.Errorf("no active project found")
+		}
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+
+	outputDir := filepath.Join(ctx.RepoRoot(), ".sow", "project", "agent-outputs")
+	report, err := buildStatsReport(proj, outputDir)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("failed to encode stats: %w", err)
+		}
+		return nil
+	case "csv":
+		return writeStatsCSV(out, report)
+	default:
+		return writeStatsText(out, report)
+	}
+}
+
+// sessionRef is an agent session recorded in project state.
+type sessionRef struct {
+	ID    string
+	Phase string // Empty for taskless sessions
+	Task  string // Empty for taskless sessions
+	Agent string
+}
+
+// projectSessions returns the sessions recorded in project state: task
+// sessions ordered by phase and task, then taskless sessions by agent.
+func projectSessions(proj *state.Project) []sessionRef {
+	phaseNames := make([]string, 0, len(proj.Phases))
+	for name := range proj.Phases {
+		phaseNames = append(phaseNames, name)
+	}
+	sort.Strings(phaseNames)
+
+	var sessions []sessionRef
+	for _, phaseName := range phaseNames {
+		for _, task := range proj.Phases[phaseName].Tasks {
+			if task.Session_id != "" {
+				sessions = append(sessions, sessionRef{ID: task.Session_id, Phase: phaseName, Task: task.Id, Agent: task.Assigned_agent})
+			}
+		}
+	}
+
+	agentNames := make([]string, 0, len(proj.Agent_sessions))
+	for name := range proj.Agent_sessions {
+		agentNames = append(agentNames, name)
+	}
+	sort.Strings(agentNames)
+	for _, name := range agentNames {
+		if id := proj.Agent_sessions[name]; id != "" {
+			sessions = append(sessions, sessionRef{ID: id, Agent: name})
+		}
+	}
+	return sessions
+}
+
+// readSessionStats aggregates the raw log of a session in outputDir. A
+// session without a raw log has zero stats.
+func readSessionStats(outputDir, sessionID string) (logformat.Stats, error) {
+	file, err := os.Open(filepath.Join(outputDir, sessionID+".json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return logformat.Stats{}, nil
+	}
+	if err != nil {
+		return logformat.Stats{}, fmt.Errorf("failed to open log of session %s: %w", sessionID, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	stats, err := logformat.ReadStats(file)
+	if err != nil {
+		return logformat.Stats{}, fmt.Errorf("failed to read log of session %s: %w", sessionID, err)
+	}
+	return stats, nil
+}
+
+// statsReport is the output of 'sow agent stats'.
+type statsReport struct {
+	Project  string                     `json:"project"`
+	Total    logformat.Stats            `json:"total"`
+	Phases   map[string]logformat.Stats `json:"phases"`
+	Agents   map[string]logformat.Stats `json:"agents"`
+	Sessions []sessionStats             `json:"sessions"`
+}
+
+// sessionStats are the stats of one session. Phase, task, and agent are
+// empty for sessions the project state does not reference.
+type sessionStats struct {
+	Session string `json:"session"`
+	Phase   string `json:"phase,omitempty"`
+	Task    string `json:"task,omitempty"`
+	Agent   string `json:"agent,omitempty"`
+	logformat.Stats
+}
+
+// buildStatsReport aggregates the session logs in outputDir, attributing
+// them using the project state.
+func buildStatsReport(proj *state.Project, outputDir string) (*statsReport, error) {
+	report := &statsReport{
+		Project:  proj.Name,
+		Phases:   make(map[string]logformat.Stats),
+		Agents:   make(map[string]logformat.Stats),
+		Sessions: []sessionStats{},
+	}
+
+	seen := make(map[string]bool)
+	add := func(ref sessionRef) error {
+		seen[ref.ID] = true
+		stats, err := readSessionStats(outputDir, ref.ID)
+		if err != nil {
+			return err
+		}
+		report.Sessions = append(report.Sessions, sessionStats{Session: ref.ID, Phase: ref.Phase, Task: ref.Task, Agent: ref.Agent, Stats: stats})
+		report.Total.Add(stats)
+		if ref.Phase != "" {
+			phase := report.Phases[ref.Phase]
+			phase.Add(stats)
+			report.Phases[ref.Phase] = phase
+		}
+		if ref.Agent != "" {
+			agent := report.Agents[ref.Agent]
+			agent.Add(stats)
+			report.Agents[ref.Agent] = agent
+		}
+		return nil
+	}
+
+	for _, ref := range projectSessions(proj) {
+		if seen[ref.ID] {
+			continue
+		}
+		if err := add(ref); err != nil {
+			return nil, err
+		}
+	}
+
+	// Logs of sessions the state no longer references
+	untracked, err := filepath.Glob(filepath.Join(outputDir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list session logs: %w", err)
+	}
+	sort.Strings(untracked)
+	for _, path := range untracked {
+		id := strings.TrimSuffix(filepath.Base(path), ".json")
+		if seen[id] {
+			continue
+		}
+		if err := add(sessionRef{ID: id}); err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+// writeStatsText writes the report as a summary followed by tables.
+func writeStatsText(out io.Writer, report *statsReport) error {
+	total := report.Total
+	var b strings.Builder
+	fmt.Fprintf(&b, "Project: %s\n", report.Project)
+	fmt.Fprintf(&b, "  Sessions: %d  Runs: %d (%d failed)\n", total.Sessions, total.Runs, total.FailedRuns)
+	fmt.Fprintf(&b, "  Cost: $%.2f\n", total.CostUSD)
+	fmt.Fprintf(&b, "  Tokens: %d (input %d, output %d, cache write %d, cache read %d)\n",
+		total.Tokens(), total.InputTokens, total.OutputTokens, total.CacheCreationTokens, total.CacheReadTokens)
+	fmt.Fprintf(&b, "  Turns: %d\n", total.Turns)
+	fmt.Fprintf(&b, "  Tool calls: %d", total.TotalToolCalls())
+	if len(total.ToolCalls) > 0 {
+		fmt.Fprintf(&b, " (%s)", formatToolCounts(total.ToolCalls, " ", ", "))
+	}
+	fmt.Fprintf(&b, "\n  Tool errors: %d\n", total.ToolErrors)
+	fmt.Fprintf(&b, "  Wall time: %s\n", total.WallTime().Round(time.Second))
+	if _, err := io.WriteString(out, b.String()); err != nil {
+		return err
+	}
+
+	if len(report.Phases) > 0 {
+		if err := writeStatsTable(out, "By phase", "PHASE", sortedStats(report.Phases)); err != nil {
+			return err
+		}
+	}
+	if len(report.Agents) > 0 {
+		if err := writeStatsTable(out, "By agent", "AGENT", sortedStats(report.Agents)); err != nil {
+			return err
+		}
+	}
+	if len(report.Sessions) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(out, "\nBy task:\n")
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  TASK\tAGENT\t"+statsColumns)
+	for _, s := range report.Sessions {
+		task, agent := "-", s.Agent
+		if s.Task != "" {
+			task = s.Phase + "/" + s.Task
+		}
+		if agent == "" {
+			agent = "(untracked " + s.Session + ")"
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", task, agent, statsRow(s.Stats))
+	}
+	return w.Flush()
+}
+
+// statsColumns are the table columns written by statsRow.
+const statsColumns = "COST\tTOKENS\tTURNS\tTOOLS\tERRORS\tFAILED\tWALL TIME"
+
+// statsRow formats stats as the tab-separated statsColumns.
+func statsRow(s logformat.Stats) string {
+	return fmt.Sprintf("$%.2f\t%d\t%d\t%d\t%d\t%d\t%s",
+		s.CostUSD, s.Tokens(), s.Turns, s.TotalToolCalls(), s.ToolErrors, s.FailedRuns, s.WallTime().Round(time.Second))
+}
+
+// namedStats are the stats of a phase or agent.
+type namedStats struct {
+	name  string
+	stats logformat.Stats
+}
+
+// sortedStats returns the entries of m sorted by name.
+func sortedStats(m map[string]logformat.Stats) []namedStats {
+	entries := make([]namedStats, 0, len(m))
+	for name, stats := range m {
+		entries = append(entries, namedStats{name: name, stats: stats})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+	return entries
+}
+
+// writeStatsTable writes a titled table of stats with the given name
+// column.
+func writeStatsTable(out io.Writer, title, column string, entries []namedStats) error {
+	fmt.Fprintf(out, "\n%s:\n", title)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  "+column+"\t"+statsColumns)
+	for _, e := range entries {
+		fmt.Fprintf(w, "  %s\t%s\n", e.name, statsRow(e.stats))
+	}
+	return w.Flush()
+}
+
+// formatToolCounts formats tool call counts sorted by tool name, e.g.
+// "Bash 3, Read 2".
+func formatToolCounts(counts map[string]int, sep, join string) string {
+	tools := make([]string, 0, len(counts))
+	for tool := range counts {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+	parts := make([]string, len(tools))
+	for i, tool := range tools {
+		parts[i] = tool + sep + strconv.Itoa(counts[tool])
+	}
+	return strings.Join(parts, join)
+}
+
+// writeStatsCSV writes one row per project, phase, agent, and session.
+// The tools column lists calls by tool as "Bash=3;Read=2".
+func writeStatsCSV(out io.Writer, report *statsReport) error {
+	w := csv.NewWriter(out)
+	_ = w.Write([]string{
+		"scope", "phase", "task", "agent", "session",
+		"sessions", "runs", "failed_runs", "cost_usd",
+		"input_tokens", "output_tokens", "cache_creation_tokens", "cache_read_tokens",
+		"turns", "tool_calls", "tool_errors", "wall_time_ms", "tools",
+	})
+	row := func(scope, phase, task, agent, session string, s logformat.Stats) {
+		_ = w.Write([]string{
+			scope, phase, task, agent, session,
+			strconv.Itoa(s.Sessions), strconv.Itoa(s.Runs), strconv.Itoa(s.FailedRuns),
+			strconv.FormatFloat(s.CostUSD, 'f', 4, 64),
+			strconv.Itoa(s.InputTokens), strconv.Itoa(s.OutputTokens),
+			strconv.Itoa(s.CacheCreationTokens), strconv.Itoa(s.CacheReadTokens),
+			strconv.Itoa(s.Turns), strconv.Itoa(s.TotalToolCalls()), strconv.Itoa(s.ToolErrors),
+			strconv.FormatInt(s.WallTimeMS, 10), formatToolCounts(s.ToolCalls, "=", ";"),
+		})
+	}
+
+	row("project", "", "", "", "", report.Total)
+	for _, e := range sortedStats(report.Phases) {
+		row("phase", e.name, "", "", "", e.stats)
+	}
+	for _, e := range sortedStats(report.Agents) {
+		row("agent", "", "", e.name, "", e.stats)
+	}
+	for _, s := range report.Sessions {
+		row("session", s.Phase, s.Task, s.Agent, s.Session, s.Stats)
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package agent
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jmgilman/sow/libs/project/state"
+	"github.com/jmgilman/sow/libs/schemas/project"
+)
+
+// writeSessionLogs writes raw session logs to a temporary output
+// directory, keyed by session ID.
+func writeSessionLogs(t *testing.T, logs map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for id, content := range logs {
+		if err := os.WriteFile(filepath.Join(dir, id+".json"), []byte(content), 0644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+	return dir
+}
+
+// statsProject returns a project with two implementation tasks, a review
+// task, and a taskless planner session.
+func statsProject() *state.Project {
+	return &state.Project{ProjectState: project.ProjectState{
+		Name: "stats-project",
+		Phases: map[string]project.PhaseState{
+			"implementation": {Tasks: []project.TaskState{
+				{Id: "010", Session_id: "s-010", Assigned_agent: "implementer"},
+				{Id: "020", Session_id: "s-020", Assigned_agent: "implementer"},
+				{Id: "030", Assigned_agent: "implementer"},
+			}},
+			"review": {Tasks: []project.TaskState{
+				{Id: "010", Session_id: "s-review", Assigned_agent: "reviewer"},
+			}},
+		},
+		Agent_sessions: map[string]string{"planner": "s-planner"},
+	}}
+}
+
+// TestBuildStatsReport verifies sessions are attributed to their task,
+// phase, and agent, and untracked logs count towards the total only.
+func TestBuildStatsReport(t *testing.T) {
+	outputDir := writeSessionLogs(t, map[string]string{
+		"s-010":     `{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Edit","input":{}}]}}` + "\n" + `{"type":"result","total_cost_usd":1.5,"num_turns":4,"duration_ms":60000}` + "\n",
+		"s-020":     `{"type":"result","is_error":true,"total_cost_usd":0.5,"num_turns":1}` + "\n",
+		"s-review":  `{"type":"result","total_cost_usd":0.25,"num_turns":2}` + "\n",
+		"s-planner": `{"type":"result","total_cost_usd":0.125,"num_turns":1}` + "\n",
+		"s-old":     `{"type":"result","total_cost_usd":2,"num_turns":1}` + "\n",
+	})
+
+	report, err := buildStatsReport(statsProject(), outputDir)
+	if err != nil {
+		t.Fatalf("buildStatsReport() error = %v", err)
+	}
+
+	if report.Total.CostUSD != 4.375 || report.Total.Sessions != 5 || report.Total.FailedRuns != 1 {
+		t.Errorf("Total = %+v", report.Total)
+	}
+	if got := report.Phases["implementation"]; got.CostUSD != 2 || got.Turns != 5 {
+		t.Errorf("implementation phase = %+v", got)
+	}
+	if got := report.Agents["implementer"]; got.CostUSD != 2 || got.ToolCalls["Edit"] != 1 {
+		t.Errorf("implementer agent = %+v", got)
+	}
+	if got := report.Agents["planner"]; got.CostUSD != 0.125 {
+		t.Errorf("planner agent = %+v", got)
+	}
+	if len(report.Phases) != 2 || len(report.Agents) != 3 {
+		t.Errorf("phases = %v, agents = %v", report.Phases, report.Agents)
+	}
+
+	var ids []string
+	for _, s := range report.Sessions {
+		ids = append(ids, s.Session)
+	}
+	if got, want := strings.Join(ids, ","), "s-010,s-020,s-review,s-planner,s-old"; got != want {
+		t.Errorf("sessions = %s, want %s", got, want)
+	}
+	if last := report.Sessions[len(report.Sessions)-1]; last.Agent != "" || last.Task != "" {
+		t.Errorf("untracked session = %+v, want no agent or task", last)
+	}
+}
+
+// TestBuildStatsReport_MissingLog verifies sessions without a raw log
+// have zero stats.
+func TestBuildStatsReport_MissingLog(t *testing.T) {
+	report, err := buildStatsReport(statsProject(), t.TempDir())
+	if err != nil {
+		t.Fatalf("buildStatsReport() error = %v", err)
+	}
+	if len(report.Sessions) != 4 || report.Total.Sessions != 0 || report.Total.CostUSD != 0 {
+		t.Errorf("report = %+v", report)
+	}
+}
+
+// TestWriteStatsCSV verifies one row is written per project, phase,
+// agent, and session.
+func TestWriteStatsCSV(t *testing.T) {
+	outputDir := writeSessionLogs(t, map[string]string{
+		"s-010": `{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Read","input":{}},{"type":"tool_use","name":"Bash","input":{}}]}}` + "\n" + `{"type":"result","total_cost_usd":1.5,"num_turns":4,"usage":{"input_tokens":100,"output_tokens":20}}` + "\n",
+	})
+	report, err := buildStatsReport(statsProject(), outputDir)
+	if err != nil {
+		t.Fatalf("buildStatsReport() error = %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := writeStatsCSV(&out, report); err != nil {
+		t.Fatalf("writeStatsCSV() error = %v", err)
+	}
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not CSV: %v", err)
+	}
+
+	// Header, project, 2 phases, 3 agents, 4 sessions
+	if len(records) != 11 {
+		t.Fatalf("got %d records, want 11:\n%v", len(records), records)
+	}
+	if got := strings.Join(records[1][:9], ","); got != "project,,,,,1,1,0,1.5000" {
+		t.Errorf("project row = %s", got)
+	}
+	session := records[7]
+	if got := strings.Join(session[:5], ","); got != "session,implementation,010,implementer,s-010" {
+		t.Errorf("session row = %s", got)
+	}
+	if session[9] != "100" || session[14] != "2" || session[17] != "Bash=1;Read=1" {
+		t.Errorf("session row = %v", session)
+	}
+}
+
+// TestWriteStatsText verifies the summary and tables are written.
+func TestWriteStatsText(t *testing.T) {
+	outputDir := writeSessionLogs(t, map[string]string{
+		"s-010": `{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Read","input":{}}]}}` + "\n" + `{"type":"result","total_cost_usd":1.5,"num_turns":4,"duration_ms":90000}` + "\n",
+		"s-old": `{"type":"result","total_cost_usd":2,"num_turns":1}` + "\n",
+	})
+	report, err := buildStatsReport(statsProject(), outputDir)
+	if err != nil {
+		t.Fatalf("buildStatsReport() error = %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := writeStatsText(&out, report); err != nil {
+		t.Fatalf("writeStatsText() error = %v", err)
+	}
+	for _, want := range []string{
+		"Project: stats-project",
+		"Cost: $3.50",
+		"Tool calls: 1 (Read 1)",
+		"Wall time: 1m30s",
+		"By phase:",
+		"By agent:",
+		"implementation/010",
+		"(untracked s-old)",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q\nGot:\n%s", want, out.String())
+		}
+	}
+}
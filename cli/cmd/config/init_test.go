@@ -168,10 +168,10 @@ func TestConfigTemplate_PassesValidation(t *testing.T) {
 	if userCfg.Agents.Bindings == nil {
 		t.Fatal("expected bindings in template")
 	}
-	if userCfg.Agents.Bindings.Orchestrator == nil || *userCfg.Agents.Bindings.Orchestrator != "claude-code" {
+	if userCfg.Agents.Bindings["orchestrator"] != "claude-code" {
 		t.Error("expected orchestrator binding 'claude-code'")
 	}
-	if userCfg.Agents.Bindings.Implementer == nil || *userCfg.Agents.Bindings.Implementer != "claude-code" {
+	if userCfg.Agents.Bindings["implementer"] != "claude-code" {
 		t.Error("expected implementer binding 'claude-code'")
 	}
 }
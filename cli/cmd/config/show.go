@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/jmgilman/go/fs/billy"
@@ -73,23 +74,19 @@ func runShowWithPath(cmd *cobra.Command, path string) error {
 	return nil
 }
 
-// getEnvOverrides returns a list of SOW_AGENTS_* environment variables that are set.
+// getEnvOverrides returns the SOW_AGENTS_* environment variables that are
+// set, sorted by name. Like the config loader, it accepts any agent name,
+// so overrides of custom agents are listed too.
 func getEnvOverrides() []string {
-	envVars := []string{
-		"SOW_AGENTS_ORCHESTRATOR",
-		"SOW_AGENTS_IMPLEMENTER",
-		"SOW_AGENTS_ARCHITECT",
-		"SOW_AGENTS_REVIEWER",
-		"SOW_AGENTS_PLANNER",
-		"SOW_AGENTS_RESEARCHER",
-		"SOW_AGENTS_DECOMPOSER",
-	}
-
 	var set []string
-	for _, ev := range envVars {
-		if os.Getenv(ev) != "" {
-			set = append(set, ev)
+	for _, env := range os.Environ() {
+		key, value, _ := strings.Cut(env, "=")
+		name, ok := strings.CutPrefix(key, config.EnvAgentsPrefix)
+		if !ok || name == "" || value == "" {
+			continue
 		}
+		set = append(set, key)
 	}
+	sort.Strings(set)
 	return set
 }
@@ -249,6 +249,20 @@ func TestGetEnvOverrides_AllVars(t *testing.T) {
 	}
 }
 
+// TestGetEnvOverrides_CustomAgents verifies overrides of custom agents are
+// detected, since the config loader applies them too.
+func TestGetEnvOverrides_CustomAgents(t *testing.T) {
+	t.Setenv("SOW_AGENTS_SECURITY_REVIEWER", "cursor")
+	t.Setenv("SOW_AGENTS_IMPLEMENTER", "windsurf")
+
+	overrides := getEnvOverrides()
+
+	expected := []string{"SOW_AGENTS_IMPLEMENTER", "SOW_AGENTS_SECURITY_REVIEWER"}
+	if strings.Join(overrides, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected overrides %v, got %v", expected, overrides)
+	}
+}
+
 // TestRunShow_OutputIsValidYAML verifies the YAML portion of output is parseable.
 func TestRunShow_OutputIsValidYAML(t *testing.T) {
 	tempDir := t.TempDir()
@@ -446,29 +460,17 @@ func TestRunShow_ShowsMergedConfig(t *testing.T) {
 	}
 
 	// Verify env override took precedence
-	if config.Agents.Bindings.Implementer == nil || *config.Agents.Bindings.Implementer != "env-executor" {
-		var got string
-		if config.Agents.Bindings.Implementer != nil {
-			got = *config.Agents.Bindings.Implementer
-		}
+	if got := config.Agents.Bindings["implementer"]; got != "env-executor" {
 		t.Errorf("expected implementer 'env-executor' (env), got %q", got)
 	}
 
 	// Verify file config preserved for architect
-	if config.Agents.Bindings.Architect == nil || *config.Agents.Bindings.Architect != "cursor" {
-		var got string
-		if config.Agents.Bindings.Architect != nil {
-			got = *config.Agents.Bindings.Architect
-		}
+	if got := config.Agents.Bindings["architect"]; got != "cursor" {
 		t.Errorf("expected architect 'cursor' (file), got %q", got)
 	}
 
 	// Verify default for non-specified binding
-	if config.Agents.Bindings.Reviewer == nil || *config.Agents.Bindings.Reviewer != "claude-code" {
-		var got string
-		if config.Agents.Bindings.Reviewer != nil {
-			got = *config.Agents.Bindings.Reviewer
-		}
+	if got := config.Agents.Bindings["reviewer"]; got != "claude-code" {
 		t.Errorf("expected reviewer 'claude-code' (default), got %q", got)
 	}
 }
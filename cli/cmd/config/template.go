@@ -9,7 +9,8 @@ var configTemplate = `# Sow Agent Configuration
 # If this file doesn't exist, all agents use Claude Code by default.
 #
 # Configuration priority:
-#   1. Environment variables (SOW_AGENTS_IMPLEMENTER=cursor)
+#   1. Environment variables (SOW_AGENTS_<NAME>=<executor>, e.g.
+#      SOW_AGENTS_IMPLEMENTER=cursor or SOW_AGENTS_SECURITY_REVIEWER=cursor)
 #   2. This config file
 #   3. Built-in defaults (Claude Code)
 
@@ -19,8 +20,15 @@ agents:
     claude-code:
       type: "claude"
       settings:
-        yolo_mode: false    # Set true to skip permission prompts
+        yolo_mode: false    # Set true to skip permission prompts (not for agents with a restricted permission profile)
         # model: "sonnet"   # or "opus", "haiku"
+      # Timeout and retry policy for every run of this executor
+      # policy:
+      #   timeout: "30m"        # Wall time limit of one attempt (default: none)
+      #   max_retries: 2        # Retries after a failed attempt (default: 0)
+      #   backoff: "10s"        # Delay before the first retry, doubled after each (default: 10s)
+      #   max_backoff: "5m"     # Upper bound of the delay (default: 5m)
+      #   retry_on: ["timeout", "exit:1"]  # "timeout", "error" (any failure), or "exit:<code>" (default: any failure)
 
     # Uncomment to enable Cursor
     # cursor:
@@ -34,7 +42,31 @@ agents:
     #   settings:
     #     yolo_mode: false
 
-  # Bindings: which executor handles which agent role
+    # Uncomment to run any other agent CLI. Arguments are Go templates with
+    # {{.Prompt}}, {{.TaskPrompt}}, {{.SessionID}}, {{.Model}},
+    # {{.AgentPromptPath}}, and the agent's permission profile in
+    # {{.Permissions}} (ReadOnly, AllowedTools, DeniedTools, AllowedCommands;
+    # join lists with {{join .Permissions.DeniedTools ","}}); arguments that
+    # render empty are dropped.
+    # aider:
+    #   type: "command"
+    #   settings:
+    #     model: "gpt-4o"
+    #   command:
+    #     program: "aider"
+    #     spawn_args: ["--yes-always", "--message", "{{.Prompt}}", "{{if .Model}}--model={{.Model}}{{end}}"]
+    #     # resume_args: [...]  # Omit if the tool cannot resume sessions
+    #     prompt: "argv"        # "stdin" (default) or "argv"
+    #     output: "text"        # "text" (default) or "stream-json"
+
+    # Uncomment for a stand-in agent that replays a script of sow commands
+    # and file writes instead of calling an AI (for end-to-end tests)
+    # fake:
+    #   type: "scripted"
+    #   script: "testdata/agents.yaml"
+
+  # Bindings: which executor handles which agent. Custom agents from
+  # .sow/agents/<name>.md are bound by name; unbound agents use claude-code.
   bindings:
     orchestrator: "claude-code"
     implementer: "claude-code"
@@ -43,4 +75,10 @@ agents:
     planner: "claude-code"
     researcher: "claude-code"
     decomposer: "claude-code"
+    # security-reviewer: "cursor"
+
+  # Policies for agent roles, overriding the executor's policy field by field
+  # policies:
+  #   reviewer:
+  #     timeout: "10m"
 `
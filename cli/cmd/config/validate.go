@@ -164,6 +164,9 @@ func checkExecutorBinariesFromConfig(config *schemas.UserConfig) []string {
 
 	for name, executor := range config.Agents.Executors {
 		binary, ok := binaries[executor.Type]
+		if executor.Type == "command" && executor.Command != nil {
+			binary, ok = executor.Command.Program, executor.Command.Program != ""
+		}
 		if !ok {
 			continue
 		}
@@ -267,33 +267,8 @@ func TestCheckExecutorBinaries_MissingBinary(t *testing.T) {
 	// Create a config with an executor whose binary likely doesn't exist
 	// Using "windsurf" as it's unlikely to be installed on CI
 	config := &schemas.UserConfig{
-		Agents: &struct {
-			Executors map[string]struct {
-				Type     string `json:"type"`
-				Settings *struct {
-					Yolo_mode *bool   `json:"yolo_mode,omitempty"`
-					Model     *string `json:"model,omitempty"`
-				} `json:"settings,omitempty"`
-				Custom_args []string `json:"custom_args,omitempty"`
-			} `json:"executors,omitempty"`
-			Bindings *struct {
-				Orchestrator *string `json:"orchestrator,omitempty"`
-				Implementer  *string `json:"implementer,omitempty"`
-				Architect    *string `json:"architect,omitempty"`
-				Reviewer     *string `json:"reviewer,omitempty"`
-				Planner      *string `json:"planner,omitempty"`
-				Researcher   *string `json:"researcher,omitempty"`
-				Decomposer   *string `json:"decomposer,omitempty"`
-			} `json:"bindings,omitempty"`
-		}{
-			Executors: map[string]struct {
-				Type     string `json:"type"`
-				Settings *struct {
-					Yolo_mode *bool   `json:"yolo_mode,omitempty"`
-					Model     *string `json:"model,omitempty"`
-				} `json:"settings,omitempty"`
-				Custom_args []string `json:"custom_args,omitempty"`
-			}{
+		Agents: &schemas.AgentsConfig{
+			Executors: map[string]schemas.ExecutorConfig{
 				"windsurf-exec": {
 					Type: "windsurf",
 				},
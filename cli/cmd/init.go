@@ -18,7 +18,7 @@ func NewInitCmd() *cobra.Command {
 Creates:
   .sow/knowledge/     - Repository-specific documentation (committed)
   .sow/refs/          - External knowledge and code references (symlinks)
-  .sow/.version       - sow structure version file
+  .sow/.version       - sow structure version file (see 'sow migrate')
 
 This command must be run from a git repository root.`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
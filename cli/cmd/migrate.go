@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jmgilman/sow/cli/internal/cmdutil"
+	"github.com/jmgilman/sow/cli/internal/migrate"
+	"github.com/jmgilman/sow/libs/config"
+	"github.com/spf13/cobra"
+)
+
+// NewMigrateCmd creates the command to upgrade the .sow structure.
+//
+// Usage:
+//
+//	sow migrate            # Apply pending migrations
+//	sow migrate --dry-run  # Show what would change
+func NewMigrateCmd() *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Upgrade the .sow structure to the current version",
+		Long: `Upgrade the .sow directory to the structure version of this sow.
+
+The structure version is recorded in .sow/.version. When a new sow release
+changes the format of project state (state.yaml), the refs index
+(refs/index.json), or the knowledge index (knowledge/index.yaml), it ships
+migrations that rewrite those files. This command applies every migration
+newer than the recorded version, in order, and then updates .sow/.version.
+
+All migrations are computed before anything is written, so a failing
+migration leaves the directory untouched. Use --dry-run to list the files
+that would change without writing them.
+
+Project state stored with the SQLite backend is not rewritten.
+
+sow refuses to run against a .sow structure newer than it supports; upgrade
+sow in that case.
+
+Examples:
+  sow migrate            # Apply pending migrations
+  sow migrate --dry-run  # Show what would change`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runMigrate(cmd, dryRun)
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would change without writing files")
+
+	return cmd
+}
+
+func runMigrate(cmd *cobra.Command, dryRun bool) error {
+	ctx := cmdutil.GetContext(cmd.Context())
+
+	// Check if sow is initialized
+	if !ctx.IsInitialized() {
+		return fmt.Errorf("sow not initialized. Run 'sow init' first")
+	}
+
+	repoConfig, err := config.LoadRepoConfig(ctx.FS())
+	if err != nil {
+		return fmt.Errorf("failed to load repo config: %w", err)
+	}
+	opts := migrate.Options{DryRun: dryRun}
+	if config.GetStateBackend(repoConfig) == config.StateBackendYAML {
+		opts.StatePath = config.GetStatePath(repoConfig)
+	}
+
+	result, err := migrate.Run(ctx.FS(), opts)
+	if err != nil {
+		return fmt.Errorf("migration failed: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	if result.UpToDate() {
+		_, _ = fmt.Fprintf(out, "✓ .sow structure is up to date (version %s)\n", result.To)
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(out, "Structure version: %s → %s\n\n", result.From, result.To)
+	for _, m := range result.Applied {
+		_, _ = fmt.Fprintf(out, "%s  %s\n", m.Version, m.Description)
+		for _, change := range result.Changes {
+			if change.Version == m.Version {
+				_, _ = fmt.Fprintf(out, "    %s: %s\n", change.Path, change.Description)
+			}
+		}
+	}
+	_, _ = fmt.Fprintln(out)
+
+	if dryRun {
+		_, _ = fmt.Fprintf(out, "Dry run: %d file change(s) not written\n", len(result.Changes))
+		return nil
+	}
+	_, _ = fmt.Fprintf(out, "✓ Migrated .sow structure to version %s\n", result.To)
+	return nil
+}
@@ -174,6 +174,13 @@ func runOutputAdd(cmd *cobra.Command, phaseName, artifactType, path string, appr
 		return fmt.Errorf("sow not initialized. Run 'sow init' first")
 	}
 
+	// Lock project state for the load-modify-save cycle
+	lock, err := cmdutil.LockProject(cmd.Context(), ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+
 	// Load project
 	proj, err := cmdutil.LoadProject(cmd.Context(), ctx)
 	if err != nil {
@@ -183,41 +190,44 @@ func runOutputAdd(cmd *cobra.Command, phaseName, artifactType, path string, appr
 		return fmt.Errorf("failed to load project: %w", err)
 	}
 
-	// Determine target phase
-	if phaseName == "" {
-		phaseName = getActivePhase(proj)
+	// Update and save project state
+	proj, err = cmdutil.UpdateProject(cmd.Context(), proj, func(p *state.Project) error {
+		// Determine target phase
 		if phaseName == "" {
-			return fmt.Errorf("could not determine active phase")
+			phaseName = getActivePhase(p)
+			if phaseName == "" {
+				return fmt.Errorf("could not determine active phase")
+			}
 		}
-	}
 
-	// Get phase from map
-	phaseState, exists := proj.Phases[phaseName]
-	if !exists {
-		return fmt.Errorf("phase not found: %s", phaseName)
-	}
+		// Get phase from map
+		phaseState, exists := p.Phases[phaseName]
+		if !exists {
+			return fmt.Errorf("phase not found: %s", phaseName)
+		}
 
-	// Create artifact
-	artifact := project.ArtifactState{
-		Type:       artifactType,
-		Path:       path,
-		Approved:   approved,
-		Created_at: time.Now(),
-		Metadata:   make(map[string]interface{}),
-	}
+		// Create artifact
+		artifact := project.ArtifactState{
+			Type:       artifactType,
+			Path:       path,
+			Approved:   approved,
+			Created_at: time.Now(),
+			Metadata:   make(map[string]interface{}),
+		}
 
-	// Add to outputs
-	phaseState.Outputs = append(phaseState.Outputs, artifact)
+		// Add to outputs
+		phaseState.Outputs = append(phaseState.Outputs, artifact)
 
-	// Update phase back in map
-	proj.Phases[phaseName] = phaseState
+		// Update phase back in map
+		p.Phases[phaseName] = phaseState
 
-	// Save project state
-	if err := proj.Save(cmd.Context()); err != nil {
-		return fmt.Errorf("failed to save project: %w", err)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	fmt.Printf("Added output artifact [%d] to phase %s\n", len(phaseState.Outputs)-1, phaseName)
+	fmt.Printf("Added output artifact [%d] to phase %s\n", len(proj.Phases[phaseName].Outputs)-1, phaseName)
 	return nil
 }
 
@@ -230,6 +240,13 @@ func runOutputSet(cmd *cobra.Command, args []string, phaseName string, index int
 		return fmt.Errorf("sow not initialized. Run 'sow init' first")
 	}
 
+	// Lock project state for the load-modify-save cycle
+	lock, err := cmdutil.LockProject(cmd.Context(), ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+
 	// Load project
 	proj, err := cmdutil.LoadProject(cmd.Context(), ctx)
 	if err != nil {
@@ -239,46 +256,49 @@ func runOutputSet(cmd *cobra.Command, args []string, phaseName string, index int
 		return fmt.Errorf("failed to load project: %w", err)
 	}
 
-	// Determine target phase
-	if phaseName == "" {
-		phaseName = getActivePhase(proj)
-		if phaseName == "" {
-			return fmt.Errorf("could not determine active phase")
-		}
-	}
-
-	// Get phase from map
-	phaseState, exists := proj.Phases[phaseName]
-	if !exists {
-		return fmt.Errorf("phase not found: %s", phaseName)
-	}
-
 	// Get field path and value from args
 	fieldPath := args[0]
 	value := args[1]
 
-	// Wrap artifacts in state.Artifact for field path mutation
-	artifacts := make([]state.Artifact, len(phaseState.Outputs))
-	for i, a := range phaseState.Outputs {
-		artifacts[i] = state.Artifact{ArtifactState: a}
-	}
+	// Update and save project state
+	_, err = cmdutil.UpdateProject(cmd.Context(), proj, func(p *state.Project) error {
+		// Determine target phase
+		if phaseName == "" {
+			phaseName = getActivePhase(p)
+			if phaseName == "" {
+				return fmt.Errorf("could not determine active phase")
+			}
+		}
 
-	// Set field using artifact helper
-	if err := cmdutil.SetArtifactField(&artifacts, index, fieldPath, value); err != nil {
-		return fmt.Errorf("failed to set field: %w", err)
-	}
+		// Get phase from map
+		phaseState, exists := p.Phases[phaseName]
+		if !exists {
+			return fmt.Errorf("phase not found: %s", phaseName)
+		}
 
-	// Unwrap back to ArtifactState
-	for i, a := range artifacts {
-		phaseState.Outputs[i] = a.ArtifactState
-	}
+		// Wrap artifacts in state.Artifact for field path mutation
+		artifacts := make([]state.Artifact, len(phaseState.Outputs))
+		for i, a := range phaseState.Outputs {
+			artifacts[i] = state.Artifact{ArtifactState: a}
+		}
+
+		// Set field using artifact helper
+		if err := cmdutil.SetArtifactField(&artifacts, index, fieldPath, value); err != nil {
+			return fmt.Errorf("failed to set field: %w", err)
+		}
+
+		// Unwrap back to ArtifactState
+		for i, a := range artifacts {
+			phaseState.Outputs[i] = a.ArtifactState
+		}
 
-	// Update phase back in map
-	proj.Phases[phaseName] = phaseState
+		// Update phase back in map
+		p.Phases[phaseName] = phaseState
 
-	// Save project state
-	if err := proj.Save(cmd.Context()); err != nil {
-		return fmt.Errorf("failed to save project: %w", err)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	fmt.Printf("Set %s on output artifact [%d] in phase %s\n", fieldPath, index, phaseName)
@@ -294,6 +314,13 @@ func runOutputRemove(cmd *cobra.Command, phaseName string, index int) error {
 		return fmt.Errorf("sow not initialized. Run 'sow init' first")
 	}
 
+	// Lock project state for the load-modify-save cycle
+	lock, err := cmdutil.LockProject(cmd.Context(), ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+
 	// Load project
 	proj, err := cmdutil.LoadProject(cmd.Context(), ctx)
 	if err != nil {
@@ -303,34 +330,37 @@ func runOutputRemove(cmd *cobra.Command, phaseName string, index int) error {
 		return fmt.Errorf("failed to load project: %w", err)
 	}
 
-	// Determine target phase
-	if phaseName == "" {
-		phaseName = getActivePhase(proj)
+	// Update and save project state
+	_, err = cmdutil.UpdateProject(cmd.Context(), proj, func(p *state.Project) error {
+		// Determine target phase
 		if phaseName == "" {
-			return fmt.Errorf("could not determine active phase")
+			phaseName = getActivePhase(p)
+			if phaseName == "" {
+				return fmt.Errorf("could not determine active phase")
+			}
 		}
-	}
 
-	// Get phase from map
-	phaseState, exists := proj.Phases[phaseName]
-	if !exists {
-		return fmt.Errorf("phase not found: %s", phaseName)
-	}
+		// Get phase from map
+		phaseState, exists := p.Phases[phaseName]
+		if !exists {
+			return fmt.Errorf("phase not found: %s", phaseName)
+		}
 
-	// Validate index
-	if err := cmdutil.IndexInRange(len(phaseState.Outputs), index); err != nil {
-		return err
-	}
+		// Validate index
+		if err := cmdutil.IndexInRange(len(phaseState.Outputs), index); err != nil {
+			return err
+		}
 
-	// Remove artifact by index
-	phaseState.Outputs = append(phaseState.Outputs[:index], phaseState.Outputs[index+1:]...)
+		// Remove artifact by index
+		phaseState.Outputs = append(phaseState.Outputs[:index], phaseState.Outputs[index+1:]...)
 
-	// Update phase back in map
-	proj.Phases[phaseName] = phaseState
+		// Update phase back in map
+		p.Phases[phaseName] = phaseState
 
-	// Save project state
-	if err := proj.Save(cmd.Context()); err != nil {
-		return fmt.Errorf("failed to save project: %w", err)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	fmt.Printf("Removed output artifact [%d] from phase %s\n", index, phaseName)
@@ -74,6 +74,13 @@ func runPhaseSet(cmd *cobra.Command, args []string, phaseName string) error {
 		return fmt.Errorf("sow not initialized. Run 'sow init' first")
 	}
 
+	// Lock project state for the load-modify-save cycle
+	lock, err := cmdutil.LockProject(cmd.Context(), ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+
 	// Load project
 	project, err := cmdutil.LoadProject(cmd.Context(), ctx)
 	if err != nil {
@@ -83,39 +90,43 @@ func runPhaseSet(cmd *cobra.Command, args []string, phaseName string) error {
 		return fmt.Errorf("failed to load project: %w", err)
 	}
 
-	// Determine target phase
-	if phaseName == "" {
-		phaseName = getActivePhase(project)
+	// Get field path and value from args
+	fieldPath := args[0]
+	value := args[1]
+
+	// Update and save project state
+	_, err = cmdutil.UpdateProject(cmd.Context(), project, func(p *state.Project) error {
+		// Determine target phase
 		if phaseName == "" {
-			return fmt.Errorf("could not determine active phase")
+			phaseName = getActivePhase(p)
+			if phaseName == "" {
+				return fmt.Errorf("could not determine active phase")
+			}
 		}
-	}
-
-	// Get phase from map
-	phaseState, exists := project.Phases[phaseName]
-	if !exists {
-		return fmt.Errorf("phase not found: %s", phaseName)
-	}
 
-	// Wrap in Phase type for field path mutation
-	phase := &state.Phase{
-		PhaseState: phaseState,
-	}
+		// Get phase from map
+		phaseState, exists := p.Phases[phaseName]
+		if !exists {
+			return fmt.Errorf("phase not found: %s", phaseName)
+		}
 
-	// Set field using field path parser
-	fieldPath := args[0]
-	value := args[1]
+		// Wrap in Phase type for field path mutation
+		phase := &state.Phase{
+			PhaseState: phaseState,
+		}
 
-	if err := cmdutil.SetField(phase, fieldPath, value); err != nil {
-		return fmt.Errorf("failed to set field: %w", err)
-	}
+		// Set field using field path parser
+		if err := cmdutil.SetField(phase, fieldPath, value); err != nil {
+			return fmt.Errorf("failed to set field: %w", err)
+		}
 
-	// Update the phase back in the map
-	project.Phases[phaseName] = phase.PhaseState
+		// Update the phase back in the map
+		p.Phases[phaseName] = phase.PhaseState
 
-	// Save project state
-	if err := project.Save(cmd.Context()); err != nil {
-		return fmt.Errorf("failed to save project: %w", err)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	return nil
@@ -0,0 +1,238 @@
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jmgilman/sow/cli/internal/cmdutil"
+	sdkproject "github.com/jmgilman/sow/libs/project"
+	"github.com/jmgilman/sow/libs/project/state"
+	"github.com/spf13/cobra"
+)
+
+func newGraphCmd() *cobra.Command {
+	var typeName, format string
+
+	cmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Render a project type's state machine",
+		Long: `Render the statechart of a project type as a diagram.
+
+The graph shows every state and transition configured for the type. States
+are grouped by the phase they belong to, transitions are labeled with their
+event and guard, and branch transitions with the value that selects them.
+When the active project has the rendered type, its current state is
+highlighted.
+
+Without --type, the active project's type is rendered.
+
+Formats:
+  mermaid  Mermaid flowchart, for Markdown documents (default)
+  dot      Graphviz, render with 'dot -Tsvg'
+  json     Machine-readable graph
+
+Examples:
+  sow project graph                             # Active project's type
+  sow project graph --type standard             # Any registered type
+  sow project graph --format dot | dot -Tsvg > standard.svg
+  sow project graph --type design --format json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runGraph(cmd, typeName, format)
+		},
+	}
+
+	cmd.Flags().StringVar(&typeName, "type", "", "Project type to render (default: active project's type)")
+	cmd.Flags().StringVar(&format, "format", "mermaid", "Output format: mermaid, dot, json")
+
+	return cmd
+}
+
+func runGraph(cmd *cobra.Command, typeName, format string) error {
+	// The active project, if any, supplies the default type and the
+	// current state to highlight
+	var proj *state.Project
+	if ctx := cmdutil.GetContext(cmd.Context()); ctx != nil && ctx.IsInitialized() {
+		proj, _ = cmdutil.LoadProject(cmd.Context(), ctx)
+	}
+
+	if typeName == "" {
+		if proj == nil {
+			return fmt.Errorf("no active project; use --type to choose one of: %s",
+				strings.Join(state.RegisteredTypes(), ", "))
+		}
+		typeName = proj.Type
+	}
+
+	config, err := lookupTypeConfig(typeName)
+	if err != nil {
+		return err
+	}
+
+	var current sdkproject.State
+	if proj != nil && proj.Type == typeName {
+		current = sdkproject.State(proj.Statechart.Current_state)
+	}
+
+	graph := config.Graph()
+	out := cmd.OutOrStdout()
+	switch format {
+	case "mermaid":
+		writeMermaidGraph(out, graph, current)
+	case "dot":
+		writeDotGraph(out, graph, current)
+	case "json":
+		data, err := json.MarshalIndent(graphOutput{Graph: graph, CurrentState: current}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal graph: %w", err)
+		}
+		_, _ = fmt.Fprintln(out, string(data))
+	default:
+		return fmt.Errorf("unknown format: %s (valid: mermaid, dot, json)", format)
+	}
+
+	return nil
+}
+
+// lookupTypeConfig returns the configuration of a registered project type.
+func lookupTypeConfig(typeName string) (*sdkproject.ProjectTypeConfig, error) {
+	registered, exists := state.GetConfig(typeName)
+	if !exists {
+		return nil, fmt.Errorf("unknown project type: %s (registered: %s)",
+			typeName, strings.Join(state.RegisteredTypes(), ", "))
+	}
+	config, ok := registered.(*sdkproject.ProjectTypeConfig)
+	if !ok {
+		return nil, fmt.Errorf("project type %s does not expose its state machine", typeName)
+	}
+	return config, nil
+}
+
+// graphOutput is the JSON form of sow project graph.
+type graphOutput struct {
+	sdkproject.Graph
+	CurrentState sdkproject.State `json:"current_state,omitempty"`
+}
+
+// graphEdgeLabel returns the label lines of a transition: the event, the
+// branch value selecting it, and its guard.
+func graphEdgeLabel(t sdkproject.GraphTransition) []string {
+	lines := []string{string(t.Event)}
+	if t.Branch != "" {
+		lines = append(lines, fmt.Sprintf("when %s", t.Branch))
+	}
+	if t.Guard != "" {
+		lines = append(lines, fmt.Sprintf("[%s]", t.Guard))
+	}
+	return lines
+}
+
+// writeMermaidGraph renders the graph as a Mermaid flowchart. Flowcharts
+// are used rather than state diagrams because Mermaid does not allow
+// transitions between states of different composite states.
+func writeMermaidGraph(out io.Writer, g sdkproject.Graph, current sdkproject.State) {
+	_, _ = fmt.Fprintln(out, "flowchart TD")
+
+	grouped := map[sdkproject.State]bool{}
+	for _, phase := range g.Phases {
+		_, _ = fmt.Fprintf(out, "    subgraph phase_%s [\"%s\"]\n", mermaidID(phase.Name), mermaidText(phase.Name))
+		for _, s := range phase.States {
+			if grouped[s] {
+				continue
+			}
+			grouped[s] = true
+			_, _ = fmt.Fprintf(out, "        %s\n", mermaidNode(s))
+		}
+		_, _ = fmt.Fprintln(out, "    end")
+	}
+	for _, s := range g.States {
+		if !grouped[s] {
+			_, _ = fmt.Fprintf(out, "    %s\n", mermaidNode(s))
+		}
+	}
+
+	if g.InitialState != "" {
+		_, _ = fmt.Fprintf(out, "    start_((start)) --> state_%s\n", mermaidID(string(g.InitialState)))
+	}
+	for _, t := range g.Transitions {
+		_, _ = fmt.Fprintf(out, "    state_%s -->|\"%s\"| state_%s\n",
+			mermaidID(string(t.From)), mermaidText(strings.Join(graphEdgeLabel(t), "<br/>")), mermaidID(string(t.To)))
+	}
+
+	if current != "" {
+		_, _ = fmt.Fprintln(out, "    classDef current fill:#fde68a,stroke:#b45309,stroke-width:3px")
+		_, _ = fmt.Fprintf(out, "    class state_%s current\n", mermaidID(string(current)))
+	}
+}
+
+// mermaidNode declares a state node with its name as the label.
+func mermaidNode(s sdkproject.State) string {
+	return fmt.Sprintf("state_%s[\"%s\"]", mermaidID(string(s)), mermaidText(string(s)))
+}
+
+// mermaidID turns a name into a Mermaid identifier.
+func mermaidID(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// mermaidText escapes text for a quoted Mermaid label.
+func mermaidText(text string) string {
+	return strings.ReplaceAll(text, `"`, "#quot;")
+}
+
+// writeDotGraph renders the graph in Graphviz DOT, with one cluster per
+// phase.
+func writeDotGraph(out io.Writer, g sdkproject.Graph, current sdkproject.State) {
+	_, _ = fmt.Fprintf(out, "digraph %s {\n", dotQuote(g.Type))
+	_, _ = fmt.Fprintln(out, "    rankdir=TB;")
+	_, _ = fmt.Fprintln(out, "    node [shape=box, style=rounded];")
+
+	grouped := map[sdkproject.State]bool{}
+	for _, phase := range g.Phases {
+		_, _ = fmt.Fprintf(out, "    subgraph %s {\n", dotQuote("cluster_"+phase.Name))
+		_, _ = fmt.Fprintf(out, "        label=%s;\n", dotQuote(phase.Name))
+		for _, s := range phase.States {
+			if grouped[s] {
+				continue
+			}
+			grouped[s] = true
+			_, _ = fmt.Fprintf(out, "        %s;\n", dotQuote(string(s)))
+		}
+		_, _ = fmt.Fprintln(out, "    }")
+	}
+	for _, s := range g.States {
+		if !grouped[s] {
+			_, _ = fmt.Fprintf(out, "    %s;\n", dotQuote(string(s)))
+		}
+	}
+
+	if g.InitialState != "" {
+		_, _ = fmt.Fprintln(out, "    __start [shape=point];")
+		_, _ = fmt.Fprintf(out, "    __start -> %s;\n", dotQuote(string(g.InitialState)))
+	}
+	for _, t := range g.Transitions {
+		_, _ = fmt.Fprintf(out, "    %s -> %s [label=%s];\n",
+			dotQuote(string(t.From)), dotQuote(string(t.To)), dotQuote(strings.Join(graphEdgeLabel(t), "\n")))
+	}
+
+	if current != "" {
+		_, _ = fmt.Fprintf(out, "    %s [style=\"rounded,filled,bold\", fillcolor=\"#fde68a\"];\n", dotQuote(string(current)))
+	}
+
+	_, _ = fmt.Fprintln(out, "}")
+}
+
+// dotQuote returns text as a quoted DOT string.
+func dotQuote(text string) string {
+	text = strings.ReplaceAll(text, `\`, `\\`)
+	text = strings.ReplaceAll(text, `"`, `\"`)
+	text = strings.ReplaceAll(text, "\n", `\n`)
+	return `"` + text + `"`
+}
@@ -0,0 +1,141 @@
+package project
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jmgilman/sow/cli/internal/cmdutil"
+	"github.com/jmgilman/sow/cli/internal/sow"
+	"github.com/spf13/cobra"
+)
+
+// newGraphTestCmd returns a command wired to sowCtx that captures stdout.
+func newGraphTestCmd(sowCtx *sow.Context) (*cobra.Command, *bytes.Buffer) {
+	cmd := &cobra.Command{}
+	cmd.SetContext(cmdutil.WithContext(context.Background(), sowCtx))
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	return cmd, &buf
+}
+
+func TestRunGraph_Mermaid(t *testing.T) {
+	sowCtx, _ := setupTestContext(t)
+	cmd, buf := newGraphTestCmd(sowCtx)
+
+	if err := runGraph(cmd, "standard", "mermaid"); err != nil {
+		t.Fatalf("runGraph failed: %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		"flowchart TD",
+		`subgraph phase_implementation ["implementation"]`,
+		`state_ImplementationDraftPRCreation["ImplementationDraftPRCreation"]`,
+		"start_((start)) --> state_ImplementationPlanning",
+		`state_ImplementationPlanning -->|"planning_complete<br/>[task descriptions approved]"| state_ImplementationDraftPRCreation`,
+		`state_ReviewActive -->|"review_pass<br/>when pass<br/>[review passed]"| state_FinalizeChecks`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "classDef current") {
+		t.Errorf("expected no highlighted state without an active project:\n%s", output)
+	}
+}
+
+func TestRunGraph_Dot(t *testing.T) {
+	sowCtx, _ := setupTestContext(t)
+	cmd, buf := newGraphTestCmd(sowCtx)
+
+	if err := runGraph(cmd, "standard", "dot"); err != nil {
+		t.Fatalf("runGraph failed: %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		`digraph "standard" {`,
+		`subgraph "cluster_finalize" {`,
+		`__start -> "ImplementationPlanning";`,
+		`"ReviewActive" -> "ImplementationPlanning" [label="review_fail\nwhen fail\n[review failed]"];`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestRunGraph_JSONHighlightsActiveProject(t *testing.T) {
+	sowCtx, _ := setupTestContext(t)
+	if _, err := initializeProject(sowCtx, "feat/graph", "Graph project", nil, nil); err != nil {
+		t.Fatalf("initializeProject failed: %v", err)
+	}
+	cmd, buf := newGraphTestCmd(sowCtx)
+
+	if err := runGraph(cmd, "", "json"); err != nil {
+		t.Fatalf("runGraph failed: %v", err)
+	}
+
+	var got graphOutput
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if got.Type != "standard" {
+		t.Errorf("Type = %q, want standard", got.Type)
+	}
+	if got.CurrentState != "ImplementationPlanning" {
+		t.Errorf("CurrentState = %q, want ImplementationPlanning", got.CurrentState)
+	}
+	if len(got.Transitions) == 0 || len(got.Phases) == 0 {
+		t.Errorf("expected phases and transitions, got %+v", got)
+	}
+}
+
+func TestRunGraph_MermaidHighlightsActiveProject(t *testing.T) {
+	sowCtx, _ := setupTestContext(t)
+	if _, err := initializeProject(sowCtx, "feat/graph", "Graph project", nil, nil); err != nil {
+		t.Fatalf("initializeProject failed: %v", err)
+	}
+	cmd, buf := newGraphTestCmd(sowCtx)
+
+	if err := runGraph(cmd, "", "mermaid"); err != nil {
+		t.Fatalf("runGraph failed: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "class state_ImplementationPlanning current") {
+		t.Errorf("expected current state highlighted:\n%s", buf.String())
+	}
+}
+
+func TestRunGraph_Errors(t *testing.T) {
+	sowCtx, _ := setupTestContext(t)
+
+	tests := []struct {
+		name     string
+		typeName string
+		format   string
+		want     string
+	}{
+		{"no active project", "", "mermaid", "use --type"},
+		{"unknown type", "nope", "mermaid", "unknown project type: nope"},
+		{"unknown format", "standard", "svg", "unknown format: svg (valid: mermaid, dot, json)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, _ := newGraphTestCmd(sowCtx)
+			err := runGraph(cmd, tt.typeName, tt.format)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("runGraph() error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDotQuote(t *testing.T) {
+	if got, want := dotQuote("a \"b\"\nc\\"), `"a \"b\"\nc\\"`; got != want {
+		t.Errorf("dotQuote() = %s, want %s", got, want)
+	}
+}
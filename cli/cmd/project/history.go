@@ -0,0 +1,156 @@
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jmgilman/sow/cli/internal/cmdutil"
+	"github.com/jmgilman/sow/libs/project/state"
+	"github.com/spf13/cobra"
+)
+
+// historyFilter selects journal entries shown by sow project history.
+// Empty fields match everything.
+type historyFilter struct {
+	phase string
+	task  string
+	event string
+}
+
+// matches reports whether the entry passes the filter. The event filter
+// matches either the entry kind or the fired state machine event.
+func (f historyFilter) matches(entry state.JournalEntry) bool {
+	if f.phase != "" && entry.Phase != f.phase {
+		return false
+	}
+	if f.task != "" && entry.Task != f.task {
+		return false
+	}
+	if f.event != "" && string(entry.Kind) != f.event && entry.Event != f.event {
+		return false
+	}
+	return true
+}
+
+func newHistoryCmd() *cobra.Command {
+	var filter historyFilter
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Show the project change history",
+		Long: `Show the project journal (.sow/project/journal.jsonl).
+
+Every state machine advance, task status change, and artifact approval is
+appended to the journal with the time, the actor, and (for advances) the
+event fired and the guards evaluated. The actor is taken from $SOW_ACTOR,
+or the OS user name if it is not set.
+
+Entries can be filtered by phase, task, or event. The event filter matches
+either the entry kind (advance, task_status, artifact_approved, rollback)
+or the name of the state machine event that fired. Advances show the
+snapshot they can be rolled back to with 'sow project rollback --to'.
+
+Examples:
+  sow project history                      # Full timeline
+  sow project history --phase implementation
+  sow project history --task 010           # Changes to one task
+  sow project history --event advance      # Only state transitions
+  sow project history --format json        # Machine-readable output`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runHistory(cmd, filter, format)
+		},
+	}
+
+	cmd.Flags().StringVar(&filter.phase, "phase", "", "Only show entries for this phase")
+	cmd.Flags().StringVar(&filter.task, "task", "", "Only show entries for this task ID")
+	cmd.Flags().StringVar(&filter.event, "event", "", "Only show entries of this kind or event")
+	cmd.Flags().StringVar(&format, "format", "timeline", "Output format: timeline, json")
+
+	return cmd
+}
+
+func runHistory(cmd *cobra.Command, filter historyFilter, format string) error {
+	ctx := cmdutil.GetContext(cmd.Context())
+
+	entries, err := state.NewJournal(cmdutil.JournalPath(ctx)).Read()
+	if err != nil {
+		return fmt.Errorf("failed to read project history: %w", err)
+	}
+
+	matched := []state.JournalEntry{}
+	for _, entry := range entries {
+		if filter.matches(entry) {
+			matched = append(matched, entry)
+		}
+	}
+
+	out := cmd.OutOrStdout()
+	switch format {
+	case "timeline":
+		printHistoryTimeline(out, matched)
+	case "json":
+		data, err := json.MarshalIndent(matched, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal history: %w", err)
+		}
+		_, _ = fmt.Fprintln(out, string(data))
+	default:
+		return fmt.Errorf("unknown format: %s (valid: timeline, json)", format)
+	}
+
+	return nil
+}
+
+// printHistoryTimeline prints one line per entry, oldest first, followed by
+// the guards evaluated for advances.
+func printHistoryTimeline(out io.Writer, entries []state.JournalEntry) {
+	if len(entries) == 0 {
+		_, _ = fmt.Fprintln(out, "No project history recorded")
+		return
+	}
+
+	for _, entry := range entries {
+		_, _ = fmt.Fprintf(out, "%s  %-12s %s\n",
+			entry.Time.Local().Format(time.DateTime), entry.Actor, describeHistoryEntry(entry))
+		for _, guard := range entry.Guards {
+			mark := "✓"
+			if !guard.Passed {
+				mark = "✗"
+			}
+			_, _ = fmt.Fprintf(out, "%s  guard %s %s\n", strings.Repeat(" ", 32), mark, guard.Description)
+		}
+	}
+}
+
+// describeHistoryEntry renders the change recorded by an entry.
+func describeHistoryEntry(entry state.JournalEntry) string {
+	var desc string
+	switch entry.Kind {
+	case state.JournalAdvance:
+		desc = fmt.Sprintf("advance %s: %s → %s", entry.Event, entry.From, entry.To)
+	case state.JournalTaskStatus:
+		desc = fmt.Sprintf("task %s: %s → %s", entry.Task, entry.From, entry.To)
+	case state.JournalArtifactApproved:
+		desc = fmt.Sprintf("approved %s %s", entry.ArtifactType, entry.ArtifactPath)
+		if entry.Task != "" {
+			desc += fmt.Sprintf(" (task %s)", entry.Task)
+		}
+	case state.JournalRollback:
+		desc = fmt.Sprintf("rollback to snapshot %d: %s → %s", entry.Snapshot, entry.From, entry.To)
+	default:
+		desc = string(entry.Kind)
+	}
+
+	if entry.Phase != "" {
+		desc += fmt.Sprintf(" [%s]", entry.Phase)
+	}
+	if entry.Kind == state.JournalAdvance && entry.Snapshot > 0 {
+		desc += fmt.Sprintf(" (snapshot %d)", entry.Snapshot)
+	}
+	return desc
+}
@@ -0,0 +1,159 @@
+package project
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmgilman/sow/cli/internal/cmdutil"
+	"github.com/jmgilman/sow/cli/internal/sow"
+	"github.com/jmgilman/sow/libs/project/state"
+	"github.com/spf13/cobra"
+)
+
+// newHistoryTestCmd returns a command wired to sowCtx that captures stdout.
+func newHistoryTestCmd(sowCtx *sow.Context) (*cobra.Command, *bytes.Buffer) {
+	cmd := &cobra.Command{}
+	cmd.SetContext(cmdutil.WithContext(context.Background(), sowCtx))
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	return cmd, &buf
+}
+
+// writeHistory appends a small journal covering each entry kind.
+func writeHistory(t *testing.T, sowCtx *sow.Context) {
+	t.Helper()
+
+	now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	err := state.NewJournal(cmdutil.JournalPath(sowCtx)).Append(
+		state.JournalEntry{
+			Time:   now,
+			Kind:   state.JournalAdvance,
+			Actor:  "alice",
+			Phase:  "planning",
+			Event:  "complete_planning",
+			From:   "PlanningActive",
+			To:     "ImplementationPlanning",
+			Guards: []state.GuardResult{{Description: "task list approved", Passed: true}},
+		},
+		state.JournalEntry{
+			Time:  now.Add(time.Minute),
+			Kind:  state.JournalTaskStatus,
+			Actor: "implementer",
+			Phase: "implementation",
+			Task:  "010",
+			From:  "pending",
+			To:    "in_progress",
+		},
+		state.JournalEntry{
+			Time:         now.Add(2 * time.Minute),
+			Kind:         state.JournalArtifactApproved,
+			Actor:        "alice",
+			Phase:        "review",
+			ArtifactType: "review",
+			ArtifactPath: "phases/review/review.md",
+		},
+	)
+	if err != nil {
+		t.Fatalf("failed to write journal: %v", err)
+	}
+}
+
+// TestRunHistory_Empty verifies a missing journal is reported, not an error.
+func TestRunHistory_Empty(t *testing.T) {
+	sowCtx, _ := setupTestContext(t)
+	cmd, buf := newHistoryTestCmd(sowCtx)
+
+	if err := runHistory(cmd, historyFilter{}, "timeline"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "No project history recorded") {
+		t.Errorf("expected empty history message, got: %s", buf.String())
+	}
+}
+
+// TestRunHistory_Timeline verifies each entry kind is rendered with its guards.
+func TestRunHistory_Timeline(t *testing.T) {
+	sowCtx, _ := setupTestContext(t)
+	writeHistory(t, sowCtx)
+	cmd, buf := newHistoryTestCmd(sowCtx)
+
+	if err := runHistory(cmd, historyFilter{}, "timeline"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		"advance complete_planning: PlanningActive → ImplementationPlanning [planning]",
+		"guard ✓ task list approved",
+		"task 010: pending → in_progress [implementation]",
+		"approved review phases/review/review.md [review]",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+// TestRunHistory_Filters verifies phase, task, and event filters.
+func TestRunHistory_Filters(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter historyFilter
+		want   []state.JournalKind
+	}{
+		{"phase", historyFilter{phase: "implementation"}, []state.JournalKind{state.JournalTaskStatus}},
+		{"task", historyFilter{task: "010"}, []state.JournalKind{state.JournalTaskStatus}},
+		{"event kind", historyFilter{event: "artifact_approved"}, []state.JournalKind{state.JournalArtifactApproved}},
+		{"event name", historyFilter{event: "complete_planning"}, []state.JournalKind{state.JournalAdvance}},
+		{"no match", historyFilter{task: "999"}, []state.JournalKind{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sowCtx, _ := setupTestContext(t)
+			writeHistory(t, sowCtx)
+			cmd, buf := newHistoryTestCmd(sowCtx)
+
+			if err := runHistory(cmd, tt.filter, "json"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var entries []state.JournalEntry
+			if err := json.Unmarshal(buf.Bytes(), &entries); err != nil {
+				t.Fatalf("output is not JSON: %v\n%s", err, buf.String())
+			}
+			got := []state.JournalKind{}
+			for _, entry := range entries {
+				got = append(got, entry.Kind)
+			}
+			if strings.Join(kindStrings(got), ",") != strings.Join(kindStrings(tt.want), ",") {
+				t.Errorf("expected kinds %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestRunHistory_UnknownFormat verifies invalid formats are rejected.
+func TestRunHistory_UnknownFormat(t *testing.T) {
+	sowCtx, _ := setupTestContext(t)
+	cmd, _ := newHistoryTestCmd(sowCtx)
+
+	err := runHistory(cmd, historyFilter{}, "yaml")
+
+	if err == nil || !strings.Contains(err.Error(), "unknown format") {
+		t.Errorf("expected unknown format error, got: %v", err)
+	}
+}
+
+func kindStrings(kinds []state.JournalKind) []string {
+	out := make([]string, len(kinds))
+	for i, k := range kinds {
+		out[i] = string(k)
+	}
+	return out
+}
@@ -0,0 +1,59 @@
+package project
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func newLintTypeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lint-type <name>",
+		Short: "Check a project type's state machine for mistakes",
+		Long: `Statically analyze the state machine of a registered project type.
+
+The checks look at the whole graph of states and transitions, ignoring
+guards:
+
+  unreachable-state       State no path from the initial state reaches
+  dead-end                Phase state without outgoing transitions
+  nondeterministic-event  Event with several transitions from one state
+  missing-determiner      State with several events but no OnAdvance
+  branch-without-path     Branch value that cannot lead anywhere
+  phase-never-entered     Phase whose start state is unreachable
+
+States outside every phase, such as NoProject, are terminal and may have
+no outgoing transitions.
+
+The command exits non-zero when issues are found.
+
+Examples:
+  sow project lint-type standard
+  sow project lint-type review    # Declarative type from .sow/types`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLintType(cmd, args[0])
+		},
+	}
+
+	return cmd
+}
+
+func runLintType(cmd *cobra.Command, typeName string) error {
+	config, err := lookupTypeConfig(typeName)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	issues := config.Lint()
+	if len(issues) == 0 {
+		_, _ = fmt.Fprintf(out, "✓ Project type %s: no issues found\n", typeName)
+		return nil
+	}
+
+	for _, issue := range issues {
+		_, _ = fmt.Fprintf(out, "  - %s\n", issue)
+	}
+	return fmt.Errorf("project type %s has %d lint issue(s)", typeName, len(issues))
+}
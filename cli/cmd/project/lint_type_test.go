@@ -0,0 +1,44 @@
+package project
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/jmgilman/sow/libs/project/state"
+	"github.com/spf13/cobra"
+)
+
+// TestLintType_RegisteredTypes keeps every built-in project type free of
+// lint issues.
+func TestLintType_RegisteredTypes(t *testing.T) {
+	types := state.RegisteredTypes()
+	if len(types) == 0 {
+		t.Fatal("expected registered project types")
+	}
+
+	for _, name := range types {
+		t.Run(name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			var buf bytes.Buffer
+			cmd.SetOut(&buf)
+
+			if err := runLintType(cmd, name); err != nil {
+				t.Errorf("runLintType(%s) failed: %v\n%s", name, err, buf.String())
+			}
+			if !strings.Contains(buf.String(), "no issues found") {
+				t.Errorf("unexpected output:\n%s", buf.String())
+			}
+		})
+	}
+}
+
+func TestLintType_UnknownType(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.SetOut(&bytes.Buffer{})
+
+	err := runLintType(cmd, "nope")
+	if err == nil || !strings.Contains(err.Error(), "unknown project type: nope") {
+		t.Errorf("runLintType() error = %v, want unknown project type", err)
+	}
+}
@@ -34,6 +34,11 @@ Examples:
 	cmd.AddCommand(newSetCmd())
 	cmd.AddCommand(newDeleteCmd())
 	cmd.AddCommand(newStatusCmd())
+	cmd.AddCommand(newUnlockCmd())
+	cmd.AddCommand(newHistoryCmd())
+	cmd.AddCommand(newRollbackCmd())
+	cmd.AddCommand(newGraphCmd())
+	cmd.AddCommand(newLintTypeCmd())
 
 	return cmd
 }
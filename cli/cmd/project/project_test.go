@@ -30,7 +30,7 @@ func TestProjectCmd_Structure(t *testing.T) {
 	}
 }
 
-// TestProjectCmd_HasCorrectSubcommands verifies that set, delete, and status subcommands exist.
+// TestProjectCmd_HasCorrectSubcommands verifies that set, delete, status, unlock, history, and rollback subcommands exist.
 // (new and continue should be removed).
 func TestProjectCmd_HasCorrectSubcommands(t *testing.T) {
 	cmd := NewProjectCmd()
@@ -48,8 +48,8 @@ func TestProjectCmd_HasCorrectSubcommands(t *testing.T) {
 		return false
 	}
 
-	// Verify 'set', 'delete', and 'status' exist (check by prefix since they may have args in Use)
-	expectedCommands := []string{"set", "delete", "status"}
+	// Verify 'set', 'delete', 'status', 'unlock', 'history', 'rollback', 'graph', and 'lint-type' exist (check by prefix since they may have args in Use)
+	expectedCommands := []string{"set", "delete", "status", "unlock", "history", "rollback", "graph", "lint-type"}
 	for _, expected := range expectedCommands {
 		if !hasCommandWithPrefix(expected) {
 			t.Errorf("Expected subcommand starting with '%s' to exist, but it doesn't", expected)
@@ -64,9 +64,9 @@ func TestProjectCmd_HasCorrectSubcommands(t *testing.T) {
 		}
 	}
 
-	// Verify we have exactly 3 subcommands (set, delete, and status)
-	if len(subcommands) != 3 {
-		t.Errorf("Expected exactly 3 subcommands (set, delete, and status), got %d", len(subcommands))
+	// Verify we have exactly 8 subcommands (set, delete, status, unlock, history, rollback, graph, and lint-type)
+	if len(subcommands) != 8 {
+		t.Errorf("Expected exactly 8 subcommands (set, delete, status, unlock, history, rollback, graph, and lint-type), got %d", len(subcommands))
 		t.Log("Subcommands found:")
 		for _, subcmd := range subcommands {
 			t.Logf("  - %s", subcmd.Use)
@@ -0,0 +1,98 @@
+package project
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jmgilman/sow/cli/internal/cmdutil"
+	"github.com/jmgilman/sow/libs/project/state"
+	"github.com/spf13/cobra"
+)
+
+func newRollbackCmd() *cobra.Command {
+	var to int
+	var list bool
+
+	cmd := &cobra.Command{
+		Use:   "rollback",
+		Short: "Restore the project to an earlier state",
+		Long: `Restore the project state from a snapshot.
+
+'sow advance' snapshots the project state before every transition
+(.sow/project/snapshots/). Rolling back to snapshot N restores the state
+from before transition N, including phase, task, and artifact changes made
+since then. Snapshot N and all later snapshots are discarded, and the
+rollback is recorded in 'sow project history'.
+
+To undo just the last transition, use 'sow advance --undo'.
+
+Examples:
+  sow project rollback --list   # Show available snapshots
+  sow project rollback --to 3   # Restore the state from before transition 3`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if list {
+				return runRollbackList(cmd)
+			}
+			if to <= 0 {
+				return fmt.Errorf("--to must be a snapshot number (use --list to see snapshots)")
+			}
+			return runRollback(cmd, to)
+		},
+	}
+
+	cmd.Flags().IntVar(&to, "to", 0, "Snapshot number to restore")
+	cmd.Flags().BoolVar(&list, "list", false, "List available snapshots")
+	cmd.MarkFlagsMutuallyExclusive("to", "list")
+
+	return cmd
+}
+
+func runRollback(cmd *cobra.Command, number int) error {
+	ctx := cmdutil.GetContext(cmd.Context())
+
+	// Lock project state for the load-modify-save cycle
+	lock, err := cmdutil.LockProject(cmd.Context(), ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+
+	// Load project
+	proj, err := cmdutil.LoadProject(cmd.Context(), ctx)
+	if err != nil {
+		return fmt.Errorf("no active project")
+	}
+	currentState := proj.Statechart.Current_state
+
+	proj, _, err = cmdutil.RollbackProject(cmd.Context(), ctx, proj, number)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "✓ Rolled back to snapshot %d: %s → %s\n",
+		number, currentState, proj.Statechart.Current_state)
+	return nil
+}
+
+func runRollbackList(cmd *cobra.Command) error {
+	ctx := cmdutil.GetContext(cmd.Context())
+
+	snapshots, err := state.NewSnapshotStore(cmdutil.SnapshotDir(ctx)).List()
+	if err != nil {
+		return fmt.Errorf("failed to list snapshots: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	if len(snapshots) == 0 {
+		_, _ = fmt.Fprintln(out, "No snapshots recorded")
+		return nil
+	}
+
+	for _, snap := range snapshots {
+		_, _ = fmt.Fprintf(out, "%3d  %s  %-12s %s (before %s)\n",
+			snap.Number, snap.Time.Local().Format(time.DateTime), snap.Actor,
+			snap.State.Statechart.Current_state, snap.Event)
+	}
+	return nil
+}
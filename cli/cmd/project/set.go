@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/jmgilman/sow/cli/internal/cmdutil"
+	"github.com/jmgilman/sow/libs/project/state"
 	"github.com/spf13/cobra"
 )
 
@@ -31,6 +32,13 @@ Examples:
 func runSet(cmd *cobra.Command, args []string) error {
 	ctx := cmdutil.GetContext(cmd.Context())
 
+	// Lock project state for the load-modify-save cycle
+	lock, err := cmdutil.LockProject(cmd.Context(), ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+
 	// Load project
 	proj, err := cmdutil.LoadProject(cmd.Context(), ctx)
 	if err != nil {
@@ -40,15 +48,17 @@ func runSet(cmd *cobra.Command, args []string) error {
 	fieldPath := args[0]
 	value := args[1]
 
-	// Use field path parser from Task 010
-	// SetField works on the embedded ProjectState
-	if err := cmdutil.SetField(&proj.ProjectState, fieldPath, value); err != nil {
-		return fmt.Errorf("failed to set field: %w", err)
-	}
-
-	// Save project
-	if err := proj.Save(cmd.Context()); err != nil {
-		return fmt.Errorf("failed to save project: %w", err)
+	// Update and save project state
+	_, err = cmdutil.UpdateProject(cmd.Context(), proj, func(p *state.Project) error {
+		// Use field path parser from Task 010
+		// SetField works on the embedded ProjectState
+		if err := cmdutil.SetField(&p.ProjectState, fieldPath, value); err != nil {
+			return fmt.Errorf("failed to set field: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	fmt.Fprintf(os.Stderr, "✓ Set %s = %s\n", fieldPath, value)
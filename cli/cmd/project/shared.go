@@ -11,6 +11,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/jmgilman/sow/cli/internal/budget"
 	"github.com/jmgilman/sow/cli/internal/cmdutil"
 	"github.com/jmgilman/sow/cli/internal/prompts"
 	"github.com/jmgilman/sow/cli/internal/sow"
@@ -174,6 +175,12 @@ func generateNewProjectPrompt(proj *state.Project, initialPrompt string) (string
 		buf.WriteString("\n\n---\n\n")
 	}
 
+	// Spend and remaining budget of agents, when budgets are configured
+	if budgetPrompt := budget.PromptSection(proj); budgetPrompt != "" {
+		buf.WriteString(budgetPrompt)
+		buf.WriteString("\n---\n\n")
+	}
+
 	// Layer 3: Initial State Prompt
 	initialState := proj.Statechart.Current_state
 	statePrompt := proj.Config().GetStatePrompt(initialState, proj)
@@ -217,6 +224,12 @@ func generateContinuePrompt(proj *state.Project) (string, error) {
 		buf.WriteString("\n\n---\n\n")
 	}
 
+	// Spend and remaining budget of agents, when budgets are configured
+	if budgetPrompt := budget.PromptSection(proj); budgetPrompt != "" {
+		buf.WriteString(budgetPrompt)
+		buf.WriteString("\n---\n\n")
+	}
+
 	// Layer 3: Current State Prompt
 	currentState := proj.Statechart.Current_state
 	statePrompt := proj.Config().GetStatePrompt(currentState, proj)
@@ -387,6 +387,44 @@ func TestGenerateContinuePrompt_UsesCurrentState(t *testing.T) {
 	}
 }
 
+func TestGenerateContinuePrompt_IncludesBudget(t *testing.T) {
+	ctx, _ := setupTestContext(t)
+
+	// Create a project
+	proj, err := initializeProject(ctx, "feat/test", "Test project", nil, nil)
+	if err != nil {
+		t.Fatalf("failed to initialize project: %v", err)
+	}
+
+	// Without recorded spend there is no budget section
+	prompt, err := generateContinuePrompt(proj)
+	if err != nil {
+		t.Fatalf("generateContinuePrompt failed: %v", err)
+	}
+	if strings.Contains(prompt, "Agent Budget") {
+		t.Error("prompt should not contain a budget section without recorded spend")
+	}
+
+	// Record spend as sow agent spawn does when a budget is configured
+	phase := proj.Phases["implementation"]
+	phase.Metadata = map[string]interface{}{"budget": map[string]interface{}{
+		"project_cost_usd":      4.0,
+		"project_tokens":        250000,
+		"project_limit_usd":     10.0,
+		"project_remaining_usd": 6.0,
+		"updated_at":            "2025-03-01T12:00:00Z",
+	}}
+	proj.Phases["implementation"] = phase
+
+	prompt, err = generateContinuePrompt(proj)
+	if err != nil {
+		t.Fatalf("generateContinuePrompt failed: %v", err)
+	}
+	if !strings.Contains(prompt, "Remaining budget: $6.00 of $10.00.") {
+		t.Errorf("prompt missing remaining budget:\n%s", prompt)
+	}
+}
+
 // Test initializeProject with knowledge files
 
 func TestInitializeProject_WithEmptyKnowledgeFiles(t *testing.T) {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/jmgilman/sow/cli/internal/cmdutil"
+	"github.com/jmgilman/sow/cli/internal/runs"
 	projschema "github.com/jmgilman/sow/libs/schemas/project"
 	"github.com/spf13/cobra"
 )
@@ -18,6 +19,7 @@ Shows:
   - Project header (name, branch, type, state)
   - Phase list with status and task progress
   - Task list for the current/active phase
+  - In-progress tasks whose agent process is gone (see 'sow agent ps')
 
 Example:
   sow project status`,
@@ -70,6 +72,17 @@ func runStatus(cmd *cobra.Command, _ []string) error {
 		}
 	}
 
+	// Orphaned tasks; the run records are best effort here
+	recorded, _ := runs.List(runs.Dir(ctx.RepoRoot()))
+	if orphans := runs.FindOrphans(proj, recorded); len(orphans) > 0 {
+		_, _ = fmt.Fprintln(out)
+		_, _ = fmt.Fprintln(out, "Orphaned tasks (in progress, agent process gone):")
+		for _, o := range orphans {
+			_, _ = fmt.Fprintf(out, "  %s/%s\n", o.Phase, o.Task)
+		}
+		_, _ = fmt.Fprintln(out, "Run 'sow agent ps' for details.")
+	}
+
 	return nil
 }
 
@@ -3,10 +3,12 @@ package project
 import (
 	"bytes"
 	"context"
+	"os/exec"
 	"strings"
 	"testing"
 
 	"github.com/jmgilman/sow/cli/internal/cmdutil"
+	"github.com/jmgilman/sow/cli/internal/runs"
 	projschema "github.com/jmgilman/sow/libs/schemas/project"
 	"github.com/spf13/cobra"
 )
@@ -252,6 +254,62 @@ func TestRunStatus_WithTasks_ShowsTasksSection(t *testing.T) {
 	}
 }
 
+// TestRunStatus_ShowsOrphanedTasks verifies in-progress tasks whose
+// recorded agent process is gone are flagged.
+func TestRunStatus_ShowsOrphanedTasks(t *testing.T) {
+	sowCtx, _ := setupTestContext(t)
+
+	proj, err := initializeProject(sowCtx, "feat/test-orphans", "Test orphaned tasks", nil, nil)
+	if err != nil {
+		t.Fatalf("failed to initialize project: %v", err)
+	}
+	implPhase := proj.Phases["implementation"]
+	implPhase.Tasks = []projschema.TaskState{
+		{
+			Id:             "010",
+			Name:           "Test task",
+			Phase:          "implementation",
+			Status:         "in_progress",
+			Iteration:      1,
+			Assigned_agent: "implementer",
+			Session_id:     "session-010",
+			Created_at:     proj.Created_at,
+			Updated_at:     proj.Created_at,
+			Inputs:         []projschema.ArtifactState{},
+			Outputs:        []projschema.ArtifactState{},
+		},
+	}
+	proj.Phases["implementation"] = implPhase
+	if err := proj.Save(context.Background()); err != nil {
+		t.Fatalf("failed to save project: %v", err)
+	}
+
+	// Record a run of the task whose process has exited
+	exited := exec.Command("true")
+	if err := exited.Run(); err != nil {
+		t.Fatalf("failed to run true: %v", err)
+	}
+	run := runs.Run{PID: exited.Process.Pid, PGID: exited.Process.Pid, Session: "session-010", Phase: "implementation", Task: "010"}
+	if err := runs.Write(runs.Dir(sowCtx.RepoRoot()), run); err != nil {
+		t.Fatalf("failed to write run: %v", err)
+	}
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(cmdutil.WithContext(context.Background(), sowCtx))
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+
+	if err := runStatus(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, "Orphaned tasks") || !strings.Contains(output, "implementation/010") {
+		t.Errorf("expected orphaned task 010 in output:\n%s", output)
+	}
+}
+
 // TestRunStatus_OutputsToStdout verifies output goes to stdout not stderr.
 func TestRunStatus_OutputsToStdout(t *testing.T) {
 	sowCtx, _ := setupTestContext(t)
@@ -0,0 +1,78 @@
+package project
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jmgilman/sow/cli/internal/cmdutil"
+	"github.com/jmgilman/sow/libs/project/state"
+	"github.com/spf13/cobra"
+)
+
+func newUnlockCmd() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "unlock",
+		Short: "Remove a stuck project state lock",
+		Long: `Remove the project state lock (.sow/project/state.lock).
+
+Commands that modify project state hold this lock while they load, change,
+and save the project. The lock is released automatically when a command
+exits, so this is only needed when a lock was left behind or its holder
+is hung.
+
+The lock is removed if its holder is no longer running. If the holder is
+still running, --force is required.
+
+Examples:
+  sow project unlock          # Remove a stale lock
+  sow project unlock --force  # Remove the lock even if its holder is running`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runUnlock(cmd, force)
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Remove the lock even if its holder is still running")
+
+	return cmd
+}
+
+func runUnlock(cmd *cobra.Command, force bool) error {
+	ctx := cmdutil.GetContext(cmd.Context())
+	path := cmdutil.LockPath(ctx)
+	out := cmd.ErrOrStderr()
+
+	holder, err := state.ReadLockInfo(path)
+	if err != nil && !force {
+		return fmt.Errorf("failed to read lock: %w", err)
+	}
+	if holder == nil && err == nil {
+		_, _ = fmt.Fprintln(out, "Project state is not locked")
+		return nil
+	}
+
+	// Unless forced, only remove a lock whose holder is gone. If the holder
+	// can't be shown to be dead, probe the lock itself: it is free if the
+	// holder exited without clearing its record.
+	if !force && !holder.Stale() {
+		probe, err := state.AcquireLock(cmd.Context(), path, 0)
+		if err != nil {
+			return fmt.Errorf("project state is locked by running process PID %d on %s since %s\n\nUse --force to remove the lock anyway",
+				holder.PID, holder.Hostname, holder.AcquiredAt.Local().Format(time.RFC3339))
+		}
+		_ = probe.Release()
+	}
+
+	if err := state.RemoveLock(path); err != nil {
+		return fmt.Errorf("failed to remove lock: %w", err)
+	}
+
+	if holder != nil {
+		_, _ = fmt.Fprintf(out, "✓ Removed project state lock held by PID %d\n", holder.PID)
+	} else {
+		_, _ = fmt.Fprintln(out, "✓ Removed project state lock")
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package project
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmgilman/sow/cli/internal/cmdutil"
+	"github.com/jmgilman/sow/cli/internal/sow"
+	"github.com/jmgilman/sow/libs/project/state"
+	"github.com/spf13/cobra"
+)
+
+// newUnlockTestCmd returns a command wired to sowCtx that captures stderr.
+func newUnlockTestCmd(sowCtx *sow.Context) (*cobra.Command, *bytes.Buffer) {
+	cmd := &cobra.Command{}
+	cmd.SetContext(cmdutil.WithContext(context.Background(), sowCtx))
+	var buf bytes.Buffer
+	cmd.SetErr(&buf)
+	return cmd, &buf
+}
+
+// writeStaleLock records a lock holder whose process has already exited.
+func writeStaleLock(t *testing.T, path string) {
+	t.Helper()
+
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatalf("failed to find test binary: %v", err)
+	}
+	proc := exec.Command(exe, "-test.run=^$")
+	if err := proc.Run(); err != nil {
+		t.Fatalf("failed to run helper process: %v", err)
+	}
+
+	hostname, _ := os.Hostname()
+	data, _ := json.Marshal(state.LockInfo{
+		PID:        proc.ProcessState.Pid(),
+		Hostname:   hostname,
+		AcquiredAt: time.Now(),
+	})
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create lock directory: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("failed to write lock file: %v", err)
+	}
+}
+
+// TestRunUnlock_NotLocked verifies unlock succeeds when there is no lock.
+func TestRunUnlock_NotLocked(t *testing.T) {
+	sowCtx, _ := setupTestContext(t)
+	cmd, buf := newUnlockTestCmd(sowCtx)
+
+	if err := runUnlock(cmd, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "not locked") {
+		t.Errorf("expected 'not locked' message, got: %s", buf.String())
+	}
+}
+
+// TestRunUnlock_RemovesStaleLock verifies a lock left by a dead process is removed.
+func TestRunUnlock_RemovesStaleLock(t *testing.T) {
+	sowCtx, _ := setupTestContext(t)
+	path := cmdutil.LockPath(sowCtx)
+	writeStaleLock(t, path)
+	cmd, buf := newUnlockTestCmd(sowCtx)
+
+	if err := runUnlock(cmd, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected lock file to be removed, stat error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Removed project state lock") {
+		t.Errorf("expected removal message, got: %s", buf.String())
+	}
+}
+
+// TestRunUnlock_HeldLockRequiresForce verifies a live lock is only removed with --force.
+func TestRunUnlock_HeldLockRequiresForce(t *testing.T) {
+	sowCtx, _ := setupTestContext(t)
+	path := cmdutil.LockPath(sowCtx)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create lock directory: %v", err)
+	}
+
+	lock, err := state.AcquireLock(context.Background(), path, 0)
+	if err != nil {
+		t.Fatalf("failed to acquire lock: %v", err)
+	}
+	defer func() { _ = lock.Release() }()
+
+	cmd, _ := newUnlockTestCmd(sowCtx)
+	err = runUnlock(cmd, false)
+	if err == nil {
+		t.Fatal("expected error when lock is held by a running process")
+	}
+	if !strings.Contains(err.Error(), "--force") {
+		t.Errorf("expected error to mention --force, got: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("expected lock file to remain, stat error: %v", err)
+	}
+
+	if err := runUnlock(cmd, true); err != nil {
+		t.Fatalf("unexpected error with --force: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected lock file to be removed, stat error: %v", err)
+	}
+}
@@ -14,8 +14,11 @@ import (
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/huh/spinner"
 	"github.com/jmgilman/sow/cli/internal/cmdutil"
+	"github.com/jmgilman/sow/cli/internal/projects/custom"
 	"github.com/jmgilman/sow/cli/internal/sow"
+	"github.com/jmgilman/sow/libs/config"
 	"github.com/jmgilman/sow/libs/git"
+	"github.com/jmgilman/sow/libs/project/state"
 )
 
 // debugLog prints debug messages to stderr when SOW_DEBUG=1 is set.
@@ -121,12 +124,31 @@ func normalizeName(name string) string {
 //   - getTypePrefix("unknown") → "feat/" (fallback)
 //   - getTypePrefix("") → "feat/" (fallback)
 func getTypePrefix(projectType string) string {
-	if config, exists := projectTypes[projectType]; exists {
+	if config, exists := lookupProjectType(projectType); exists && config.Prefix != "" {
 		return config.Prefix
 	}
 	return "feat/" // Default fallback
 }
 
+// lookupProjectType returns the wizard configuration for a built-in project
+// type or for a custom type declared in .sow/types/.
+func lookupProjectType(projectType string) (ProjectTypeConfig, bool) {
+	if config, exists := projectTypes[projectType]; exists {
+		return config, true
+	}
+	if typ, exists := custom.Get(projectType); exists {
+		description := typ.Definition.Description
+		if description == "" {
+			description = projectType
+		}
+		return ProjectTypeConfig{
+			Prefix:      typ.Definition.Branch_prefix,
+			Description: description,
+		}, true
+	}
+	return ProjectTypeConfig{}, false
+}
+
 // getTypeOptions converts the projectTypes map into huh-compatible options
 // for select prompts.
 //
@@ -135,7 +157,8 @@ func getTypePrefix(projectType string) string {
 //  2. exploration
 //  3. design
 //  4. breakdown
-//  5. cancel
+//  5. custom types from .sow/types/, sorted by name
+//  6. cancel
 //
 // Each option displays the type's description as the label and uses
 // the type name as the value.
@@ -143,13 +166,17 @@ func getTypePrefix(projectType string) string {
 // Returns a slice of huh.Option[string] ready to use in a select prompt.
 func getTypeOptions() []huh.Option[string] {
 	// Return options in consistent order
-	return []huh.Option[string]{
+	options := []huh.Option[string]{
 		huh.NewOption(projectTypes["standard"].Description, "standard"),
 		huh.NewOption(projectTypes["exploration"].Description, "exploration"),
 		huh.NewOption(projectTypes["design"].Description, "design"),
 		huh.NewOption(projectTypes["breakdown"].Description, "breakdown"),
-		huh.NewOption("Cancel", "cancel"),
 	}
+	for _, typ := range custom.Types() {
+		config, _ := lookupProjectType(typ.Definition.Name)
+		options = append(options, huh.NewOption(config.Description, typ.Definition.Name))
+	}
+	return append(options, huh.NewOption("Cancel", "cancel"))
 }
 
 // previewBranchName shows what the branch name will be for a given project type and name.
@@ -441,9 +468,9 @@ func checkBranchState(ctx *sow.Context, branchName string) (*BranchState, error)
 		state.WorktreeExists = true
 
 		// If worktree exists, check for project
-		projectStatePath := filepath.Join(worktreePath, ".sow", "project", "state.yaml")
-		if _, err := os.Stat(projectStatePath); err == nil {
-			state.ProjectExists = true
+		state.ProjectExists, err = projectExists(worktreePath, branchName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check for project in worktree: %w", err)
 		}
 	}
 
@@ -539,6 +566,20 @@ type ProjectInfo struct {
 	ModTime        time.Time // State file modification time for sorting
 }
 
+// projectExists reports whether the worktree of branch at worktreePath
+// holds a project, in whichever state backend the worktree is configured
+// with.
+func projectExists(worktreePath, branch string) (bool, error) {
+	backend, err := cmdutil.NewWorktreeBackend(worktreePath, branch)
+	if errors.Is(err, sow.ErrNotInitialized) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return backend.Exists(context.Background())
+}
+
 // listProjects discovers all active projects by scanning the worktrees directory.
 // Returns projects sorted by modification time (most recent first).
 func listProjects(ctx *sow.Context) ([]ProjectInfo, error) {
@@ -557,30 +598,31 @@ func listProjects(ctx *sow.Context) ([]ProjectInfo, error) {
 
 	var projects []ProjectInfo
 
-	// Walk the worktrees directory tree to find all state.yaml files
+	// Walk the worktrees directory tree to find the worktrees holding a
+	// project. Branch names may contain slashes, so worktrees are nested.
 	err := filepath.Walk(worktreesDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
-
-		// Look for state.yaml files in .sow/project/ subdirectories
-		if info.IsDir() || info.Name() != "state.yaml" {
+		if !info.IsDir() || path == worktreesDir {
 			return nil
 		}
 
-		// Check if this is a project state file (path ends with .sow/project/state.yaml)
-		if filepath.Base(filepath.Dir(path)) != "project" ||
-			filepath.Base(filepath.Dir(filepath.Dir(path))) != ".sow" {
+		// A worktree root has a .sow directory; other git checkouts hold no project
+		if _, err := os.Stat(filepath.Join(path, ".sow")); err != nil {
+			if _, err := os.Stat(filepath.Join(path, ".git")); err == nil {
+				return filepath.SkipDir
+			}
 			return nil
 		}
 
-		// Process this project state file
-		projectInfo := processProjectState(path, worktreesDir, info)
+		// Process this worktree
+		projectInfo := processProjectState(path, worktreesDir)
 		if projectInfo != nil {
 			projects = append(projects, *projectInfo)
 		}
 
-		return nil
+		return filepath.SkipDir
 	})
 
 	if err != nil {
@@ -595,15 +637,26 @@ func listProjects(ctx *sow.Context) ([]ProjectInfo, error) {
 	return projects, nil
 }
 
-// processProjectState processes a project state.yaml file and returns project info.
-// Returns nil if the project cannot be loaded (with warning to stderr).
-func processProjectState(path, worktreesDir string, info os.FileInfo) *ProjectInfo {
+// processProjectState processes the project of the worktree at worktreePath
+// and returns project info.
+// Returns nil if the worktree holds no project, or if the project cannot be
+// loaded (with warning to stderr).
+func processProjectState(worktreePath, worktreesDir string) *ProjectInfo {
 	// Extract branch name from path
-	// Path structure: worktreesDir/branchName/.sow/project/state.yaml
-	worktreePath := filepath.Dir(filepath.Dir(filepath.Dir(path)))
+	// Path structure: worktreesDir/branchName
 	branchName, err := filepath.Rel(worktreesDir, worktreePath)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: failed to extract branch name from %s: %v\n", path, err)
+		fmt.Fprintf(os.Stderr, "Warning: failed to extract branch name from %s: %v\n", worktreePath, err)
+		return nil
+	}
+	branchName = filepath.ToSlash(branchName)
+
+	exists, err := projectExists(worktreePath, branchName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to check project state for %s: %v\n", branchName, err)
+		return nil
+	}
+	if !exists {
 		return nil
 	}
 
@@ -640,8 +693,22 @@ func processProjectState(path, worktreesDir string, info os.FileInfo) *ProjectIn
 		Phase:          proj.Statechart.Current_state,
 		TasksCompleted: tasksCompleted,
 		TasksTotal:     tasksTotal,
-		ModTime:        info.ModTime(),
+		ModTime:        stateModTime(worktreeCtx, proj),
+	}
+}
+
+// stateModTime returns when the project state of a worktree was last
+// saved: the modification time of its state file for local backends, or
+// the project's update time for remote ones.
+func stateModTime(worktreeCtx *sow.Context, proj *state.Project) time.Time {
+	repoConfig, err := config.LoadRepoConfig(worktreeCtx.FS())
+	if err == nil && config.GetStateBackend(repoConfig) != config.StateBackendHTTP {
+		statePath := filepath.Join(worktreeCtx.RepoRoot(), ".sow", config.GetStatePath(repoConfig))
+		if info, err := os.Stat(statePath); err == nil {
+			return info.ModTime()
+		}
 	}
+	return proj.Updated_at
 }
 
 // formatProjectProgress formats progress information for display in project selection.
@@ -10,9 +10,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/jmgilman/sow/cli/internal/cmdutil"
 	"github.com/jmgilman/sow/cli/internal/sow"
 	"github.com/jmgilman/sow/libs/git"
 	"github.com/jmgilman/sow/libs/git/mocks"
+	"github.com/jmgilman/sow/libs/project/state"
 )
 
 // TestNormalizeName tests the normalizeName function with various inputs.
@@ -684,6 +686,49 @@ statechart:
 	}
 }
 
+// TestListProjects_SQLiteProject tests discovery of a project stored in the
+// SQLite backend, which has no state.yaml.
+func TestListProjects_SQLiteProject(t *testing.T) {
+	ctx, tmpDir := setupTestContext(t)
+
+	branchName := "feat/sqlite-project"
+	worktreePath := filepath.Join(tmpDir, ".sow", "worktrees", branchName)
+	if err := os.MkdirAll(filepath.Join(worktreePath, ".sow"), 0755); err != nil {
+		t.Fatalf("failed to create .sow directory: %v", err)
+	}
+	setupTestRepo(t, worktreePath)
+	if err := os.WriteFile(filepath.Join(worktreePath, ".sow", "config.yaml"), []byte("state:\n  backend: sqlite\n"), 0644); err != nil {
+		t.Fatalf("failed to write config.yaml: %v", err)
+	}
+
+	worktreeCtx, err := sow.NewContext(worktreePath)
+	if err != nil {
+		t.Fatalf("failed to create worktree context: %v", err)
+	}
+	if _, err := cmdutil.CreateProject(context.Background(), worktreeCtx, state.CreateOpts{
+		Branch:      branchName,
+		Description: "SQLite backed project",
+	}); err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+
+	projects, err := listProjects(ctx)
+	if err != nil {
+		t.Fatalf("listProjects() returned error: %v", err)
+	}
+	if len(projects) != 1 {
+		t.Fatalf("listProjects() returned %d projects; want 1", len(projects))
+	}
+	if projects[0].Branch != branchName {
+		t.Errorf("Branch = %q; want %q", projects[0].Branch, branchName)
+	}
+
+	exists, err := projectExists(worktreePath, branchName)
+	if err != nil || !exists {
+		t.Errorf("projectExists() = %v, %v; want true", exists, err)
+	}
+}
+
 // TestListProjects_MultipleProjectsSorted tests that multiple projects are returned sorted by modification time.
 func TestListProjects_MultipleProjectsSorted(t *testing.T) {
 	ctx, tmpDir := setupTestContext(t)
@@ -503,7 +503,7 @@ func (w *Wizard) handlePromptEntry() error {
 
 	// Add project type for clarity
 	if projectType, ok := w.choices["type"].(string); ok {
-		typeConfig := projectTypes[projectType]
+		typeConfig, _ := lookupProjectType(projectType)
 		contextLines = append(contextLines,
 			fmt.Sprintf("Type: %s", typeConfig.Description))
 	}
@@ -605,12 +605,11 @@ func (w *Wizard) handleProjectSelect() error {
 		return fmt.Errorf("internal error: selected project not found in list")
 	}
 
-	// Double-check state file still exists (race condition check)
+	// Double-check project still exists (race condition check)
 	worktreePath := git.WorktreePath(w.ctx.MainRepoRoot(), selectedBranch)
-	statePath := filepath.Join(worktreePath, ".sow", "project", "state.yaml")
-	if _, err := os.Stat(statePath); err != nil {
+	if exists, err := projectExists(worktreePath, selectedBranch); err != nil || !exists {
 		// Project was deleted between discovery and selection
-		_ = showError("Project no longer exists (state file missing)\n\nPress Enter to try again")
+		_ = showError("Project no longer exists (state missing)\n\nPress Enter to try again")
 		return nil // Stay in current state to retry
 	}
 
@@ -11,7 +11,10 @@ import (
 	"github.com/jmgilman/sow/cli/cmd/issue"
 	"github.com/jmgilman/sow/cli/cmd/project"
 	"github.com/jmgilman/sow/cli/cmd/refs"
+	"github.com/jmgilman/sow/cli/internal/agents"
 	"github.com/jmgilman/sow/cli/internal/cmdutil"
+	"github.com/jmgilman/sow/cli/internal/migrate"
+	"github.com/jmgilman/sow/cli/internal/projects/custom"
 	"github.com/jmgilman/sow/cli/internal/sow"
 	"github.com/spf13/cobra"
 
@@ -47,8 +50,12 @@ orchestrating multiple AI agents across a 5-phase development workflow.`,
 				return fmt.Errorf("failed to get current directory: %w", err)
 			}
 
-			// Find repository root (walk up to find .git)
-			repoRoot := findRepoRoot(cwd)
+			// Find repository root (walk up to find .git), unless one is
+			// set explicitly (agents working in a task worktree)
+			repoRoot := os.Getenv(sow.RepoRootEnv)
+			if repoRoot == "" {
+				repoRoot = findRepoRoot(cwd)
+			}
 			if repoRoot == "" {
 				repoRoot = cwd // Fallback to cwd if not in a git repo
 			}
@@ -61,6 +68,27 @@ orchestrating multiple AI agents across a 5-phase development workflow.`,
 				return fmt.Errorf("failed to create sow context: %w", err)
 			}
 
+			// Refuse to touch a .sow structure written by a newer sow
+			if sowContext.IsInitialized() {
+				pending, err := migrate.Check(sowContext.FS())
+				if err != nil {
+					return fmt.Errorf("unsupported .sow structure: %w", err)
+				}
+				if pending && cmd.Name() != "migrate" {
+					fmt.Fprintln(cmd.ErrOrStderr(), "Warning: .sow structure is out of date; run 'sow migrate' to upgrade it")
+				}
+
+				// Register project types declared in .sow/types/
+				for _, err := range custom.Load(sowContext.FS()) {
+					fmt.Fprintf(cmd.ErrOrStderr(), "Warning: skipping custom project type: %v\n", err)
+				}
+
+				// Register agents declared in .sow/agents/
+				for _, err := range agents.LoadCustom(sowContext.FS()) {
+					fmt.Fprintf(cmd.ErrOrStderr(), "Warning: skipping custom agent: %v\n", err)
+				}
+			}
+
 			// Add to command context
 			ctx := cmdutil.WithContext(cmd.Context(), sowContext)
 			cmd.SetContext(ctx)
@@ -81,12 +109,15 @@ orchestrating multiple AI agents across a 5-phase development workflow.`,
 	cmd.AddCommand(NewInputCmd())
 	cmd.AddCommand(NewOutputCmd())
 	cmd.AddCommand(NewAdvanceCmd())
+	cmd.AddCommand(NewRunCmd())
 	cmd.AddCommand(agent.NewAgentCmd())
 	cmd.AddCommand(NewPromptCmd())
 	cmd.AddCommand(issue.NewIssueCmd())
 	cmd.AddCommand(refs.NewRefsCmd())
 	cmd.AddCommand(NewWorktreeCmd())
 	cmd.AddCommand(config.NewConfigCmd())
+	cmd.AddCommand(NewMigrateCmd())
+	cmd.AddCommand(NewServeCmd())
 
 	return cmd
 }
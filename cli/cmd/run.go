@@ -0,0 +1,379 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jmgilman/sow/cli/cmd/agent"
+	"github.com/jmgilman/sow/cli/internal/cmdutil"
+	"github.com/jmgilman/sow/libs/project"
+	"github.com/jmgilman/sow/libs/project/state"
+	projschema "github.com/jmgilman/sow/libs/schemas/project"
+	"github.com/spf13/cobra"
+)
+
+// NewRunCmd creates the command that drives the project until it needs a human.
+func NewRunCmd() *cobra.Command {
+	var maxSteps int
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Drive the project until it needs a human",
+		Long: `Drive the project forward until it needs a human decision.
+
+sow run repeats one deterministic step at a time, re-reading the project
+state after each:
+
+1. If the current state's next event (as chosen by 'sow advance') has its
+   guards met, advance. Hooks and verification run as for 'sow advance'.
+2. Otherwise, if the project is in the state where its phase executes
+   tasks (the phase's end state, e.g. ImplementationExecuting) and a
+   pending task's dependencies are done, spawn the task's assigned agent
+   (the first ready task in dependency order).
+3. Otherwise, if a task is in_progress, resume its session so the agent can
+   address feedback (or spawn it if it has no session).
+4. Otherwise, if nothing is waiting on a human and the project type binds
+   an agent to the current state (e.g. the reviewer to ReviewActive),
+   spawn that agent without a task, or resume its session if it has one.
+5. Otherwise, stop with a summary of what the project is waiting on: tasks
+   that need review, paused tasks, unapproved outputs, and unmet guard
+   conditions.
+
+Stopping at such a gate is not an error. sow run fails if an agent fails,
+exits without moving its task to another status (or, for an agent bound to
+the state, without leaving the state for a human or the next transition),
+or the step limit is hit.
+
+Examples:
+  sow run                 # Run until a human gate
+  sow run --dry-run       # Show the next step without taking it
+  sow run --max-steps 10  # Stop after at most 10 steps`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runLoop(cmd, maxSteps, dryRun)
+		},
+	}
+
+	cmd.Flags().IntVar(&maxSteps, "max-steps", 50, "Maximum number of steps to take")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the next step without taking it")
+
+	return cmd
+}
+
+// runAction is the kind of step sow run takes next.
+type runAction int
+
+const (
+	runStop runAction = iota
+	runAdvance
+	runSpawn
+	runResume
+)
+
+// runStep is the next step of sow run.
+type runStep struct {
+	action runAction
+
+	// event is the event to fire (runAdvance).
+	event project.Event
+
+	// phase and task are the task to spawn or resume an agent for
+	// (runSpawn, runResume).
+	phase string
+	task  projschema.TaskState
+
+	// agent is the agent bound to the current state, run without a task
+	// instead (runSpawn, runResume).
+	agent string
+
+	// reason explains why the loop stops (runStop); done is set if the
+	// project reached a terminal state.
+	reason string
+	done   bool
+
+	// gates lists the tasks and artifacts waiting on a human, and unmet the
+	// conditions blocking the next transition (runStop).
+	gates []string
+	unmet []string
+}
+
+// describe returns a one-line description of the step.
+func (s runStep) describe() string {
+	switch s.action {
+	case runAdvance:
+		return fmt.Sprintf("advance (%s)", s.event)
+	case runSpawn:
+		if s.agent != "" {
+			return fmt.Sprintf("spawn %s (%s phase)", s.agent, s.phase)
+		}
+		return fmt.Sprintf("spawn %s for task %s (%s)", s.task.Assigned_agent, s.task.Id, s.task.Name)
+	case runResume:
+		if s.agent != "" {
+			return fmt.Sprintf("resume %s (%s phase)", s.agent, s.phase)
+		}
+		return fmt.Sprintf("resume %s for task %s (%s)", s.task.Assigned_agent, s.task.Id, s.task.Name)
+	default:
+		return "stop: " + s.reason
+	}
+}
+
+// resumeTaskPrompt is the prompt for resuming a task sent back for rework.
+const resumeTaskPrompt = "Continue the task. Address the latest feedback in feedback/ if there is any, then set the task status to needs_review."
+
+// resumeAgentPrompt is the prompt for resuming the agent bound to a state
+// the project has returned to, e.g. planning after a failed review.
+const resumeAgentPrompt = "The project is back in a state you work on. Follow your agent instructions for the current state, taking the latest review and feedback into account."
+
+// nextRunStep decides the next step of sow run from the project state.
+func nextRunStep(config *project.ProjectTypeConfig, p *state.Project) runStep {
+	current := project.State(p.Statechart.Current_state)
+	if len(config.GetAvailableTransitions(current)) == 0 {
+		return runStep{action: runStop, done: true, reason: fmt.Sprintf("%s has no further transitions", current)}
+	}
+
+	// Advance whenever the next transition is allowed
+	event, determineErr := config.DetermineEvent(p)
+	var unmet []string
+	if determineErr == nil {
+		result := config.EvaluateGuard(current, event, p)
+		if result.Passed() {
+			return runStep{action: runAdvance, event: event}
+		}
+		unmet = result.Unmet
+	} else if errors.As(determineErr, new(*project.ErrBranchNotFound)) {
+		unmet = branchUnmetConditions(config, current, p).Unmet
+	}
+
+	// Otherwise look for agent work in the current phase, and collect what
+	// is waiting on a human. Tasks only run once the phase reaches its end
+	// state, so a task list still being planned is not started.
+	var gates []string
+	phaseName := config.GetPhaseForState(string(current))
+	if phase, ok := p.Phases[phaseName]; ok {
+		if config.PhaseSupportsTasks(phaseName) && config.IsPhaseEndState(phaseName, string(current)) {
+			ready, err := state.ReadyTasks(phase.Tasks)
+			if err != nil {
+				return runStep{action: runStop, reason: err.Error()}
+			}
+			if len(ready) > 0 {
+				return runStep{action: runSpawn, phase: phaseName, task: ready[0]}
+			}
+
+			for _, task := range phase.Tasks {
+				if task.Status != "in_progress" {
+					continue
+				}
+				if task.Session_id == "" {
+					return runStep{action: runSpawn, phase: phaseName, task: task}
+				}
+				return runStep{action: runResume, phase: phaseName, task: task}
+			}
+
+			for _, task := range phase.Tasks {
+				switch task.Status {
+				case "needs_review":
+					gates = append(gates, fmt.Sprintf("task %s (%s) needs review", task.Id, task.Name))
+				case "paused":
+					gates = append(gates, fmt.Sprintf("task %s (%s) is paused", task.Id, task.Name))
+				}
+			}
+		}
+
+		for _, output := range phase.Outputs {
+			if !output.Approved {
+				gates = append(gates, fmt.Sprintf("%s output %s is not approved", output.Type, output.Path))
+			}
+		}
+	}
+
+	// Otherwise run the agent bound to the state, e.g. the reviewer, unless
+	// the state waits on a human. A branch without a match is still open,
+	// such as a review without an assessment.
+	bound := config.GetStateAgent(string(current))
+	if bound != "" && len(gates) == 0 &&
+		(determineErr == nil || errors.As(determineErr, new(*project.ErrBranchNotFound))) {
+		if p.Agent_sessions[bound] != "" {
+			return runStep{action: runResume, phase: phaseName, agent: bound}
+		}
+		return runStep{action: runSpawn, phase: phaseName, agent: bound}
+	}
+
+	step := runStep{action: runStop, gates: gates, unmet: unmet}
+	switch {
+	case determineErr != nil:
+		step.reason = fmt.Sprintf("cannot choose the next transition: %v", determineErr)
+	case len(gates) > 0:
+		step.reason = "waiting on a human"
+	default:
+		step.reason = fmt.Sprintf("transition %s is blocked and no agent work is left", event)
+	}
+	return step
+}
+
+// runLoop implements the run command logic.
+func runLoop(cmd *cobra.Command, maxSteps int, dryRun bool) error {
+	ctx := cmdutil.GetContext(cmd.Context())
+
+	// Check if sow is initialized
+	if !ctx.IsInitialized() {
+		return fmt.Errorf("sow not initialized. Run 'sow init' first")
+	}
+
+	advances, agentRuns := 0, 0
+	for steps := 0; ; steps++ {
+		proj, err := cmdutil.LoadProject(cmd.Context(), ctx)
+		if err != nil {
+			if strings.Contains(err.Error(), "no such file") {
+				return fmt.Errorf("no active project found")
+			}
+			return fmt.Errorf("failed to load project: %w", err)
+		}
+		config, ok := proj.Config().(*project.ProjectTypeConfig)
+		if !ok {
+			return fmt.Errorf("invalid project configuration")
+		}
+
+		step := nextRunStep(config, proj)
+		if dryRun {
+			fmt.Printf("Current state: %s\n", proj.Statechart.Current_state)
+			fmt.Printf("Next step: %s\n", step.describe())
+			if step.action == runStop {
+				printRunGates(step)
+			}
+			return nil
+		}
+		if step.action == runStop {
+			printRunSummary(proj.Statechart.Current_state, step, advances, agentRuns)
+			return nil
+		}
+		if steps >= maxSteps {
+			return fmt.Errorf("stopped after %d steps (--max-steps) in state %s", steps, proj.Statechart.Current_state)
+		}
+
+		fmt.Printf("\n==> %s\n", step.describe())
+		switch step.action {
+		case runAdvance:
+			if err := runLoopAdvance(cmd); err != nil {
+				return err
+			}
+			advances++
+		case runSpawn, runResume:
+			if step.agent != "" {
+				if err := runLoopStateAgent(cmd, step); err != nil {
+					return err
+				}
+				agentRuns++
+				if err := checkStateAgentProgress(cmd, proj.Statechart.Current_state, step); err != nil {
+					return err
+				}
+				continue
+			}
+			if step.action == runSpawn {
+				err = agent.SpawnTask(cmd, step.phase, step.task.Id)
+			} else {
+				err = agent.ResumeTask(cmd, step.phase, step.task.Id, resumeTaskPrompt)
+			}
+			if err != nil {
+				return fmt.Errorf("task %s: %w", step.task.Id, err)
+			}
+			agentRuns++
+			if err := checkTaskProgress(cmd, step); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// runLoopAdvance fires the next transition as sow advance does, which
+// takes the state lock once the hooks have run.
+func runLoopAdvance(cmd *cobra.Command) error {
+	ctx := cmdutil.GetContext(cmd.Context())
+	proj, err := cmdutil.LoadProject(cmd.Context(), ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+	return executeAutoTransition(cmd, ctx, proj, proj.Statechart.Current_state)
+}
+
+// checkTaskProgress re-reads the state after an agent exits and returns an
+// error if the agent left its task pending or in progress, so the loop does
+// not spawn it again and again.
+func checkTaskProgress(cmd *cobra.Command, step runStep) error {
+	proj, err := cmdutil.LoadProject(cmd.Context(), cmdutil.GetContext(cmd.Context()))
+	if err != nil {
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+	for _, task := range proj.Phases[step.phase].Tasks {
+		if task.Id != step.task.Id {
+			continue
+		}
+		if task.Status == "pending" || task.Status == "in_progress" {
+			return fmt.Errorf("agent exited without finishing task %s (status %s)", task.Id, task.Status)
+		}
+		fmt.Printf("Task %s is now %s\n", task.Id, task.Status)
+		return nil
+	}
+	return fmt.Errorf("task %s not found in phase %s", step.task.Id, step.phase)
+}
+
+// runLoopStateAgent spawns the agent bound to the current state without a
+// task, as 'sow agent spawn --agent <name>' does, or resumes its session.
+func runLoopStateAgent(cmd *cobra.Command, step runStep) error {
+	var err error
+	if step.action == runSpawn {
+		err = agent.SpawnAgent(cmd, step.agent, "")
+	} else {
+		err = agent.ResumeAgent(cmd, step.agent, resumeAgentPrompt)
+	}
+	if err != nil {
+		return fmt.Errorf("agent %s: %w", step.agent, err)
+	}
+	return nil
+}
+
+// checkStateAgentProgress re-reads the state after the agent bound to
+// fromState exits and returns an error if sow run would run it again, i.e.
+// the agent neither advanced the project nor left work for a human, so the
+// loop does not spawn it again and again.
+func checkStateAgentProgress(cmd *cobra.Command, fromState string, step runStep) error {
+	proj, err := cmdutil.LoadProject(cmd.Context(), cmdutil.GetContext(cmd.Context()))
+	if err != nil {
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+	config, ok := proj.Config().(*project.ProjectTypeConfig)
+	if !ok {
+		return fmt.Errorf("invalid project configuration")
+	}
+	if proj.Statechart.Current_state != fromState {
+		return nil
+	}
+	if next := nextRunStep(config, proj); next.agent == step.agent {
+		return fmt.Errorf("agent %s exited without recording its work in %s", step.agent, fromState)
+	}
+	return nil
+}
+
+// printRunSummary prints why sow run stopped and what it did.
+func printRunSummary(currentState string, step runStep, advances, agentRuns int) {
+	fmt.Println()
+	if step.done {
+		fmt.Printf("Project finished: %s\n", step.reason)
+	} else {
+		fmt.Printf("Stopped in %s: %s\n", currentState, step.reason)
+	}
+	printRunGates(step)
+	fmt.Printf("\nTook %d transition(s) and ran %d agent(s).\n", advances, agentRuns)
+}
+
+// printRunGates lists what a stopped run is waiting on.
+func printRunGates(step runStep) {
+	if len(step.gates) > 0 {
+		fmt.Println("Waiting on:")
+		for _, gate := range step.gates {
+			fmt.Printf("  - %s\n", gate)
+		}
+	}
+	printUnmetConditions("", project.GuardResult{Unmet: step.unmet})
+}
@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jmgilman/sow/libs/project"
+	"github.com/jmgilman/sow/libs/project/state"
+	projschema "github.com/jmgilman/sow/libs/schemas/project"
+)
+
+// runTestConfig is a project type with a planning phase gated on an
+// approved plan, a build phase whose tasks run in BuildExecuting, and a
+// review phase whose report the reviewer bound to Reviewing writes.
+func runTestConfig() *project.ProjectTypeConfig {
+	next := func(event project.Event) project.EventDeterminer {
+		return func(*state.Project) (project.Event, error) { return event, nil }
+	}
+
+	return project.NewProjectTypeConfigBuilder("run-test").
+		SetInitialState("Planning").
+		WithPhase("plan",
+			project.WithStartState("Planning"),
+			project.WithEndState("Planning"),
+			project.WithOutputs("plan"),
+		).
+		WithPhase("build",
+			project.WithStartState("BuildPlanning"),
+			project.WithEndState("BuildExecuting"),
+			project.WithTasks(),
+		).
+		WithPhase("review",
+			project.WithStartState("Reviewing"),
+			project.WithEndState("Reviewing"),
+			project.WithOutputs("review"),
+		).
+		AddTransition("Planning", "BuildPlanning", "plan_done",
+			project.WithProjectGuardResult("plan approved", func(p *state.Project) project.GuardResult {
+				var result project.GuardResult
+				for _, output := range p.Phases["plan"].Outputs {
+					if !output.Approved {
+						result.Addf("plan not approved")
+					}
+				}
+				return result
+			}),
+		).
+		AddTransition("BuildPlanning", "BuildExecuting", "start",
+			project.WithProjectGuardResult("tasks approved", func(p *state.Project) project.GuardResult {
+				var result project.GuardResult
+				if approved, _ := p.Phases["build"].Metadata["tasks_approved"].(bool); !approved {
+					result.Addf("tasks not approved")
+				}
+				return result
+			}),
+		).
+		AddTransition("BuildExecuting", "Reviewing", "finish",
+			project.WithProjectGuardResult("all tasks complete", func(p *state.Project) project.GuardResult {
+				var result project.GuardResult
+				for _, task := range p.Phases["build"].Tasks {
+					if task.Status != "completed" {
+						result.Addf("task %s not complete (%s)", task.Id, task.Status)
+					}
+				}
+				return result
+			}),
+		).
+		AddTransition("Reviewing", "Done", "approve",
+			project.WithProjectGuardResult("review approved", func(p *state.Project) project.GuardResult {
+				var result project.GuardResult
+				outputs := p.Phases["review"].Outputs
+				if len(outputs) == 0 || !outputs[0].Approved {
+					result.Addf("review not approved")
+				}
+				return result
+			}),
+		).
+		OnAdvance("Planning", next("plan_done")).
+		OnAdvance("BuildPlanning", next("start")).
+		OnAdvance("BuildExecuting", next("finish")).
+		OnAdvance("Reviewing", next("approve")).
+		WithAgent("Reviewing", "reviewer").
+		Build()
+}
+
+// runTestProject returns a project in currentState with the given plan
+// outputs and build tasks.
+func runTestProject(currentState string, outputs []projschema.ArtifactState, tasks ...projschema.TaskState) *state.Project {
+	p := &state.Project{}
+	p.Statechart.Current_state = currentState
+	p.Phases = map[string]projschema.PhaseState{
+		"plan":   {Outputs: outputs},
+		"build":  {Tasks: tasks, Metadata: map[string]interface{}{}},
+		"review": {},
+	}
+	return p
+}
+
+// runTestTask returns a build task with the given status and dependencies.
+func runTestTask(id, status string, dependsOn ...string) projschema.TaskState {
+	return projschema.TaskState{
+		Id:             id,
+		Name:           "Task " + id,
+		Status:         status,
+		Assigned_agent: "implementer",
+		Depends_on:     dependsOn,
+	}
+}
+
+func TestNextRunStep_AdvancesWhenGuardPasses(t *testing.T) {
+	p := runTestProject("Planning", []projschema.ArtifactState{{Type: "plan", Path: "plan.md", Approved: true}})
+
+	step := nextRunStep(runTestConfig(), p)
+
+	if step.action != runAdvance || step.event != "plan_done" {
+		t.Errorf("expected advance with plan_done, got %s", step.describe())
+	}
+}
+
+func TestNextRunStep_StopsAtUnapprovedOutput(t *testing.T) {
+	p := runTestProject("Planning", []projschema.ArtifactState{{Type: "plan", Path: "plan.md"}})
+
+	step := nextRunStep(runTestConfig(), p)
+
+	if step.action != runStop {
+		t.Fatalf("expected stop, got %s", step.describe())
+	}
+	if step.done {
+		t.Error("expected stop at a gate, not a finished project")
+	}
+	if len(step.gates) != 1 || step.gates[0] != "plan output plan.md is not approved" {
+		t.Errorf("unexpected gates: %v", step.gates)
+	}
+	if len(step.unmet) != 1 || step.unmet[0] != "plan not approved" {
+		t.Errorf("unexpected unmet conditions: %v", step.unmet)
+	}
+}
+
+func TestNextRunStep_DoesNotStartTasksWhilePlanning(t *testing.T) {
+	p := runTestProject("BuildPlanning", nil, runTestTask("010", "pending"))
+
+	step := nextRunStep(runTestConfig(), p)
+
+	if step.action != runStop {
+		t.Fatalf("expected stop, got %s", step.describe())
+	}
+	if !strings.Contains(step.reason, "no agent work is left") {
+		t.Errorf("unexpected reason: %s", step.reason)
+	}
+}
+
+func TestNextRunStep_SpawnsFirstReadyTask(t *testing.T) {
+	p := runTestProject("BuildExecuting", nil,
+		runTestTask("010", "pending", "020"),
+		runTestTask("020", "pending"),
+	)
+
+	step := nextRunStep(runTestConfig(), p)
+
+	if step.action != runSpawn || step.task.Id != "020" || step.phase != "build" {
+		t.Errorf("expected spawn for task 020 in build, got %s", step.describe())
+	}
+}
+
+func TestNextRunStep_ResumesTaskInProgress(t *testing.T) {
+	reworked := runTestTask("010", "in_progress")
+	reworked.Session_id = "session-010"
+	p := runTestProject("BuildExecuting", nil, reworked, runTestTask("020", "pending", "010"))
+
+	step := nextRunStep(runTestConfig(), p)
+
+	if step.action != runResume || step.task.Id != "010" {
+		t.Errorf("expected resume for task 010, got %s", step.describe())
+	}
+
+	reworked.Session_id = ""
+	p = runTestProject("BuildExecuting", nil, reworked)
+	if step := nextRunStep(runTestConfig(), p); step.action != runSpawn {
+		t.Errorf("expected spawn for task without a session, got %s", step.describe())
+	}
+}
+
+func TestNextRunStep_StopsAtTasksNeedingReview(t *testing.T) {
+	p := runTestProject("BuildExecuting", nil,
+		runTestTask("010", "needs_review"),
+		runTestTask("020", "pending", "010"),
+		runTestTask("030", "paused"),
+	)
+
+	step := nextRunStep(runTestConfig(), p)
+
+	if step.action != runStop {
+		t.Fatalf("expected stop, got %s", step.describe())
+	}
+	if step.reason != "waiting on a human" {
+		t.Errorf("unexpected reason: %s", step.reason)
+	}
+	want := []string{"task 010 (Task 010) needs review", "task 030 (Task 030) is paused"}
+	if strings.Join(step.gates, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected gates %v, got %v", want, step.gates)
+	}
+}
+
+func TestNextRunStep_AdvancesWhenTasksComplete(t *testing.T) {
+	p := runTestProject("BuildExecuting", nil, runTestTask("010", "completed"))
+
+	step := nextRunStep(runTestConfig(), p)
+
+	if step.action != runAdvance || step.event != "finish" {
+		t.Errorf("expected advance with finish, got %s", step.describe())
+	}
+}
+
+func TestNextRunStep_StopsWhenFinished(t *testing.T) {
+	step := nextRunStep(runTestConfig(), runTestProject("Done", nil))
+
+	if step.action != runStop || !step.done {
+		t.Errorf("expected finished stop, got %s", step.describe())
+	}
+}
+
+func TestNextRunStep_SpawnsStateAgent(t *testing.T) {
+	p := runTestProject("Reviewing", nil)
+
+	step := nextRunStep(runTestConfig(), p)
+
+	if step.action != runSpawn || step.agent != "reviewer" || step.phase != "review" {
+		t.Errorf("expected spawn of the reviewer in review, got %s", step.describe())
+	}
+
+	p.Agent_sessions = map[string]string{"reviewer": "session-review"}
+	if step := nextRunStep(runTestConfig(), p); step.action != runResume || step.agent != "reviewer" {
+		t.Errorf("expected resume of the reviewer's session, got %s", step.describe())
+	}
+}
+
+func TestNextRunStep_StopsAtStateAgentOutput(t *testing.T) {
+	p := runTestProject("Reviewing", nil)
+	p.Phases["review"] = projschema.PhaseState{
+		Outputs: []projschema.ArtifactState{{Type: "review", Path: "review.md"}},
+	}
+
+	step := nextRunStep(runTestConfig(), p)
+
+	if step.action != runStop || step.reason != "waiting on a human" {
+		t.Fatalf("expected stop waiting on a human, got %s", step.describe())
+	}
+	if len(step.gates) != 1 || step.gates[0] != "review output review.md is not approved" {
+		t.Errorf("unexpected gates: %v", step.gates)
+	}
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/jmgilman/go/fs/billy"
+	"github.com/jmgilman/sow/libs/config"
+	"github.com/jmgilman/sow/libs/project/state"
+	"github.com/spf13/cobra"
+)
+
+// serveBackendMemory keeps served state in memory only.
+const serveBackendMemory = "memory"
+
+// NewServeCmd creates the command to run a project state server.
+//
+// Usage:
+//
+//	sow serve --dir ./state                  # Serve YAML files from ./state
+//	sow serve --backend sqlite --dir ./state # One SQLite database per project
+//	sow serve --backend memory               # Nothing written to disk
+func NewServeCmd() *cobra.Command {
+	var addr, backend, dir string
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve project state over HTTP",
+		Long: `Run a state server for the http state backend.
+
+The server hosts project state for any number of repositories and branches,
+one project per branch, so several machines (or a dashboard) can share
+project state without committing .sow/project/state.yaml. Point a repository
+at it in .sow/config.yaml:
+
+  state:
+    backend: http
+    url: http://localhost:7070
+
+Each project is stored with one of the other backends:
+  yaml    one <branch>.yaml file per project in --dir
+  sqlite  one <branch>.db database per project in --dir
+  memory  in memory only; state is lost when the server stops
+
+Saves are compare-and-swap on the project revision, exactly as with local
+backends, so concurrent writers get a conflict instead of overwriting.
+
+The server has no authentication. Bind it to localhost or put it behind a
+proxy that authenticates requests.
+
+Examples:
+  sow serve --dir ./state
+  sow serve --addr 127.0.0.1:8080 --backend sqlite --dir ./state
+  sow serve --backend memory`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			open, err := newServeOpener(backend, dir)
+			if err != nil {
+				return err
+			}
+			return runServe(cmd, addr, backend, open)
+		},
+	}
+
+	cmd.Flags().StringVar(&addr, "addr", "127.0.0.1:7070", "Address to listen on")
+	cmd.Flags().StringVar(&backend, "backend", config.StateBackendYAML,
+		"Backend storing served projects (yaml, sqlite, or memory)")
+	cmd.Flags().StringVar(&dir, "dir", "", "Directory for the yaml and sqlite backends")
+
+	return cmd
+}
+
+func runServe(cmd *cobra.Command, addr, backend string, open state.BackendOpener) error {
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	server := &http.Server{
+		Handler:           state.NewHTTPHandler(open),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.Serve(listener)
+	}()
+
+	_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "✓ Serving project state (%s backend) on http://%s\n",
+		backend, listener.Addr())
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("state server failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to stop state server: %w", err)
+	}
+	if err := <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("state server failed: %w", err)
+	}
+	return nil
+}
+
+// newServeOpener returns the function the state server uses to open the
+// backend for a project key.
+func newServeOpener(backend, dir string) (state.BackendOpener, error) {
+	if backend == serveBackendMemory {
+		var mu sync.Mutex
+		backends := map[string]*state.MemoryBackend{}
+		return func(key string) (state.Backend, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if _, ok := backends[key]; !ok {
+				backends[key] = state.NewMemoryBackend()
+			}
+			return backends[key], nil
+		}, nil
+	}
+
+	if dir == "" {
+		return nil, fmt.Errorf("--dir is required for the %s backend", backend)
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve --dir: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	switch backend {
+	case config.StateBackendYAML:
+		fs, err := billy.NewLocal().Chroot(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", dir, err)
+		}
+		return func(key string) (state.Backend, error) {
+			return state.NewYAMLBackendWithPath(fs, serveFileName(key, ".yaml")), nil
+		}, nil
+	case config.StateBackendSQLite:
+		return func(key string) (state.Backend, error) {
+			return state.NewSQLiteBackend(filepath.Join(dir, serveFileName(key, ".db"))), nil
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown backend: %s (valid: %s, %s, %s)",
+			backend, config.StateBackendYAML, config.StateBackendSQLite, serveBackendMemory)
+	}
+}
+
+// serveFileName maps a project key to a file name. Escaping keeps branch
+// names such as "feat/x" (or "..") inside the served directory.
+func serveFileName(key, ext string) string {
+	return url.PathEscape(key) + ext
+}
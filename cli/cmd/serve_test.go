@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jmgilman/sow/libs/project/state"
+	projschema "github.com/jmgilman/sow/libs/schemas/project"
+)
+
+func TestNewServeOpener_RoundTrip(t *testing.T) {
+	tests := []struct {
+		backend string
+		file    string // expected file in --dir, if any
+	}{
+		{backend: "yaml", file: "feat%2Fserve.yaml"},
+		{backend: "sqlite", file: "feat%2Fserve.db"},
+		{backend: "memory"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.backend, func(t *testing.T) {
+			dir := t.TempDir()
+			open, err := newServeOpener(tt.backend, dir)
+			if err != nil {
+				t.Fatalf("newServeOpener() error = %v", err)
+			}
+			server := httptest.NewServer(state.NewHTTPHandler(open))
+			defer server.Close()
+
+			ctx := context.Background()
+			backend := state.NewHTTPBackend(server.URL, "feat/serve", nil)
+			saved := &projschema.ProjectState{Name: "serve", Type: "standard", Branch: "feat/serve"}
+			if err := backend.Save(ctx, saved); err != nil {
+				t.Fatalf("Save() error = %v", err)
+			}
+
+			loaded, err := backend.Load(ctx)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if loaded.Name != "serve" || loaded.Revision != 1 {
+				t.Errorf("Load() = name %q revision %d, want serve revision 1", loaded.Name, loaded.Revision)
+			}
+
+			if tt.file != "" {
+				if _, err := os.Stat(filepath.Join(dir, tt.file)); err != nil {
+					t.Errorf("expected %s in served directory: %v", tt.file, err)
+				}
+			}
+		})
+	}
+}
+
+func TestNewServeOpener_Errors(t *testing.T) {
+	if _, err := newServeOpener("yaml", ""); err == nil || !strings.Contains(err.Error(), "--dir is required") {
+		t.Errorf("expected --dir error, got %v", err)
+	}
+
+	if _, err := newServeOpener("postgres", t.TempDir()); err == nil || !strings.Contains(err.Error(), "unknown backend") {
+		t.Errorf("expected unknown backend error, got %v", err)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/jmgilman/sow/cli/internal/agents"
 	"github.com/jmgilman/sow/cli/internal/cmdutil"
 	"github.com/jmgilman/sow/libs/project/state"
 	"github.com/jmgilman/sow/cli/internal/sow"
@@ -30,6 +31,8 @@ Tasks are the atomic units of work, each with inputs, outputs, and status tracki
 	cmd.AddCommand(newTaskSetCmd())
 	cmd.AddCommand(newTaskAbandonCmd())
 	cmd.AddCommand(newTaskStatusCmd())
+	cmd.AddCommand(newTaskNextCmd())
+	cmd.AddCommand(newTaskGraphCmd())
 	cmd.AddCommand(newTaskInputCmd())
 	cmd.AddCommand(newTaskOutputCmd())
 
@@ -39,6 +42,7 @@ Tasks are the atomic units of work, each with inputs, outputs, and status tracki
 // newTaskAddCmd creates the task add subcommand.
 func newTaskAddCmd() *cobra.Command {
 	var agent, description, taskID, phase string
+	var dependsOn []string
 
 	cmd := &cobra.Command{
 		Use:   "add <name>",
@@ -53,6 +57,11 @@ the task directory structure:
 
 The task ID is auto-generated by default, but can be specified with --id.
 
+Dependencies:
+  Use --depends-on to list tasks in the same phase that must be completed
+  before this one is ready. Dependencies must name existing tasks and may
+  not form a cycle. 'sow task next' lists the tasks that are ready.
+
 Phase Support:
   Not all phases support tasks. Use --phase to specify which phase, or let
   the command choose a smart default based on current project state.
@@ -65,10 +74,13 @@ Examples:
   sow task add "Implement JWT signing" --agent implementer --phase implementation
 
   # Add task with specific ID
-  sow task add "Implement JWT signing" --agent implementer --id 010`,
+  sow task add "Implement JWT signing" --agent implementer --id 010
+
+  # Add task that runs after tasks 010 and 020
+  sow task add "Wire JWT middleware" --agent implementer --depends-on 010,020`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runTaskAdd(cmd, args[0], agent, description, taskID, phase)
+			return runTaskAdd(cmd, args[0], agent, description, taskID, phase, dependsOn)
 		},
 	}
 
@@ -76,6 +88,7 @@ Examples:
 	cmd.Flags().StringVar(&description, "description", "", "Task description")
 	cmd.Flags().StringVar(&taskID, "id", "", "Task ID (optional, auto-generated if not specified)")
 	cmd.Flags().StringVar(&phase, "phase", "", "Target phase (defaults to current phase)")
+	cmd.Flags().StringSliceVar(&dependsOn, "depends-on", nil, "IDs of tasks that must be completed first (comma-separated)")
 
 	_ = cmd.MarkFlagRequired("agent")
 
@@ -93,6 +106,7 @@ func newTaskSetCmd() *cobra.Command {
 
 Supports:
   - Direct fields: status, iteration, assigned_agent, name
+  - Dependencies: depends_on (comma-separated task IDs, empty to clear)
   - Metadata fields: metadata.* (any custom field)
 
 Examples:
@@ -105,6 +119,9 @@ Examples:
   # Set metadata field
   sow task set --id 010 metadata.complexity high
 
+  # Set dependencies
+  sow task set --id 030 depends_on 010,020
+
   # Set field in specific phase
   sow task set --id 010 --phase implementation status in_progress`,
 		Args: cobra.ExactArgs(2),
@@ -185,7 +202,7 @@ Examples:
 }
 
 // runTaskAdd implements the task add command logic.
-func runTaskAdd(cmd *cobra.Command, name, agent, description, taskID, explicitPhase string) error {
+func runTaskAdd(cmd *cobra.Command, name, agent, description, taskID, explicitPhase string, dependsOn []string) error {
 	ctx := cmdutil.GetContext(cmd.Context())
 
 	// Check if sow is initialized
@@ -193,6 +210,13 @@ func runTaskAdd(cmd *cobra.Command, name, agent, description, taskID, explicitPh
 		return fmt.Errorf("sow not initialized. Run 'sow init' first")
 	}
 
+	// Lock project state for the load-modify-save cycle
+	lock, err := cmdutil.LockProject(cmd.Context(), ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+
 	// Load project
 	proj, err := cmdutil.LoadProject(cmd.Context(), ctx)
 	if err != nil {
@@ -208,64 +232,73 @@ func runTaskAdd(cmd *cobra.Command, name, agent, description, taskID, explicitPh
 		return err
 	}
 
-	// Get phase
-	phaseState, exists := proj.Phases[phaseName]
-	if !exists {
-		return fmt.Errorf("phase not found: %s", phaseName)
-	}
-
-	// Generate or validate task ID
-	if taskID == "" {
-		// Auto-generate next task ID (gap-numbered)
-		taskID = generateNextTaskID(phaseState.Tasks)
-	} else {
-		// Validate specified task ID format (must be 3 digits)
+	// Validate specified task ID format (must be 3 digits)
+	if taskID != "" {
 		if len(taskID) != 3 {
 			return fmt.Errorf("task ID must be 3 digits (e.g., 010, 020)")
 		}
 		if _, err := strconv.Atoi(taskID); err != nil {
 			return fmt.Errorf("task ID must be numeric: %w", err)
 		}
+	}
+
+	// Update and save project state. The ID is generated inside the update
+	// so a retry after a concurrent add picks the next free ID.
+	var id string
+	_, err = cmdutil.UpdateProject(cmd.Context(), proj, func(p *state.Project) error {
+		// Get phase
+		phaseState, exists := p.Phases[phaseName]
+		if !exists {
+			return fmt.Errorf("phase not found: %s", phaseName)
+		}
 
-		// Check for duplicate task ID
-		for _, task := range phaseState.Tasks {
-			if task.Id == taskID {
-				return fmt.Errorf("task ID %s already exists", taskID)
+		// Generate or check task ID
+		id = taskID
+		if id == "" {
+			// Auto-generate next task ID (gap-numbered)
+			id = generateNextTaskID(phaseState.Tasks)
+		} else {
+			// Check for duplicate task ID
+			for _, task := range phaseState.Tasks {
+				if task.Id == id {
+					return fmt.Errorf("task ID %s already exists", id)
+				}
 			}
 		}
-	}
 
-	// Create task
-	now := time.Now()
-	task := project.TaskState{
-		Id:             taskID,
-		Name:           name,
-		Phase:          phaseName,
-		Status:         "pending",
-		Iteration:      1,
-		Assigned_agent: agent,
-		Created_at:     now,
-		Updated_at:     now,
-		Inputs:         []project.ArtifactState{},
-		Outputs:        []project.ArtifactState{},
-		Metadata:       make(map[string]interface{}),
-	}
+		// Create task
+		now := time.Now()
+		task := project.TaskState{
+			Id:             id,
+			Name:           name,
+			Phase:          phaseName,
+			Status:         "pending",
+			Iteration:      1,
+			Assigned_agent: agent,
+			Depends_on:     dependsOn,
+			Created_at:     now,
+			Updated_at:     now,
+			Inputs:         []project.ArtifactState{},
+			Outputs:        []project.ArtifactState{},
+			Metadata:       make(map[string]interface{}),
+		}
 
-	// Add to phase tasks
-	phaseState.Tasks = append(phaseState.Tasks, task)
-	proj.Phases[phaseName] = phaseState
+		// Add to phase tasks
+		phaseState.Tasks = append(phaseState.Tasks, task)
+		p.Phases[phaseName] = phaseState
 
-	// Create task directory
-	if err := createTaskDirectory(ctx, phaseName, taskID, description); err != nil {
-		return fmt.Errorf("failed to create task directory: %w", err)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	// Save project state
-	if err := proj.Save(cmd.Context()); err != nil {
-		return fmt.Errorf("failed to save project: %w", err)
+	// Create task directory
+	if err := createTaskDirectory(ctx, phaseName, id, description); err != nil {
+		return fmt.Errorf("failed to create task directory: %w", err)
 	}
 
-	fmt.Printf("Added task [%s] %s to phase %s\n", taskID, name, phaseName)
+	fmt.Printf("Added task [%s] %s to phase %s\n", id, name, phaseName)
 	return nil
 }
 
@@ -278,6 +311,13 @@ func runTaskSet(cmd *cobra.Command, args []string, taskID, explicitPhase string)
 		return fmt.Errorf("sow not initialized. Run 'sow init' first")
 	}
 
+	// Lock project state for the load-modify-save cycle
+	lock, err := cmdutil.LockProject(cmd.Context(), ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+
 	// Load project
 	proj, err := cmdutil.LoadProject(cmd.Context(), ctx)
 	if err != nil {
@@ -293,48 +333,52 @@ func runTaskSet(cmd *cobra.Command, args []string, taskID, explicitPhase string)
 		return err
 	}
 
-	// Get phase
-	phaseState, exists := proj.Phases[phaseName]
-	if !exists {
-		return fmt.Errorf("phase not found: %s", phaseName)
-	}
+	// Get field path and value from args
+	fieldPath := args[0]
+	value := args[1]
 
-	// Find task by ID
-	taskIndex := -1
-	for i, t := range phaseState.Tasks {
-		if t.Id == taskID {
-			taskIndex = i
-			break
+	// Update and save project state
+	_, err = cmdutil.UpdateProject(cmd.Context(), proj, func(p *state.Project) error {
+		// Get phase
+		phaseState, exists := p.Phases[phaseName]
+		if !exists {
+			return fmt.Errorf("phase not found: %s", phaseName)
 		}
-	}
 
-	if taskIndex == -1 {
-		return fmt.Errorf("task not found: %s", taskID)
-	}
+		// Find task by ID
+		taskIndex := -1
+		for i, t := range phaseState.Tasks {
+			if t.Id == taskID {
+				taskIndex = i
+				break
+			}
+		}
 
-	// Wrap in Task type for field path mutation
-	task := &state.Task{
-		TaskState: phaseState.Tasks[taskIndex],
-	}
+		if taskIndex == -1 {
+			return fmt.Errorf("task not found: %s", taskID)
+		}
 
-	// Set field using field path parser
-	fieldPath := args[0]
-	value := args[1]
+		// Wrap in Task type for field path mutation
+		task := &state.Task{
+			TaskState: phaseState.Tasks[taskIndex],
+		}
 
-	if err := cmdutil.SetField(task, fieldPath, value); err != nil {
-		return fmt.Errorf("failed to set field: %w", err)
-	}
+		// Set field using field path parser
+		if err := cmdutil.SetField(task, fieldPath, value); err != nil {
+			return fmt.Errorf("failed to set field: %w", err)
+		}
 
-	// Update task's updated_at timestamp
-	task.Updated_at = time.Now()
+		// Update task's updated_at timestamp
+		task.Updated_at = time.Now()
 
-	// Update back in phase
-	phaseState.Tasks[taskIndex] = task.TaskState
-	proj.Phases[phaseName] = phaseState
+		// Update back in phase
+		phaseState.Tasks[taskIndex] = task.TaskState
+		p.Phases[phaseName] = phaseState
 
-	// Save project state
-	if err := proj.Save(cmd.Context()); err != nil {
-		return fmt.Errorf("failed to save project: %w", err)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	fmt.Printf("Set %s on task [%s] in phase %s\n", fieldPath, taskID, phaseName)
@@ -350,6 +394,13 @@ func runTaskAbandon(cmd *cobra.Command, taskID, explicitPhase string) error {
 		return fmt.Errorf("sow not initialized. Run 'sow init' first")
 	}
 
+	// Lock project state for the load-modify-save cycle
+	lock, err := cmdutil.LockProject(cmd.Context(), ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+
 	// Load project
 	proj, err := cmdutil.LoadProject(cmd.Context(), ctx)
 	if err != nil {
@@ -365,36 +416,39 @@ func runTaskAbandon(cmd *cobra.Command, taskID, explicitPhase string) error {
 		return err
 	}
 
-	// Get phase
-	phaseState, exists := proj.Phases[phaseName]
-	if !exists {
-		return fmt.Errorf("phase not found: %s", phaseName)
-	}
+	// Update and save project state
+	_, err = cmdutil.UpdateProject(cmd.Context(), proj, func(p *state.Project) error {
+		// Get phase
+		phaseState, exists := p.Phases[phaseName]
+		if !exists {
+			return fmt.Errorf("phase not found: %s", phaseName)
+		}
 
-	// Find task by ID
-	taskIndex := -1
-	for i, t := range phaseState.Tasks {
-		if t.Id == taskID {
-			taskIndex = i
-			break
+		// Find task by ID
+		taskIndex := -1
+		for i, t := range phaseState.Tasks {
+			if t.Id == taskID {
+				taskIndex = i
+				break
+			}
 		}
-	}
 
-	if taskIndex == -1 {
-		return fmt.Errorf("task not found: %s", taskID)
-	}
+		if taskIndex == -1 {
+			return fmt.Errorf("task not found: %s", taskID)
+		}
 
-	// Update task status
-	now := time.Now()
-	phaseState.Tasks[taskIndex].Status = "abandoned"
-	phaseState.Tasks[taskIndex].Completed_at = now
-	phaseState.Tasks[taskIndex].Updated_at = now
+		// Update task status
+		now := time.Now()
+		phaseState.Tasks[taskIndex].Status = "abandoned"
+		phaseState.Tasks[taskIndex].Completed_at = now
+		phaseState.Tasks[taskIndex].Updated_at = now
 
-	proj.Phases[phaseName] = phaseState
+		p.Phases[phaseName] = phaseState
 
-	// Save project state
-	if err := proj.Save(cmd.Context()); err != nil {
-		return fmt.Errorf("failed to save project: %w", err)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	fmt.Printf("Abandoned task [%s] in phase %s\n", taskID, phaseName)
@@ -466,7 +520,18 @@ func displayTaskOverview(phaseName string, tasks []project.TaskState) error {
 		if task.Iteration > 1 {
 			iterationInfo = fmt.Sprintf(" - iteration %d", task.Iteration)
 		}
-		fmt.Printf("  [%s] %s (%s)%s\n", task.Id, task.Name, task.Status, iterationInfo)
+		dependencyInfo := ""
+		if unmet := state.UnmetDependencies(tasks, task); task.Status == "pending" && len(unmet) > 0 {
+			dependencyInfo = fmt.Sprintf(" - waiting on %s", strings.Join(unmet, ", "))
+		}
+		attemptInfo := ""
+		if attempts := agents.AttemptsFromMetadata(task.Metadata); len(attempts) > 0 {
+			last := attempts[len(attempts)-1]
+			if len(attempts) > 1 || last.Outcome != agents.OutcomeSucceeded {
+				attemptInfo = fmt.Sprintf(" - %d attempt(s), last %s", len(attempts), last.Outcome)
+			}
+		}
+		fmt.Printf("  [%s] %s (%s)%s%s%s\n", task.Id, task.Name, task.Status, iterationInfo, dependencyInfo, attemptInfo)
 	}
 
 	// Summary line
@@ -486,6 +551,9 @@ func displayTaskDetail(task project.TaskState) error {
 	fmt.Printf("Status: %s\n", task.Status)
 	fmt.Printf("Iteration: %d\n", task.Iteration)
 	fmt.Printf("Assigned Agent: %s\n", task.Assigned_agent)
+	if len(task.Depends_on) > 0 {
+		fmt.Printf("Depends On: %s\n", strings.Join(task.Depends_on, ", "))
+	}
 	fmt.Println()
 
 	// Timestamps
@@ -530,6 +598,23 @@ func displayTaskDetail(task project.TaskState) error {
 		fmt.Println()
 	}
 
+	// Agent attempts
+	if attempts := agents.AttemptsFromMetadata(task.Metadata); len(attempts) > 0 {
+		fmt.Printf("Attempts (%d):\n", len(attempts))
+		for _, attempt := range attempts {
+			outcome := attempt.Outcome
+			if attempt.Outcome == agents.OutcomeFailed && attempt.ExitCode >= 0 {
+				outcome = fmt.Sprintf("%s (exit %d)", outcome, attempt.ExitCode)
+			}
+			fmt.Printf("  [%d] %s on %s at %s, %s: %s\n", attempt.Number, attempt.Action, attempt.Executor,
+				attempt.StartedAt.Local().Format("2006-01-02 15:04:05"), attempt.Duration, outcome)
+			if attempt.Error != "" {
+				fmt.Printf("      └─ %s\n", attempt.Error)
+			}
+		}
+		fmt.Println()
+	}
+
 	// File locations
 	taskDir := filepath.Join(".sow/project/phases", task.Phase, "tasks", task.Id)
 	fmt.Println("Files:")
@@ -538,10 +623,16 @@ func displayTaskDetail(task project.TaskState) error {
 	fmt.Printf("  Feedback:    %s/feedback/\n", taskDir)
 	fmt.Println()
 
-	// Metadata
-	if len(task.Metadata) > 0 {
+	// Metadata (attempts are shown above)
+	metadata := make(map[string]interface{}, len(task.Metadata))
+	for key, value := range task.Metadata {
+		if key != agents.AttemptsMetadataKey {
+			metadata[key] = value
+		}
+	}
+	if len(metadata) > 0 {
 		fmt.Println("Metadata:")
-		for key, value := range task.Metadata {
+		for key, value := range metadata {
 			fmt.Printf("  %s: %v\n", key, value)
 		}
 	}
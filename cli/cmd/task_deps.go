@@ -0,0 +1,288 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jmgilman/sow/cli/internal/cmdutil"
+	"github.com/jmgilman/sow/libs/project/state"
+	"github.com/jmgilman/sow/libs/schemas/project"
+	"github.com/spf13/cobra"
+)
+
+// newTaskNextCmd creates the task next subcommand.
+func newTaskNextCmd() *cobra.Command {
+	var phase string
+
+	cmd := &cobra.Command{
+		Use:   "next",
+		Short: "Show tasks that are ready to start",
+		Long: `Show the pending tasks whose dependencies are all done.
+
+A task is ready when it is pending and every task listed in its depends_on
+is completed or abandoned. Ready tasks are listed in dependency order, so
+the first one is a good choice to start next. Tasks that are not ready are
+summarized with the tasks they are waiting on.
+
+Examples:
+  # Show ready tasks (default phase)
+  sow task next
+
+  # Show ready tasks in specific phase
+  sow task next --phase implementation`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runTaskNext(cmd, phase)
+		},
+	}
+
+	cmd.Flags().StringVar(&phase, "phase", "", "Target phase (defaults to current phase)")
+
+	return cmd
+}
+
+// newTaskGraphCmd creates the task graph subcommand.
+func newTaskGraphCmd() *cobra.Command {
+	var phase, format string
+
+	cmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Show task dependencies",
+		Long: `Show the dependencies between the tasks of a phase.
+
+Tasks are listed in dependency order, each with the tasks it depends on and
+whether it is ready to start or still waiting.
+
+Formats:
+  text     Task list in dependency order (default)
+  mermaid  Mermaid flowchart, for Markdown documents
+  dot      Graphviz, render with 'dot -Tsvg'
+
+Examples:
+  sow task graph
+  sow task graph --phase implementation --format mermaid
+  sow task graph --format dot | dot -Tsvg > tasks.svg`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runTaskGraph(cmd, phase, format)
+		},
+	}
+
+	cmd.Flags().StringVar(&phase, "phase", "", "Target phase (defaults to current phase)")
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: text, mermaid, dot")
+
+	return cmd
+}
+
+// loadTaskPhase loads the active project and returns the name and tasks of
+// the target phase.
+func loadTaskPhase(cmd *cobra.Command, explicitPhase string) (string, []project.TaskState, error) {
+	ctx := cmdutil.GetContext(cmd.Context())
+
+	// Check if sow is initialized
+	if !ctx.IsInitialized() {
+		return "", nil, fmt.Errorf("sow not initialized. Run 'sow init' first")
+	}
+
+	// Load project
+	proj, err := cmdutil.LoadProject(cmd.Context(), ctx)
+	if err != nil {
+		if strings.Contains(err.Error(), "no such file") {
+			return "", nil, fmt.Errorf("no active project found")
+		}
+		return "", nil, fmt.Errorf("failed to load project: %w", err)
+	}
+
+	// Resolve which phase to use
+	phaseName, err := resolveTaskPhase(proj, explicitPhase)
+	if err != nil {
+		return "", nil, err
+	}
+
+	phaseState, exists := proj.Phases[phaseName]
+	if !exists {
+		return "", nil, fmt.Errorf("phase not found: %s", phaseName)
+	}
+
+	return phaseName, phaseState.Tasks, nil
+}
+
+// runTaskNext implements the task next command logic.
+func runTaskNext(cmd *cobra.Command, explicitPhase string) error {
+	phaseName, tasks, err := loadTaskPhase(cmd, explicitPhase)
+	if err != nil {
+		return err
+	}
+
+	ready, err := state.ReadyTasks(tasks)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if len(ready) > 0 {
+		_, _ = fmt.Fprintf(out, "Ready tasks in %s phase:\n", phaseName)
+		for _, task := range ready {
+			_, _ = fmt.Fprintf(out, "  [%s] %s (%s)\n", task.Id, task.Name, task.Assigned_agent)
+		}
+	} else {
+		_, _ = fmt.Fprintf(out, "No tasks ready in %s phase.\n", phaseName)
+	}
+
+	// Summarize the tasks that are not finished and not ready, so it is
+	// clear what the phase is waiting on
+	var waiting []string
+	for _, task := range tasks {
+		if state.DependencySatisfied(task.Status) {
+			continue
+		}
+		if unmet := state.UnmetDependencies(tasks, task); len(unmet) > 0 {
+			waiting = append(waiting, fmt.Sprintf("  [%s] %s - waiting on %s", task.Id, task.Name, strings.Join(unmet, ", ")))
+		} else if task.Status != "pending" {
+			waiting = append(waiting, fmt.Sprintf("  [%s] %s (%s)", task.Id, task.Name, task.Status))
+		}
+	}
+	if len(waiting) > 0 {
+		_, _ = fmt.Fprintln(out, "\nNot ready:")
+		for _, line := range waiting {
+			_, _ = fmt.Fprintln(out, line)
+		}
+	}
+
+	return nil
+}
+
+// runTaskGraph implements the task graph command logic.
+func runTaskGraph(cmd *cobra.Command, explicitPhase, format string) error {
+	phaseName, tasks, err := loadTaskPhase(cmd, explicitPhase)
+	if err != nil {
+		return err
+	}
+
+	ordered, err := state.TopologicalOrder(tasks)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	switch format {
+	case "text":
+		writeTaskGraphText(out, phaseName, tasks, ordered)
+	case "mermaid":
+		writeTaskGraphMermaid(out, tasks, ordered)
+	case "dot":
+		writeTaskGraphDot(out, phaseName, tasks, ordered)
+	default:
+		return fmt.Errorf("unknown format: %s (valid: text, mermaid, dot)", format)
+	}
+
+	return nil
+}
+
+// taskReadiness describes whether a task can start: "ready", "waiting on
+// <ids>", or "" for tasks that are not pending.
+func taskReadiness(tasks []project.TaskState, task project.TaskState) string {
+	unmet := state.UnmetDependencies(tasks, task)
+	switch {
+	case task.Status != "pending":
+		return ""
+	case len(unmet) > 0:
+		return "waiting on " + strings.Join(unmet, ", ")
+	default:
+		return "ready"
+	}
+}
+
+// writeTaskGraphText lists tasks in dependency order with their
+// dependencies and readiness.
+func writeTaskGraphText(out io.Writer, phaseName string, tasks, ordered []project.TaskState) {
+	if len(tasks) == 0 {
+		_, _ = fmt.Fprintf(out, "No tasks found in phase %s.\n", phaseName)
+		return
+	}
+
+	_, _ = fmt.Fprintf(out, "Tasks in %s phase (dependency order):\n", phaseName)
+	for _, task := range ordered {
+		line := fmt.Sprintf("  [%s] %s (%s)", task.Id, task.Name, task.Status)
+		if readiness := taskReadiness(tasks, task); readiness != "" {
+			line += " - " + readiness
+		}
+		_, _ = fmt.Fprintln(out, line)
+		if len(task.Depends_on) > 0 {
+			_, _ = fmt.Fprintf(out, "      └─ depends on %s\n", strings.Join(task.Depends_on, ", "))
+		}
+	}
+}
+
+// taskGraphClass returns the class used to style a task node: its status,
+// or "ready" for pending tasks that can start.
+func taskGraphClass(tasks []project.TaskState, task project.TaskState) string {
+	if taskReadiness(tasks, task) == "ready" {
+		return "ready"
+	}
+	return task.Status
+}
+
+// taskGraphStyles are the Mermaid and DOT fill colors per task class.
+var taskGraphStyles = []struct {
+	class, fill string
+}{
+	{"completed", "#bbf7d0"},
+	{"in_progress", "#bfdbfe"},
+	{"needs_review", "#ddd6fe"},
+	{"ready", "#fde68a"},
+	{"abandoned", "#e5e7eb"},
+}
+
+// writeTaskGraphMermaid renders the dependencies as a Mermaid flowchart
+// with edges from each dependency to the task depending on it.
+func writeTaskGraphMermaid(out io.Writer, tasks, ordered []project.TaskState) {
+	_, _ = fmt.Fprintln(out, "flowchart TD")
+	for _, task := range ordered {
+		label := strings.ReplaceAll(fmt.Sprintf("%s: %s", task.Id, task.Name), `"`, "#quot;")
+		_, _ = fmt.Fprintf(out, "    task_%s[\"%s\"]\n", task.Id, label)
+	}
+	for _, task := range ordered {
+		for _, dep := range task.Depends_on {
+			_, _ = fmt.Fprintf(out, "    task_%s --> task_%s\n", dep, task.Id)
+		}
+	}
+	for _, style := range taskGraphStyles {
+		var ids []string
+		for _, task := range ordered {
+			if taskGraphClass(tasks, task) == style.class {
+				ids = append(ids, "task_"+task.Id)
+			}
+		}
+		if len(ids) > 0 {
+			_, _ = fmt.Fprintf(out, "    classDef %s fill:%s\n", style.class, style.fill)
+			_, _ = fmt.Fprintf(out, "    class %s %s\n", strings.Join(ids, ","), style.class)
+		}
+	}
+}
+
+// writeTaskGraphDot renders the dependencies in Graphviz DOT.
+func writeTaskGraphDot(out io.Writer, phaseName string, tasks, ordered []project.TaskState) {
+	fills := make(map[string]string, len(taskGraphStyles))
+	for _, style := range taskGraphStyles {
+		fills[style.class] = style.fill
+	}
+
+	_, _ = fmt.Fprintf(out, "digraph %q {\n", phaseName)
+	_, _ = fmt.Fprintln(out, "    rankdir=TB;")
+	_, _ = fmt.Fprintln(out, "    node [shape=box, style=\"rounded,filled\", fillcolor=\"#ffffff\"];")
+	for _, task := range ordered {
+		attrs := fmt.Sprintf("label=%q", fmt.Sprintf("%s: %s", task.Id, task.Name))
+		if fill, ok := fills[taskGraphClass(tasks, task)]; ok {
+			attrs += fmt.Sprintf(", fillcolor=%q", fill)
+		}
+		_, _ = fmt.Fprintf(out, "    %q [%s];\n", task.Id, attrs)
+	}
+	for _, task := range ordered {
+		for _, dep := range task.Depends_on {
+			_, _ = fmt.Fprintf(out, "    %q -> %q;\n", dep, task.Id)
+		}
+	}
+	_, _ = fmt.Fprintln(out, "}")
+}
@@ -183,6 +183,13 @@ func runTaskInputAdd(cmd *cobra.Command, taskID, artifactType, path, explicitPha
 		return fmt.Errorf("sow not initialized. Run 'sow init' first")
 	}
 
+	// Lock project state for the load-modify-save cycle
+	lock, err := cmdutil.LockProject(cmd.Context(), ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+
 	// Load project
 	proj, err := cmdutil.LoadProject(cmd.Context(), ctx)
 	if err != nil {
@@ -198,46 +205,52 @@ func runTaskInputAdd(cmd *cobra.Command, taskID, artifactType, path, explicitPha
 		return err
 	}
 
-	// Get phase
-	phaseState, exists := proj.Phases[phaseName]
-	if !exists {
-		return fmt.Errorf("phase not found: %s", phaseName)
-	}
+	var added int
 
-	// Find task by ID
-	taskIndex := -1
-	for i, t := range phaseState.Tasks {
-		if t.Id == taskID {
-			taskIndex = i
-			break
+	// Update and save project state
+	_, err = cmdutil.UpdateProject(cmd.Context(), proj, func(p *state.Project) error {
+		// Get phase
+		phaseState, exists := p.Phases[phaseName]
+		if !exists {
+			return fmt.Errorf("phase not found: %s", phaseName)
 		}
-	}
 
-	if taskIndex == -1 {
-		return fmt.Errorf("task not found: %s", taskID)
-	}
+		// Find task by ID
+		taskIndex := -1
+		for i, t := range phaseState.Tasks {
+			if t.Id == taskID {
+				taskIndex = i
+				break
+			}
+		}
 
-	// Create artifact
-	artifact := project.ArtifactState{
-		Type:       artifactType,
-		Path:       path,
-		Approved:   approved,
-		Created_at: time.Now(),
-		Metadata:   make(map[string]interface{}),
-	}
+		if taskIndex == -1 {
+			return fmt.Errorf("task not found: %s", taskID)
+		}
 
-	// Add to task inputs
-	phaseState.Tasks[taskIndex].Inputs = append(phaseState.Tasks[taskIndex].Inputs, artifact)
-	phaseState.Tasks[taskIndex].Updated_at = time.Now()
+		// Create artifact
+		artifact := project.ArtifactState{
+			Type:       artifactType,
+			Path:       path,
+			Approved:   approved,
+			Created_at: time.Now(),
+			Metadata:   make(map[string]interface{}),
+		}
 
-	proj.Phases[phaseName] = phaseState
+		// Add to task inputs
+		phaseState.Tasks[taskIndex].Inputs = append(phaseState.Tasks[taskIndex].Inputs, artifact)
+		added = len(phaseState.Tasks[taskIndex].Inputs) - 1
+		phaseState.Tasks[taskIndex].Updated_at = time.Now()
 
-	// Save project state
-	if err := proj.Save(cmd.Context()); err != nil {
-		return fmt.Errorf("failed to save project: %w", err)
+		p.Phases[phaseName] = phaseState
+
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	fmt.Printf("Added input artifact [%d] to task [%s]\n", len(phaseState.Tasks[taskIndex].Inputs)-1, taskID)
+	fmt.Printf("Added input artifact [%d] to task [%s]\n", added, taskID)
 	return nil
 }
 
@@ -250,6 +263,13 @@ func runTaskInputSet(cmd *cobra.Command, args []string, taskID, explicitPhase st
 		return fmt.Errorf("sow not initialized. Run 'sow init' first")
 	}
 
+	// Lock project state for the load-modify-save cycle
+	lock, err := cmdutil.LockProject(cmd.Context(), ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+
 	// Load project
 	proj, err := cmdutil.LoadProject(cmd.Context(), ctx)
 	if err != nil {
@@ -265,52 +285,55 @@ func runTaskInputSet(cmd *cobra.Command, args []string, taskID, explicitPhase st
 		return err
 	}
 
-	// Get phase
-	phaseState, exists := proj.Phases[phaseName]
-	if !exists {
-		return fmt.Errorf("phase not found: %s", phaseName)
-	}
+	// Get field path and value from args
+	fieldPath := args[0]
+	value := args[1]
 
-	// Find task by ID
-	taskIndex := -1
-	for i, t := range phaseState.Tasks {
-		if t.Id == taskID {
-			taskIndex = i
-			break
+	// Update and save project state
+	_, err = cmdutil.UpdateProject(cmd.Context(), proj, func(p *state.Project) error {
+		// Get phase
+		phaseState, exists := p.Phases[phaseName]
+		if !exists {
+			return fmt.Errorf("phase not found: %s", phaseName)
 		}
-	}
 
-	if taskIndex == -1 {
-		return fmt.Errorf("task not found: %s", taskID)
-	}
+		// Find task by ID
+		taskIndex := -1
+		for i, t := range phaseState.Tasks {
+			if t.Id == taskID {
+				taskIndex = i
+				break
+			}
+		}
 
-	// Get field path and value from args
-	fieldPath := args[0]
-	value := args[1]
+		if taskIndex == -1 {
+			return fmt.Errorf("task not found: %s", taskID)
+		}
 
-	// Wrap artifacts in state.Artifact for field path mutation
-	artifacts := make([]state.Artifact, len(phaseState.Tasks[taskIndex].Inputs))
-	for i, a := range phaseState.Tasks[taskIndex].Inputs {
-		artifacts[i] = state.Artifact{ArtifactState: a}
-	}
+		// Wrap artifacts in state.Artifact for field path mutation
+		artifacts := make([]state.Artifact, len(phaseState.Tasks[taskIndex].Inputs))
+		for i, a := range phaseState.Tasks[taskIndex].Inputs {
+			artifacts[i] = state.Artifact{ArtifactState: a}
+		}
 
-	// Set field using artifact helper
-	if err := cmdutil.SetArtifactField(&artifacts, index, fieldPath, value); err != nil {
-		return fmt.Errorf("failed to set field: %w", err)
-	}
+		// Set field using artifact helper
+		if err := cmdutil.SetArtifactField(&artifacts, index, fieldPath, value); err != nil {
+			return fmt.Errorf("failed to set field: %w", err)
+		}
 
-	// Unwrap back to ArtifactState
-	for i, a := range artifacts {
-		phaseState.Tasks[taskIndex].Inputs[i] = a.ArtifactState
-	}
+		// Unwrap back to ArtifactState
+		for i, a := range artifacts {
+			phaseState.Tasks[taskIndex].Inputs[i] = a.ArtifactState
+		}
 
-	phaseState.Tasks[taskIndex].Updated_at = time.Now()
+		phaseState.Tasks[taskIndex].Updated_at = time.Now()
 
-	proj.Phases[phaseName] = phaseState
+		p.Phases[phaseName] = phaseState
 
-	// Save project state
-	if err := proj.Save(cmd.Context()); err != nil {
-		return fmt.Errorf("failed to save project: %w", err)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	fmt.Printf("Set %s on input artifact [%d] in task [%s]\n", fieldPath, index, taskID)
@@ -326,6 +349,13 @@ func runTaskInputRemove(cmd *cobra.Command, taskID, explicitPhase string, index
 		return fmt.Errorf("sow not initialized. Run 'sow init' first")
 	}
 
+	// Lock project state for the load-modify-save cycle
+	lock, err := cmdutil.LockProject(cmd.Context(), ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+
 	// Load project
 	proj, err := cmdutil.LoadProject(cmd.Context(), ctx)
 	if err != nil {
@@ -341,42 +371,45 @@ func runTaskInputRemove(cmd *cobra.Command, taskID, explicitPhase string, index
 		return err
 	}
 
-	// Get phase
-	phaseState, exists := proj.Phases[phaseName]
-	if !exists {
-		return fmt.Errorf("phase not found: %s", phaseName)
-	}
+	// Update and save project state
+	_, err = cmdutil.UpdateProject(cmd.Context(), proj, func(p *state.Project) error {
+		// Get phase
+		phaseState, exists := p.Phases[phaseName]
+		if !exists {
+			return fmt.Errorf("phase not found: %s", phaseName)
+		}
 
-	// Find task by ID
-	taskIndex := -1
-	for i, t := range phaseState.Tasks {
-		if t.Id == taskID {
-			taskIndex = i
-			break
+		// Find task by ID
+		taskIndex := -1
+		for i, t := range phaseState.Tasks {
+			if t.Id == taskID {
+				taskIndex = i
+				break
+			}
 		}
-	}
 
-	if taskIndex == -1 {
-		return fmt.Errorf("task not found: %s", taskID)
-	}
+		if taskIndex == -1 {
+			return fmt.Errorf("task not found: %s", taskID)
+		}
 
-	// Validate index
-	if err := cmdutil.IndexInRange(len(phaseState.Tasks[taskIndex].Inputs), index); err != nil {
-		return err
-	}
+		// Validate index
+		if err := cmdutil.IndexInRange(len(phaseState.Tasks[taskIndex].Inputs), index); err != nil {
+			return err
+		}
 
-	// Remove artifact by index
-	phaseState.Tasks[taskIndex].Inputs = append(
-		phaseState.Tasks[taskIndex].Inputs[:index],
-		phaseState.Tasks[taskIndex].Inputs[index+1:]...,
-	)
-	phaseState.Tasks[taskIndex].Updated_at = time.Now()
+		// Remove artifact by index
+		phaseState.Tasks[taskIndex].Inputs = append(
+			phaseState.Tasks[taskIndex].Inputs[:index],
+			phaseState.Tasks[taskIndex].Inputs[index+1:]...,
+		)
+		phaseState.Tasks[taskIndex].Updated_at = time.Now()
 
-	proj.Phases[phaseName] = phaseState
+		p.Phases[phaseName] = phaseState
 
-	// Save project state
-	if err := proj.Save(cmd.Context()); err != nil {
-		return fmt.Errorf("failed to save project: %w", err)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	fmt.Printf("Removed input artifact [%d] from task [%s]\n", index, taskID)
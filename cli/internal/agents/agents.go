@@ -2,7 +2,8 @@
 //
 // This package defines the Agent struct and provides standard agent definitions
 // for common roles in software development: Implementer, Architect, Reviewer,
-// Planner, Researcher, and Decomposer.
+// Planner, Researcher, and Decomposer. Projects can add their own agents in
+// .sow/agents/<name>.md (see LoadCustom).
 //
 // Agents are lightweight configuration (data), not behavior. They are simple
 // structs representing roles that can be used by the executor system to spawn
@@ -46,6 +47,27 @@ type Agent struct {
 	// PromptPath is the path to the embedded prompt template.
 	// Relative to the templates/ directory.
 	PromptPath string
+
+	// Prompt is the prompt of a custom agent, taken from its definition
+	// file. It takes precedence over PromptPath.
+	Prompt string
+
+	// Source is the definition file of a custom agent, relative to .sow/.
+	// Empty for standard agents.
+	Source string
+
+	// Permissions is the agent's tool permission profile, which the
+	// executor translates into CLI flags. The zero value is unrestricted.
+	Permissions Permissions
+}
+
+// LoadPrompt returns the agent's prompt: Prompt if set, otherwise the
+// embedded template at PromptPath.
+func (a *Agent) LoadPrompt() (string, error) {
+	if a.Prompt != "" {
+		return a.Prompt, nil
+	}
+	return LoadPrompt(a.PromptPath)
 }
 
 // Standard agent definitions for the sow multi-agent system.
@@ -65,6 +87,7 @@ var (
 		Description:  "System design and architecture decisions",
 		Capabilities: "Must be able to read/write files, search codebase",
 		PromptPath:   "architect.md",
+		Permissions:  Permissions{AllowedCommands: sowCommands},
 	}
 
 	// Reviewer is the agent responsible for code review and quality assessment.
@@ -73,6 +96,7 @@ var (
 		Description:  "Code review and quality assessment",
 		Capabilities: "Must be able to read files, search codebase, execute shell commands",
 		PromptPath:   "reviewer.md",
+		Permissions:  Permissions{ReadOnly: true, AllowedCommands: reviewerCommands},
 	}
 
 	// Planner is the agent responsible for researching codebase and creating task breakdowns.
@@ -81,6 +105,7 @@ var (
 		Description:  "Research codebase and create comprehensive implementation task breakdown",
 		Capabilities: "Must be able to read files, search codebase, write task descriptions",
 		PromptPath:   "planner.md",
+		Permissions:  Permissions{ReadOnly: true, AllowedCommands: sowCommands},
 	}
 
 	// Researcher is the agent responsible for focused, impartial research.
@@ -89,6 +114,7 @@ var (
 		Description:  "Focused, impartial research with comprehensive source investigation and citation",
 		Capabilities: "Must be able to read files, search codebase, access web resources",
 		PromptPath:   "researcher.md",
+		Permissions:  Permissions{ReadOnly: true, AllowedCommands: sowCommands},
 	}
 
 	// Decomposer is the agent responsible for decomposing complex features into work units.
@@ -97,6 +123,7 @@ var (
 		Description:  "Specialized for decomposing complex features into project-sized, implementable work units",
 		Capabilities: "Must be able to read files, search codebase, write specifications",
 		PromptPath:   "decomposer.md",
+		Permissions:  Permissions{ReadOnly: true, AllowedCommands: sowCommands},
 	}
 )
 
@@ -0,0 +1,171 @@
+package agents
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/jmgilman/go/fs/core"
+	"gopkg.in/yaml.v3"
+)
+
+// CustomDir is the directory holding custom agent definitions, relative
+// to .sow/.
+const CustomDir = "agents"
+
+// customNamePattern restricts custom agent names so that every agent can
+// be bound through a SOW_AGENTS_<NAME> environment variable.
+var customNamePattern = regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`)
+
+// customFrontMatter is the YAML front matter of a custom agent file.
+type customFrontMatter struct {
+	Description  string      `yaml:"description"`
+	Capabilities string      `yaml:"capabilities"`
+	Permissions  Permissions `yaml:"permissions"`
+}
+
+// custom tracks the agents registered by LoadCustom, keyed by name.
+var (
+	custom   = make(map[string]*Agent)
+	customMu sync.RWMutex
+)
+
+// LoadCustom registers the agents defined in .sow/agents/<name>.md, so
+// that NewAgentRegistry includes them.
+//
+// A file that fails to load does not prevent the others from loading; one
+// error is returned per failed file. Names of standard agents are
+// rejected. Loading the same directory again replaces the agents it
+// registered before.
+func LoadCustom(sowFS core.FS) []error {
+	entries, err := sowFS.ReadDir(CustomDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return []error{fmt.Errorf("read %s: %w", CustomDir, err)}
+	}
+
+	var errs []error
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".md" {
+			continue
+		}
+		file := path.Join(CustomDir, entry.Name())
+		src, err := sowFS.ReadFile(file)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("read %s: %w", file, err))
+			continue
+		}
+		agent, err := ParseCustomAgent(file, src)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := registerCustom(agent); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", file, err))
+		}
+	}
+	return errs
+}
+
+// registerCustom adds a custom agent to the set NewAgentRegistry includes.
+func registerCustom(agent *Agent) error {
+	for _, standard := range StandardAgents() {
+		if standard.Name == agent.Name {
+			return fmt.Errorf("agent %q is a standard agent", agent.Name)
+		}
+	}
+
+	customMu.Lock()
+	defer customMu.Unlock()
+	custom[agent.Name] = agent
+	return nil
+}
+
+// ParseCustomAgent parses a custom agent definition. file is the path of
+// the definition relative to .sow/; its base name is the agent name.
+//
+// The definition is Markdown with YAML front matter:
+//
+//	---
+//	description: Reviews changes for security issues
+//	capabilities: Must be able to read files and search the codebase
+//	permissions:
+//	  read_only: true
+//	  allowed_commands: ["sow *", "git diff *"]
+//	---
+//	You are a security reviewer...
+//
+// The description is required; permissions (see Permissions) default to
+// unrestricted. The body after the front matter is the agent prompt.
+func ParseCustomAgent(file string, src []byte) (*Agent, error) {
+	name := strings.TrimSuffix(path.Base(file), ".md")
+	if !customNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("%s: agent name %q must be lowercase letters, digits, and dashes", file, name)
+	}
+
+	front, body, err := splitFrontMatter(src)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	var meta customFrontMatter
+	if err := yaml.Unmarshal(front, &meta); err != nil {
+		return nil, fmt.Errorf("%s: invalid front matter: %w", file, err)
+	}
+	if meta.Description == "" {
+		return nil, fmt.Errorf("%s: front matter must set description", file)
+	}
+	if err := meta.Permissions.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	prompt := strings.TrimSpace(string(body))
+	if prompt == "" {
+		return nil, fmt.Errorf("%s: agent prompt is empty", file)
+	}
+
+	return &Agent{
+		Name:         name,
+		Description:  meta.Description,
+		Capabilities: meta.Capabilities,
+		Prompt:       prompt + "\n",
+		Source:       file,
+		Permissions:  meta.Permissions,
+	}, nil
+}
+
+// splitFrontMatter splits src into the YAML between the leading "---"
+// lines and the body that follows.
+func splitFrontMatter(src []byte) (front, body []byte, err error) {
+	src = bytes.ReplaceAll(src, []byte("\r\n"), []byte("\n"))
+	rest, ok := bytes.CutPrefix(src, []byte("---\n"))
+	if !ok {
+		return nil, nil, errors.New("missing front matter (the file must start with ---)")
+	}
+	if front, body, ok = bytes.Cut(rest, []byte("\n---\n")); ok {
+		return front, body, nil
+	}
+	if front, ok = bytes.CutSuffix(rest, []byte("\n---")); ok {
+		return front, nil, nil
+	}
+	return nil, nil, errors.New("front matter is not closed with ---")
+}
+
+// CustomAgents returns the agents registered by LoadCustom, sorted by name.
+func CustomAgents() []*Agent {
+	customMu.RLock()
+	defer customMu.RUnlock()
+
+	agents := make([]*Agent, 0, len(custom))
+	for _, agent := range custom {
+		agents = append(agents, agent)
+	}
+	sort.Slice(agents, func(i, j int) bool { return agents[i].Name < agents[j].Name })
+	return agents
+}
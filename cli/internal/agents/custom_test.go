@@ -0,0 +1,198 @@
+package agents
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jmgilman/go/fs/billy"
+	"github.com/jmgilman/go/fs/core"
+)
+
+// securityReviewer is a valid custom agent definition.
+const securityReviewer = `---
+description: Reviews changes for security issues
+capabilities: Must be able to read files and search the codebase
+---
+You are a security reviewer.
+`
+
+// newAgentsFS returns an in-memory .sow filesystem holding the given files
+// under agents/. Custom agents registered from it are removed when the
+// test ends, since the set of custom agents is global.
+func newAgentsFS(t *testing.T, files map[string]string) core.FS {
+	t.Helper()
+
+	memFS := billy.NewMemory()
+	if err := memFS.MkdirAll(CustomDir, 0755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	for name, content := range files {
+		if err := memFS.WriteFile(CustomDir+"/"+name, []byte(content), 0644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+	t.Cleanup(func() {
+		customMu.Lock()
+		defer customMu.Unlock()
+		for name := range files {
+			delete(custom, strings.TrimSuffix(name, ".md"))
+		}
+	})
+	return memFS
+}
+
+// TestLoadCustom verifies custom agents are registered alongside the
+// standard agents and failures are reported per file.
+func TestLoadCustom(t *testing.T) {
+	sowFS := newAgentsFS(t, map[string]string{
+		"security-reviewer.md": securityReviewer,
+		"reviewer.md":          securityReviewer,
+		"broken.md":            "no front matter",
+		"notes.txt":            "not an agent",
+	})
+
+	errs := LoadCustom(sowFS)
+	if len(errs) != 2 {
+		t.Fatalf("LoadCustom() returned %d errors, want 2: %v", len(errs), errs)
+	}
+	joined := errs[0].Error() + "\n" + errs[1].Error()
+	if !strings.Contains(joined, `agents/reviewer.md: agent "reviewer" is a standard agent`) {
+		t.Errorf("expected standard agent error, got %v", errs)
+	}
+	if !strings.Contains(joined, "agents/broken.md: missing front matter") {
+		t.Errorf("expected front matter error, got %v", errs)
+	}
+
+	registry := NewAgentRegistry()
+	agent, err := registry.Get("security-reviewer")
+	if err != nil {
+		t.Fatalf("Get(security-reviewer) error = %v", err)
+	}
+	if agent.Source != "agents/security-reviewer.md" {
+		t.Errorf("Source = %q, want %q", agent.Source, "agents/security-reviewer.md")
+	}
+	if len(registry.List()) != len(StandardAgents())+1 {
+		t.Errorf("registry has %d agents, want standard agents plus one", len(registry.List()))
+	}
+
+	// Loading again replaces the agent instead of failing
+	if errs := LoadCustom(sowFS); len(errs) != 2 {
+		t.Errorf("second LoadCustom() returned %d errors, want 2: %v", len(errs), errs)
+	}
+}
+
+// TestLoadCustom_MissingDirectory verifies a project without custom agents
+// loads nothing.
+func TestLoadCustom_MissingDirectory(t *testing.T) {
+	if errs := LoadCustom(billy.NewMemory()); len(errs) != 0 {
+		t.Errorf("LoadCustom() errors = %v, want none", errs)
+	}
+}
+
+// TestParseCustomAgent verifies front matter and prompt parsing.
+func TestParseCustomAgent(t *testing.T) {
+	agent, err := ParseCustomAgent("agents/security-reviewer.md", []byte(securityReviewer))
+	if err != nil {
+		t.Fatalf("ParseCustomAgent() error = %v", err)
+	}
+	if agent.Name != "security-reviewer" {
+		t.Errorf("Name = %q, want %q", agent.Name, "security-reviewer")
+	}
+	if agent.Description != "Reviews changes for security issues" {
+		t.Errorf("Description = %q", agent.Description)
+	}
+	if agent.Capabilities != "Must be able to read files and search the codebase" {
+		t.Errorf("Capabilities = %q", agent.Capabilities)
+	}
+	prompt, err := agent.LoadPrompt()
+	if err != nil || prompt != "You are a security reviewer.\n" {
+		t.Errorf("LoadPrompt() = %q, %v; want the body", prompt, err)
+	}
+	if !agent.Permissions.IsZero() {
+		t.Errorf("Permissions = %+v, want unrestricted", agent.Permissions)
+	}
+
+	restricted, err := ParseCustomAgent("agents/auditor.md", []byte(
+		"---\ndescription: Audits\npermissions:\n  read_only: true\n  denied_tools: [WebFetch]\n  allowed_commands: [\"git diff *\"]\n---\nAudit.\n",
+	))
+	if err != nil {
+		t.Fatalf("ParseCustomAgent() error = %v", err)
+	}
+	if got := restricted.Permissions.String(); got != "read-only; deny: WebFetch; shell: git diff *" {
+		t.Errorf("Permissions = %q", got)
+	}
+
+	tests := []struct {
+		name    string
+		file    string
+		src     string
+		wantErr string
+	}{
+		{
+			name:    "invalid name",
+			file:    "agents/Security_Reviewer.md",
+			src:     securityReviewer,
+			wantErr: `agent name "Security_Reviewer" must be lowercase`,
+		},
+		{
+			name:    "unclosed front matter",
+			file:    "agents/a.md",
+			src:     "---\ndescription: x\n",
+			wantErr: "front matter is not closed",
+		},
+		{
+			name:    "missing description",
+			file:    "agents/a.md",
+			src:     "---\ncapabilities: x\n---\nPrompt\n",
+			wantErr: "front matter must set description",
+		},
+		{
+			name:    "empty prompt",
+			file:    "agents/a.md",
+			src:     "---\ndescription: x\n---\n",
+			wantErr: "agent prompt is empty",
+		},
+		{
+			name:    "blank permission entry",
+			file:    "agents/a.md",
+			src:     "---\ndescription: x\npermissions:\n  allowed_tools: [\"\"]\n---\nPrompt\n",
+			wantErr: "permissions.allowed_tools must not contain empty entries",
+		},
+		{
+			name:    "invalid yaml",
+			file:    "agents/a.md",
+			src:     "---\ndescription: [x\n---\nPrompt\n",
+			wantErr: "invalid front matter",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseCustomAgent(tt.file, []byte(tt.src))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseCustomAgent() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestResolveExecutorName verifies bindings apply to any agent name and
+// unbound agents use the default executor.
+func TestResolveExecutorName(t *testing.T) {
+	bindings := map[string]string{
+		"implementer":       "cursor",
+		"security-reviewer": "gemini",
+	}
+	tests := map[string]string{
+		"implementer":       "cursor",
+		"security-reviewer": "gemini",
+		"reviewer":          DefaultExecutorName,
+	}
+	for agent, want := range tests {
+		if got := resolveExecutorName(agent, bindings); got != want {
+			t.Errorf("resolveExecutorName(%q) = %q, want %q", agent, got, want)
+		}
+	}
+	if got := resolveExecutorName("implementer", nil); got != DefaultExecutorName {
+		t.Errorf("resolveExecutorName with nil bindings = %q, want %q", got, DefaultExecutorName)
+	}
+}
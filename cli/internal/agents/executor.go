@@ -3,12 +3,14 @@ package agents
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/jmgilman/sow/cli/internal/agents/logformat"
 )
@@ -55,6 +57,14 @@ type Executor interface {
 	ValidateAvailability() error
 }
 
+// SessionChecker is implemented by executors that can tell whether a
+// session was created. A spawn that failed is only retried by resuming its
+// session once the session is known to exist.
+type SessionChecker interface {
+	// SessionStarted reports whether the session exists and can be resumed.
+	SessionStarted(sessionID string) bool
+}
+
 // CommandRunner abstracts subprocess execution for testability.
 // In production, this is backed by os/exec. In tests, it's mocked.
 //
@@ -79,7 +89,49 @@ type CommandRunner interface {
 // For example, if outputPath is "session-123.log", it creates:
 //   - session-123.json (raw JSON)
 //   - session-123.log (formatted)
-type DefaultCommandRunner struct{}
+//
+// If PlainOutput is set, stdout is not stream-json: it is appended to
+// outputPath as is and no raw JSON file is written.
+type DefaultCommandRunner struct {
+	PlainOutput bool
+}
+
+// workspaceKey is the context key for the workspace set by WithWorkspace.
+type workspaceKey struct{}
+
+// workspace is where DefaultCommandRunner runs a command.
+type workspace struct {
+	dir string
+	env []string
+}
+
+// WithWorkspace returns a context under which DefaultCommandRunner runs
+// commands in dir instead of the current directory, with env ("KEY=value")
+// added to the inherited environment. It is used to run an agent inside a
+// task's own git worktree.
+func WithWorkspace(ctx context.Context, dir string, env ...string) context.Context {
+	return context.WithValue(ctx, workspaceKey{}, workspace{dir: dir, env: env})
+}
+
+// commandWaitDelay bounds how long a command killed because its context
+// ended (e.g. a policy timeout) may keep its output open before Run returns.
+const commandWaitDelay = 5 * time.Second
+
+// workspaceCommand returns a command for name and args that runs in the
+// workspace carried by ctx, if any (see WithWorkspace). The command leads
+// its own process group, which is killed when ctx ends.
+func workspaceCommand(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.WaitDelay = commandWaitDelay
+	setProcessGroup(cmd)
+	if ws, ok := ctx.Value(workspaceKey{}).(workspace); ok {
+		cmd.Dir = ws.dir
+		if len(ws.env) > 0 {
+			cmd.Env = append(os.Environ(), ws.env...)
+		}
+	}
+	return cmd
+}
 
 // Run executes a command with the given arguments and stdin.
 //
@@ -87,14 +139,21 @@ type DefaultCommandRunner struct{}
 //   - Raw JSON to {base}.json
 //   - Formatted output to {base}.log (or the original path if not .log)
 //
+// With PlainOutput, stdout and stderr are appended to outputPath unchanged.
+//
 // The output files are created/appended, and the directory is created if needed.
 // If outputPath is empty, output is discarded (but stderr is still captured for error messages).
 //
+// If ctx carries a workspace (see WithWorkspace), the command runs in the
+// workspace directory with its environment. The started process is
+// reported to the observer carried by ctx (see WithProcessObserver), and
+// interrupts sow receives while it runs are forwarded to its process group.
+//
 // Note: Agent commands are invoked by the orchestrator, not humans, so
 // terminal output is not needed. The orchestrator reads state.yaml after
 // the subprocess exits to determine the outcome.
 func (r *DefaultCommandRunner) Run(ctx context.Context, name string, args []string, stdin io.Reader, outputPath string) error {
-	cmd := exec.CommandContext(ctx, name, args...)
+	cmd := workspaceCommand(ctx, name, args...)
 	cmd.Stdin = stdin
 
 	// Always capture stderr for error messages
@@ -110,35 +169,51 @@ func (r *DefaultCommandRunner) Run(ctx context.Context, name string, args []stri
 			return fmt.Errorf("failed to create output directory: %w", err)
 		}
 
-		// Determine file paths for raw JSON and formatted output
-		rawPath, formattedPath := splitOutputPaths(outputPath)
+		if r.PlainOutput {
+			logFile, err := os.OpenFile(outputPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+			if err != nil {
+				return fmt.Errorf("failed to open output file: %w", err)
+			}
+			defer func() { _ = logFile.Close() }()
 
-		// Open raw JSON file for appending
-		rawFile, err := os.OpenFile(rawPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
-		if err != nil {
-			return fmt.Errorf("failed to open raw output file: %w", err)
+			cmd.Stdout = logFile
+			cmd.Stderr = io.MultiWriter(logFile, &stderrBuf)
+			return r.wait(ctx, cmd, name, nil, &stderrBuf)
 		}
-		defer func() { _ = rawFile.Close() }()
 
-		// Open formatted output file for appending
-		formattedFile, err := os.OpenFile(formattedPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		log, err := openStreamLog(outputPath)
 		if err != nil {
-			return fmt.Errorf("failed to open formatted output file: %w", err)
+			return err
 		}
-		defer func() { _ = formattedFile.Close() }()
-
-		// Create a dual writer that writes raw JSON and formatted output
-		dualWriter = logformat.NewDualWriter(rawFile, formattedFile)
+		defer func() { _ = log.Close() }()
+		dualWriter = log.writer
 
 		cmd.Stdout = dualWriter
 		// Write stderr to raw file, formatted file, and buffer (for error messages)
-		cmd.Stderr = io.MultiWriter(rawFile, formattedFile, &stderrBuf)
+		cmd.Stderr = io.MultiWriter(log.raw, log.formatted, &stderrBuf)
 	} else {
 		// No file output, but still capture stderr for error messages
 		cmd.Stderr = &stderrBuf
 	}
 
-	runErr := cmd.Run()
+	return r.wait(ctx, cmd, name, dualWriter, &stderrBuf)
+}
+
+// wait runs cmd to completion, flushes dualWriter if set, and turns a
+// failure into an error carrying the captured stderr.
+func (r *DefaultCommandRunner) wait(ctx context.Context, cmd *exec.Cmd, name string, dualWriter *logformat.DualWriter, stderrBuf *bytes.Buffer) error {
+	runErr := cmd.Start()
+	if runErr == nil {
+		startedAt, ok := ProcessStartTime(cmd.Process.Pid)
+		if !ok {
+			startedAt = time.Now()
+		}
+		exited := observeProcess(ctx, cmd.Process.Pid, startedAt)
+		stopForwarding := forwardSignals(cmd.Process.Pid)
+		runErr = cmd.Wait()
+		stopForwarding()
+		exited()
+	}
 
 	// Flush any buffered formatted output before returning
 	if dualWriter != nil {
@@ -155,6 +230,79 @@ func (r *DefaultCommandRunner) Run(ctx context.Context, name string, args []stri
 	return nil
 }
 
+// streamLog is an open pair of session log files: the raw stream-json
+// events and their formatted rendering.
+type streamLog struct {
+	raw       *os.File
+	formatted *os.File
+	writer    *logformat.DualWriter
+}
+
+// openStreamLog opens the raw and formatted log files for outputPath for
+// appending (see splitOutputPaths) and logs the start of a run. The output
+// directory must exist.
+func openStreamLog(outputPath string) (*streamLog, error) {
+	// Determine file paths for raw JSON and formatted output
+	rawPath, formattedPath := splitOutputPaths(outputPath)
+
+	// Open raw JSON file for appending
+	rawFile, err := os.OpenFile(rawPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open raw output file: %w", err)
+	}
+
+	// Open formatted output file for appending
+	formattedFile, err := os.OpenFile(formattedPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		_ = rawFile.Close()
+		return nil, fmt.Errorf("failed to open formatted output file: %w", err)
+	}
+
+	// Create a dual writer that writes raw JSON and formatted output
+	log := &streamLog{
+		raw:       rawFile,
+		formatted: formattedFile,
+		writer:    logformat.NewDualWriter(rawFile, formattedFile),
+	}
+
+	// Date the events of this run, which carry no timestamps of their own
+	if marker, err := json.Marshal(logformat.NewRunStartEvent(time.Now())); err == nil {
+		_, _ = log.writer.Write(append(marker, '\n'))
+	}
+	return log, nil
+}
+
+// Close flushes buffered formatted output and closes both files.
+func (l *streamLog) Close() error {
+	_ = l.writer.Flush()
+	rawErr := l.raw.Close()
+	if err := l.formatted.Close(); err != nil {
+		return err
+	}
+	return rawErr
+}
+
+// sessionLogged reports whether the raw log of outputPath holds an event
+// of the agent's session. Run start markers and stderr output, which are
+// logged before the agent gets to create its session, do not count.
+func sessionLogged(outputPath string) bool {
+	if outputPath == "" {
+		return false
+	}
+	rawPath, _ := splitOutputPaths(outputPath)
+	data, err := os.ReadFile(rawPath)
+	if err != nil {
+		return false
+	}
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		event, err := logformat.ParseEvent(line)
+		if err == nil && event.SessionID != "" {
+			return true
+		}
+	}
+	return false
+}
+
 // splitOutputPaths determines the raw JSON and formatted output paths.
 // If outputPath ends in .log, the raw path uses .json extension.
 // Otherwise, raw uses .json suffix and formatted uses .log suffix.
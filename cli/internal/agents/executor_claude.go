@@ -23,7 +23,7 @@ import (
 //	    return fmt.Errorf("failed to spawn: %w", err)
 //	}
 type ClaudeExecutor struct {
-	yoloMode   bool     // When true, adds --dangerously-skip-permissions flag
+	yoloMode   bool     // When true, adds --dangerously-skip-permissions for unrestricted agents
 	model      string   // Model to use (e.g., "sonnet", "opus"), empty for default
 	outputDir  string   // Directory for output logs (empty disables logging)
 	customArgs []string // Additional CLI arguments from user config
@@ -91,6 +91,8 @@ func (e *ClaudeExecutor) outputPath(sessionID string) string {
 // The --print flag is always used to run in non-interactive mode.
 // This ensures the agent processes the prompt and exits without waiting
 // for user input, which is required for subprocess-based agent execution.
+// The agent's permission profile becomes --permission-mode, --allowedTools
+// and --disallowedTools flags.
 //
 // Parameters:
 //   - ctx: context for cancellation
@@ -101,7 +103,7 @@ func (e *ClaudeExecutor) outputPath(sessionID string) string {
 // Returns error if prompt loading fails or subprocess execution fails.
 func (e *ClaudeExecutor) Spawn(ctx context.Context, agent *Agent, prompt string, sessionID string) error {
 	// Load agent prompt template
-	agentPrompt, err := LoadPrompt(agent.PromptPath)
+	agentPrompt, err := agent.LoadPrompt()
 	if err != nil {
 		return fmt.Errorf("failed to load agent prompt: %w", err)
 	}
@@ -111,11 +113,8 @@ func (e *ClaudeExecutor) Spawn(ctx context.Context, agent *Agent, prompt string,
 
 	// Build args - always use --print for non-interactive mode
 	// Use stream-json for real-time output to log files (requires --verbose)
-	// Use acceptEdits permission mode to allow file writes without full bypass
-	args := []string{"--print", "--verbose", "--output-format", "stream-json", "--permission-mode", "acceptEdits"}
-	if e.yoloMode {
-		args = append(args, "--dangerously-skip-permissions")
-	}
+	args := []string{"--print", "--verbose", "--output-format", "stream-json"}
+	args = append(args, e.permissionArgs(agent.Permissions)...)
 	if e.model != "" {
 		args = append(args, "--model", e.model)
 	}
@@ -136,7 +135,8 @@ func (e *ClaudeExecutor) Spawn(ctx context.Context, agent *Agent, prompt string,
 }
 
 // Resume continues an existing Claude session with additional prompt.
-// It uses the --resume flag to resume the session identified by sessionID.
+// It uses the --resume flag to resume the session identified by sessionID,
+// under the permission profile set with WithPermissions.
 //
 // The method blocks until the subprocess exits.
 //
@@ -153,9 +153,11 @@ func (e *ClaudeExecutor) Spawn(ctx context.Context, agent *Agent, prompt string,
 func (e *ClaudeExecutor) Resume(ctx context.Context, sessionID string, prompt string) error {
 	// Build args - always use --print for non-interactive mode
 	// Use stream-json for real-time output to log files (requires --verbose)
-	// Use acceptEdits permission mode to allow file writes without full bypass
-	args := []string{"--print", "--verbose", "--output-format", "stream-json", "--permission-mode", "acceptEdits", "--resume", sessionID}
-	if e.yoloMode {
+	permissions := permissionsFromContext(ctx)
+	args := []string{"--print", "--verbose", "--output-format", "stream-json"}
+	args = append(args, permissions.claudeArgs()...)
+	args = append(args, "--resume", sessionID)
+	if e.yoloMode && !permissions.Restricted() {
 		args = append(args, "--dangerously-skip-permissions")
 	}
 
@@ -171,12 +173,29 @@ func (e *ClaudeExecutor) Resume(ctx context.Context, sessionID string, prompt st
 	return nil
 }
 
+// permissionArgs returns the permission flags for an agent's profile.
+// The profile sets the permission mode and tool rules; yolo mode adds
+// --dangerously-skip-permissions only for profiles it would not defeat.
+func (e *ClaudeExecutor) permissionArgs(permissions Permissions) []string {
+	args := permissions.claudeArgs()
+	if e.yoloMode && !permissions.Restricted() {
+		args = append(args, "--dangerously-skip-permissions")
+	}
+	return args
+}
+
 // SupportsResumption indicates that Claude Code supports session resumption.
 // Claude Code's --resume flag allows continuing existing sessions.
 func (e *ClaudeExecutor) SupportsResumption() bool {
 	return true
 }
 
+// SessionStarted reports whether Claude Code logged an event of the
+// session, which it only does once the session was created.
+func (e *ClaudeExecutor) SessionStarted(sessionID string) bool {
+	return sessionLogged(e.outputPath(sessionID))
+}
+
 // ValidateAvailability checks if the claude CLI binary is available on PATH.
 // Returns nil if available, error with installation guidance if not.
 func (e *ClaudeExecutor) ValidateAvailability() error {
@@ -188,4 +207,7 @@ func (e *ClaudeExecutor) ValidateAvailability() error {
 }
 
 // Compile-time check that ClaudeExecutor implements Executor.
-var _ Executor = (*ClaudeExecutor)(nil)
+var (
+	_ Executor       = (*ClaudeExecutor)(nil)
+	_ SessionChecker = (*ClaudeExecutor)(nil)
+)
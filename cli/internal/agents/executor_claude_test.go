@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"io"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -411,3 +413,31 @@ func TestClaudeExecutor_ValidateAvailability(t *testing.T) {
 	// We can't predict if "claude" is on PATH, so just verify no panic
 	_ = err
 }
+
+// TestClaudeExecutor_SessionStarted verifies a session counts as started
+// once Claude logged one of its events, not just sow's run marker and stderr.
+func TestClaudeExecutor_SessionStarted(t *testing.T) {
+	dir := t.TempDir()
+	executor := NewClaudeExecutor(false, "", dir, nil)
+	raw := filepath.Join(dir, "session-1.json")
+
+	if executor.SessionStarted("session-1") {
+		t.Error("SessionStarted() = true without a log, want false")
+	}
+
+	log := `{"type":"system","subtype":"run_start","timestamp":"2025-01-15T10:00:00Z"}` + "\nError: invalid API key\n"
+	if err := os.WriteFile(raw, []byte(log), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if executor.SessionStarted("session-1") {
+		t.Error("SessionStarted() = true with only a run marker and stderr, want false")
+	}
+
+	log += `{"type":"system","subtype":"init","session_id":"session-1"}` + "\n"
+	if err := os.WriteFile(raw, []byte(log), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !executor.SessionStarted("session-1") {
+		t.Error("SessionStarted() = false after the init event, want true")
+	}
+}
@@ -0,0 +1,253 @@
+package agents
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// Prompt delivery modes for CommandSpec.Prompt.
+const (
+	// PromptStdin writes the prompt to the program's stdin.
+	PromptStdin = "stdin"
+	// PromptArgv passes the prompt only through the {{.Prompt}} argument.
+	PromptArgv = "argv"
+)
+
+// Output formats for CommandSpec.Output.
+const (
+	// OutputText logs the program's stdout as is.
+	OutputText = "text"
+	// OutputStreamJSON parses stdout as Claude Code stream-json events and
+	// keeps both the raw events and a formatted log.
+	OutputStreamJSON = "stream-json"
+)
+
+// CommandSpec describes how a CommandExecutor invokes its program.
+//
+// SpawnArgs and ResumeArgs are Go templates rendered with CommandArgs.
+// Arguments that render to an empty string are dropped, so optional flags
+// can be written as "{{if .Model}}--model={{.Model}}{{end}}". The join
+// function joins a list, e.g. "{{join .Permissions.DeniedTools \",\"}}".
+type CommandSpec struct {
+	Program    string   // Program to run, looked up on PATH
+	SpawnArgs  []string // Argument templates for a new session
+	ResumeArgs []string // Argument templates for resuming (empty disables resumption)
+	Prompt     string   // PromptStdin (default) or PromptArgv
+	Output     string   // OutputText (default) or OutputStreamJSON
+	Model      string   // Value of {{.Model}}
+	CustomArgs []string // Additional arguments appended verbatim
+}
+
+// CommandArgs is the data the argument templates of a CommandSpec are
+// rendered with.
+type CommandArgs struct {
+	// Prompt is the full prompt: the agent prompt followed by the task
+	// prompt when spawning, the resume prompt when resuming.
+	Prompt string
+	// TaskPrompt is the task prompt (or resume prompt) alone.
+	TaskPrompt string
+	// SessionID is the session sow assigned to the task.
+	SessionID string
+	// Model is the configured model, if any.
+	Model string
+	// AgentPromptPath is a temporary file holding the agent prompt. It is
+	// only set when spawning and is removed once the program exits.
+	AgentPromptPath string
+	// Permissions is the agent's permission profile, to be mapped onto
+	// the program's own flags.
+	Permissions Permissions
+}
+
+// CommandExecutor implements Executor for an arbitrary agent CLI described
+// by a CommandSpec in the user config (executor type "command"). It lets
+// tools such as aider or gemini-cli be plugged in without code changes.
+//
+// Example usage:
+//
+//	executor := NewCommandExecutor("aider", CommandSpec{
+//	    Program:   "aider",
+//	    SpawnArgs: []string{"--yes", "--message", "{{.Prompt}}"},
+//	    Prompt:    PromptArgv,
+//	}, ".sow/project/agent-outputs")
+//	err := executor.Spawn(ctx, agents.Implementer, "Execute task", sessionID)
+type CommandExecutor struct {
+	name      string
+	spec      CommandSpec
+	outputDir string // Directory for output logs (empty disables logging)
+	runner    CommandRunner
+}
+
+// NewCommandExecutor creates a CommandExecutor registered under name.
+// It uses DefaultCommandRunner for subprocess execution, logging output as
+// plain text unless spec.Output is OutputStreamJSON.
+func NewCommandExecutor(name string, spec CommandSpec, outputDir string) *CommandExecutor {
+	return NewCommandExecutorWithRunner(name, spec, outputDir, &DefaultCommandRunner{
+		PlainOutput: spec.Output != OutputStreamJSON,
+	})
+}
+
+// NewCommandExecutorWithRunner creates a CommandExecutor with a custom
+// CommandRunner. This is primarily for testing to inject mock command execution.
+func NewCommandExecutorWithRunner(name string, spec CommandSpec, outputDir string, runner CommandRunner) *CommandExecutor {
+	return &CommandExecutor{
+		name:      name,
+		spec:      spec,
+		outputDir: outputDir,
+		runner:    runner,
+	}
+}
+
+// Name returns the name the executor is configured under.
+func (e *CommandExecutor) Name() string {
+	return e.name
+}
+
+// outputPath returns the path for saving output logs for a given session.
+// Returns empty string if outputDir is not configured.
+func (e *CommandExecutor) outputPath(sessionID string) string {
+	if e.outputDir == "" || sessionID == "" {
+		return ""
+	}
+	return filepath.Join(e.outputDir, sessionID+".log")
+}
+
+// Spawn runs the program with the rendered spawn arguments.
+// The agent prompt is loaded, combined with the task prompt into
+// {{.Prompt}}, and written to a temporary file for {{.AgentPromptPath}}.
+//
+// The method blocks until the subprocess exits.
+func (e *CommandExecutor) Spawn(ctx context.Context, agent *Agent, prompt string, sessionID string) error {
+	agentPrompt, err := agent.LoadPrompt()
+	if err != nil {
+		return fmt.Errorf("failed to load agent prompt: %w", err)
+	}
+
+	promptFile, err := os.CreateTemp("", "sow-agent-*.md")
+	if err != nil {
+		return fmt.Errorf("failed to write agent prompt: %w", err)
+	}
+	defer func() { _ = os.Remove(promptFile.Name()) }()
+	_, err = promptFile.WriteString(agentPrompt)
+	if closeErr := promptFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write agent prompt: %w", err)
+	}
+
+	data := CommandArgs{
+		Prompt:          agentPrompt + "\n\n" + prompt,
+		TaskPrompt:      prompt,
+		SessionID:       sessionID,
+		Model:           e.spec.Model,
+		AgentPromptPath: promptFile.Name(),
+		Permissions:     agent.Permissions,
+	}
+	if err := e.run(ctx, e.spec.SpawnArgs, data); err != nil {
+		return fmt.Errorf("%s spawn failed: %w", e.name, err)
+	}
+	return nil
+}
+
+// Resume runs the program with the rendered resume arguments, under the
+// permission profile set with WithPermissions. Returns an error if the spec has no resume arguments.
+//
+// The method blocks until the subprocess exits.
+func (e *CommandExecutor) Resume(ctx context.Context, sessionID string, prompt string) error {
+	if !e.SupportsResumption() {
+		return fmt.Errorf("executor %s does not support resumption (no resume_args configured)", e.name)
+	}
+
+	data := CommandArgs{
+		Prompt:      prompt,
+		TaskPrompt:  prompt,
+		SessionID:   sessionID,
+		Model:       e.spec.Model,
+		Permissions: permissionsFromContext(ctx),
+	}
+	if err := e.run(ctx, e.spec.ResumeArgs, data); err != nil {
+		return fmt.Errorf("%s resume failed: %w", e.name, err)
+	}
+	return nil
+}
+
+// run renders templates with data and runs the program, delivering the
+// prompt on stdin unless the spec passes it through the arguments.
+func (e *CommandExecutor) run(ctx context.Context, templates []string, data CommandArgs) error {
+	args, err := renderArgs(templates, data)
+	if err != nil {
+		return err
+	}
+	args = append(args, e.spec.CustomArgs...)
+
+	var stdin io.Reader
+	if e.spec.Prompt != PromptArgv {
+		stdin = strings.NewReader(data.Prompt)
+	}
+	return e.runner.Run(ctx, e.spec.Program, args, stdin, e.outputPath(data.SessionID))
+}
+
+// renderArgs renders each argument template with data, dropping arguments
+// that render to an empty string.
+func renderArgs(templates []string, data CommandArgs) ([]string, error) {
+	args := make([]string, 0, len(templates))
+	for _, text := range templates {
+		arg, err := renderTemplate(text, data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument %q: %w", text, err)
+		}
+		if arg != "" {
+			args = append(args, arg)
+		}
+	}
+	return args, nil
+}
+
+// templateFuncs are the functions available to argument templates.
+var templateFuncs = template.FuncMap{"join": strings.Join}
+
+// renderTemplate renders the Go template text with data.
+func renderTemplate(text string, data any) (string, error) {
+	tmpl, err := template.New("").Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// SupportsResumption reports whether resume arguments are configured.
+func (e *CommandExecutor) SupportsResumption() bool {
+	return len(e.spec.ResumeArgs) > 0
+}
+
+// SessionStarted reports whether the program logged an event of the
+// session. Programs with plain text output cannot tell.
+func (e *CommandExecutor) SessionStarted(sessionID string) bool {
+	return e.spec.Output == OutputStreamJSON && sessionLogged(e.outputPath(sessionID))
+}
+
+// ValidateAvailability checks if the program is available on PATH.
+// Returns nil if available, error with guidance if not.
+func (e *CommandExecutor) ValidateAvailability() error {
+	if _, err := exec.LookPath(e.spec.Program); err != nil {
+		return fmt.Errorf("%s not found on PATH (executor %s): %w\n\nInstall it or fix command.program in ~/.config/sow/config.yaml", e.spec.Program, e.name, err)
+	}
+	return nil
+}
+
+// Compile-time check that CommandExecutor implements Executor.
+var (
+	_ Executor       = (*CommandExecutor)(nil)
+	_ SessionChecker = (*CommandExecutor)(nil)
+)
@@ -0,0 +1,277 @@
+package agents
+
+import (
+	"context"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jmgilman/sow/libs/schemas"
+	"gopkg.in/yaml.v3"
+)
+
+// testCommandSpec returns a spec for a fictional agent CLI.
+func testCommandSpec() CommandSpec {
+	return CommandSpec{
+		Program:    "agentcli",
+		SpawnArgs:  []string{"run", "--session", "{{.SessionID}}", "{{if .Model}}--model={{.Model}}{{end}}"},
+		ResumeArgs: []string{"resume", "{{.SessionID}}"},
+		Prompt:     PromptStdin,
+		Output:     OutputText,
+	}
+}
+
+// TestCommandExecutor_Name verifies Name() returns the configured name.
+func TestCommandExecutor_Name(t *testing.T) {
+	executor := NewCommandExecutor("my-agent", testCommandSpec(), "")
+	if got := executor.Name(); got != "my-agent" {
+		t.Errorf("Name() = %q, want %q", got, "my-agent")
+	}
+}
+
+// TestCommandExecutor_SupportsResumption verifies resumption follows the
+// presence of resume arguments.
+func TestCommandExecutor_SupportsResumption(t *testing.T) {
+	spec := testCommandSpec()
+	if !NewCommandExecutor("a", spec, "").SupportsResumption() {
+		t.Error("SupportsResumption() = false with resume args, want true")
+	}
+
+	spec.ResumeArgs = nil
+	executor := NewCommandExecutorWithRunner("a", spec, "", &MockCommandRunner{})
+	if executor.SupportsResumption() {
+		t.Error("SupportsResumption() = true without resume args, want false")
+	}
+	if err := executor.Resume(context.Background(), "session-1", "continue"); err == nil {
+		t.Error("expected Resume() to fail without resume args")
+	}
+}
+
+// TestCommandExecutor_Spawn_RendersArgs verifies spawn arguments are
+// rendered, empty arguments dropped, and custom args appended.
+func TestCommandExecutor_Spawn_RendersArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		model    string
+		wantArgs []string
+	}{
+		{
+			name:     "without model",
+			wantArgs: []string{"run", "--session", "session-1", "--verbose"},
+		},
+		{
+			name:     "with model",
+			model:    "gpt-4o",
+			wantArgs: []string{"run", "--session", "session-1", "--model=gpt-4o", "--verbose"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := testCommandSpec()
+			spec.Model = tt.model
+			spec.CustomArgs = []string{"--verbose"}
+			runner := &MockCommandRunner{}
+			executor := NewCommandExecutorWithRunner("a", spec, "/outputs", runner)
+
+			if err := executor.Spawn(context.Background(), Implementer, "Execute task 010", "session-1"); err != nil {
+				t.Fatalf("Spawn() error = %v", err)
+			}
+
+			if runner.LastName != "agentcli" {
+				t.Errorf("command = %q, want %q", runner.LastName, "agentcli")
+			}
+			if !reflect.DeepEqual(runner.LastArgs, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", runner.LastArgs, tt.wantArgs)
+			}
+			if runner.LastOutputPath != "/outputs/session-1.log" {
+				t.Errorf("output path = %q, want %q", runner.LastOutputPath, "/outputs/session-1.log")
+			}
+		})
+	}
+}
+
+// TestCommandExecutor_Spawn_PromptDelivery verifies the prompt goes on stdin
+// or only into the arguments.
+func TestCommandExecutor_Spawn_PromptDelivery(t *testing.T) {
+	agentPrompt, err := LoadPrompt(Implementer.PromptPath)
+	if err != nil {
+		t.Fatalf("LoadPrompt() error = %v", err)
+	}
+	fullPrompt := agentPrompt + "\n\n" + "Execute task 010"
+
+	t.Run("stdin", func(t *testing.T) {
+		runner := &MockCommandRunner{}
+		executor := NewCommandExecutorWithRunner("a", testCommandSpec(), "", runner)
+		if err := executor.Spawn(context.Background(), Implementer, "Execute task 010", "session-1"); err != nil {
+			t.Fatalf("Spawn() error = %v", err)
+		}
+		if runner.LastStdin != fullPrompt {
+			t.Errorf("stdin does not hold the agent and task prompt: %q", runner.LastStdin)
+		}
+	})
+
+	t.Run("argv", func(t *testing.T) {
+		spec := testCommandSpec()
+		spec.Prompt = PromptArgv
+		spec.SpawnArgs = []string{"--message", "{{.Prompt}}", "--task", "{{.TaskPrompt}}"}
+		var stdin io.Reader
+		runner := &MockCommandRunner{
+			RunFunc: func(_ context.Context, _ string, _ []string, in io.Reader, _ string) error {
+				stdin = in
+				return nil
+			},
+		}
+		executor := NewCommandExecutorWithRunner("a", spec, "", runner)
+		if err := executor.Spawn(context.Background(), Implementer, "Execute task 010", "session-1"); err != nil {
+			t.Fatalf("Spawn() error = %v", err)
+		}
+		if stdin != nil {
+			t.Error("expected no stdin for argv delivery")
+		}
+		want := []string{"--message", fullPrompt, "--task", "Execute task 010"}
+		if !reflect.DeepEqual(runner.LastArgs, want) {
+			t.Errorf("args = %q, want %q", runner.LastArgs, want)
+		}
+	})
+}
+
+// TestCommandExecutor_Spawn_AgentPromptPath verifies the agent prompt is
+// written to a file for the duration of the run.
+func TestCommandExecutor_Spawn_AgentPromptPath(t *testing.T) {
+	spec := testCommandSpec()
+	spec.SpawnArgs = []string{"--system-prompt-file", "{{.AgentPromptPath}}"}
+
+	var path, content string
+	runner := &MockCommandRunner{
+		RunFunc: func(_ context.Context, _ string, args []string, _ io.Reader, _ string) error {
+			path = args[1]
+			data, err := os.ReadFile(path)
+			content = string(data)
+			return err
+		},
+	}
+	executor := NewCommandExecutorWithRunner("a", spec, "", runner)
+	if err := executor.Spawn(context.Background(), Implementer, "Execute task 010", "session-1"); err != nil {
+		t.Fatalf("Spawn() error = %v", err)
+	}
+
+	agentPrompt, _ := LoadPrompt(Implementer.PromptPath)
+	if content != agentPrompt {
+		t.Error("expected agent prompt file to hold the agent prompt")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected agent prompt file %s to be removed", path)
+	}
+}
+
+// TestCommandExecutor_Resume_RendersArgs verifies resume arguments and stdin.
+func TestCommandExecutor_Resume_RendersArgs(t *testing.T) {
+	runner := &MockCommandRunner{}
+	executor := NewCommandExecutorWithRunner("a", testCommandSpec(), "", runner)
+
+	if err := executor.Resume(context.Background(), "session-1", "Address feedback"); err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	if want := []string{"resume", "session-1"}; !reflect.DeepEqual(runner.LastArgs, want) {
+		t.Errorf("args = %v, want %v", runner.LastArgs, want)
+	}
+	if runner.LastStdin != "Address feedback" {
+		t.Errorf("stdin = %q, want %q", runner.LastStdin, "Address feedback")
+	}
+}
+
+// TestCommandExecutor_Spawn_InvalidTemplate verifies template errors are
+// reported without running the program.
+func TestCommandExecutor_Spawn_InvalidTemplate(t *testing.T) {
+	for _, arg := range []string{"{{.SessionID", "{{.Unknown}}"} {
+		spec := testCommandSpec()
+		spec.SpawnArgs = []string{arg}
+		runner := &MockCommandRunner{}
+		executor := NewCommandExecutorWithRunner("a", spec, "", runner)
+
+		err := executor.Spawn(context.Background(), Implementer, "prompt", "session-1")
+		if err == nil || !strings.Contains(err.Error(), arg) {
+			t.Errorf("expected error naming %q, got %v", arg, err)
+		}
+		if runner.LastName != "" {
+			t.Errorf("expected %q not to run the program", arg)
+		}
+	}
+}
+
+// TestCommandExecutor_ValidateAvailability verifies the program is looked
+// up on PATH.
+func TestCommandExecutor_ValidateAvailability(t *testing.T) {
+	spec := testCommandSpec()
+	spec.Program = "sh"
+	if err := NewCommandExecutor("a", spec, "").ValidateAvailability(); err != nil {
+		t.Errorf("ValidateAvailability() error = %v, want nil", err)
+	}
+
+	spec.Program = "sow-nonexistent-agent-cli"
+	err := NewCommandExecutor("a", spec, "").ValidateAvailability()
+	if err == nil || !strings.Contains(err.Error(), "sow-nonexistent-agent-cli not found on PATH") {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+// TestLoadExecutorRegistry_CommandExecutor verifies command executors are
+// built from the user config.
+func TestLoadExecutorRegistry_CommandExecutor(t *testing.T) {
+	var config schemas.UserConfig
+	err := yaml.Unmarshal([]byte(`agents:
+  executors:
+    gemini:
+      type: command
+      settings:
+        model: gemini-2.5-pro
+      custom_args: ["--debug"]
+      command:
+        program: gemini
+        spawn_args: ["--model", "{{.Model}}", "--prompt", "{{.Prompt}}"]
+        prompt: argv
+        output: stream-json
+    broken:
+      type: command
+`), &config)
+	if err != nil {
+		t.Fatalf("failed to parse config: %v", err)
+	}
+
+	_, err = LoadExecutorRegistry(&config, "/outputs")
+	if err == nil || !strings.Contains(err.Error(), `executor "broken" of type command has no command block`) {
+		t.Fatalf("expected missing command block error, got %v", err)
+	}
+
+	delete(config.Agents.Executors, "broken")
+	registry, err := LoadExecutorRegistry(&config, "/outputs")
+	if err != nil {
+		t.Fatalf("LoadExecutorRegistry() error = %v", err)
+	}
+	executor, err := registry.Get("gemini")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	command, ok := executor.(*CommandExecutor)
+	if !ok {
+		t.Fatalf("expected *CommandExecutor, got %T", executor)
+	}
+
+	want := CommandSpec{
+		Program:    "gemini",
+		SpawnArgs:  []string{"--model", "{{.Model}}", "--prompt", "{{.Prompt}}"},
+		Prompt:     PromptArgv,
+		Output:     OutputStreamJSON,
+		Model:      "gemini-2.5-pro",
+		CustomArgs: []string{"--debug"},
+	}
+	if !reflect.DeepEqual(command.spec, want) {
+		t.Errorf("spec = %+v, want %+v", command.spec, want)
+	}
+	if runner, ok := command.runner.(*DefaultCommandRunner); !ok || runner.PlainOutput {
+		t.Errorf("expected stream-json output to be formatted, got runner %+v", command.runner)
+	}
+}
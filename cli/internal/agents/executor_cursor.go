@@ -83,14 +83,15 @@ func (e *CursorExecutor) outputPath(sessionID string) string {
 // The method builds CLI arguments as follows:
 //   - Base command: cursor-agent agent
 //   - --print: non-interactive mode (always used)
-//   - --force: skip permission prompts (only when yoloMode is true)
+//   - --force: skip permission prompts (only when yoloMode is true and
+//     the agent's permission profile is unrestricted)
 //   - If sessionID is not empty: --chat-id <sessionID>
 //
 // The combined prompt is passed via stdin.
 // Blocks until the subprocess exits.
 func (e *CursorExecutor) Spawn(ctx context.Context, agent *Agent, prompt string, sessionID string) error {
 	// Load agent prompt template
-	agentPrompt, err := LoadPrompt(agent.PromptPath)
+	agentPrompt, err := agent.LoadPrompt()
 	if err != nil {
 		return fmt.Errorf("failed to load agent prompt: %w", err)
 	}
@@ -103,7 +104,7 @@ func (e *CursorExecutor) Spawn(ctx context.Context, agent *Agent, prompt string,
 	args := []string{"agent", "--print", "--output-format", "stream-json"}
 
 	// Add --force when yoloMode enabled (Cursor's equivalent of skip-permissions)
-	if e.yoloMode {
+	if e.force(agent.Permissions) {
 		args = append(args, "--force")
 	}
 
@@ -127,7 +128,8 @@ func (e *CursorExecutor) Spawn(ctx context.Context, agent *Agent, prompt string,
 // Uses cursor-agent agent --resume <sessionID> format.
 //
 // The --print flag is always used for non-interactive mode.
-// The --force flag is added when yoloMode is enabled.
+// The --force flag is added when yoloMode is enabled and the permission
+// profile set with WithPermissions is unrestricted.
 //
 // The prompt is passed via stdin.
 // Blocks until the subprocess exits.
@@ -136,7 +138,7 @@ func (e *CursorExecutor) Resume(ctx context.Context, sessionID string, prompt st
 	args := []string{"agent", "--print", "--output-format", "stream-json", "--resume", sessionID}
 
 	// Add --force when yoloMode enabled (Cursor's equivalent of skip-permissions)
-	if e.yoloMode {
+	if e.force(permissionsFromContext(ctx)) {
 		args = append(args, "--force")
 	}
 
@@ -152,12 +154,28 @@ func (e *CursorExecutor) Resume(ctx context.Context, sessionID string, prompt st
 	return nil
 }
 
+// force reports whether to pass --force for a permission profile.
+//
+// cursor-agent has no flags for tool rules; without --force it applies
+// the allow and deny lists of its own CLI config (.cursor/cli.json) and
+// rejects anything else in --print mode. Any restriction in the profile
+// therefore withholds --force, leaving enforcement to that config.
+func (e *CursorExecutor) force(permissions Permissions) bool {
+	return e.yoloMode && permissions.IsZero()
+}
+
 // SupportsResumption indicates that Cursor supports session resumption.
 // Cursor Agent CLI supports the --resume flag for continuing sessions.
 func (e *CursorExecutor) SupportsResumption() bool {
 	return true
 }
 
+// SessionStarted reports whether Cursor logged an event of the session,
+// which it only does once the session was created.
+func (e *CursorExecutor) SessionStarted(sessionID string) bool {
+	return sessionLogged(e.outputPath(sessionID))
+}
+
 // ValidateAvailability checks if the cursor-agent CLI binary is available on PATH.
 // Returns nil if available, error with installation guidance if not.
 func (e *CursorExecutor) ValidateAvailability() error {
@@ -169,4 +187,7 @@ func (e *CursorExecutor) ValidateAvailability() error {
 }
 
 // Compile-time check that CursorExecutor implements Executor.
-var _ Executor = (*CursorExecutor)(nil)
+var (
+	_ Executor       = (*CursorExecutor)(nil)
+	_ SessionChecker = (*CursorExecutor)(nil)
+)
@@ -25,12 +25,14 @@ import (
 //   - Resume() returns nil
 //   - SupportsResumption() returns false
 //   - ValidateAvailability() returns nil
+//   - SessionStarted() returns false
 type MockExecutor struct {
 	NameFunc                  func() string
 	SpawnFunc                 func(ctx context.Context, agent *Agent, prompt string, sessionID string) error
 	ResumeFunc                func(ctx context.Context, sessionID string, prompt string) error
 	SupportsResumptionFunc    func() bool
 	ValidateAvailabilityFunc  func() error
+	SessionStartedFunc        func(sessionID string) bool
 }
 
 // Name calls the mock function if set, otherwise returns empty string.
@@ -73,6 +75,14 @@ func (m *MockExecutor) ValidateAvailability() error {
 	return nil
 }
 
+// SessionStarted calls the mock function if set, otherwise returns false.
+func (m *MockExecutor) SessionStarted(sessionID string) bool {
+	if m.SessionStartedFunc != nil {
+		return m.SessionStartedFunc(sessionID)
+	}
+	return false
+}
+
 // Compile-time check that MockExecutor implements Executor.
 var _ Executor = (*MockExecutor)(nil)
 
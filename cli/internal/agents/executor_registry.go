@@ -23,6 +23,11 @@ import (
 //	}
 type ExecutorRegistry struct {
 	executors map[string]Executor
+
+	// Execution policies from the user config, keyed by executor name and
+	// by agent name. GetAgentExecutor merges the two.
+	executorPolicies map[string]*schemas.ExecutionPolicy
+	agentPolicies    map[string]schemas.ExecutionPolicy
 }
 
 // NewExecutorRegistry creates a new empty ExecutorRegistry.
@@ -35,7 +40,9 @@ type ExecutorRegistry struct {
 //	registry.Register(executor)
 func NewExecutorRegistry() *ExecutorRegistry {
 	return &ExecutorRegistry{
-		executors: make(map[string]Executor),
+		executors:        make(map[string]Executor),
+		executorPolicies: make(map[string]*schemas.ExecutionPolicy),
+		agentPolicies:    make(map[string]schemas.ExecutionPolicy),
 	}
 }
 
@@ -117,6 +124,9 @@ const DefaultExecutorName = "claude-code"
 // If userConfig is nil or has no executors defined, a default registry is created
 // with a single "claude-code" executor using safe defaults.
 //
+// Execution policies of executors and agents are kept for GetAgentExecutor.
+// Returns an error if a policy has an invalid duration.
+//
 // Parameters:
 //   - userConfig: user configuration with executor definitions
 //   - outputDir: directory for agent output logs (can be empty to disable logging)
@@ -131,6 +141,15 @@ const DefaultExecutorName = "claude-code"
 func LoadExecutorRegistry(userConfig *schemas.UserConfig, outputDir string) (*ExecutorRegistry, error) {
 	registry := NewExecutorRegistry()
 
+	if userConfig != nil && userConfig.Agents != nil {
+		for agentName, policy := range userConfig.Agents.Policies {
+			if _, err := policyFromConfig(&policy); err != nil {
+				return nil, fmt.Errorf("policy of agent %q: %w", agentName, err)
+			}
+			registry.agentPolicies[agentName] = policy
+		}
+	}
+
 	// If no config or no executors defined, create default
 	if userConfig == nil || userConfig.Agents == nil || len(userConfig.Agents.Executors) == 0 {
 		// Register default claude-code executor
@@ -160,71 +179,84 @@ func LoadExecutorRegistry(userConfig *schemas.UserConfig, outputDir string) (*Ex
 			executor = NewClaudeExecutor(yoloMode, model, outputDir, customArgs)
 		case "cursor":
 			executor = NewCursorExecutor(yoloMode, outputDir, customArgs)
+		case "command":
+			if execConfig.Command == nil {
+				return nil, fmt.Errorf("executor %q of type command has no command block", name)
+			}
+			executor = NewCommandExecutor(name, commandSpec(execConfig.Command, model, customArgs), outputDir)
+		case "scripted":
+			executor = NewScriptedExecutor(name, execConfig.Script, outputDir)
 		default:
 			return nil, fmt.Errorf("unknown executor type %q for executor %q", execConfig.Type, name)
 		}
 
+		if _, err := policyFromConfig(execConfig.Policy); err != nil {
+			return nil, fmt.Errorf("policy of executor %q: %w", name, err)
+		}
+		registry.executorPolicies[name] = execConfig.Policy
+
 		registry.RegisterNamed(name, executor)
 	}
 
 	return registry, nil
 }
 
+// commandSpec converts a command executor definition from the user config
+// into a CommandSpec.
+func commandSpec(config *schemas.CommandExecutorConfig, model string, customArgs []string) CommandSpec {
+	spec := CommandSpec{
+		Program:    config.Program,
+		SpawnArgs:  config.Spawn_args,
+		ResumeArgs: config.Resume_args,
+		Prompt:     PromptStdin,
+		Output:     OutputText,
+		Model:      model,
+		CustomArgs: customArgs,
+	}
+	if config.Prompt != nil {
+		spec.Prompt = *config.Prompt
+	}
+	if config.Output != nil {
+		spec.Output = *config.Output
+	}
+	return spec
+}
+
 // GetAgentExecutor looks up the executor for an agent based on bindings.
 // It first finds the executor name from bindings, then looks up the executor.
 //
+// The executor is wrapped in a PolicyExecutor enforcing the executor's
+// policy, with the fields set in the agent's policy taking precedence.
+//
 // Parameters:
 //   - agentName: the agent role (e.g., "implementer", "reviewer")
 //   - bindings: the bindings configuration from user config (can be nil)
 //
 // Returns the executor for the agent, or error if not found.
-func (r *ExecutorRegistry) GetAgentExecutor(agentName string, bindings *struct {
-	Orchestrator *string `json:"orchestrator,omitempty"`
-	Implementer  *string `json:"implementer,omitempty"`
-	Architect    *string `json:"architect,omitempty"`
-	Reviewer     *string `json:"reviewer,omitempty"`
-	Planner      *string `json:"planner,omitempty"`
-	Researcher   *string `json:"researcher,omitempty"`
-	Decomposer   *string `json:"decomposer,omitempty"`
-}) (Executor, error) {
+func (r *ExecutorRegistry) GetAgentExecutor(agentName string, bindings map[string]string) (Executor, error) {
 	executorName := resolveExecutorName(agentName, bindings)
-	return r.Get(executorName)
-}
-
-// resolveExecutorName determines the executor name for an agent based on bindings.
-func resolveExecutorName(agentName string, bindings *struct {
-	Orchestrator *string `json:"orchestrator,omitempty"`
-	Implementer  *string `json:"implementer,omitempty"`
-	Architect    *string `json:"architect,omitempty"`
-	Reviewer     *string `json:"reviewer,omitempty"`
-	Planner      *string `json:"planner,omitempty"`
-	Researcher   *string `json:"researcher,omitempty"`
-	Decomposer   *string `json:"decomposer,omitempty"`
-}) string {
-	if bindings == nil {
-		return DefaultExecutorName
+	executor, err := r.Get(executorName)
+	if err != nil {
+		return nil, err
 	}
 
-	var bound *string
-	switch agentName {
-	case "orchestrator":
-		bound = bindings.Orchestrator
-	case "implementer":
-		bound = bindings.Implementer
-	case "architect":
-		bound = bindings.Architect
-	case "reviewer":
-		bound = bindings.Reviewer
-	case "planner":
-		bound = bindings.Planner
-	case "researcher":
-		bound = bindings.Researcher
-	case "decomposer":
-		bound = bindings.Decomposer
+	agentPolicy, ok := r.agentPolicies[agentName]
+	config := r.executorPolicies[executorName]
+	if ok {
+		config = mergePolicyConfig(config, &agentPolicy)
 	}
+	policy, err := policyFromConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("policy of agent %s on executor %s: %w", agentName, executorName, err)
+	}
+	return NewPolicyExecutor(executor, policy), nil
+}
 
-	if bound != nil {
-		return *bound
+// resolveExecutorName determines the executor name for an agent based on
+// bindings. Unbound agents use the default executor.
+func resolveExecutorName(agentName string, bindings map[string]string) string {
+	if bound, ok := bindings[agentName]; ok && bound != "" {
+		return bound
 	}
 	return DefaultExecutorName
 }
@@ -0,0 +1,476 @@
+package agents
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jmgilman/sow/cli/internal/agents/logformat"
+	"gopkg.in/yaml.v3"
+)
+
+// Script is the script of a ScriptedExecutor: what each agent does when it
+// is spawned or resumed. Keys are "<agent>/<task id>" (e.g.
+// "implementer/010") for task agents, or "<agent>" for taskless spawns and
+// as a fallback for any task of that agent.
+//
+// Example:
+//
+//	implementer/010:
+//	  spawn:
+//	    - write: src/feature.go
+//	      content: "package feature\n"
+//	    - sow: task set --id {{.TaskID}} status needs_review
+//	  resume:
+//	    - sow: task set --id {{.TaskID}} status completed
+type Script map[string]ScriptEntry
+
+// ScriptEntry lists the steps an agent performs on spawn and on each resume.
+type ScriptEntry struct {
+	Spawn  []ScriptStep `yaml:"spawn"`
+	Resume []ScriptStep `yaml:"resume"`
+}
+
+// ScriptStep is one action of a scripted agent. Exactly one of Sow, Write,
+// and Fail is set. Arguments, paths, and content are Go templates with the
+// fields .Agent, .TaskID, and .SessionID.
+type ScriptStep struct {
+	// Sow runs the sow CLI (from PATH) with these arguments. Written as a
+	// list or as a string split on spaces, honoring quotes.
+	Sow ScriptArgs `yaml:"sow"`
+
+	// Write writes Content to this path, relative to the agent's working
+	// directory.
+	Write   string `yaml:"write"`
+	Content string `yaml:"content"`
+
+	// Fail makes the agent fail with this message.
+	Fail string `yaml:"fail"`
+}
+
+// ScriptArgs are the arguments of a sow step.
+type ScriptArgs []string
+
+// UnmarshalYAML accepts a list of arguments or a single string.
+func (a *ScriptArgs) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		args, err := splitScriptArgs(node.Value)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		*a = args
+		return nil
+	}
+	var args []string
+	if err := node.Decode(&args); err != nil {
+		return err
+	}
+	*a = args
+	return nil
+}
+
+// splitScriptArgs splits s on spaces, keeping single- or double-quoted
+// text together.
+func splitScriptArgs(s string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	var quote rune
+	inArg := false
+	for _, r := range s {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in %q", s)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// LoadScript reads and parses a script file.
+func LoadScript(path string) (Script, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read script: %w", err)
+	}
+	var script Script
+	if err := yaml.Unmarshal(data, &script); err != nil {
+		return nil, fmt.Errorf("failed to parse script %s: %w", path, err)
+	}
+	for key, entry := range script {
+		for _, step := range append(append([]ScriptStep{}, entry.Spawn...), entry.Resume...) {
+			if err := step.validate(); err != nil {
+				return nil, fmt.Errorf("script %s, %s: %w", path, key, err)
+			}
+		}
+	}
+	return script, nil
+}
+
+// validate checks that exactly one action is set.
+func (s ScriptStep) validate() error {
+	actions := 0
+	for _, set := range []bool{len(s.Sow) > 0, s.Write != "", s.Fail != ""} {
+		if set {
+			actions++
+		}
+	}
+	if actions != 1 {
+		return fmt.Errorf("each step needs exactly one of sow, write, or fail")
+	}
+	return nil
+}
+
+// scriptData is the data script steps are rendered with.
+type scriptData struct {
+	Agent     string
+	TaskID    string
+	SessionID string
+}
+
+// scriptSession is what a ScriptedExecutor remembers about a session so it
+// can find the script entry again on resume.
+type scriptSession struct {
+	Agent  string `json:"agent"`
+	TaskID string `json:"task_id,omitempty"`
+}
+
+// promptTaskID extracts the task ID from a task prompt ("Execute task 010.").
+var promptTaskID = regexp.MustCompile(`Execute task ([^\s.]+)`)
+
+// ScriptedExecutor implements Executor by replaying a Script instead of
+// running an AI agent (executor type "scripted"). It performs the script's
+// sow CLI calls and file writes and logs them as synthetic stream-json, so
+// whole workflows can be tested end to end without an LLM.
+//
+// The task is taken from the spawn prompt. Each session's agent and task
+// are recorded next to its log in outputDir so Resume can find them.
+type ScriptedExecutor struct {
+	name       string
+	scriptPath string
+	outputDir  string
+}
+
+// NewScriptedExecutor creates a ScriptedExecutor registered under name that
+// replays the script at scriptPath.
+func NewScriptedExecutor(name, scriptPath, outputDir string) *ScriptedExecutor {
+	return &ScriptedExecutor{
+		name:       name,
+		scriptPath: scriptPath,
+		outputDir:  outputDir,
+	}
+}
+
+// Name returns the name the executor is configured under.
+func (e *ScriptedExecutor) Name() string {
+	return e.name
+}
+
+// Spawn performs the spawn steps of the script entry for the agent and the
+// task named in prompt.
+func (e *ScriptedExecutor) Spawn(ctx context.Context, agent *Agent, prompt string, sessionID string) error {
+	session := scriptSession{Agent: agent.Name}
+	if match := promptTaskID.FindStringSubmatch(prompt); match != nil {
+		session.TaskID = match[1]
+	}
+
+	entry, err := e.entry(session)
+	if err != nil {
+		return err
+	}
+	if err := e.saveSession(sessionID, session); err != nil {
+		return err
+	}
+	return e.run(ctx, entry.Spawn, session, sessionID)
+}
+
+// Resume performs the resume steps of the script entry the session was
+// spawned with.
+func (e *ScriptedExecutor) Resume(ctx context.Context, sessionID string, _ string) error {
+	session, err := e.loadSession(sessionID)
+	if err != nil {
+		return err
+	}
+	entry, err := e.entry(session)
+	if err != nil {
+		return err
+	}
+	return e.run(ctx, entry.Resume, session, sessionID)
+}
+
+// SupportsResumption indicates that scripted sessions can be resumed.
+func (e *ScriptedExecutor) SupportsResumption() bool {
+	return true
+}
+
+// SessionStarted reports whether the session was recorded for Resume.
+func (e *ScriptedExecutor) SessionStarted(sessionID string) bool {
+	path := e.sessionPath(sessionID)
+	if path == "" {
+		return false
+	}
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// ValidateAvailability checks that the script can be loaded.
+func (e *ScriptedExecutor) ValidateAvailability() error {
+	_, err := LoadScript(e.scriptPath)
+	return err
+}
+
+// entry returns the script entry for a session's agent and task.
+func (e *ScriptedExecutor) entry(session scriptSession) (ScriptEntry, error) {
+	script, err := LoadScript(e.scriptPath)
+	if err != nil {
+		return ScriptEntry{}, err
+	}
+	if session.TaskID != "" {
+		if entry, ok := script[session.Agent+"/"+session.TaskID]; ok {
+			return entry, nil
+		}
+	}
+	if entry, ok := script[session.Agent]; ok {
+		return entry, nil
+	}
+	if session.TaskID != "" {
+		return ScriptEntry{}, fmt.Errorf("script %s has no entry for %s/%s or %s", e.scriptPath, session.Agent, session.TaskID, session.Agent)
+	}
+	return ScriptEntry{}, fmt.Errorf("script %s has no entry for %s", e.scriptPath, session.Agent)
+}
+
+// sessionPath returns where a session's agent and task are recorded.
+func (e *ScriptedExecutor) sessionPath(sessionID string) string {
+	if e.outputDir == "" || sessionID == "" {
+		return ""
+	}
+	return filepath.Join(e.outputDir, sessionID+".scripted")
+}
+
+// saveSession records a session's agent and task for Resume.
+func (e *ScriptedExecutor) saveSession(sessionID string, session scriptSession) error {
+	path := e.sessionPath(sessionID)
+	if path == "" {
+		return nil
+	}
+	data, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %w", err)
+	}
+	if err := os.MkdirAll(e.outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to record session: %w", err)
+	}
+	return nil
+}
+
+// loadSession reads the agent and task recorded for a session.
+func (e *ScriptedExecutor) loadSession(sessionID string) (scriptSession, error) {
+	var session scriptSession
+	path := e.sessionPath(sessionID)
+	if path == "" {
+		return session, fmt.Errorf("cannot resume scripted session %s without an output directory", sessionID)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return session, fmt.Errorf("scripted session %s not found: %w", sessionID, err)
+	}
+	if err := json.Unmarshal(data, &session); err != nil {
+		return session, fmt.Errorf("failed to read session %s: %w", sessionID, err)
+	}
+	return session, nil
+}
+
+// run performs steps in order, logging each as a tool call in the
+// session's stream-json log. It stops at the first failing step.
+func (e *ScriptedExecutor) run(ctx context.Context, steps []ScriptStep, session scriptSession, sessionID string) error {
+	log := io.Discard
+	if e.outputDir != "" && sessionID != "" {
+		if err := os.MkdirAll(e.outputDir, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+		streamLog, err := openStreamLog(filepath.Join(e.outputDir, sessionID+".log"))
+		if err != nil {
+			return err
+		}
+		defer func() { _ = streamLog.Close() }()
+		log = streamLog.writer
+	}
+	events := scriptEvents{w: log, sessionID: sessionID}
+
+	start := time.Now()
+	cwd := workspaceDir(ctx)
+	events.init(cwd)
+
+	data := scriptData{Agent: session.Agent, TaskID: session.TaskID, SessionID: sessionID}
+	for i, step := range steps {
+		if err := e.runStep(ctx, i+1, step, data, cwd, &events); err != nil {
+			events.result(start, i+1, err)
+			return fmt.Errorf("%s: step %d failed: %w", e.name, i+1, err)
+		}
+	}
+	events.result(start, len(steps), nil)
+	return nil
+}
+
+// runStep performs one step and logs its call and result.
+func (e *ScriptedExecutor) runStep(ctx context.Context, n int, step ScriptStep, data scriptData, cwd string, events *scriptEvents) error {
+	toolID := fmt.Sprintf("step-%d", n)
+	switch {
+	case step.Fail != "":
+		message, err := renderTemplate(step.Fail, data)
+		if err != nil {
+			return fmt.Errorf("invalid fail message %q: %w", step.Fail, err)
+		}
+		return fmt.Errorf("%s", message)
+
+	case step.Write != "":
+		path, err := renderTemplate(step.Write, data)
+		if err != nil {
+			return fmt.Errorf("invalid write path %q: %w", step.Write, err)
+		}
+		content, err := renderTemplate(step.Content, data)
+		if err != nil {
+			return fmt.Errorf("invalid content for %s: %w", path, err)
+		}
+		events.toolUse(toolID, "Write", logformat.ToolInput{FilePath: path, Content: content})
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(cwd, path)
+		}
+		err = os.MkdirAll(filepath.Dir(path), 0755)
+		if err == nil {
+			err = os.WriteFile(path, []byte(content), 0644)
+		}
+		if err != nil {
+			events.toolResult(toolID, "", err.Error(), true)
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		events.toolResult(toolID, fmt.Sprintf("Wrote %d bytes to %s", len(content), path), "", false)
+		return nil
+
+	default:
+		args := make([]string, len(step.Sow))
+		for i, arg := range step.Sow {
+			rendered, err := renderTemplate(arg, data)
+			if err != nil {
+				return fmt.Errorf("invalid argument %q: %w", arg, err)
+			}
+			args[i] = rendered
+		}
+		events.toolUse(toolID, "Bash", logformat.ToolInput{Command: "sow " + strings.Join(args, " ")})
+
+		cmd := workspaceCommand(ctx, "sow", args...)
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		err := cmd.Run()
+		events.toolResult(toolID, stdout.String(), stderr.String(), err != nil)
+		if err != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return fmt.Errorf("sow %s: %w\nstderr: %s", strings.Join(args, " "), err, msg)
+			}
+			return fmt.Errorf("sow %s: %w", strings.Join(args, " "), err)
+		}
+		return nil
+	}
+}
+
+// workspaceDir returns the directory agents run in under ctx: the workspace
+// set by WithWorkspace, or the current directory.
+func workspaceDir(ctx context.Context) string {
+	if ws, ok := ctx.Value(workspaceKey{}).(workspace); ok && ws.dir != "" {
+		return ws.dir
+	}
+	dir, _ := os.Getwd()
+	return dir
+}
+
+// scriptEvents writes the synthetic stream-json events of a scripted run.
+type scriptEvents struct {
+	w         io.Writer
+	sessionID string
+}
+
+// emit writes event as one JSON line.
+func (s *scriptEvents) emit(event logformat.Event) {
+	event.SessionID = s.sessionID
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	_, _ = s.w.Write(append(data, '\n'))
+}
+
+// init logs the start of the session.
+func (s *scriptEvents) init(cwd string) {
+	s.emit(logformat.Event{Type: logformat.EventTypeSystem, Subtype: "init", Model: "scripted", CWD: cwd})
+}
+
+// toolUse logs a tool call.
+func (s *scriptEvents) toolUse(id, name string, input logformat.ToolInput) {
+	rawInput, _ := json.Marshal(input)
+	content, _ := json.Marshal([]logformat.ContentBlock{{Type: "tool_use", ID: id, Name: name, Input: rawInput}})
+	s.emit(logformat.Event{
+		Type:    logformat.EventTypeAssistant,
+		Message: &logformat.Message{Role: "assistant", Content: content, Model: "scripted"},
+	})
+}
+
+// toolResult logs the outcome of a tool call.
+func (s *scriptEvents) toolResult(id, stdout, stderr string, isError bool) {
+	content, _ := json.Marshal([]logformat.ContentBlock{{Type: "tool_result", ToolUseID: id, Content: stdout, IsError: isError}})
+	s.emit(logformat.Event{
+		Type:          logformat.EventTypeUser,
+		Message:       &logformat.Message{Role: "user", Content: content},
+		ToolUseResult: &logformat.ToolUseResult{Stdout: stdout, Stderr: stderr},
+	})
+}
+
+// result logs the end of the session.
+func (s *scriptEvents) result(start time.Time, turns int, err error) {
+	event := logformat.Event{
+		Type:       logformat.EventTypeResult,
+		Subtype:    "success",
+		DurationMS: time.Since(start).Milliseconds(),
+		NumTurns:   turns,
+		Result:     "Script finished",
+	}
+	if err != nil {
+		event.Subtype = "error"
+		event.IsError = true
+		event.Result = err.Error()
+	}
+	s.emit(event)
+}
+
+// Compile-time check that ScriptedExecutor implements Executor.
+var (
+	_ Executor       = (*ScriptedExecutor)(nil)
+	_ SessionChecker = (*ScriptedExecutor)(nil)
+)
@@ -0,0 +1,175 @@
+package agents
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// setupScript writes script to a temp dir and puts a fake sow on PATH that
+// appends its arguments to sow.calls in that dir. It returns the dir.
+func setupScript(t *testing.T, script string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "script.yaml"), []byte(script), 0644); err != nil {
+		t.Fatalf("failed to write script: %v", err)
+	}
+
+	bin := filepath.Join(dir, "bin")
+	if err := os.MkdirAll(bin, 0755); err != nil {
+		t.Fatalf("failed to create bin: %v", err)
+	}
+	fake := "#!/bin/sh\necho \"$*\" >> " + filepath.Join(dir, "sow.calls") + "\n" +
+		"if [ \"$1\" = fail ]; then echo 'sow failed' >&2; exit 1; fi\necho ok\n"
+	if err := os.WriteFile(filepath.Join(bin, "sow"), []byte(fake), 0755); err != nil {
+		t.Fatalf("failed to write fake sow: %v", err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return dir
+}
+
+// readFile returns the content of path, or "" if it does not exist.
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+// TestSplitScriptArgs verifies string steps are split honoring quotes.
+func TestSplitScriptArgs(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []string
+		wantErr bool
+	}{
+		{in: "task set --id 010 status completed", want: []string{"task", "set", "--id", "010", "status", "completed"}},
+		{in: `task add 'Update docs' --agent  implementer`, want: []string{"task", "add", "Update docs", "--agent", "implementer"}},
+		{in: `output add --path "a b.md" ''`, want: []string{"output", "add", "--path", "a b.md", ""}},
+		{in: `task add 'unterminated`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := splitScriptArgs(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("splitScriptArgs(%q) expected error", tt.in)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitScriptArgs(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+// TestLoadScript_RejectsAmbiguousSteps verifies each step has one action.
+func TestLoadScript_RejectsAmbiguousSteps(t *testing.T) {
+	dir := setupScript(t, "implementer:\n  spawn:\n    - sow: task list\n      fail: boom\n")
+
+	_, err := LoadScript(filepath.Join(dir, "script.yaml"))
+	if err == nil || !strings.Contains(err.Error(), "exactly one of sow, write, or fail") {
+		t.Errorf("expected step error, got %v", err)
+	}
+}
+
+// TestScriptedExecutor_SpawnAndResume verifies the task entry's steps run
+// on spawn and resume, with a stream-json log of each step.
+func TestScriptedExecutor_SpawnAndResume(t *testing.T) {
+	dir := setupScript(t, `implementer/010:
+  spawn:
+    - write: src/{{.TaskID}}.txt
+      content: "work by {{.Agent}}\n"
+    - sow: task set --id {{.TaskID}} status needs_review
+  resume:
+    - sow: [task, set, --id, "{{.TaskID}}", status, completed]
+implementer:
+  spawn:
+    - sow: task set --id {{.TaskID}} status paused
+`)
+	outputDir := filepath.Join(dir, "outputs")
+	executor := NewScriptedExecutor("fake", filepath.Join(dir, "script.yaml"), outputDir)
+	ctx := WithWorkspace(context.Background(), dir)
+
+	if err := executor.ValidateAvailability(); err != nil {
+		t.Fatalf("ValidateAvailability() error = %v", err)
+	}
+	if err := executor.Spawn(ctx, Implementer, "Execute task 010.\n\nTask location: ...", "session-1"); err != nil {
+		t.Fatalf("Spawn() error = %v", err)
+	}
+	if err := executor.Resume(ctx, "session-1", "Continue"); err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	// Another task of the agent falls back to the agent entry
+	if err := executor.Spawn(ctx, Implementer, "Execute task 020.", "session-2"); err != nil {
+		t.Fatalf("Spawn() error = %v", err)
+	}
+
+	if got := readFile(t, filepath.Join(dir, "src", "010.txt")); got != "work by implementer\n" {
+		t.Errorf("written file = %q", got)
+	}
+	wantCalls := "task set --id 010 status needs_review\ntask set --id 010 status completed\ntask set --id 020 status paused\n"
+	if got := readFile(t, filepath.Join(dir, "sow.calls")); got != wantCalls {
+		t.Errorf("sow calls = %q, want %q", got, wantCalls)
+	}
+
+	raw := readFile(t, filepath.Join(outputDir, "session-1.json"))
+	for _, want := range []string{`"subtype":"init"`, `"name":"Write"`, `"command":"sow task set --id 010 status completed"`, `"type":"result"`} {
+		if !strings.Contains(raw, want) {
+			t.Errorf("expected raw log to contain %s, got:\n%s", want, raw)
+		}
+	}
+	formatted := readFile(t, filepath.Join(outputDir, "session-1.log"))
+	for _, want := range []string{"SESSION STARTED", "Model: scripted", "TOOL: Bash", "SESSION COMPLETE"} {
+		if !strings.Contains(formatted, want) {
+			t.Errorf("expected formatted log to contain %s, got:\n%s", want, formatted)
+		}
+	}
+}
+
+// TestScriptedExecutor_Failures verifies failing steps and missing entries
+// are reported.
+func TestScriptedExecutor_Failures(t *testing.T) {
+	dir := setupScript(t, `implementer/010:
+  spawn:
+    - fail: "cannot do task {{.TaskID}}"
+implementer/020:
+  spawn:
+    - sow: fail now
+    - sow: task list
+`)
+	outputDir := filepath.Join(dir, "outputs")
+	executor := NewScriptedExecutor("fake", filepath.Join(dir, "script.yaml"), outputDir)
+	ctx := WithWorkspace(context.Background(), dir)
+
+	err := executor.Spawn(ctx, Implementer, "Execute task 010.", "session-1")
+	if err == nil || !strings.Contains(err.Error(), "step 1 failed: cannot do task 010") {
+		t.Errorf("expected fail step error, got %v", err)
+	}
+	if raw := readFile(t, filepath.Join(outputDir, "session-1.json")); !strings.Contains(raw, `"is_error":true`) {
+		t.Errorf("expected failed result in log, got:\n%s", raw)
+	}
+
+	err = executor.Spawn(ctx, Implementer, "Execute task 020.", "session-2")
+	if err == nil || !strings.Contains(err.Error(), "sow failed") {
+		t.Errorf("expected sow error with stderr, got %v", err)
+	}
+	if got := readFile(t, filepath.Join(dir, "sow.calls")); got != "fail now\n" {
+		t.Errorf("expected run to stop at the failing step, got calls %q", got)
+	}
+
+	err = executor.Spawn(ctx, Reviewer, "Execute task 030.", "session-3")
+	if err == nil || !strings.Contains(err.Error(), "no entry for reviewer/030 or reviewer") {
+		t.Errorf("expected missing entry error, got %v", err)
+	}
+
+	err = executor.Resume(ctx, "unknown-session", "Continue")
+	if err == nil || !strings.Contains(err.Error(), "scripted session unknown-session not found") {
+		t.Errorf("expected unknown session error, got %v", err)
+	}
+}
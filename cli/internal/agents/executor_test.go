@@ -4,8 +4,12 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 // TestExecutorInterface verifies interface compliance.
@@ -290,3 +294,105 @@ func TestSplitOutputPaths(t *testing.T) {
 		})
 	}
 }
+
+// TestDefaultCommandRunner_Workspace verifies commands run in the workspace
+// directory with its environment.
+func TestDefaultCommandRunner_Workspace(t *testing.T) {
+	dir := t.TempDir()
+	ctx := WithWorkspace(context.Background(), dir, "SOW_TEST_WORKSPACE=task-010")
+
+	runner := &DefaultCommandRunner{}
+	err := runner.Run(ctx, "sh", []string{"-c", `echo "$SOW_TEST_WORKSPACE" > marker`}, nil, "")
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, "marker"))
+	if err != nil {
+		t.Fatalf("expected command to run in workspace directory: %v", err)
+	}
+	if string(got) != "task-010\n" {
+		t.Errorf("marker = %q, want %q", got, "task-010\n")
+	}
+}
+
+func TestDefaultCommandRunner_PlainOutput(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "session-1.log")
+
+	runner := &DefaultCommandRunner{PlainOutput: true}
+	err := runner.Run(context.Background(), "sh", []string{"-c", "echo plain text; echo warning >&2"}, nil, outputPath)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	got, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("expected output file: %v", err)
+	}
+	if string(got) != "plain text\nwarning\n" {
+		t.Errorf("output = %q, want %q", got, "plain text\nwarning\n")
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(outputPath), "session-1.json")); !os.IsNotExist(err) {
+		t.Error("expected no raw JSON file for plain output")
+	}
+}
+
+// TestDefaultCommandRunner_ProcessObserver verifies started processes are
+// reported to the observer, and their exit afterwards.
+func TestDefaultCommandRunner_ProcessObserver(t *testing.T) {
+	dir := t.TempDir()
+	var observed int
+	exited := false
+	ctx := WithWorkspace(context.Background(), dir)
+	ctx = WithProcessObserver(ctx, func(pid int, startedAt time.Time) func() {
+		observed = pid
+		if startedAt.IsZero() {
+			t.Error("startedAt is zero")
+		}
+		if !ProcessRunning(pid, startedAt) {
+			t.Errorf("process %d reported before it started, or with another start time", pid)
+		}
+		return func() { exited = true }
+	})
+
+	runner := &DefaultCommandRunner{}
+	if err := runner.Run(ctx, "sh", []string{"-c", "echo $$ > pid"}, nil, ""); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, "pid"))
+	if err != nil {
+		t.Fatalf("failed to read pid: %v", err)
+	}
+	if want := fmt.Sprintf("%d\n", observed); string(got) != want {
+		t.Errorf("observed pid %d, command ran as %s", observed, got)
+	}
+	if !exited {
+		t.Error("exit was not reported")
+	}
+	if ProcessAlive(observed) {
+		t.Errorf("process %d still alive after Run", observed)
+	}
+}
+
+// TestProcessRunning verifies a process is identified by its PID and start
+// time, so a PID reused by a later process is not taken for it.
+func TestProcessRunning(t *testing.T) {
+	started, ok := ProcessStartTime(os.Getpid())
+	if !ok {
+		t.Skip("process start times are not available on this platform")
+	}
+	if started.After(time.Now()) {
+		t.Errorf("ProcessStartTime() = %v, in the future", started)
+	}
+
+	if !ProcessRunning(os.Getpid(), started) {
+		t.Error("ProcessRunning() = false for this process")
+	}
+	if !ProcessRunning(os.Getpid(), time.Time{}) {
+		t.Error("ProcessRunning() = false without a start time")
+	}
+	if ProcessRunning(os.Getpid(), started.Add(-time.Hour)) {
+		t.Error("ProcessRunning() = true for a process started at another time")
+	}
+}
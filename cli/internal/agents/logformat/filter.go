@@ -0,0 +1,115 @@
+package logformat
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Filter kinds accepted by ParseFilter.
+const (
+	FilterKindText   = "text"
+	FilterKindTools  = "tools"
+	FilterKindErrors = "errors"
+)
+
+// Filter selects kinds of content from stream-json events. The zero value
+// selects everything.
+//
+// Session and run headers are always kept, so filtered output still shows
+// which session and run the content belongs to.
+type Filter struct {
+	// Text keeps the assistant's text and the session's final result.
+	Text bool
+
+	// Tools keeps tool calls and their results.
+	Tools bool
+
+	// Errors keeps failed tool results and failed sessions.
+	Errors bool
+}
+
+// ParseFilter builds a Filter from kind names ("text", "tools", "errors").
+func ParseFilter(kinds []string) (Filter, error) {
+	var f Filter
+	for _, kind := range kinds {
+		switch strings.TrimSpace(kind) {
+		case FilterKindText:
+			f.Text = true
+		case FilterKindTools:
+			f.Tools = true
+		case FilterKindErrors:
+			f.Errors = true
+		default:
+			return Filter{}, fmt.Errorf("unknown filter %q (want %s, %s, or %s)", kind, FilterKindText, FilterKindTools, FilterKindErrors)
+		}
+	}
+	return f, nil
+}
+
+// IsZero reports whether the filter selects everything.
+func (f Filter) IsZero() bool {
+	return !f.Text && !f.Tools && !f.Errors
+}
+
+// Apply returns event reduced to the content the filter selects, or nil
+// if none is selected. Events are returned unchanged by the zero filter;
+// otherwise content blocks that are not selected are removed from a copy.
+func (f Filter) Apply(event *Event) *Event {
+	if event == nil || f.IsZero() {
+		return event
+	}
+
+	switch event.Type {
+	case EventTypeSystem:
+		return event
+	case EventTypeAssistant:
+		return f.filterBlocks(event, func(block ContentBlock) bool {
+			return (block.Type == "text" && f.Text) || (block.Type == "tool_use" && f.Tools)
+		})
+	case EventTypeUser:
+		return f.filterBlocks(event, func(block ContentBlock) bool {
+			return block.Type == "tool_result" && (f.Tools || (f.Errors && block.IsError))
+		})
+	case EventTypeResult:
+		if f.Text || (f.Errors && event.IsError) {
+			return event
+		}
+	}
+	return nil
+}
+
+// filterBlocks returns a copy of event holding only the content blocks
+// keep selects, or nil if there are none.
+func (f Filter) filterBlocks(event *Event, keep func(ContentBlock) bool) *Event {
+	if event.Message == nil {
+		return nil
+	}
+	blocks, err := event.Message.ParseContentBlocks()
+	if err != nil {
+		return nil
+	}
+
+	var kept []ContentBlock
+	for _, block := range blocks {
+		if keep(block) {
+			kept = append(kept, block)
+		}
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	if len(kept) == len(blocks) {
+		return event
+	}
+
+	content, err := json.Marshal(kept)
+	if err != nil {
+		return nil
+	}
+	message := *event.Message
+	message.Content = content
+	filtered := *event
+	filtered.Message = &message
+	return &filtered
+}
@@ -0,0 +1,122 @@
+package logformat
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// mixedAssistant is an assistant event with both text and a tool call.
+const mixedAssistant = `{"type":"assistant","message":{"content":[{"type":"text","text":"Let me look"},{"type":"tool_use","name":"Read","input":{"file_path":"main.go"}}]}}`
+
+func TestParseFilter(t *testing.T) {
+	f, err := ParseFilter([]string{"text", " errors"})
+	if err != nil {
+		t.Fatalf("ParseFilter() error = %v", err)
+	}
+	if !f.Text || f.Tools || !f.Errors {
+		t.Errorf("ParseFilter() = %+v, want text and errors", f)
+	}
+
+	if _, err := ParseFilter([]string{"tool"}); err == nil || !strings.Contains(err.Error(), `unknown filter "tool"`) {
+		t.Errorf("ParseFilter(tool) error = %v, want unknown filter", err)
+	}
+}
+
+func TestFilter_Apply(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		input  string
+		want   []string // substrings of the formatted event; nil = dropped
+		absent []string
+	}{
+		{
+			name:  "zero filter keeps everything",
+			input: mixedAssistant,
+			want:  []string{"THINKING", "TOOL: Read"},
+		},
+		{
+			name:   "text drops tool calls",
+			filter: Filter{Text: true},
+			input:  mixedAssistant,
+			want:   []string{"Let me look"},
+			absent: []string{"TOOL: Read"},
+		},
+		{
+			name:   "tools drops text",
+			filter: Filter{Tools: true},
+			input:  mixedAssistant,
+			want:   []string{"TOOL: Read"},
+			absent: []string{"THINKING"},
+		},
+		{
+			name:   "errors keeps failed tool results",
+			filter: Filter{Errors: true},
+			input:  `{"type":"user","message":{"content":[{"type":"tool_result","content":"boom","is_error":true}]}}`,
+			want:   []string{"RESULT [ERROR]", "boom"},
+		},
+		{
+			name:   "errors drops successful tool results",
+			filter: Filter{Errors: true},
+			input:  `{"type":"user","message":{"content":[{"type":"tool_result","content":"fine"}]}}`,
+		},
+		{
+			name:   "errors keeps failed sessions",
+			filter: Filter{Errors: true},
+			input:  `{"type":"result","is_error":true,"num_turns":2}`,
+			want:   []string{"SESSION FAILED"},
+		},
+		{
+			name:   "headers are always kept",
+			filter: Filter{Errors: true},
+			input:  `{"type":"system","subtype":"run_start","timestamp":"2025-01-01T00:00:00Z"}`,
+			want:   []string{"RUN STARTED 2025-01-01T00:00:00Z"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := ParseEvent([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+
+			filtered := tt.filter.Apply(event)
+			if tt.want == nil {
+				if filtered != nil {
+					t.Errorf("Apply() = %+v, want nil", filtered)
+				}
+				return
+			}
+			if filtered == nil {
+				t.Fatal("Apply() = nil, want event")
+			}
+
+			output := NewFormatter().Format(filtered)
+			for _, check := range tt.want {
+				if !strings.Contains(output, check) {
+					t.Errorf("output missing %q\nGot:\n%s", check, output)
+				}
+			}
+			for _, check := range tt.absent {
+				if strings.Contains(output, check) {
+					t.Errorf("output contains %q\nGot:\n%s", check, output)
+				}
+			}
+		})
+	}
+}
+
+func TestRunStartEvent(t *testing.T) {
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	event := NewRunStartEvent(start)
+
+	got, ok := event.RunStart()
+	if !ok || !got.Equal(start) {
+		t.Errorf("RunStart() = %v, %v; want %v, true", got, ok, start)
+	}
+	if _, ok := (&Event{Type: EventTypeSystem, Subtype: "init"}).RunStart(); ok {
+		t.Error("RunStart() ok for an init event")
+	}
+}
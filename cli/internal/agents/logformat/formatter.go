@@ -25,6 +25,9 @@ type Formatter struct {
 
 	// MaxThinkingLen controls truncation of thinking text (0 = use default).
 	MaxThinkingLen int
+
+	// Filter limits the output to some kinds of content (zero = show all).
+	Filter Filter
 }
 
 // NewFormatter creates a Formatter with default settings.
@@ -38,10 +41,17 @@ func NewFormatter() *Formatter {
 // Format formats a single event into human-readable lines.
 // Returns empty string for events that don't need to be shown.
 func (f *Formatter) Format(event *Event) string {
+	if event = f.Filter.Apply(event); event == nil {
+		return ""
+	}
+
 	switch event.Type {
 	case EventTypeSystem:
-		if event.Subtype == "init" {
+		switch event.Subtype {
+		case "init":
 			return f.formatInit(event)
+		case SubtypeRunStart:
+			return f.formatRunStart(event)
 		}
 		return ""
 	case EventTypeAssistant:
@@ -78,6 +88,11 @@ func (f *Formatter) formatInit(event *Event) string {
 	return b.String()
 }
 
+// formatRunStart formats the marker sow writes at the start of a run.
+func (f *Formatter) formatRunStart(event *Event) string {
+	return fmt.Sprintf("%sRUN STARTED %s\n%s\n", doubleLine(), event.Timestamp, doubleLine())
+}
+
 // formatAssistant formats assistant messages (thinking and tool calls).
 func (f *Formatter) formatAssistant(event *Event) string {
 	if event.Message == nil {
@@ -0,0 +1,188 @@
+package logformat
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Stats aggregates the activity recorded in stream-json logs.
+//
+// Cost, turns, and wall time come from the result event that ends each
+// run. Token counts come from the result event's usage when present, and
+// otherwise from the usage of the run's assistant messages, so that runs
+// that never finished are still counted.
+type Stats struct {
+	Sessions            int            `json:"sessions"`
+	Runs                int            `json:"runs"`        // Runs that ended with a result event
+	FailedRuns          int            `json:"failed_runs"` // Runs whose result is an error
+	CostUSD             float64        `json:"cost_usd"`
+	InputTokens         int            `json:"input_tokens"`
+	OutputTokens        int            `json:"output_tokens"`
+	CacheCreationTokens int            `json:"cache_creation_tokens"`
+	CacheReadTokens     int            `json:"cache_read_tokens"`
+	Turns               int            `json:"turns"`
+	ToolCalls           map[string]int `json:"tool_calls"` // Calls by tool name
+	ToolErrors          int            `json:"tool_errors"`
+	WallTimeMS          int64          `json:"wall_time_ms"`
+}
+
+// Tokens returns the total number of tokens, cache tokens included.
+func (s Stats) Tokens() int {
+	return s.InputTokens + s.OutputTokens + s.CacheCreationTokens + s.CacheReadTokens
+}
+
+// TotalToolCalls returns the number of tool calls of every tool.
+func (s Stats) TotalToolCalls() int {
+	total := 0
+	for _, n := range s.ToolCalls {
+		total += n
+	}
+	return total
+}
+
+// WallTime returns the summed duration of the runs.
+func (s Stats) WallTime() time.Duration {
+	return time.Duration(s.WallTimeMS) * time.Millisecond
+}
+
+// Add adds other to s.
+func (s *Stats) Add(other Stats) {
+	s.Sessions += other.Sessions
+	s.Runs += other.Runs
+	s.FailedRuns += other.FailedRuns
+	s.CostUSD += other.CostUSD
+	s.InputTokens += other.InputTokens
+	s.OutputTokens += other.OutputTokens
+	s.CacheCreationTokens += other.CacheCreationTokens
+	s.CacheReadTokens += other.CacheReadTokens
+	s.Turns += other.Turns
+	s.ToolErrors += other.ToolErrors
+	s.WallTimeMS += other.WallTimeMS
+	for tool, n := range other.ToolCalls {
+		s.countTool(tool, n)
+	}
+}
+
+// countTool adds n calls of tool.
+func (s *Stats) countTool(tool string, n int) {
+	if s.ToolCalls == nil {
+		s.ToolCalls = make(map[string]int)
+	}
+	s.ToolCalls[tool] += n
+}
+
+// addUsage adds token counts.
+func (s *Stats) addUsage(u Usage) {
+	s.InputTokens += u.InputTokens
+	s.OutputTokens += u.OutputTokens
+	s.CacheCreationTokens += u.CacheCreationInputTokens
+	s.CacheReadTokens += u.CacheReadInputTokens
+}
+
+// ReadStats aggregates the stream-json events of one session log. Lines
+// that are not events are skipped. A log holding no events has zero
+// sessions.
+func ReadStats(input io.Reader) (Stats, error) {
+	scanner := bufio.NewScanner(input)
+
+	// Handle very long lines
+	const maxLineSize = 10 * 1024 * 1024 // 10MB
+	buf := make([]byte, maxLineSize)
+	scanner.Buffer(buf, maxLineSize)
+
+	var acc statsAccumulator
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		event, err := ParseEvent(line)
+		if err != nil {
+			continue
+		}
+		acc.add(event)
+	}
+	if err := scanner.Err(); err != nil {
+		return Stats{}, fmt.Errorf("failed to scan input: %w", err)
+	}
+
+	acc.endRun()
+	if acc.events > 0 {
+		acc.stats.Sessions = 1
+	}
+	return acc.stats, nil
+}
+
+// statsAccumulator builds the Stats of one session log.
+type statsAccumulator struct {
+	stats  Stats
+	events int
+
+	// Usage of the assistant messages of the current run, by message ID.
+	// Claude Code repeats a message's usage on every event of the message.
+	runUsage map[string]Usage
+}
+
+// add accounts for one event.
+func (a *statsAccumulator) add(event *Event) {
+	a.events++
+
+	switch event.Type {
+	case EventTypeSystem:
+		// A new run starts; count the tokens of one that never finished
+		a.endRun()
+	case EventTypeAssistant:
+		if event.Message == nil {
+			return
+		}
+		if event.Message.Usage != nil {
+			if a.runUsage == nil {
+				a.runUsage = make(map[string]Usage)
+			}
+			id := event.Message.ID
+			if id == "" {
+				id = fmt.Sprintf("#%d", a.events)
+			}
+			a.runUsage[id] = *event.Message.Usage
+		}
+		blocks, _ := event.Message.ParseContentBlocks()
+		for _, block := range blocks {
+			if block.Type == "tool_use" {
+				a.stats.countTool(block.Name, 1)
+			}
+		}
+	case EventTypeUser:
+		if event.Message == nil {
+			return
+		}
+		blocks, _ := event.Message.ParseContentBlocks()
+		for _, block := range blocks {
+			if block.Type == "tool_result" && block.IsError {
+				a.stats.ToolErrors++
+			}
+		}
+	case EventTypeResult:
+		a.stats.Runs++
+		if event.IsError {
+			a.stats.FailedRuns++
+		}
+		a.stats.CostUSD += event.TotalCostUSD
+		a.stats.Turns += event.NumTurns
+		a.stats.WallTimeMS += event.DurationMS
+		if event.Usage != nil {
+			a.stats.addUsage(*event.Usage)
+			a.runUsage = nil
+		}
+		a.endRun()
+	}
+}
+
+// endRun adds the message usage of the current run and starts a new one.
+func (a *statsAccumulator) endRun() {
+	for _, usage := range a.runUsage {
+		a.stats.addUsage(usage)
+	}
+	a.runUsage = nil
+}
@@ -0,0 +1,105 @@
+package logformat
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadStats(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		check func(t *testing.T, s Stats)
+	}{
+		{
+			name: "result usage, cost, and tools",
+			input: `{"type":"system","subtype":"init","session_id":"s1"}
+{"type":"assistant","message":{"id":"m1","usage":{"input_tokens":10,"output_tokens":5},"content":[{"type":"tool_use","name":"Read","input":{}}]}}
+{"type":"user","message":{"content":[{"type":"tool_result","content":"boom","is_error":true}]}}
+{"type":"assistant","message":{"id":"m2","content":[{"type":"tool_use","name":"Bash","input":{}},{"type":"tool_use","name":"Read","input":{}}]}}
+not json
+{"type":"result","subtype":"success","total_cost_usd":0.25,"num_turns":3,"duration_ms":1500,"usage":{"input_tokens":100,"output_tokens":50,"cache_creation_input_tokens":20,"cache_read_input_tokens":30}}
+`,
+			check: func(t *testing.T, s Stats) {
+				if s.Sessions != 1 || s.Runs != 1 || s.FailedRuns != 0 {
+					t.Errorf("sessions/runs/failed = %d/%d/%d, want 1/1/0", s.Sessions, s.Runs, s.FailedRuns)
+				}
+				if s.CostUSD != 0.25 || s.Turns != 3 || s.WallTimeMS != 1500 {
+					t.Errorf("cost/turns/wall = %v/%d/%d", s.CostUSD, s.Turns, s.WallTimeMS)
+				}
+				// Result usage replaces message usage
+				if s.InputTokens != 100 || s.OutputTokens != 50 || s.CacheCreationTokens != 20 || s.CacheReadTokens != 30 {
+					t.Errorf("tokens = %+v", s)
+				}
+				if s.ToolCalls["Read"] != 2 || s.ToolCalls["Bash"] != 1 || s.TotalToolCalls() != 3 {
+					t.Errorf("ToolCalls = %v", s.ToolCalls)
+				}
+				if s.ToolErrors != 1 {
+					t.Errorf("ToolErrors = %d, want 1", s.ToolErrors)
+				}
+			},
+		},
+		{
+			name: "message usage of unfinished runs is counted once per message",
+			input: `{"type":"system","subtype":"init"}
+{"type":"assistant","message":{"id":"m1","usage":{"input_tokens":10,"output_tokens":5},"content":[{"type":"text","text":"a"}]}}
+{"type":"assistant","message":{"id":"m1","usage":{"input_tokens":10,"output_tokens":5},"content":[{"type":"text","text":"b"}]}}
+{"type":"system","subtype":"init"}
+{"type":"assistant","message":{"id":"m2","usage":{"input_tokens":7,"output_tokens":3},"content":[]}}
+`,
+			check: func(t *testing.T, s Stats) {
+				if s.Runs != 0 || s.InputTokens != 17 || s.OutputTokens != 8 {
+					t.Errorf("runs/input/output = %d/%d/%d, want 0/17/8", s.Runs, s.InputTokens, s.OutputTokens)
+				}
+			},
+		},
+		{
+			name: "failed runs",
+			input: `{"type":"result","is_error":true,"total_cost_usd":0.1,"num_turns":1}
+{"type":"result","total_cost_usd":0.2,"num_turns":2}
+`,
+			check: func(t *testing.T, s Stats) {
+				if s.Runs != 2 || s.FailedRuns != 1 || s.Turns != 3 {
+					t.Errorf("runs/failed/turns = %d/%d/%d, want 2/1/3", s.Runs, s.FailedRuns, s.Turns)
+				}
+			},
+		},
+		{
+			name:  "no events",
+			input: "plain output\n",
+			check: func(t *testing.T, s Stats) {
+				if s.Sessions != 0 || s.Tokens() != 0 {
+					t.Errorf("ReadStats() = %+v, want zero", s)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ReadStats(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("ReadStats() error = %v", err)
+			}
+			tt.check(t, s)
+		})
+	}
+}
+
+func TestStats_Add(t *testing.T) {
+	a := Stats{Sessions: 1, CostUSD: 0.5, InputTokens: 10, ToolCalls: map[string]int{"Read": 1}}
+	a.Add(Stats{Sessions: 1, CostUSD: 0.25, InputTokens: 5, ToolCalls: map[string]int{"Read": 2, "Bash": 1}})
+
+	if a.Sessions != 2 || a.CostUSD != 0.75 || a.InputTokens != 15 {
+		t.Errorf("Add() = %+v", a)
+	}
+	if a.ToolCalls["Read"] != 3 || a.ToolCalls["Bash"] != 1 {
+		t.Errorf("ToolCalls = %v", a.ToolCalls)
+	}
+
+	var zero Stats
+	zero.Add(Stats{ToolCalls: map[string]int{"Edit": 1}})
+	if zero.ToolCalls["Edit"] != 1 {
+		t.Errorf("Add() to zero stats ToolCalls = %v", zero.ToolCalls)
+	}
+}
@@ -6,6 +6,7 @@
 //
 // Event types:
 //   - system (init): Session metadata (model, session_id, cwd, tools)
+//   - system (run_start): Start of a run, written by sow (timestamp)
 //   - assistant: Model output (text thinking, tool_use calls)
 //   - user: Tool results (stdout, stderr, errors)
 //   - result: Final summary (duration, cost, turns, outcome)
@@ -14,6 +15,7 @@ package logformat
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 )
 
 // EventType represents the type of stream-json event.
@@ -27,6 +29,11 @@ const (
 	EventTypeResult    EventType = "result"
 )
 
+// SubtypeRunStart is the subtype of the system events sow writes to a raw
+// log before each run of an agent command. Claude Code's events carry no
+// timestamps, so these markers date the events that follow them.
+const SubtypeRunStart = "run_start"
+
 // Event represents a parsed stream-json event.
 // This is the common structure across all event types.
 type Event struct {
@@ -55,6 +62,10 @@ type Event struct {
 	NumTurns      int     `json:"num_turns,omitempty"`
 	Result        string  `json:"result,omitempty"`
 	TotalCostUSD  float64 `json:"total_cost_usd,omitempty"`
+	Usage         *Usage  `json:"usage,omitempty"` // Token totals of the run
+
+	// For run start markers (RFC 3339)
+	Timestamp string `json:"timestamp,omitempty"`
 }
 
 // Message represents the message content in assistant/user events.
@@ -131,6 +142,21 @@ type Usage struct {
 	CacheReadInputTokens     int `json:"cache_read_input_tokens,omitempty"`
 }
 
+// NewRunStartEvent returns the marker sow logs when a run starts at t.
+func NewRunStartEvent(t time.Time) *Event {
+	return &Event{Type: EventTypeSystem, Subtype: SubtypeRunStart, Timestamp: t.UTC().Format(time.RFC3339)}
+}
+
+// RunStart returns the start time of a run start marker. ok is false for
+// any other event.
+func (e *Event) RunStart() (t time.Time, ok bool) {
+	if e.Type != EventTypeSystem || e.Subtype != SubtypeRunStart {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, e.Timestamp)
+	return t, err == nil
+}
+
 // ParseEvent parses a single JSON line into an Event.
 func ParseEvent(line []byte) (*Event, error) {
 	var event Event
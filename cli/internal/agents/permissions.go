@@ -0,0 +1,149 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// Permissions is an agent's tool permission profile. Executors translate
+// it into the flags of their CLI; the zero value places no restrictions
+// on the agent.
+//
+// Tool names follow Claude Code (Read, Edit, Write, Bash, WebFetch, ...).
+// Command patterns are shell command lines where a trailing "*" matches
+// any arguments, e.g. "go test *".
+type Permissions struct {
+	// ReadOnly keeps the agent from editing files outside .sow/, where
+	// it records its outputs.
+	ReadOnly bool `yaml:"read_only"`
+
+	// AllowedTools are tools the agent may use without asking.
+	AllowedTools []string `yaml:"allowed_tools"`
+
+	// DeniedTools are tools the agent may never use.
+	DeniedTools []string `yaml:"denied_tools"`
+
+	// AllowedCommands restricts shell use to commands matching these
+	// patterns. Empty leaves the shell unrestricted.
+	AllowedCommands []string `yaml:"allowed_commands"`
+}
+
+// sowCommands allows agents without general shell access to run the sow
+// CLI, which every agent prompt relies on to read and record its work.
+var sowCommands = []string{"sow *"}
+
+// reviewerCommands lets the reviewer inspect the changes and build and
+// test them, without the commands of a full shell that could modify them.
+var reviewerCommands = append(append([]string(nil), sowCommands...),
+	"git diff *", "git log *", "git show *", "git status *",
+	"go build *", "go vet *", "go test *",
+	"npm test *", "npm run build *", "cargo build *", "cargo test *", "pytest *",
+	"rg *", "grep *", "ls *",
+)
+
+// Restricted reports whether the profile restricts the agent in a way an
+// executor's permission bypass (yolo mode) would defeat.
+func (p Permissions) Restricted() bool {
+	return p.ReadOnly || len(p.AllowedCommands) > 0
+}
+
+// IsZero reports whether the profile places no restrictions on the agent.
+func (p Permissions) IsZero() bool {
+	return !p.ReadOnly && len(p.AllowedTools) == 0 && len(p.DeniedTools) == 0 && len(p.AllowedCommands) == 0
+}
+
+// String summarizes the profile for display, e.g.
+// "read-only; shell: sow *".
+func (p Permissions) String() string {
+	if p.IsZero() {
+		return "unrestricted"
+	}
+	var parts []string
+	if p.ReadOnly {
+		parts = append(parts, "read-only")
+	}
+	if len(p.AllowedTools) > 0 {
+		parts = append(parts, "allow: "+strings.Join(p.AllowedTools, ", "))
+	}
+	if len(p.DeniedTools) > 0 {
+		parts = append(parts, "deny: "+strings.Join(p.DeniedTools, ", "))
+	}
+	if len(p.AllowedCommands) > 0 {
+		parts = append(parts, "shell: "+strings.Join(p.AllowedCommands, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Validate checks that no tool name or command pattern is blank.
+func (p Permissions) Validate() error {
+	for _, list := range []struct {
+		field  string
+		values []string
+	}{
+		{"allowed_tools", p.AllowedTools},
+		{"denied_tools", p.DeniedTools},
+		{"allowed_commands", p.AllowedCommands},
+	} {
+		for _, value := range list.values {
+			if strings.TrimSpace(value) == "" {
+				return errors.New("permissions." + list.field + " must not contain empty entries")
+			}
+		}
+	}
+	return nil
+}
+
+// claudeArgs translates the profile into Claude Code flags.
+//
+// File edits are accepted automatically unless the profile is read-only,
+// in which case only edits under .sow/ are. Shell commands are limited
+// to the allowed patterns, as Bash(<prefix>:*) rules.
+func (p Permissions) claudeArgs() []string {
+	mode := "acceptEdits"
+	allowed := append([]string(nil), p.AllowedTools...)
+	if p.ReadOnly {
+		mode = "default"
+		allowed = append(allowed, "Edit(.sow/**)")
+	}
+	for _, pattern := range p.AllowedCommands {
+		allowed = append(allowed, "Bash("+claudeCommandRule(pattern)+")")
+	}
+
+	args := []string{"--permission-mode", mode}
+	if len(allowed) > 0 {
+		args = append(args, "--allowedTools", strings.Join(allowed, ","))
+	}
+	if len(p.DeniedTools) > 0 {
+		args = append(args, "--disallowedTools", strings.Join(p.DeniedTools, ","))
+	}
+	return args
+}
+
+// claudeCommandRule converts a command pattern into Claude Code's prefix
+// rule syntax: "go test *" becomes "go test:*".
+func claudeCommandRule(pattern string) string {
+	pattern = strings.TrimSpace(pattern)
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.TrimSpace(prefix) + ":*"
+	}
+	return pattern
+}
+
+// permissionsKey is the context key for the profile set by WithPermissions.
+type permissionsKey struct{}
+
+// WithPermissions returns a context under which Resume applies the given
+// permission profile. Resume is not passed the agent, so callers resuming
+// an agent's session set its profile this way; Spawn uses the profile of
+// the agent it is given.
+func WithPermissions(ctx context.Context, permissions Permissions) context.Context {
+	return context.WithValue(ctx, permissionsKey{}, permissions)
+}
+
+// permissionsFromContext returns the profile set by WithPermissions, or
+// the unrestricted profile.
+func permissionsFromContext(ctx context.Context) Permissions {
+	permissions, _ := ctx.Value(permissionsKey{}).(Permissions)
+	return permissions
+}
@@ -0,0 +1,313 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/jmgilman/sow/cli/internal/prompts"
+)
+
+// TestPermissions_String verifies the summary shown by 'sow agent list'.
+func TestPermissions_String(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions Permissions
+		want        string
+	}{
+		{
+			name: "unrestricted",
+			want: "unrestricted",
+		},
+		{
+			name:        "standard researcher",
+			permissions: Researcher.Permissions,
+			want:        "read-only; shell: sow *",
+		},
+		{
+			name: "all fields",
+			permissions: Permissions{
+				ReadOnly:        true,
+				AllowedTools:    []string{"Read", "Grep"},
+				DeniedTools:     []string{"WebFetch"},
+				AllowedCommands: []string{"go test *"},
+			},
+			want: "read-only; allow: Read, Grep; deny: WebFetch; shell: go test *",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.permissions.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestPermissions_Validate verifies blank tool names and command patterns
+// are rejected.
+func TestPermissions_Validate(t *testing.T) {
+	if err := Reviewer.Permissions.Validate(); err != nil {
+		t.Errorf("Validate() error = %v, want nil", err)
+	}
+	err := Permissions{AllowedCommands: []string{"sow *", " "}}.Validate()
+	if err == nil || !strings.Contains(err.Error(), "permissions.allowed_commands") {
+		t.Errorf("Validate() error = %v, want allowed_commands error", err)
+	}
+}
+
+// TestPermissions_ClaudeArgs verifies profiles become Claude Code flags.
+func TestPermissions_ClaudeArgs(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions Permissions
+		want        []string
+	}{
+		{
+			name: "unrestricted accepts edits",
+			want: []string{"--permission-mode", "acceptEdits"},
+		},
+		{
+			name:        "read-only only accepts edits under .sow",
+			permissions: Permissions{ReadOnly: true},
+			want:        []string{"--permission-mode", "default", "--allowedTools", "Edit(.sow/**)"},
+		},
+		{
+			name:        "read-only with commands limits the shell",
+			permissions: Permissions{ReadOnly: true, AllowedCommands: []string{"sow *"}},
+			want:        []string{"--permission-mode", "default", "--allowedTools", "Edit(.sow/**),Bash(sow:*)"},
+		},
+		{
+			name: "tools and commands",
+			permissions: Permissions{
+				AllowedTools:    []string{"WebFetch"},
+				DeniedTools:     []string{"WebSearch", "NotebookEdit"},
+				AllowedCommands: []string{"go test *", "make lint"},
+			},
+			want: []string{
+				"--permission-mode", "acceptEdits",
+				"--allowedTools", "WebFetch,Bash(go test:*),Bash(make lint)",
+				"--disallowedTools", "WebSearch,NotebookEdit",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.permissions.claudeArgs(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("claudeArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// reviewerAllowedTools is the --allowedTools value of the reviewer's profile.
+const reviewerAllowedTools = "Edit(.sow/**),Bash(sow:*),Bash(git diff:*),Bash(git log:*),Bash(git show:*),Bash(git status:*)," +
+	"Bash(go build:*),Bash(go vet:*),Bash(go test:*),Bash(npm test:*),Bash(npm run build:*),Bash(cargo build:*)," +
+	"Bash(cargo test:*),Bash(pytest:*),Bash(rg:*),Bash(grep:*),Bash(ls:*)"
+
+// TestReviewer_ClaudeArgsLimitShell verifies the read-only reviewer is
+// never given the whole shell, through which it could edit files.
+func TestReviewer_ClaudeArgsLimitShell(t *testing.T) {
+	args := Reviewer.Permissions.claudeArgs()
+	for i, arg := range args {
+		if arg != "--allowedTools" || i+1 == len(args) {
+			continue
+		}
+		for _, tool := range strings.Split(args[i+1], ",") {
+			if tool == "Bash" || tool == "Bash(*)" || tool == "Bash(:*)" {
+				t.Errorf("allowed tools %q give the reviewer the whole shell", args[i+1])
+			}
+		}
+	}
+}
+
+// TestClaudeExecutor_RestrictedAgent verifies a restricted profile is
+// passed on spawn and resume and is not bypassed by yolo mode.
+func TestClaudeExecutor_RestrictedAgent(t *testing.T) {
+	runner := &MockCommandRunner{}
+	executor := NewClaudeExecutorWithRunner(true, "", "", nil, runner)
+
+	if err := executor.Spawn(context.Background(), Reviewer, "Review", "session-1"); err != nil {
+		t.Fatalf("Spawn() error = %v", err)
+	}
+	wantArgs := []string{
+		"--print", "--verbose", "--output-format", "stream-json",
+		"--permission-mode", "default", "--allowedTools", reviewerAllowedTools,
+		"--session-id", "session-1",
+	}
+	if !reflect.DeepEqual(runner.LastArgs, wantArgs) {
+		t.Errorf("spawn args = %v, want %v", runner.LastArgs, wantArgs)
+	}
+
+	ctx := WithPermissions(context.Background(), Reviewer.Permissions)
+	if err := executor.Resume(ctx, "session-1", "Continue"); err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	wantArgs = []string{
+		"--print", "--verbose", "--output-format", "stream-json",
+		"--permission-mode", "default", "--allowedTools", reviewerAllowedTools,
+		"--resume", "session-1",
+	}
+	if !reflect.DeepEqual(runner.LastArgs, wantArgs) {
+		t.Errorf("resume args = %v, want %v", runner.LastArgs, wantArgs)
+	}
+}
+
+// TestCursorExecutor_RestrictedAgent verifies yolo mode's --force is
+// withheld from agents with a permission profile.
+func TestCursorExecutor_RestrictedAgent(t *testing.T) {
+	runner := &MockCommandRunner{}
+	executor := NewCursorExecutorWithRunner(true, "", nil, runner)
+
+	if err := executor.Spawn(context.Background(), Reviewer, "Review", ""); err != nil {
+		t.Fatalf("Spawn() error = %v", err)
+	}
+	ctx := WithPermissions(context.Background(), Permissions{DeniedTools: []string{"Shell"}})
+	if err := executor.Resume(ctx, "session-1", "Continue"); err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	for _, arg := range runner.LastArgs {
+		if arg == "--force" {
+			t.Errorf("args = %v, want no --force", runner.LastArgs)
+		}
+	}
+}
+
+// TestCommandExecutor_Permissions verifies the profile is available to
+// argument templates on spawn and resume.
+func TestCommandExecutor_Permissions(t *testing.T) {
+	spec := testCommandSpec()
+	spec.SpawnArgs = []string{"run", "{{if .Permissions.ReadOnly}}--read-only{{end}}", `{{join .Permissions.AllowedCommands ","}}`}
+	spec.ResumeArgs = []string{"resume", `{{join .Permissions.DeniedTools ","}}`}
+	runner := &MockCommandRunner{}
+	executor := NewCommandExecutorWithRunner("a", spec, "", runner)
+
+	if err := executor.Spawn(context.Background(), Planner, "Plan", "session-1"); err != nil {
+		t.Fatalf("Spawn() error = %v", err)
+	}
+	if want := []string{"run", "--read-only", "sow *"}; !reflect.DeepEqual(runner.LastArgs, want) {
+		t.Errorf("spawn args = %v, want %v", runner.LastArgs, want)
+	}
+
+	ctx := WithPermissions(context.Background(), Permissions{DeniedTools: []string{"web", "shell"}})
+	if err := executor.Resume(ctx, "session-1", "Continue"); err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	if want := []string{"resume", "web,shell"}; !reflect.DeepEqual(runner.LastArgs, want) {
+		t.Errorf("resume args = %v, want %v", runner.LastArgs, want)
+	}
+}
+
+// TestPolicyExecutor_RetryKeepsPermissions verifies a spawn retried by
+// resuming its session keeps the agent's profile.
+func TestPolicyExecutor_RetryKeepsPermissions(t *testing.T) {
+	var got Permissions
+	mock := &MockExecutor{
+		SpawnFunc: func(_ context.Context, _ *Agent, _ string, _ string) error {
+			return errors.New("crashed")
+		},
+		ResumeFunc: func(ctx context.Context, _ string, _ string) error {
+			got = permissionsFromContext(ctx)
+			return nil
+		},
+		SupportsResumptionFunc: func() bool { return true },
+		SessionStartedFunc:     func(string) bool { return true },
+	}
+	executor := NewPolicyExecutor(mock, ExecutionPolicy{MaxRetries: 1})
+
+	if err := executor.Spawn(context.Background(), Reviewer, "Review", "session-1"); err != nil {
+		t.Fatalf("Spawn() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, Reviewer.Permissions) {
+		t.Errorf("resume permissions = %+v, want %+v", got, Reviewer.Permissions)
+	}
+}
+
+// sowCommandPattern matches sow invocations in prompts, in code blocks or
+// inline code.
+var sowCommandPattern = regexp.MustCompile("(?m)(?:^\\s*|`)(sow [^`\\n]+)")
+
+// TestStandardAgents_CanRunPromptCommands verifies each standard agent's
+// profile lets it run the sow commands its prompt and the guidance it
+// loads tell it to run, and lets the reviewer run the test suite.
+func TestStandardAgents_CanRunPromptCommands(t *testing.T) {
+	extra := map[string][]string{
+		"reviewer": {
+			"git diff origin/main...HEAD", "go build ./...", "go test ./... -v", "npm run build", "npm test",
+			"cargo build", "cargo test", "pytest", `rg "TODO|FIXME" --type go`,
+		},
+	}
+
+	for _, agent := range StandardAgents() {
+		t.Run(agent.Name, func(t *testing.T) {
+			prompt, err := agent.LoadPrompt()
+			if err != nil {
+				t.Fatalf("LoadPrompt() error = %v", err)
+			}
+
+			var commands []string
+			for _, match := range sowCommandPattern.FindAllStringSubmatch(prompt, -1) {
+				commands = append(commands, match[1])
+				guidance, ok := strings.CutPrefix(match[1], "sow prompt ")
+				if !ok {
+					continue
+				}
+				content, err := prompts.FS.ReadFile("templates/" + strings.TrimSpace(guidance) + ".md")
+				if err != nil {
+					// Not every agent's base guidance has been written yet
+					continue
+				}
+				for _, match := range sowCommandPattern.FindAllStringSubmatch(string(content), -1) {
+					commands = append(commands, match[1])
+				}
+			}
+			if len(commands) == 0 {
+				t.Fatal("expected the prompt to run sow commands")
+			}
+			commands = append(commands, extra[agent.Name]...)
+
+			for _, command := range commands {
+				if !claudeAllowsCommand(agent.Permissions, command) {
+					t.Errorf("%s (%s) may not run %q", agent.Name, agent.Permissions, command)
+				}
+			}
+		})
+	}
+}
+
+// claudeAllowsCommand reports whether Claude Code runs command without
+// asking under the profile. A profile that is neither read-only nor
+// restricts the shell leaves it to the user's own settings, which is taken
+// as allowed.
+func claudeAllowsCommand(p Permissions, command string) bool {
+	args := p.claudeArgs()
+	var allowed []string
+	for i, arg := range args {
+		if arg == "--allowedTools" && i+1 < len(args) {
+			allowed = strings.Split(args[i+1], ",")
+		}
+	}
+	if !p.ReadOnly && len(p.AllowedCommands) == 0 {
+		return true
+	}
+	for _, tool := range allowed {
+		if tool == "Bash" {
+			return true
+		}
+		rule, ok := strings.CutPrefix(tool, "Bash(")
+		if !ok {
+			continue
+		}
+		rule = strings.TrimSuffix(rule, ")")
+		if prefix, ok := strings.CutSuffix(rule, ":*"); ok && strings.HasPrefix(command, prefix) {
+			return true
+		}
+		if rule == command {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,438 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmgilman/sow/libs/schemas"
+)
+
+// Failure kinds for ExecutionPolicy.RetryOn. A specific exit code is
+// written as "exit:<code>" (e.g. "exit:137").
+const (
+	// RetryOnTimeout retries attempts that hit the policy timeout.
+	RetryOnTimeout = "timeout"
+	// RetryOnError retries any failed attempt, timeouts included.
+	RetryOnError = "error"
+)
+
+// Outcomes of an Attempt.
+const (
+	OutcomeSucceeded = "succeeded"
+	OutcomeFailed    = "failed"
+	OutcomeTimeout   = "timeout"
+	OutcomeCanceled  = "canceled"
+)
+
+// Defaults for policies loaded from the user config.
+const (
+	defaultBackoff    = 10 * time.Second
+	defaultMaxBackoff = 5 * time.Minute
+)
+
+// ExecutionPolicy bounds how long an agent may run and whether failed runs
+// are retried. The zero value runs each agent once without a time limit.
+type ExecutionPolicy struct {
+	Timeout    time.Duration // Wall time limit of one attempt (0 disables it)
+	MaxRetries int           // Retries after the first failed attempt
+	Backoff    time.Duration // Delay before the first retry, doubled for each further retry
+	MaxBackoff time.Duration // Upper bound of the delay (0 disables it)
+	RetryOn    []string      // Retryable failures (empty retries any failure)
+}
+
+// retryable reports whether the policy retries a failed attempt.
+func (p ExecutionPolicy) retryable(attempt Attempt) bool {
+	if len(p.RetryOn) == 0 {
+		return true
+	}
+	for _, kind := range p.RetryOn {
+		switch {
+		case kind == RetryOnError:
+			return true
+		case kind == RetryOnTimeout && attempt.Outcome == OutcomeTimeout:
+			return true
+		case attempt.Outcome == OutcomeFailed && attempt.ExitCode >= 0 &&
+			kind == "exit:"+strconv.Itoa(attempt.ExitCode):
+			return true
+		}
+	}
+	return false
+}
+
+// delay returns how long to wait before the given retry (1 for the first).
+func (p ExecutionPolicy) delay(retry int) time.Duration {
+	d := p.Backoff
+	for i := 1; i < retry; i++ {
+		d *= 2
+		if p.MaxBackoff > 0 && d >= p.MaxBackoff {
+			break
+		}
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	return d
+}
+
+// mergePolicyConfig returns base with the fields set in override replacing
+// its own. Either may be nil.
+func mergePolicyConfig(base, override *schemas.ExecutionPolicy) *schemas.ExecutionPolicy {
+	if override == nil {
+		return base
+	}
+	if base == nil {
+		return override
+	}
+	merged := *base
+	if override.Timeout != nil {
+		merged.Timeout = override.Timeout
+	}
+	if override.Max_retries != nil {
+		merged.Max_retries = override.Max_retries
+	}
+	if override.Backoff != nil {
+		merged.Backoff = override.Backoff
+	}
+	if override.Max_backoff != nil {
+		merged.Max_backoff = override.Max_backoff
+	}
+	if override.Retry_on != nil {
+		merged.Retry_on = override.Retry_on
+	}
+	return &merged
+}
+
+// policyFromConfig converts a policy from the user config, applying the
+// default backoff. A nil config yields the zero policy.
+func policyFromConfig(config *schemas.ExecutionPolicy) (ExecutionPolicy, error) {
+	if config == nil {
+		return ExecutionPolicy{}, nil
+	}
+	policy := ExecutionPolicy{Backoff: defaultBackoff, MaxBackoff: defaultMaxBackoff}
+
+	durations := []struct {
+		field string
+		value *string
+		dest  *time.Duration
+	}{
+		{"timeout", config.Timeout, &policy.Timeout},
+		{"backoff", config.Backoff, &policy.Backoff},
+		{"max_backoff", config.Max_backoff, &policy.MaxBackoff},
+	}
+	for _, d := range durations {
+		if d.value == nil {
+			continue
+		}
+		parsed, err := time.ParseDuration(*d.value)
+		if err != nil || parsed < 0 {
+			return ExecutionPolicy{}, fmt.Errorf("invalid %s %q", d.field, *d.value)
+		}
+		*d.dest = parsed
+	}
+	if config.Max_retries != nil {
+		policy.MaxRetries = int(*config.Max_retries)
+	}
+	policy.RetryOn = config.Retry_on
+	return policy, nil
+}
+
+// Attempt records one run of an agent under an ExecutionPolicy.
+type Attempt struct {
+	Number    int           // 1 for the first attempt
+	Action    string        // "spawn" or "resume"
+	Executor  string        // Name of the executor that ran the agent
+	StartedAt time.Time     // When the attempt started
+	Duration  time.Duration // Wall time of the attempt
+	Outcome   string        // OutcomeSucceeded, OutcomeFailed, OutcomeTimeout, or OutcomeCanceled
+	ExitCode  int           // Exit code of the agent process, -1 if it did not exit normally
+	Error     string        // Error of a failed attempt
+}
+
+// AttemptsMetadataKey is the task metadata key attempts are recorded under.
+const AttemptsMetadataKey = "attempts"
+
+// Metadata returns the attempt in the form it is stored in task metadata.
+func (a Attempt) Metadata() map[string]interface{} {
+	m := map[string]interface{}{
+		"attempt":    a.Number,
+		"action":     a.Action,
+		"executor":   a.Executor,
+		"started_at": a.StartedAt.UTC().Format(time.RFC3339),
+		"duration":   a.Duration.Round(time.Millisecond).String(),
+		"outcome":    a.Outcome,
+	}
+	if a.ExitCode >= 0 {
+		m["exit_code"] = a.ExitCode
+	}
+	if a.Error != "" {
+		m["error"] = a.Error
+	}
+	return m
+}
+
+// AppendAttempt returns metadata with the attempt appended to the list
+// under AttemptsMetadataKey. A nil metadata map is allocated.
+func AppendAttempt(metadata map[string]interface{}, attempt Attempt) map[string]interface{} {
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+	list, _ := metadata[AttemptsMetadataKey].([]interface{})
+	metadata[AttemptsMetadataKey] = append(list, attempt.Metadata())
+	return metadata
+}
+
+// AttemptsFromMetadata decodes the attempts recorded in task metadata.
+// Malformed entries are skipped.
+func AttemptsFromMetadata(metadata map[string]interface{}) []Attempt {
+	list, _ := metadata[AttemptsMetadataKey].([]interface{})
+	attempts := make([]Attempt, 0, len(list))
+	for _, item := range list {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		attempt := Attempt{ExitCode: -1}
+		attempt.Number, _ = metadataInt(m["attempt"])
+		attempt.Action, _ = m["action"].(string)
+		attempt.Executor, _ = m["executor"].(string)
+		attempt.Outcome, _ = m["outcome"].(string)
+		attempt.Error, _ = m["error"].(string)
+		if s, ok := m["started_at"].(string); ok {
+			attempt.StartedAt, _ = time.Parse(time.RFC3339, s)
+		} else if t, ok := m["started_at"].(time.Time); ok {
+			attempt.StartedAt = t
+		}
+		if s, ok := m["duration"].(string); ok {
+			attempt.Duration, _ = time.ParseDuration(s)
+		}
+		if code, ok := metadataInt(m["exit_code"]); ok {
+			attempt.ExitCode = code
+		}
+		attempts = append(attempts, attempt)
+	}
+	return attempts
+}
+
+// metadataInt converts a number decoded from YAML or JSON to an int.
+func metadataInt(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	case uint64:
+		return int(n), true
+	case float64:
+		return int(n), true
+	}
+	return 0, false
+}
+
+// attemptRecorderKey is the context key for the recorder set by
+// WithAttemptRecorder.
+type attemptRecorderKey struct{}
+
+// WithAttemptRecorder returns a context under which a PolicyExecutor calls
+// record after each attempt, so callers can persist attempts as they
+// happen (e.g. in task metadata).
+func WithAttemptRecorder(ctx context.Context, record func(Attempt)) context.Context {
+	return context.WithValue(ctx, attemptRecorderKey{}, record)
+}
+
+// recordAttempt passes attempt to the recorder carried by ctx, if any.
+func recordAttempt(ctx context.Context, attempt Attempt) {
+	if record, ok := ctx.Value(attemptRecorderKey{}).(func(Attempt)); ok {
+		record(attempt)
+	}
+}
+
+// retryCheckKey is the context key for the check set by WithRetryCheck.
+type retryCheckKey struct{}
+
+// WithRetryCheck returns a context under which a PolicyExecutor calls check
+// before each retry, so callers can stop retrying once a retry would not
+// be allowed to start (e.g. a spent budget). An error from check ends the
+// run with the failed attempt's error and its own.
+func WithRetryCheck(ctx context.Context, check func() error) context.Context {
+	return context.WithValue(ctx, retryCheckKey{}, check)
+}
+
+// checkRetry calls the check carried by ctx, if any.
+func checkRetry(ctx context.Context) error {
+	if check, ok := ctx.Value(retryCheckKey{}).(func() error); ok {
+		return check()
+	}
+	return nil
+}
+
+// PolicyExecutor wraps an Executor to enforce an ExecutionPolicy: each
+// attempt is bounded by the policy timeout, retryable failures are retried
+// with exponential backoff once the check set by WithRetryCheck allows it,
+// and every attempt is reported to the recorder set by WithAttemptRecorder.
+//
+// A failed spawn is retried by resuming its session when the executor
+// supports resumption, so the agent picks up where it stopped; otherwise
+// the call is repeated as is.
+//
+// Example usage:
+//
+//	executor := NewPolicyExecutor(claude, ExecutionPolicy{
+//	    Timeout:    30 * time.Minute,
+//	    MaxRetries: 2,
+//	    Backoff:    10 * time.Second,
+//	})
+//	err := executor.Spawn(ctx, agents.Implementer, prompt, sessionID)
+type PolicyExecutor struct {
+	Executor
+	policy ExecutionPolicy
+	sleep  func(ctx context.Context, d time.Duration) error
+}
+
+// NewPolicyExecutor creates a PolicyExecutor enforcing policy on executor.
+func NewPolicyExecutor(executor Executor, policy ExecutionPolicy) *PolicyExecutor {
+	return &PolicyExecutor{Executor: executor, policy: policy, sleep: sleepContext}
+}
+
+// Policy returns the policy the executor enforces.
+func (e *PolicyExecutor) Policy() ExecutionPolicy {
+	return e.policy
+}
+
+// Unwrap returns the wrapped executor.
+func (e *PolicyExecutor) Unwrap() Executor {
+	return e.Executor
+}
+
+// Spawn spawns the agent through the wrapped executor under the policy.
+// A failed spawn is retried by resuming its session if the executor
+// supports resumption and reports the session as started (see
+// SessionChecker); otherwise it is spawned again.
+func (e *PolicyExecutor) Spawn(ctx context.Context, agent *Agent, prompt string, sessionID string) error {
+	spawned := false
+	return e.run(ctx, func(ctx context.Context) (string, error) {
+		if spawned && e.SupportsResumption() && e.sessionStarted(sessionID) {
+			return "resume", e.Executor.Resume(WithPermissions(ctx, agent.Permissions), sessionID, retryPrompt+prompt)
+		}
+		spawned = true
+		return "spawn", e.Executor.Spawn(ctx, agent, prompt, sessionID)
+	})
+}
+
+// Resume resumes the session through the wrapped executor under the policy.
+func (e *PolicyExecutor) Resume(ctx context.Context, sessionID string, prompt string) error {
+	return e.run(ctx, func(ctx context.Context) (string, error) {
+		return "resume", e.Executor.Resume(ctx, sessionID, prompt)
+	})
+}
+
+// sessionStarted reports whether the wrapped executor knows the session
+// to exist. Executors that cannot tell are assumed not to have created it.
+func (e *PolicyExecutor) sessionStarted(sessionID string) bool {
+	checker, ok := e.Executor.(SessionChecker)
+	return ok && checker.SessionStarted(sessionID)
+}
+
+// retryPrompt precedes the prompt when a failed spawn is retried by
+// resuming its session.
+const retryPrompt = "Your previous run was interrupted before it finished. Continue where you left off.\n\n"
+
+// run calls fn until it succeeds, fails in a way the policy does not
+// retry, runs out of retries, or ctx is done. fn returns the action it
+// took ("spawn" or "resume").
+func (e *PolicyExecutor) run(ctx context.Context, fn func(context.Context) (string, error)) error {
+	for number := 1; ; number++ {
+		attempt, err := e.attempt(ctx, number, fn)
+		recordAttempt(ctx, attempt)
+		if err == nil {
+			return nil
+		}
+
+		if attempt.Outcome == OutcomeCanceled || number > e.policy.MaxRetries || !e.policy.retryable(attempt) {
+			if number > 1 {
+				return fmt.Errorf("gave up after %d attempts: %w", number, err)
+			}
+			return err
+		}
+		if err := e.sleep(ctx, e.policy.delay(number)); err != nil {
+			return fmt.Errorf("%s canceled while waiting to retry: %w", attempt.Action, err)
+		}
+		if checkErr := checkRetry(ctx); checkErr != nil {
+			return fmt.Errorf("not retrying after %d attempt(s): %w (last error: %w)", number, checkErr, err)
+		}
+	}
+}
+
+// attempt makes one call to fn bounded by the policy timeout and
+// classifies its outcome.
+func (e *PolicyExecutor) attempt(ctx context.Context, number int, fn func(context.Context) (string, error)) (Attempt, error) {
+	attemptCtx, cancel := ctx, context.CancelFunc(func() {})
+	if e.policy.Timeout > 0 {
+		attemptCtx, cancel = context.WithTimeout(ctx, e.policy.Timeout)
+	}
+	defer cancel()
+
+	attempt := Attempt{
+		Number:    number,
+		Executor:  e.Name(),
+		StartedAt: time.Now(),
+		Outcome:   OutcomeSucceeded,
+	}
+	action, err := fn(attemptCtx)
+	attempt.Action = action
+	attempt.Duration = time.Since(attempt.StartedAt)
+	if err == nil {
+		return attempt, nil
+	}
+
+	attempt.ExitCode = exitCode(err)
+	switch {
+	case ctx.Err() != nil:
+		attempt.Outcome = OutcomeCanceled
+	case errors.Is(attemptCtx.Err(), context.DeadlineExceeded):
+		attempt.Outcome = OutcomeTimeout
+		err = fmt.Errorf("%s timed out after %s: %w", action, e.policy.Timeout, err)
+	default:
+		attempt.Outcome = OutcomeFailed
+	}
+	attempt.Error = firstLine(err.Error())
+	return attempt, err
+}
+
+// exitCode returns the exit code of the process behind err, or -1 if err
+// does not come from a process that exited normally.
+func exitCode(err error) int {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+// firstLine returns the first line of s, dropping captured stderr.
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Compile-time check that PolicyExecutor implements Executor.
+var _ Executor = (*PolicyExecutor)(nil)
@@ -0,0 +1,373 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmgilman/sow/libs/schemas"
+	"gopkg.in/yaml.v3"
+)
+
+// exitError returns the error of a process that exited with code.
+func exitError(t *testing.T, code int) error {
+	t.Helper()
+	err := exec.Command("sh", "-c", fmt.Sprintf("exit %d", code)).Run()
+	if err == nil {
+		t.Fatalf("expected exit %d to fail", code)
+	}
+	return fmt.Errorf("command sh failed: %w", err)
+}
+
+// newTestPolicyExecutor wraps a mock whose Spawn returns the errors in turn,
+// recording the backoff delays instead of sleeping.
+func newTestPolicyExecutor(policy ExecutionPolicy, errs ...error) (*PolicyExecutor, *[]time.Duration, *int) {
+	calls := 0
+	mock := &MockExecutor{
+		NameFunc: func() string { return "mock" },
+		SpawnFunc: func(_ context.Context, _ *Agent, _ string, _ string) error {
+			calls++
+			if calls <= len(errs) {
+				return errs[calls-1]
+			}
+			return nil
+		},
+	}
+	var delays []time.Duration
+	executor := NewPolicyExecutor(mock, policy)
+	executor.sleep = func(_ context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+	return executor, &delays, &calls
+}
+
+// TestPolicyExecutor_RetriesWithBackoff verifies failed attempts are retried
+// with doubling delays capped at the maximum, and each attempt is recorded.
+func TestPolicyExecutor_RetriesWithBackoff(t *testing.T) {
+	policy := ExecutionPolicy{MaxRetries: 3, Backoff: time.Second, MaxBackoff: 3 * time.Second}
+	failure := exitError(t, 2)
+	executor, delays, calls := newTestPolicyExecutor(policy, failure, failure, failure)
+
+	var attempts []Attempt
+	ctx := WithAttemptRecorder(context.Background(), func(a Attempt) { attempts = append(attempts, a) })
+	if err := executor.Spawn(ctx, Implementer, "prompt", "session-1"); err != nil {
+		t.Fatalf("Spawn() error = %v", err)
+	}
+
+	if *calls != 4 {
+		t.Errorf("calls = %d, want 4", *calls)
+	}
+	if want := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}; !reflect.DeepEqual(*delays, want) {
+		t.Errorf("delays = %v, want %v", *delays, want)
+	}
+	if len(attempts) != 4 {
+		t.Fatalf("recorded %d attempts, want 4", len(attempts))
+	}
+	for i, a := range attempts[:3] {
+		if a.Number != i+1 || a.Action != "spawn" || a.Executor != "mock" || a.Outcome != OutcomeFailed || a.ExitCode != 2 {
+			t.Errorf("attempt %d = %+v, want failed spawn with exit code 2", i+1, a)
+		}
+	}
+	if last := attempts[3]; last.Outcome != OutcomeSucceeded || last.ExitCode != 0 || last.Error != "" {
+		t.Errorf("last attempt = %+v, want success", last)
+	}
+}
+
+// TestPolicyExecutor_GivesUp verifies the last error is returned once the
+// retries are used up.
+func TestPolicyExecutor_GivesUp(t *testing.T) {
+	failure := errors.New("boom")
+	executor, _, calls := newTestPolicyExecutor(ExecutionPolicy{MaxRetries: 1}, failure, failure, failure)
+
+	err := executor.Spawn(context.Background(), Implementer, "prompt", "session-1")
+	if !errors.Is(err, failure) || !strings.Contains(err.Error(), "gave up after 2 attempts") {
+		t.Errorf("Spawn() error = %v, want boom after 2 attempts", err)
+	}
+	if *calls != 2 {
+		t.Errorf("calls = %d, want 2", *calls)
+	}
+}
+
+// TestPolicyExecutor_RetryCheck verifies retries stop once the retry check
+// refuses them.
+func TestPolicyExecutor_RetryCheck(t *testing.T) {
+	failure := errors.New("boom")
+	spent := errors.New("budget spent")
+	executor, _, calls := newTestPolicyExecutor(ExecutionPolicy{MaxRetries: 3}, failure, failure, failure)
+
+	checks := 0
+	ctx := WithRetryCheck(context.Background(), func() error {
+		checks++
+		if checks > 1 {
+			return spent
+		}
+		return nil
+	})
+	err := executor.Spawn(ctx, Implementer, "prompt", "session-1")
+	if !errors.Is(err, spent) || !errors.Is(err, failure) || !strings.Contains(err.Error(), "not retrying after 2 attempt(s)") {
+		t.Errorf("Spawn() error = %v, want budget spent after 2 attempts", err)
+	}
+	if *calls != 2 {
+		t.Errorf("calls = %d, want 2", *calls)
+	}
+}
+
+// TestPolicyExecutor_RetriesSpawnByResuming verifies a failed spawn is
+// retried by resuming its session when the executor supports resumption.
+func TestPolicyExecutor_RetriesSpawnByResuming(t *testing.T) {
+	var resumed []string
+	mock := &MockExecutor{
+		SpawnFunc: func(_ context.Context, _ *Agent, _ string, _ string) error {
+			return errors.New("crashed")
+		},
+		ResumeFunc: func(_ context.Context, sessionID string, prompt string) error {
+			resumed = append(resumed, sessionID)
+			if !strings.HasSuffix(prompt, "Execute task 010") {
+				t.Errorf("resume prompt %q does not end with the spawn prompt", prompt)
+			}
+			return nil
+		},
+		SupportsResumptionFunc: func() bool { return true },
+		SessionStartedFunc:     func(string) bool { return true },
+	}
+	executor := NewPolicyExecutor(mock, ExecutionPolicy{MaxRetries: 1})
+
+	var actions []string
+	ctx := WithAttemptRecorder(context.Background(), func(a Attempt) { actions = append(actions, a.Action) })
+	if err := executor.Spawn(ctx, Implementer, "Execute task 010", "session-1"); err != nil {
+		t.Fatalf("Spawn() error = %v", err)
+	}
+	if !reflect.DeepEqual(resumed, []string{"session-1"}) {
+		t.Errorf("resumed sessions = %v, want [session-1]", resumed)
+	}
+	if want := []string{"spawn", "resume"}; !reflect.DeepEqual(actions, want) {
+		t.Errorf("attempt actions = %v, want %v", actions, want)
+	}
+}
+
+// TestPolicyExecutor_RetriesUnstartedSpawnBySpawning verifies a spawn that
+// failed before its session was created is spawned again rather than resumed.
+func TestPolicyExecutor_RetriesUnstartedSpawnBySpawning(t *testing.T) {
+	dir := t.TempDir()
+	spawns := 0
+	runner := &MockCommandRunner{
+		RunFunc: func(_ context.Context, _ string, args []string, _ io.Reader, outputPath string) error {
+			if slices.Contains(args, "--resume") {
+				return errors.New("no conversation found with session ID session-1")
+			}
+			spawns++
+			if spawns == 1 {
+				// Only the run start marker and stderr reach the log
+				log := `{"type":"system","subtype":"run_start","timestamp":"2025-01-15T10:00:00Z"}` + "\ninvalid API key\n"
+				if err := os.WriteFile(strings.TrimSuffix(outputPath, ".log")+".json", []byte(log), 0644); err != nil {
+					return err
+				}
+				return errors.New("command claude failed: exit status 1")
+			}
+			return nil
+		},
+	}
+	executor := NewPolicyExecutor(NewClaudeExecutorWithRunner(false, "", dir, nil, runner), ExecutionPolicy{MaxRetries: 2})
+
+	var actions []string
+	ctx := WithAttemptRecorder(context.Background(), func(a Attempt) { actions = append(actions, a.Action) })
+	if err := executor.Spawn(ctx, Implementer, "Execute task 010", "session-1"); err != nil {
+		t.Fatalf("Spawn() error = %v", err)
+	}
+	if want := []string{"spawn", "spawn"}; !reflect.DeepEqual(actions, want) {
+		t.Errorf("attempt actions = %v, want %v", actions, want)
+	}
+}
+
+// TestPolicyExecutor_RetryOn verifies only the configured failures are
+// retried.
+func TestPolicyExecutor_RetryOn(t *testing.T) {
+	tests := []struct {
+		name      string
+		retryOn   []string
+		err       error
+		wantCalls int
+	}{
+		{name: "any failure by default", err: errors.New("boom"), wantCalls: 2},
+		{name: "error matches any failure", retryOn: []string{RetryOnError}, err: exitError(t, 1), wantCalls: 2},
+		{name: "matching exit code", retryOn: []string{"exit:3"}, err: exitError(t, 3), wantCalls: 2},
+		{name: "other exit code", retryOn: []string{"exit:3"}, err: exitError(t, 1), wantCalls: 1},
+		{name: "timeout only", retryOn: []string{RetryOnTimeout}, err: exitError(t, 1), wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := ExecutionPolicy{MaxRetries: 1, RetryOn: tt.retryOn}
+			executor, _, calls := newTestPolicyExecutor(policy, tt.err)
+			_ = executor.Spawn(context.Background(), Implementer, "prompt", "session-1")
+			if *calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", *calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+// TestPolicyExecutor_Timeout verifies an attempt is cut off at the timeout,
+// recorded as a timeout, and retried when timeouts are retryable.
+func TestPolicyExecutor_Timeout(t *testing.T) {
+	calls := 0
+	mock := &MockExecutor{
+		ResumeFunc: func(ctx context.Context, _ string, _ string) error {
+			calls++
+			if calls == 1 {
+				<-ctx.Done()
+				return ctx.Err()
+			}
+			return nil
+		},
+	}
+	executor := NewPolicyExecutor(mock, ExecutionPolicy{
+		Timeout:    10 * time.Millisecond,
+		MaxRetries: 1,
+		RetryOn:    []string{RetryOnTimeout},
+	})
+
+	var attempts []Attempt
+	ctx := WithAttemptRecorder(context.Background(), func(a Attempt) { attempts = append(attempts, a) })
+	if err := executor.Resume(ctx, "session-1", "continue"); err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	if len(attempts) != 2 {
+		t.Fatalf("recorded %d attempts, want 2", len(attempts))
+	}
+	first := attempts[0]
+	if first.Action != "resume" || first.Outcome != OutcomeTimeout || first.ExitCode != -1 {
+		t.Errorf("first attempt = %+v, want resume timeout", first)
+	}
+	if !strings.Contains(first.Error, "timed out after 10ms") {
+		t.Errorf("first attempt error = %q, want timeout message", first.Error)
+	}
+}
+
+// TestPolicyExecutor_Canceled verifies a canceled context is not retried.
+func TestPolicyExecutor_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	mock := &MockExecutor{
+		SpawnFunc: func(ctx context.Context, _ *Agent, _ string, _ string) error {
+			cancel()
+			return ctx.Err()
+		},
+	}
+	executor := NewPolicyExecutor(mock, ExecutionPolicy{MaxRetries: 3})
+
+	var attempts []Attempt
+	ctx = WithAttemptRecorder(ctx, func(a Attempt) { attempts = append(attempts, a) })
+	if err := executor.Spawn(ctx, Implementer, "prompt", "session-1"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Spawn() error = %v, want context.Canceled", err)
+	}
+	if len(attempts) != 1 || attempts[0].Outcome != OutcomeCanceled {
+		t.Errorf("attempts = %+v, want one canceled attempt", attempts)
+	}
+}
+
+// TestAttemptMetadata_RoundTrip verifies attempts survive being stored in
+// task metadata and serialized as YAML.
+func TestAttemptMetadata_RoundTrip(t *testing.T) {
+	started := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	want := []Attempt{
+		{Number: 1, Action: "spawn", Executor: "claude-code", StartedAt: started, Duration: 90 * time.Second,
+			Outcome: OutcomeTimeout, ExitCode: -1, Error: "spawn timed out after 1m30s"},
+		{Number: 2, Action: "spawn", Executor: "claude-code", StartedAt: started.Add(time.Hour), Duration: time.Second,
+			Outcome: OutcomeSucceeded, ExitCode: 0},
+	}
+
+	metadata := map[string]interface{}{"other": "kept"}
+	for _, a := range want {
+		metadata = AppendAttempt(metadata, a)
+	}
+	data, err := yaml.Marshal(metadata)
+	if err != nil {
+		t.Fatalf("yaml.Marshal() error = %v", err)
+	}
+	var decoded map[string]interface{}
+	if err := yaml.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("yaml.Unmarshal() error = %v", err)
+	}
+
+	if got := AttemptsFromMetadata(decoded); !reflect.DeepEqual(got, want) {
+		t.Errorf("AttemptsFromMetadata() = %+v, want %+v", got, want)
+	}
+	if decoded["other"] != "kept" {
+		t.Error("expected other metadata to be kept")
+	}
+	if got := AttemptsFromMetadata(nil); len(got) != 0 {
+		t.Errorf("AttemptsFromMetadata(nil) = %+v, want none", got)
+	}
+}
+
+// TestGetAgentExecutor_Policy verifies agent policies override the policy of
+// the executor they are bound to field by field.
+func TestGetAgentExecutor_Policy(t *testing.T) {
+	var config schemas.UserConfig
+	err := yaml.Unmarshal([]byte(`agents:
+  executors:
+    claude-code:
+      type: claude
+      policy:
+        timeout: 30m
+        max_retries: 2
+  policies:
+    reviewer:
+      timeout: 5m
+      retry_on: ["timeout"]
+`), &config)
+	if err != nil {
+		t.Fatalf("failed to parse config: %v", err)
+	}
+
+	registry, err := LoadExecutorRegistry(&config, "")
+	if err != nil {
+		t.Fatalf("LoadExecutorRegistry() error = %v", err)
+	}
+
+	tests := []struct {
+		agent string
+		want  ExecutionPolicy
+	}{
+		{
+			agent: "implementer",
+			want:  ExecutionPolicy{Timeout: 30 * time.Minute, MaxRetries: 2, Backoff: defaultBackoff, MaxBackoff: defaultMaxBackoff},
+		},
+		{
+			agent: "reviewer",
+			want: ExecutionPolicy{Timeout: 5 * time.Minute, MaxRetries: 2, Backoff: defaultBackoff, MaxBackoff: defaultMaxBackoff,
+				RetryOn: []string{RetryOnTimeout}},
+		},
+	}
+	for _, tt := range tests {
+		executor, err := registry.GetAgentExecutor(tt.agent, nil)
+		if err != nil {
+			t.Fatalf("GetAgentExecutor(%s) error = %v", tt.agent, err)
+		}
+		policyExecutor, ok := executor.(*PolicyExecutor)
+		if !ok {
+			t.Fatalf("expected *PolicyExecutor, got %T", executor)
+		}
+		if got := policyExecutor.Policy(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("policy of %s = %+v, want %+v", tt.agent, got, tt.want)
+		}
+		if _, ok := policyExecutor.Unwrap().(*ClaudeExecutor); !ok {
+			t.Errorf("expected wrapped *ClaudeExecutor, got %T", policyExecutor.Unwrap())
+		}
+	}
+
+	timeout := "soon"
+	config.Agents.Policies["reviewer"] = schemas.ExecutionPolicy{Timeout: &timeout}
+	if _, err := LoadExecutorRegistry(&config, ""); err == nil || !strings.Contains(err.Error(), `invalid timeout "soon"`) {
+		t.Errorf("expected invalid timeout error, got %v", err)
+	}
+}
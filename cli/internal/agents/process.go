@@ -0,0 +1,63 @@
+package agents
+
+import (
+	"context"
+	"time"
+)
+
+// processObserverKey is the context key for the observer set by
+// WithProcessObserver.
+type processObserverKey struct{}
+
+// ProcessObserver is called by DefaultCommandRunner after it starts an agent
+// process, with the process's PID and start time. The returned function,
+// if not nil, is called once the process has exited.
+//
+// Agent processes lead their own process group, whose ID is the PID, so
+// the agent and every process it starts can be signaled together (see
+// TerminateProcessGroup).
+type ProcessObserver func(pid int, startedAt time.Time) (exited func())
+
+// WithProcessObserver returns a context under which DefaultCommandRunner
+// reports the agent processes it starts to observe, e.g. to record running
+// executions where other sow processes can find them.
+func WithProcessObserver(ctx context.Context, observe ProcessObserver) context.Context {
+	return context.WithValue(ctx, processObserverKey{}, observe)
+}
+
+// observeProcess passes a started process to the observer carried by ctx,
+// if any, and returns the function to call once it has exited.
+func observeProcess(ctx context.Context, pid int, startedAt time.Time) func() {
+	if observe, ok := ctx.Value(processObserverKey{}).(ProcessObserver); ok {
+		if exited := observe(pid, startedAt); exited != nil {
+			return exited
+		}
+	}
+	return func() {}
+}
+
+// startTimeTolerance is how far the start time of a process may be from
+// the recorded one for it to be the same process. It covers the delay
+// between starting a process and recording it, and the one-second
+// resolution of the boot time process start times are derived from on
+// Linux.
+const startTimeTolerance = 2 * time.Second
+
+// ProcessRunning reports whether the process that was started at startedAt
+// with the given PID still runs. A process with the PID that started at
+// another time is a different process the PID was reused for. When
+// startedAt is zero or the start time cannot be read, only the PID is
+// checked (see ProcessAlive).
+func ProcessRunning(pid int, startedAt time.Time) bool {
+	if !ProcessAlive(pid) {
+		return false
+	}
+	if startedAt.IsZero() {
+		return true
+	}
+	started, ok := ProcessStartTime(pid)
+	if !ok {
+		return true
+	}
+	return started.Sub(startedAt).Abs() <= startTimeTolerance
+}
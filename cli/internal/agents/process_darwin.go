@@ -0,0 +1,23 @@
+//go:build darwin
+
+package agents
+
+import (
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// ProcessStartTime returns when the process with the given PID started,
+// or false if it is gone or its start time cannot be read.
+func ProcessStartTime(pid int) (time.Time, bool) {
+	if pid <= 0 {
+		return time.Time{}, false
+	}
+	info, err := unix.SysctlKinfoProc("kern.proc.pid", pid)
+	if err != nil || info.Proc.P_pid != int32(pid) {
+		return time.Time{}, false
+	}
+	start := info.Proc.P_starttime
+	return time.Unix(start.Sec, int64(start.Usec)*int64(time.Microsecond)), true
+}
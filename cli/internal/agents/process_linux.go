@@ -0,0 +1,65 @@
+//go:build linux
+
+package agents
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// clockTicks is the unit of process times in /proc (USER_HZ), which the
+// kernel fixes at 100 for user space.
+const clockTicks = 100
+
+// ProcessStartTime returns when the process with the given PID started,
+// or false if it is gone or its start time cannot be read.
+func ProcessStartTime(pid int) (time.Time, bool) {
+	boot, ok := bootTime()
+	if !ok || pid <= 0 {
+		return time.Time{}, false
+	}
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	// The command name in parentheses may contain spaces, so the fields
+	// are counted from its end. starttime is field 22; the state after
+	// the name is field 3.
+	end := bytes.LastIndexByte(data, ')')
+	if end < 0 {
+		return time.Time{}, false
+	}
+	fields := strings.Fields(string(data[end+1:]))
+	if len(fields) < 20 {
+		return time.Time{}, false
+	}
+	ticks, err := strconv.ParseInt(fields[19], 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return boot.Add(time.Duration(ticks) * time.Second / clockTicks), true
+}
+
+// bootTime returns when the system booted, read once from /proc/stat.
+var bootTime = sync.OnceValues(func() (time.Time, bool) {
+	data, err := os.ReadFile("/proc/stat")
+	if err != nil {
+		return time.Time{}, false
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if value, ok := strings.CutPrefix(line, "btime "); ok {
+			seconds, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+			if err != nil {
+				return time.Time{}, false
+			}
+			return time.Unix(seconds, 0), true
+		}
+	}
+	return time.Time{}, false
+})
@@ -0,0 +1,12 @@
+//go:build !linux && !darwin && !windows
+
+package agents
+
+import "time"
+
+// ProcessStartTime returns when the process with the given PID started.
+// It is not supported on this platform, so it always returns false and
+// processes are identified by PID alone.
+func ProcessStartTime(_ int) (time.Time, bool) {
+	return time.Time{}, false
+}
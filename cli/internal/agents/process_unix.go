@@ -0,0 +1,73 @@
+//go:build !windows
+
+package agents
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+
+	"golang.org/x/sys/unix"
+)
+
+// setProcessGroup makes cmd lead a new process group, and kill the whole
+// group when its context ends.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &unix.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return unix.Kill(-cmd.Process.Pid, unix.SIGKILL)
+	}
+}
+
+// forwardSignals relays the interrupts sow receives to the process group
+// pgid until stop is called. The group does not receive the terminal's
+// interrupts itself, as it is not the terminal's foreground group.
+func forwardSignals(pgid int) (stop func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, unix.SIGINT, unix.SIGTERM, unix.SIGHUP)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				_ = unix.Kill(-pgid, sig.(unix.Signal))
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
+
+// ProcessAlive reports whether a process with the given PID exists.
+func ProcessAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := unix.Kill(pid, 0)
+	// EPERM means the process exists but belongs to another user
+	return err == nil || errors.Is(err, unix.EPERM)
+}
+
+// TerminateProcessGroup asks every process of group pgid to exit (SIGTERM),
+// or kills them (SIGKILL) when force is set. A group that no longer exists
+// is not an error.
+func TerminateProcessGroup(pgid int, force bool) error {
+	if pgid <= 0 {
+		// Would signal sow's own process group, or every process
+		return fmt.Errorf("invalid process group %d", pgid)
+	}
+	sig := unix.SIGTERM
+	if force {
+		sig = unix.SIGKILL
+	}
+	if err := unix.Kill(-pgid, sig); err != nil && !errors.Is(err, unix.ESRCH) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+//go:build windows
+
+package agents
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"os/signal"
+	"time"
+
+	"golang.org/x/sys/windows"
+)
+
+// setProcessGroup makes cmd lead a new process group. Windows cannot
+// signal a group, so a canceled command only kills its own process.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &windows.SysProcAttr{CreationFlags: windows.CREATE_NEW_PROCESS_GROUP}
+}
+
+// forwardSignals kills the process pgid when sow is interrupted, until
+// stop is called. Processes of a new group do not receive Ctrl-C.
+func forwardSignals(pgid int) (stop func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-signals:
+			_ = TerminateProcessGroup(pgid, true)
+		case <-done:
+		}
+	}()
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
+
+// ProcessAlive reports whether a process with the given PID exists.
+func ProcessAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		// Access denied means the process exists but we cannot query it
+		return errors.Is(err, windows.ERROR_ACCESS_DENIED)
+	}
+	defer func() { _ = windows.CloseHandle(handle) }()
+
+	var code uint32
+	if err := windows.GetExitCodeProcess(handle, &code); err != nil {
+		return true
+	}
+	const stillActive = 259
+	return code == stillActive
+}
+
+// ProcessStartTime returns when the process with the given PID started,
+// or false if it is gone or its start time cannot be read.
+func ProcessStartTime(pid int) (time.Time, bool) {
+	if pid <= 0 {
+		return time.Time{}, false
+	}
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return time.Time{}, false
+	}
+	defer func() { _ = windows.CloseHandle(handle) }()
+
+	var creation, exit, kernel, user windows.Filetime
+	if err := windows.GetProcessTimes(handle, &creation, &exit, &kernel, &user); err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(0, creation.Nanoseconds()), true
+}
+
+// TerminateProcessGroup kills the process pgid. Windows has no graceful
+// equivalent of SIGTERM for console processes, so force is ignored.
+func TerminateProcessGroup(pgid int, _ bool) error {
+	process, err := os.FindProcess(pgid)
+	if err != nil {
+		return nil
+	}
+	if err := process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return err
+	}
+	return nil
+}
@@ -12,7 +12,8 @@ type AgentRegistry struct {
 	agents map[string]*Agent
 }
 
-// NewAgentRegistry creates a new AgentRegistry pre-populated with all standard agents.
+// NewAgentRegistry creates a new AgentRegistry pre-populated with all standard agents
+// and the custom agents registered by LoadCustom.
 // This is the recommended way to create a registry for production use.
 //
 // Example:
@@ -31,6 +32,9 @@ func NewAgentRegistry() *AgentRegistry {
 	for _, agent := range StandardAgents() {
 		r.Register(agent)
 	}
+	for _, agent := range CustomAgents() {
+		r.Register(agent)
+	}
 
 	return r
 }
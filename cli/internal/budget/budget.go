@@ -0,0 +1,225 @@
+// Package budget enforces the agent spend limits configured in
+// .sow/config.yaml. Before starting an agent, sow agent spawn and sow agent
+// resume check what the project, and the task's session, have spent
+// according to the session logs: a reached limit refuses the run and a
+// nearly spent one is reported as a warning.
+//
+// After each run the totals are recorded in phase metadata under
+// MetadataKey, from which PromptSection tells the orchestrator how much of
+// the budget remains.
+package budget
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/jmgilman/sow/cli/internal/sow"
+	"github.com/jmgilman/sow/libs/config"
+	"github.com/jmgilman/sow/libs/project/state"
+	"github.com/jmgilman/sow/libs/schemas"
+)
+
+// MetadataKey is the phase metadata key holding the latest agent spend.
+const MetadataKey = "budget"
+
+// Spend is what agent sessions have spent.
+type Spend struct {
+	CostUSD float64
+	Tokens  int
+}
+
+// Budget holds the configured spend limits.
+type Budget struct {
+	project *schemas.BudgetLimit
+	task    *schemas.BudgetLimit
+	warnAt  float64
+}
+
+// New returns the budget configured in repoConfig.
+func New(repoConfig *schemas.Config) *Budget {
+	b := &Budget{warnAt: config.GetBudgetWarnAt(repoConfig)}
+	if repoConfig != nil && repoConfig.Budget != nil {
+		b.project = repoConfig.Budget.Project
+		b.task = repoConfig.Budget.Task
+	}
+	return b
+}
+
+// FromContext returns the budget configured in the repository of sowCtx.
+func FromContext(sowCtx *sow.Context) (*Budget, error) {
+	repoConfig, err := config.LoadRepoConfig(sowCtx.FS())
+	if err != nil {
+		return nil, fmt.Errorf("load budget: %w", err)
+	}
+	return New(repoConfig), nil
+}
+
+// Enabled reports whether any limit is configured.
+func (b *Budget) Enabled() bool {
+	return !isZero(b.project) || !isZero(b.task)
+}
+
+// Check checks the spend of the project and, when taskID is not empty, of
+// the task's session against their limits. It returns a warning for each
+// limit past the warning threshold, and an *ExceededError for the first
+// limit reached.
+func (b *Budget) Check(project Spend, taskID string, task Spend) ([]string, error) {
+	var warnings []string
+	check := func(scope, key string, limit *schemas.BudgetLimit, spent Spend) error {
+		if limit == nil {
+			return nil
+		}
+		if limit.Usd != nil {
+			if err := b.checkValue(&warnings, scope, key+".usd", spent.CostUSD, *limit.Usd, formatUSD); err != nil {
+				return err
+			}
+		}
+		if limit.Tokens != nil {
+			return b.checkValue(&warnings, scope, key+".tokens", float64(spent.Tokens), float64(*limit.Tokens), formatTokens)
+		}
+		return nil
+	}
+
+	if err := check("project", "budget.project", b.project, project); err != nil {
+		return warnings, err
+	}
+	if taskID != "" {
+		if err := check("task "+taskID, "budget.task", b.task, task); err != nil {
+			return warnings, err
+		}
+	}
+	return warnings, nil
+}
+
+// checkValue compares one spent value against its limit.
+func (b *Budget) checkValue(warnings *[]string, scope, key string, spent, limit float64, format func(float64) string) error {
+	if spent >= limit {
+		return &ExceededError{Scope: scope, Key: key, Spent: format(spent), Limit: format(limit)}
+	}
+	if spent >= b.warnAt*limit {
+		*warnings = append(*warnings, fmt.Sprintf("%s has spent %s of its %s budget (%.0f%%)",
+			scope, format(spent), format(limit), 100*spent/limit))
+	}
+	return nil
+}
+
+// ExceededError reports a run refused because a budget is spent.
+type ExceededError struct {
+	Scope string // "project" or "task <id>"
+	Key   string // Configuration key of the limit, e.g. "budget.project.usd"
+	Spent string
+	Limit string
+}
+
+// Error describes the limit and how to raise it.
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("agent budget exceeded: %s has spent %s of its %s budget (raise %s in .sow/config.yaml to continue)",
+		e.Scope, e.Spent, e.Limit, e.Key)
+}
+
+// Metadata returns the spend of a phase and of the project in the form
+// stored in phase metadata, with what is left of the project budget.
+func (b *Budget) Metadata(phase, project Spend, now time.Time) map[string]interface{} {
+	m := map[string]interface{}{
+		"phase_cost_usd":   roundUSD(phase.CostUSD),
+		"phase_tokens":     phase.Tokens,
+		"project_cost_usd": roundUSD(project.CostUSD),
+		"project_tokens":   project.Tokens,
+		"updated_at":       now.UTC().Format(time.RFC3339),
+	}
+	if b.project != nil && b.project.Usd != nil {
+		m["project_limit_usd"] = *b.project.Usd
+		m["project_remaining_usd"] = roundUSD(math.Max(0, *b.project.Usd-project.CostUSD))
+	}
+	if b.project != nil && b.project.Tokens != nil {
+		m["project_limit_tokens"] = *b.project.Tokens
+		m["project_remaining_tokens"] = max(0, *b.project.Tokens-int64(project.Tokens))
+	}
+	return m
+}
+
+// PromptSection describes the project's agent spend and remaining budget
+// for the orchestrator, from the most recent totals recorded in phase
+// metadata. It returns an empty string if none are recorded.
+func PromptSection(p *state.Project) string {
+	var latest map[string]interface{}
+	latestAt := ""
+	for _, phase := range p.Phases {
+		recorded, ok := phase.Metadata[MetadataKey].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		at, _ := recorded["updated_at"].(string)
+		if latest == nil || at > latestAt {
+			latest, latestAt = recorded, at
+		}
+	}
+	if latest == nil {
+		return ""
+	}
+
+	var buf strings.Builder
+	buf.WriteString("## Agent Budget\n\n")
+	spentUSD, _ := number(latest["project_cost_usd"])
+	spentTokens, _ := number(latest["project_tokens"])
+	buf.WriteString(fmt.Sprintf("Agents have spent %s and %s on this project", formatUSD(spentUSD), formatTokens(spentTokens)))
+	if latestAt != "" {
+		buf.WriteString(fmt.Sprintf(" (as of %s)", latestAt))
+	}
+	buf.WriteString(".\n")
+
+	var remaining []string
+	if left, ok := number(latest["project_remaining_usd"]); ok {
+		limit, _ := number(latest["project_limit_usd"])
+		remaining = append(remaining, fmt.Sprintf("%s of %s", formatUSD(left), formatUSD(limit)))
+	}
+	if left, ok := number(latest["project_remaining_tokens"]); ok {
+		limit, _ := number(latest["project_limit_tokens"])
+		remaining = append(remaining, fmt.Sprintf("%s of %s", formatTokens(left), formatTokens(limit)))
+	}
+	if len(remaining) > 0 {
+		buf.WriteString(fmt.Sprintf("Remaining budget: %s.\n", strings.Join(remaining, ", ")))
+	}
+
+	buf.WriteString("\nPlan the remaining work within the budget: `sow agent spawn` and `sow agent resume` refuse to start agents once it is spent.\n")
+	return buf.String()
+}
+
+// isZero reports whether limit sets no limit.
+func isZero(limit *schemas.BudgetLimit) bool {
+	return limit == nil || (limit.Usd == nil && limit.Tokens == nil)
+}
+
+// roundUSD rounds a cost to a hundredth of a cent.
+func roundUSD(usd float64) float64 {
+	return math.Round(usd*10000) / 10000
+}
+
+// formatUSD formats a cost, e.g. "$1.25".
+func formatUSD(usd float64) string {
+	return fmt.Sprintf("$%.2f", usd)
+}
+
+// formatTokens formats a token count, e.g. "1200 tokens".
+func formatTokens(tokens float64) string {
+	return fmt.Sprintf("%.0f tokens", tokens)
+}
+
+// number converts a metadata value to float64. Values read back from
+// state may be decoded as any numeric type.
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
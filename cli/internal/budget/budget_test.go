@@ -0,0 +1,113 @@
+package budget
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmgilman/sow/libs/project/state"
+	"github.com/jmgilman/sow/libs/schemas"
+	projschema "github.com/jmgilman/sow/libs/schemas/project"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestBudget_Enabled(t *testing.T) {
+	assert.False(t, New(nil).Enabled())
+	assert.False(t, New(&schemas.Config{Budget: &schemas.BudgetConfig{Project: &schemas.BudgetLimit{}}}).Enabled())
+	assert.True(t, New(&schemas.Config{Budget: &schemas.BudgetConfig{Task: &schemas.BudgetLimit{Tokens: ptr(int64(1000))}}}).Enabled())
+}
+
+func TestBudget_Check(t *testing.T) {
+	b := New(&schemas.Config{Budget: &schemas.BudgetConfig{
+		Project: &schemas.BudgetLimit{Usd: ptr(10.0), Tokens: ptr(int64(1000000))},
+		Task:    &schemas.BudgetLimit{Usd: ptr(2.0)},
+	}})
+
+	t.Run("under every limit", func(t *testing.T) {
+		warnings, err := b.Check(Spend{CostUSD: 1, Tokens: 1000}, "010", Spend{CostUSD: 0.5})
+		require.NoError(t, err)
+		assert.Empty(t, warnings)
+	})
+
+	t.Run("warns past the threshold", func(t *testing.T) {
+		warnings, err := b.Check(Spend{CostUSD: 8.5, Tokens: 900000}, "010", Spend{CostUSD: 1.7})
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"project has spent $8.50 of its $10.00 budget (85%)",
+			"project has spent 900000 tokens of its 1000000 tokens budget (90%)",
+			"task 010 has spent $1.70 of its $2.00 budget (85%)",
+		}, warnings)
+	})
+
+	t.Run("refuses a reached project limit", func(t *testing.T) {
+		_, err := b.Check(Spend{CostUSD: 3, Tokens: 1000000}, "", Spend{})
+		var exceeded *ExceededError
+		require.True(t, errors.As(err, &exceeded))
+		assert.Equal(t, "budget.project.tokens", exceeded.Key)
+		assert.Contains(t, err.Error(), "project has spent 1000000 tokens of its 1000000 tokens budget")
+	})
+
+	t.Run("refuses a reached task limit", func(t *testing.T) {
+		_, err := b.Check(Spend{CostUSD: 3}, "010", Spend{CostUSD: 2.25})
+		var exceeded *ExceededError
+		require.True(t, errors.As(err, &exceeded))
+		assert.Equal(t, "task 010", exceeded.Scope)
+		assert.Contains(t, err.Error(), "raise budget.task.usd in .sow/config.yaml")
+	})
+
+	t.Run("task limit is skipped without a task", func(t *testing.T) {
+		_, err := b.Check(Spend{CostUSD: 3}, "", Spend{CostUSD: 5})
+		assert.NoError(t, err)
+	})
+}
+
+func TestBudget_Metadata(t *testing.T) {
+	b := New(&schemas.Config{Budget: &schemas.BudgetConfig{
+		Project: &schemas.BudgetLimit{Usd: ptr(5.0)},
+	}})
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	m := b.Metadata(Spend{CostUSD: 1.23456, Tokens: 100}, Spend{CostUSD: 6, Tokens: 300}, now)
+	assert.Equal(t, 1.2346, m["phase_cost_usd"])
+	assert.Equal(t, 300, m["project_tokens"])
+	assert.Equal(t, 5.0, m["project_limit_usd"])
+	assert.Equal(t, 0.0, m["project_remaining_usd"], "remaining budget is never negative")
+	assert.Equal(t, "2025-03-01T12:00:00Z", m["updated_at"])
+	assert.NotContains(t, m, "project_remaining_tokens")
+}
+
+func TestPromptSection(t *testing.T) {
+	t.Run("no recorded spend", func(t *testing.T) {
+		p := &state.Project{ProjectState: projschema.ProjectState{Phases: map[string]projschema.PhaseState{
+			"implementation": {},
+		}}}
+		assert.Empty(t, PromptSection(p))
+	})
+
+	t.Run("latest totals with remaining budget", func(t *testing.T) {
+		p := &state.Project{ProjectState: projschema.ProjectState{Phases: map[string]projschema.PhaseState{
+			"planning": {Metadata: map[string]interface{}{MetadataKey: map[string]interface{}{
+				"project_cost_usd": 1, "project_tokens": 10, "updated_at": "2025-03-01T10:00:00Z",
+			}}},
+			"implementation": {Metadata: map[string]interface{}{MetadataKey: map[string]interface{}{
+				"project_cost_usd":         3.5,
+				"project_tokens":           uint64(120000),
+				"project_limit_usd":        5,
+				"project_remaining_usd":    1.5,
+				"project_limit_tokens":     int64(200000),
+				"project_remaining_tokens": 80000,
+				"updated_at":               "2025-03-01T12:00:00Z",
+			}}},
+		}}}
+
+		section := PromptSection(p)
+		assert.Contains(t, section, "## Agent Budget")
+		assert.Contains(t, section, "Agents have spent $3.50 and 120000 tokens on this project (as of 2025-03-01T12:00:00Z).")
+		assert.Contains(t, section, "Remaining budget: $1.50 of $5.00, 80000 tokens of 200000 tokens.")
+	})
+}
@@ -3,10 +3,15 @@ package cmdutil
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 
 	"github.com/jmgilman/sow/cli/internal/sow"
+	"github.com/jmgilman/sow/libs/config"
 	"github.com/jmgilman/sow/libs/project/state"
+	projschema "github.com/jmgilman/sow/libs/schemas/project"
 )
 
 // Context keys for storing values in command context.
@@ -41,10 +46,91 @@ func WithContext(ctx context.Context, sowCtx *sow.Context) context.Context {
 	return context.WithValue(ctx, sowContextKey, sowCtx)
 }
 
+// NewBackend returns the project state backend selected in .sow/config.yaml.
+// Uses the YAML backend when no backend is configured.
+func NewBackend(sowCtx *sow.Context) (state.Backend, error) {
+	return newBackend(sowCtx.FS(), sowCtx.RepoRoot(), sowCtx.Git().CurrentBranch)
+}
+
+// NewWorktreeBackend returns the project state backend of the worktree of
+// branch at worktreePath, as selected in the worktree's .sow/config.yaml.
+// Unlike NewBackend it does not open the worktree's git repository.
+// Returns sow.ErrNotInitialized if the worktree has no .sow directory.
+func NewWorktreeBackend(worktreePath, branch string) (state.Backend, error) {
+	fsys, err := sow.NewFS(worktreePath)
+	if err != nil {
+		return nil, err
+	}
+	return newBackend(fsys, worktreePath, func() (string, error) { return branch, nil })
+}
+
+// newBackend returns the backend selected in the config of the .sow
+// directory fsys of repoRoot. currentBranch is only called for backends
+// that key projects by branch.
+func newBackend(fsys sow.FS, repoRoot string, currentBranch func() (string, error)) (state.Backend, error) {
+	repoConfig, err := config.LoadRepoConfig(fsys)
+	if err != nil {
+		return nil, fmt.Errorf("select state backend: %w", err)
+	}
+
+	path := config.GetStatePath(repoConfig)
+	switch backend := config.GetStateBackend(repoConfig); backend {
+	case config.StateBackendYAML:
+		return state.NewYAMLBackendWithPath(fsys, path), nil
+	case config.StateBackendSQLite:
+		return state.NewSQLiteBackend(filepath.Join(repoRoot, ".sow", path)), nil
+	case config.StateBackendHTTP:
+		// Projects are one per branch, so the branch identifies the project on the server
+		branch, err := currentBranch()
+		if err != nil {
+			return nil, fmt.Errorf("select state backend: %w", err)
+		}
+		return state.NewHTTPBackend(config.GetStateURL(repoConfig), branch, nil), nil
+	default:
+		return nil, fmt.Errorf("select state backend: unknown backend %q", backend)
+	}
+}
+
+// LockPath returns the OS path of the project state lock file.
+func LockPath(sowCtx *sow.Context) string {
+	return filepath.Join(sowCtx.RepoRoot(), ".sow", state.DefaultLockFile)
+}
+
+// LockProject acquires the cross-process project state lock so that a
+// LoadProject → mutate → save span cannot interleave with other sow
+// processes. Waits up to state.lock_timeout from .sow/config.yaml.
+// The caller must release the returned lock, typically with defer.
+//
+// If there is no project directory there is nothing to protect, so no lock
+// file is created and a nil lock (which is safe to release) is returned.
+func LockProject(ctx context.Context, sowCtx *sow.Context) (*state.Lock, error) {
+	path := LockPath(sowCtx)
+	if _, err := os.Stat(filepath.Dir(path)); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	repoConfig, err := config.LoadRepoConfig(sowCtx.FS())
+	if err != nil {
+		return nil, fmt.Errorf("lock project: %w", err)
+	}
+
+	lock, err := state.AcquireLock(ctx, path, config.GetStateLockTimeout(repoConfig))
+	if err != nil {
+		if errors.Is(err, state.ErrLocked) {
+			return nil, fmt.Errorf("%w\n\nIf that process is no longer running, use 'sow project unlock' to remove the lock", err)
+		}
+		return nil, fmt.Errorf("lock project: %w", err)
+	}
+	return lock, nil
+}
+
 // LoadProject loads the project from the sow context's filesystem.
-// This is a convenience wrapper around state.Load with the YAML backend.
+// This is a convenience wrapper around state.Load with the configured backend.
 func LoadProject(ctx context.Context, sowCtx *sow.Context) (*state.Project, error) {
-	backend := state.NewYAMLBackend(sowCtx.FS())
+	backend, err := NewBackend(sowCtx)
+	if err != nil {
+		return nil, fmt.Errorf("load project: %w", err)
+	}
 	proj, err := state.Load(ctx, backend)
 	if err != nil {
 		return nil, fmt.Errorf("load project: %w", err)
@@ -61,10 +147,55 @@ func SaveProject(ctx context.Context, proj *state.Project) error {
 	return nil
 }
 
+// UpdateProject applies mutate to the project and saves it.
+// This is a convenience wrapper around state.UpdateWithRetry: if another
+// process saved the project since it was loaded, the project is reloaded and
+// mutate is applied again. Errors from mutate are returned unchanged so
+// commands keep their own messages; save failures are wrapped.
+//
+// Task status changes and artifact approvals made by mutate are recorded in
+// the project journal once the save succeeds.
+func UpdateProject(ctx context.Context, proj *state.Project, mutate func(*state.Project) error) (*state.Project, error) {
+	updated, before, err := updateProject(ctx, proj, mutate)
+	if err != nil {
+		return nil, err
+	}
+
+	AppendJournal(ctx, state.JournalChanges(before, &updated.ProjectState)...)
+	return updated, nil
+}
+
+// updateProject is UpdateProject without journaling. It also returns the
+// state the successful mutate was applied to, for callers that journal the
+// change themselves.
+func updateProject(
+	ctx context.Context,
+	proj *state.Project,
+	mutate func(*state.Project) error,
+) (*state.Project, *projschema.ProjectState, error) {
+	var mutateErr error
+	var before *projschema.ProjectState
+	updated, err := state.UpdateWithRetry(ctx, proj, func(p *state.Project) error {
+		before = p.Snapshot()
+		mutateErr = mutate(p)
+		return mutateErr
+	})
+	if mutateErr != nil {
+		return nil, nil, mutateErr
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to save project: %w", err)
+	}
+	return updated, before, nil
+}
+
 // CreateProject creates a new project and saves it.
-// This is a convenience wrapper around state.Create with the YAML backend.
+// This is a convenience wrapper around state.Create with the configured backend.
 func CreateProject(ctx context.Context, sowCtx *sow.Context, opts state.CreateOpts) (*state.Project, error) {
-	backend := state.NewYAMLBackend(sowCtx.FS())
+	backend, err := NewBackend(sowCtx)
+	if err != nil {
+		return nil, fmt.Errorf("create project: %w", err)
+	}
 	proj, err := state.Create(ctx, backend, opts)
 	if err != nil {
 		return nil, fmt.Errorf("create project: %w", err)
@@ -0,0 +1,249 @@
+package cmdutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/jmgilman/sow/cli/internal/sow"
+	"github.com/jmgilman/sow/libs/project/state"
+	projschema "github.com/jmgilman/sow/libs/schemas/project"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	// Register the standard project type for CreateProject.
+	_ "github.com/jmgilman/sow/cli/internal/projects/standard"
+)
+
+// setupSowContext creates a git repository with a .sow directory and optional config.yaml.
+func setupSowContext(t *testing.T, configYAML string) *sow.Context {
+	t.Helper()
+
+	repoRoot := t.TempDir()
+	_, err := gogit.PlainInit(repoRoot, false)
+	require.NoError(t, err)
+
+	sowDir := filepath.Join(repoRoot, ".sow")
+	require.NoError(t, os.MkdirAll(sowDir, 0755))
+	if configYAML != "" {
+		require.NoError(t, os.WriteFile(filepath.Join(sowDir, "config.yaml"), []byte(configYAML), 0644))
+	}
+
+	sowCtx, err := sow.NewContext(repoRoot)
+	require.NoError(t, err)
+	return sowCtx
+}
+
+func TestNewBackend(t *testing.T) {
+	t.Run("defaults to YAML backend", func(t *testing.T) {
+		sowCtx := setupSowContext(t, "")
+
+		backend, err := NewBackend(sowCtx)
+
+		require.NoError(t, err)
+		assert.IsType(t, &state.YAMLBackend{}, backend)
+	})
+
+	t.Run("selects SQLite backend from config", func(t *testing.T) {
+		sowCtx := setupSowContext(t, "state:\n  backend: sqlite\n")
+
+		backend, err := NewBackend(sowCtx)
+
+		require.NoError(t, err)
+		require.IsType(t, &state.SQLiteBackend{}, backend)
+		sqliteBackend, _ := backend.(*state.SQLiteBackend)
+		assert.Equal(t, filepath.Join(sowCtx.RepoRoot(), ".sow", "project", "state.db"), sqliteBackend.Path())
+	})
+
+	t.Run("selects HTTP backend keyed by branch", func(t *testing.T) {
+		sowCtx := setupSowContext(t, "state:\n  backend: http\n  url: http://localhost:7070\n")
+		branch, err := sowCtx.Git().CurrentBranch()
+		require.NoError(t, err)
+
+		backend, err := NewBackend(sowCtx)
+
+		require.NoError(t, err)
+		require.IsType(t, &state.HTTPBackend{}, backend)
+		httpBackend, _ := backend.(*state.HTTPBackend)
+		assert.Equal(t, "http://localhost:7070/projects/"+branch, httpBackend.URL())
+	})
+
+	t.Run("rejects unknown backend", func(t *testing.T) {
+		sowCtx := setupSowContext(t, "state:\n  backend: postgres\n")
+
+		_, err := NewBackend(sowCtx)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestNewWorktreeBackend(t *testing.T) {
+	t.Run("selects backend from the worktree config", func(t *testing.T) {
+		worktree := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(worktree, ".sow"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(worktree, ".sow", "config.yaml"), []byte("state:\n  backend: sqlite\n"), 0644))
+
+		backend, err := NewWorktreeBackend(worktree, "feat/x")
+
+		require.NoError(t, err)
+		require.IsType(t, &state.SQLiteBackend{}, backend)
+		sqliteBackend, _ := backend.(*state.SQLiteBackend)
+		assert.Equal(t, filepath.Join(worktree, ".sow", "project", "state.db"), sqliteBackend.Path())
+	})
+
+	t.Run("keys HTTP backend by the given branch", func(t *testing.T) {
+		worktree := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(worktree, ".sow"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(worktree, ".sow", "config.yaml"), []byte("state:\n  backend: http\n  url: http://localhost:7070\n"), 0644))
+
+		backend, err := NewWorktreeBackend(worktree, "feat/x")
+
+		require.NoError(t, err)
+		require.IsType(t, &state.HTTPBackend{}, backend)
+		httpBackend, _ := backend.(*state.HTTPBackend)
+		assert.Equal(t, "http://localhost:7070/projects/feat%2Fx", httpBackend.URL())
+	})
+
+	t.Run("reports worktree without .sow", func(t *testing.T) {
+		_, err := NewWorktreeBackend(t.TempDir(), "feat/x")
+
+		assert.ErrorIs(t, err, sow.ErrNotInitialized)
+	})
+}
+
+func TestCreateAndLoadProject_SQLite(t *testing.T) {
+	sowCtx := setupSowContext(t, "state:\n  backend: sqlite\n")
+	ctx := context.Background()
+
+	created, err := CreateProject(ctx, sowCtx, state.CreateOpts{
+		Branch:      "feat/sqlite",
+		Description: "SQLite backed project",
+	})
+	require.NoError(t, err)
+
+	_, err = os.Stat(filepath.Join(sowCtx.RepoRoot(), ".sow", "project", "state.db"))
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(sowCtx.RepoRoot(), ".sow", "project", "state.yaml"))
+	assert.True(t, os.IsNotExist(err))
+
+	loaded, err := LoadProject(ctx, sowCtx)
+	require.NoError(t, err)
+	assert.Equal(t, created.Name, loaded.Name)
+	assert.Equal(t, created.Statechart.Current_state, loaded.Statechart.Current_state)
+	assert.Len(t, loaded.Phases, len(created.Phases))
+}
+
+func TestUpdateProject(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reapplies change after concurrent save", func(t *testing.T) {
+		sowCtx := setupSowContext(t, "")
+		_, err := CreateProject(ctx, sowCtx, state.CreateOpts{
+			Branch:      "feat/concurrent",
+			Description: "Concurrent project",
+		})
+		require.NoError(t, err)
+
+		stale, err := LoadProject(ctx, sowCtx)
+		require.NoError(t, err)
+
+		other, err := LoadProject(ctx, sowCtx)
+		require.NoError(t, err)
+		other.Agent_sessions = map[string]string{"implementer": "sess-1"}
+		require.NoError(t, other.Save(ctx))
+
+		_, err = UpdateProject(ctx, stale, func(p *state.Project) error {
+			p.Description = "Updated"
+			return nil
+		})
+		require.NoError(t, err)
+
+		loaded, err := LoadProject(ctx, sowCtx)
+		require.NoError(t, err)
+		assert.Equal(t, "Updated", loaded.Description)
+		assert.Equal(t, "sess-1", loaded.Agent_sessions["implementer"])
+	})
+
+	t.Run("returns mutate error unchanged", func(t *testing.T) {
+		sowCtx := setupSowContext(t, "")
+		proj, err := CreateProject(ctx, sowCtx, state.CreateOpts{
+			Branch:      "feat/error",
+			Description: "Error project",
+		})
+		require.NoError(t, err)
+
+		_, err = UpdateProject(ctx, proj, func(_ *state.Project) error {
+			return fmt.Errorf("phase not found: missing")
+		})
+
+		require.EqualError(t, err, "phase not found: missing")
+	})
+
+	t.Run("records task status changes in journal", func(t *testing.T) {
+		sowCtx := setupSowContext(t, "")
+		t.Setenv(state.ActorEnvVar, "implementer")
+		proj, err := CreateProject(ctx, sowCtx, state.CreateOpts{
+			Branch:      "feat/journal",
+			Description: "Journal project",
+		})
+		require.NoError(t, err)
+		impl := proj.Phases["implementation"]
+		impl.Tasks = append(impl.Tasks, projschema.TaskState{Id: "010", Name: "Task", Phase: "implementation", Status: "pending"})
+		proj.Phases["implementation"] = impl
+		require.NoError(t, proj.Save(ctx))
+
+		_, err = UpdateProject(WithContext(ctx, sowCtx), proj, func(p *state.Project) error {
+			impl := p.Phases["implementation"]
+			impl.Tasks[0].Status = "in_progress"
+			p.Phases["implementation"] = impl
+			return nil
+		})
+		require.NoError(t, err)
+
+		entries, err := state.NewJournal(JournalPath(sowCtx)).Read()
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, state.JournalTaskStatus, entries[0].Kind)
+		assert.Equal(t, "010", entries[0].Task)
+		assert.Equal(t, "pending", entries[0].From)
+		assert.Equal(t, "in_progress", entries[0].To)
+		assert.Equal(t, "implementer", entries[0].Actor)
+		assert.False(t, entries[0].Time.IsZero())
+	})
+}
+
+func TestLockProject(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("returns nil lock without a project directory", func(t *testing.T) {
+		sowCtx := setupSowContext(t, "")
+
+		lock, err := LockProject(ctx, sowCtx)
+
+		require.NoError(t, err)
+		assert.Nil(t, lock)
+		assert.NoError(t, lock.Release())
+		_, err = os.Stat(filepath.Join(sowCtx.RepoRoot(), ".sow", "project"))
+		assert.True(t, os.IsNotExist(err), "locking must not create the project directory")
+	})
+
+	t.Run("second lock times out with unlock hint", func(t *testing.T) {
+		sowCtx := setupSowContext(t, "state:\n  lock_timeout: 0s\n")
+		require.NoError(t, os.MkdirAll(filepath.Join(sowCtx.RepoRoot(), ".sow", "project"), 0755))
+
+		lock, err := LockProject(ctx, sowCtx)
+		require.NoError(t, err)
+		require.NotNil(t, lock)
+		defer func() { _ = lock.Release() }()
+		assert.Equal(t, LockPath(sowCtx), lock.Path())
+
+		_, err = LockProject(ctx, sowCtx)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, state.ErrLocked)
+		assert.Contains(t, err.Error(), "sow project unlock")
+	})
+}
@@ -281,6 +281,20 @@ func getMetadataMap(target interface{}) (map[string]interface{}, error) {
 
 // setFieldValue sets a reflect.Value to the converted value.
 func setFieldValue(field reflect.Value, value string) error {
+	// String lists are given comma-separated and are not type-converted,
+	// so IDs like "010" keep their leading zeros. An empty value clears
+	// the list.
+	if field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String {
+		items := []string{}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+		return nil
+	}
+
 	converted := ConvertValue(value)
 
 	switch field.Kind() {
@@ -322,7 +336,7 @@ func capitalizeFirst(s string) string {
 var knownFields = map[string][]string{
 	"Artifact": {"type", "path", "approved", "created_at"},
 	"Phase":    {"status", "enabled", "created_at", "started_at", "completed_at"},
-	"Task":     {"id", "name", "phase", "status", "iteration", "assigned_agent", "created_at", "started_at", "updated_at", "completed_at"},
+	"Task":     {"id", "name", "phase", "status", "iteration", "assigned_agent", "depends_on", "created_at", "started_at", "updated_at", "completed_at"},
 	"Project":  {"name", "type", "branch", "description", "created_at", "updated_at"},
 }
 
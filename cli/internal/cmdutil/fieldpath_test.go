@@ -263,6 +263,21 @@ func TestSetFieldTask(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, "high", task.Metadata["priority"])
 	})
+
+	t.Run("set task dependencies", func(t *testing.T) {
+		task := &state.Task{}
+		err := SetField(task, "depends_on", "010, 020")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"010", "020"}, task.Depends_on)
+	})
+
+	t.Run("clear task dependencies", func(t *testing.T) {
+		task := &state.Task{}
+		task.Depends_on = []string{"010"}
+		err := SetField(task, "depends_on", "")
+		require.NoError(t, err)
+		assert.Empty(t, task.Depends_on)
+	})
 }
 
 // Test SetField - sets fields on Phase.
@@ -697,7 +712,7 @@ func TestKnownFieldsComprehensive(t *testing.T) {
 	})
 
 	t.Run("all task known fields", func(t *testing.T) {
-		fields := []string{"id", "name", "phase", "status", "iteration", "assigned_agent", "created_at", "started_at", "updated_at", "completed_at"}
+		fields := []string{"id", "name", "phase", "status", "iteration", "assigned_agent", "depends_on", "created_at", "started_at", "updated_at", "completed_at"}
 		for _, field := range fields {
 			assert.True(t, IsKnownField("Task", field), "field %s should be known", field)
 		}
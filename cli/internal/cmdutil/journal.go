@@ -0,0 +1,45 @@
+package cmdutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jmgilman/sow/cli/internal/sow"
+	"github.com/jmgilman/sow/libs/project/state"
+)
+
+// JournalPath returns the OS path of the project journal.
+func JournalPath(sowCtx *sow.Context) string {
+	return filepath.Join(sowCtx.RepoRoot(), ".sow", state.DefaultJournalFile)
+}
+
+// AppendJournal records entries in the project journal, filling in the
+// time and actor where unset. The sow.Context is taken from ctx; without
+// one (as in unit tests) nothing is recorded.
+//
+// The journal is written after state has been saved, so a failure here
+// must not fail the command. It is reported as a warning instead.
+func AppendJournal(ctx context.Context, entries ...state.JournalEntry) {
+	sowCtx, ok := ctx.Value(sowContextKey).(*sow.Context)
+	if !ok || len(entries) == 0 {
+		return
+	}
+
+	now := time.Now().UTC()
+	actor := state.Actor()
+	for i := range entries {
+		if entries[i].Time.IsZero() {
+			entries[i].Time = now
+		}
+		if entries[i].Actor == "" {
+			entries[i].Actor = actor
+		}
+	}
+
+	if err := state.NewJournal(JournalPath(sowCtx)).Append(entries...); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record project history: %v\n", err)
+	}
+}
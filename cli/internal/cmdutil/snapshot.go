@@ -0,0 +1,99 @@
+package cmdutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jmgilman/sow/cli/internal/sow"
+	"github.com/jmgilman/sow/libs/project/state"
+	projschema "github.com/jmgilman/sow/libs/schemas/project"
+)
+
+// SnapshotDir returns the OS path of the project snapshot directory.
+func SnapshotDir(sowCtx *sow.Context) string {
+	return filepath.Join(sowCtx.RepoRoot(), ".sow", state.DefaultSnapshotDir)
+}
+
+// SaveSnapshot stores the project state from before a transition so it can
+// be undone, and returns the snapshot number. As with AppendJournal, the
+// sow.Context is taken from ctx and failures are reported as warnings, in
+// which case 0 is returned.
+func SaveSnapshot(ctx context.Context, before *projschema.ProjectState, event string) int {
+	sowCtx, ok := ctx.Value(sowContextKey).(*sow.Context)
+	if !ok || before == nil {
+		return 0
+	}
+
+	snap := &state.Snapshot{
+		Time:  time.Now().UTC(),
+		Actor: state.Actor(),
+		Event: event,
+		State: *before,
+	}
+	if err := state.NewSnapshotStore(SnapshotDir(sowCtx)).Save(snap); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save state snapshot: %v\n", err)
+		return 0
+	}
+	return snap.Number
+}
+
+// RollbackProject restores the project to the given snapshot, or to the
+// most recent one if number is 0, and saves it. The restored snapshot and
+// any later ones are discarded and the rollback is recorded in the journal
+// as a single entry.
+//
+// Returns the saved project and the snapshot that was restored.
+func RollbackProject(
+	ctx context.Context,
+	sowCtx *sow.Context,
+	proj *state.Project,
+	number int,
+) (*state.Project, *state.Snapshot, error) {
+	store := state.NewSnapshotStore(SnapshotDir(sowCtx))
+
+	var snap *state.Snapshot
+	var err error
+	if number == 0 {
+		snap, err = store.Latest()
+	} else {
+		snap, err = store.Get(number)
+	}
+	if err != nil {
+		if errors.Is(err, state.ErrSnapshotNotFound) && number == 0 {
+			return nil, nil, fmt.Errorf("nothing to undo: no snapshots recorded")
+		}
+		return nil, nil, fmt.Errorf("rollback project: %w", err)
+	}
+
+	// The rollback is journaled as a single entry: the task status changes
+	// and approvals it reverts are not recorded as changes of their own
+	proj, before, err := updateProject(ctx, proj, func(p *state.Project) error {
+		return p.Restore(&snap.State)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := store.DiscardFrom(snap.Number); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to discard snapshots: %v\n", err)
+	}
+
+	entry := state.JournalEntry{
+		Kind:     state.JournalRollback,
+		Event:    snap.Event,
+		From:     before.Statechart.Current_state,
+		To:       proj.Statechart.Current_state,
+		Snapshot: snap.Number,
+		Revision: proj.Revision,
+	}
+	if config := proj.Config(); config != nil {
+		entry.Phase = config.GetPhaseForState(entry.To)
+	}
+	AppendJournal(ctx, entry)
+
+	return proj, snap, nil
+}
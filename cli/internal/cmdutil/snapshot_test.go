@@ -0,0 +1,50 @@
+package cmdutil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmgilman/sow/libs/project/state"
+	projschema "github.com/jmgilman/sow/libs/schemas/project"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRollbackProject(t *testing.T) {
+	sowCtx := setupSowContext(t, "")
+	ctx := WithContext(context.Background(), sowCtx)
+	proj, err := CreateProject(ctx, sowCtx, state.CreateOpts{
+		Branch:      "feat/rollback",
+		Description: "Rollback project",
+	})
+	require.NoError(t, err)
+	impl := proj.Phases["implementation"]
+	impl.Tasks = append(impl.Tasks, projschema.TaskState{Id: "010", Name: "Task", Phase: "implementation", Status: "pending"})
+	proj.Phases["implementation"] = impl
+	require.NoError(t, proj.Save(ctx))
+
+	number := SaveSnapshot(ctx, proj.Snapshot(), "all_tasks_complete")
+	require.Equal(t, 1, number)
+
+	proj, err = UpdateProject(ctx, proj, func(p *state.Project) error {
+		p.Phases["implementation"].Tasks[0].Status = "completed"
+		return nil
+	})
+	require.NoError(t, err)
+
+	proj, snap, err := RollbackProject(ctx, sowCtx, proj, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, snap.Number)
+	assert.Equal(t, "pending", proj.Phases["implementation"].Tasks[0].Status)
+
+	// The reverted task status is part of the rollback entry, not an
+	// entry of its own
+	entries, err := state.NewJournal(JournalPath(sowCtx)).Read()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, state.JournalTaskStatus, entries[0].Kind)
+	assert.Equal(t, state.JournalRollback, entries[1].Kind)
+	assert.Equal(t, "all_tasks_complete", entries[1].Event)
+	assert.Equal(t, 1, entries[1].Snapshot)
+	assert.Equal(t, proj.Revision, entries[1].Revision)
+}
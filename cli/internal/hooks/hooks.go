@@ -0,0 +1,226 @@
+// Package hooks runs the shell hooks configured in .sow/config.yaml when
+// sow advance moves a project between states.
+//
+// Hooks run at four points of a transition:
+//
+//   - pre_advance: before every transition
+//   - on_exit: before leaving the listed state
+//   - on_enter: before entering the listed state
+//   - post_advance: after the transition has been saved
+//
+// The first three run before the new state is saved, so a non-zero exit
+// from any of their commands vetoes the transition. post_advance failures
+// are reported to the caller but cannot undo the transition.
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	osexec "os/exec"
+	"strings"
+	"time"
+
+	"github.com/jmgilman/go/fs/core"
+	"github.com/jmgilman/sow/cli/internal/logging"
+	"github.com/jmgilman/sow/cli/internal/sow"
+	"github.com/jmgilman/sow/libs/config"
+	"github.com/jmgilman/sow/libs/exec"
+	"github.com/jmgilman/sow/libs/schemas"
+)
+
+// Hook points, as named in .sow/config.yaml.
+const (
+	PreAdvance  = "pre_advance"
+	OnExit      = "on_exit"
+	OnEnter     = "on_enter"
+	PostAdvance = "post_advance"
+)
+
+// LogPath is the project log receiving hook output, relative to .sow/.
+const LogPath = "project/log.md"
+
+// waitDelay bounds how long a timed-out hook's children may keep its
+// output open after the hook is killed.
+const waitDelay = time.Second
+
+// logAgent is the agent recorded in project log entries written by hooks.
+const logAgent = "hook"
+
+// Transition describes the transition hooks run for. Its fields are passed
+// to hook commands as SOW_* environment variables.
+type Transition struct {
+	Project string // SOW_PROJECT_NAME
+	Type    string // SOW_PROJECT_TYPE
+	Branch  string // SOW_PROJECT_BRANCH
+	Phase   string // SOW_PHASE, the phase of the state being left
+	Event   string // SOW_EVENT
+	From    string // SOW_FROM_STATE
+	To      string // SOW_TO_STATE
+}
+
+// Error reports a hook command that failed.
+type Error struct {
+	Point   string
+	Command string
+	Output  string
+	Err     error
+}
+
+// Error returns the error message.
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s hook %q failed: %v", e.Point, e.Command, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Runner runs the configured hooks.
+type Runner struct {
+	hooks   *schemas.HooksConfig
+	timeout time.Duration
+	dir     string
+	logFS   core.FS
+	out     io.Writer
+}
+
+// New returns a runner for the hooks in repoConfig. Commands run in dir and
+// their output is appended to LogPath in logFS, a filesystem rooted at
+// .sow/. Progress lines are written to out.
+func New(repoConfig *schemas.Config, dir string, logFS core.FS, out io.Writer) *Runner {
+	r := &Runner{
+		hooks:   &schemas.HooksConfig{},
+		timeout: config.GetHookTimeout(repoConfig),
+		dir:     dir,
+		logFS:   logFS,
+		out:     out,
+	}
+	if repoConfig != nil && repoConfig.Hooks != nil {
+		r.hooks = repoConfig.Hooks
+	}
+	return r
+}
+
+// FromContext returns a runner for the hooks configured in the repository
+// of sowCtx. Commands run from the repository root.
+func FromContext(sowCtx *sow.Context, out io.Writer) (*Runner, error) {
+	repoConfig, err := config.LoadRepoConfig(sowCtx.FS())
+	if err != nil {
+		return nil, fmt.Errorf("load hooks: %w", err)
+	}
+	return New(repoConfig, sowCtx.RepoRoot(), sowCtx.FS(), out), nil
+}
+
+// Before runs the pre_advance hooks, then the on_exit hooks of t.From,
+// then the on_enter hooks of t.To. It stops at the first failing command
+// and returns its *Error.
+func (r *Runner) Before(ctx context.Context, t Transition) error {
+	if err := r.run(ctx, PreAdvance, r.hooks.Pre_advance, t); err != nil {
+		return err
+	}
+	if err := r.run(ctx, OnExit, r.hooks.On_exit[t.From], t); err != nil {
+		return err
+	}
+	return r.run(ctx, OnEnter, r.hooks.On_enter[t.To], t)
+}
+
+// After runs the post_advance hooks. Every command runs even if an earlier
+// one fails; the first failure is returned.
+func (r *Runner) After(ctx context.Context, t Transition) error {
+	var first error
+	for _, command := range r.hooks.Post_advance {
+		if err := r.runCommand(ctx, PostAdvance, command, t); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// run runs commands in order, stopping at the first failure.
+func (r *Runner) run(ctx context.Context, point string, commands []string, t Transition) error {
+	for _, command := range commands {
+		if err := r.runCommand(ctx, point, command, t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runCommand runs one hook command with sh and records it in the project log.
+func (r *Runner) runCommand(ctx context.Context, point, command string, t Transition) error {
+	_, _ = fmt.Fprintf(r.out, "Running %s hook: %s\n", point, command)
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	sh := exec.NewLocalExecutor("sh",
+		exec.WithDir(r.dir),
+		exec.WithEnv(environ(point, t)...),
+		exec.WithWaitDelay(waitDelay),
+	)
+	stdout, stderr, err := sh.RunContext(ctx, "-c", command)
+	output := stdout + stderr
+
+	var exitErr *osexec.ExitError
+	switch {
+	case err == nil:
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		err = fmt.Errorf("timed out after %s", r.timeout)
+	case errors.As(err, &exitErr):
+		err = fmt.Errorf("exit status %d", exitErr.ExitCode())
+	}
+
+	r.log(point, command, t, output, err)
+
+	if err != nil {
+		return &Error{Point: point, Command: command, Output: output, Err: err}
+	}
+	return nil
+}
+
+// log appends a hook run to the project log. The log is informational, so
+// a failure to write it is only reported as a warning.
+func (r *Runner) log(point, command string, t Transition, output string, runErr error) {
+	if r.logFS == nil {
+		return
+	}
+
+	result := "success"
+	if runErr != nil {
+		result = "failed: " + runErr.Error()
+	}
+
+	notes := fmt.Sprintf("Transition: %s -> %s (%s)", t.From, t.To, t.Event)
+	if output != "" {
+		notes += "\n\n```\n" + strings.TrimRight(output, "\n") + "\n```"
+	}
+
+	entry := &logging.LogEntry{
+		Timestamp: time.Now(),
+		AgentID:   logAgent,
+		Action:    fmt.Sprintf("%s: %s", point, command),
+		Result:    result,
+		Notes:     notes,
+	}
+	if err := logging.AppendLog(r.logFS, LogPath, entry); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record hook output: %v\n", err)
+	}
+}
+
+// environ returns the SOW_* variables describing a hook run.
+func environ(point string, t Transition) []string {
+	return []string{
+		"SOW_HOOK=" + point,
+		"SOW_PROJECT_NAME=" + t.Project,
+		"SOW_PROJECT_TYPE=" + t.Type,
+		"SOW_PROJECT_BRANCH=" + t.Branch,
+		"SOW_PHASE=" + t.Phase,
+		"SOW_EVENT=" + t.Event,
+		"SOW_FROM_STATE=" + t.From,
+		"SOW_TO_STATE=" + t.To,
+	}
+}
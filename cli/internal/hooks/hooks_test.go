@@ -0,0 +1,120 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jmgilman/go/fs/billy"
+	"github.com/jmgilman/go/fs/core"
+	"github.com/jmgilman/sow/libs/schemas"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testTransition = Transition{
+	Project: "demo",
+	Type:    "standard",
+	Branch:  "feat/demo",
+	Phase:   "implementation",
+	Event:   "all_tasks_complete",
+	From:    "ImplementationExecuting",
+	To:      "ReviewActive",
+}
+
+// newTestRunner returns a runner for hooks logging to an in-memory .sow
+// filesystem, and a buffer receiving its progress lines.
+func newTestRunner(t *testing.T, hooks *schemas.HooksConfig) (*Runner, core.FS, *bytes.Buffer) {
+	t.Helper()
+
+	logFS := billy.NewMemory()
+	require.NoError(t, logFS.MkdirAll("project", 0755))
+	var out bytes.Buffer
+	return New(&schemas.Config{Hooks: hooks}, t.TempDir(), logFS, &out), logFS, &out
+}
+
+func TestRunner_Before(t *testing.T) {
+	t.Run("runs hooks in order with the transition in the environment", func(t *testing.T) {
+		runner, logFS, out := newTestRunner(t, &schemas.HooksConfig{
+			Pre_advance: []string{"echo pre $SOW_HOOK $SOW_EVENT"},
+			On_exit: map[string][]string{
+				"ImplementationExecuting": {"echo exit $SOW_FROM_STATE $SOW_PHASE"},
+				"ReviewActive":            {"echo wrong state"},
+			},
+			On_enter: map[string][]string{
+				"ReviewActive": {"echo enter $SOW_TO_STATE $SOW_PROJECT_NAME $SOW_PROJECT_TYPE $SOW_PROJECT_BRANCH"},
+			},
+		})
+
+		require.NoError(t, runner.Before(context.Background(), testTransition))
+
+		assert.Equal(t, "Running pre_advance hook: echo pre $SOW_HOOK $SOW_EVENT\n"+
+			"Running on_exit hook: echo exit $SOW_FROM_STATE $SOW_PHASE\n"+
+			"Running on_enter hook: echo enter $SOW_TO_STATE $SOW_PROJECT_NAME $SOW_PROJECT_TYPE $SOW_PROJECT_BRANCH\n",
+			out.String())
+
+		log, err := logFS.ReadFile(LogPath)
+		require.NoError(t, err)
+		assert.Contains(t, string(log), "pre pre_advance all_tasks_complete\n")
+		assert.Contains(t, string(log), "exit ImplementationExecuting implementation\n")
+		assert.Contains(t, string(log), "enter ReviewActive demo standard feat/demo\n")
+		assert.Contains(t, string(log), "agent: hook\n")
+		assert.Contains(t, string(log), "result: success\n")
+		assert.Contains(t, string(log), "Transition: ImplementationExecuting -> ReviewActive (all_tasks_complete)")
+		assert.NotContains(t, string(log), "wrong state")
+	})
+
+	t.Run("stops at the first failing hook", func(t *testing.T) {
+		runner, logFS, out := newTestRunner(t, &schemas.HooksConfig{
+			Pre_advance: []string{"echo lint errors >&2; exit 3", "echo never"},
+			On_enter:    map[string][]string{"ReviewActive": {"echo never"}},
+		})
+
+		err := runner.Before(context.Background(), testTransition)
+
+		var hookErr *Error
+		require.True(t, errors.As(err, &hookErr))
+		assert.Equal(t, PreAdvance, hookErr.Point)
+		assert.Equal(t, "echo lint errors >&2; exit 3", hookErr.Command)
+		assert.Equal(t, "lint errors\n", hookErr.Output)
+		assert.EqualError(t, err, `pre_advance hook "echo lint errors >&2; exit 3" failed: exit status 3`)
+		assert.NotContains(t, out.String(), "never")
+
+		log, err := logFS.ReadFile(LogPath)
+		require.NoError(t, err)
+		assert.Contains(t, string(log), "result: failed: exit status 3\n")
+	})
+
+	t.Run("no hooks configured", func(t *testing.T) {
+		runner := New(nil, t.TempDir(), nil, &bytes.Buffer{})
+		assert.NoError(t, runner.Before(context.Background(), testTransition))
+		assert.NoError(t, runner.After(context.Background(), testTransition))
+	})
+}
+
+func TestRunner_After(t *testing.T) {
+	runner, _, out := newTestRunner(t, &schemas.HooksConfig{
+		Post_advance: []string{"exit 1", "echo notified"},
+	})
+
+	err := runner.After(context.Background(), testTransition)
+
+	assert.EqualError(t, err, `post_advance hook "exit 1" failed: exit status 1`)
+	assert.Contains(t, out.String(), "Running post_advance hook: echo notified", "later hooks still run")
+}
+
+func TestRunner_Timeout(t *testing.T) {
+	timeout := "50ms"
+	logFS := billy.NewMemory()
+	runner := New(&schemas.Config{Hooks: &schemas.HooksConfig{
+		Pre_advance: []string{"sleep 5; true"},
+		Timeout:     &timeout,
+	}}, t.TempDir(), logFS, &bytes.Buffer{})
+
+	err := runner.Before(context.Background(), testTransition)
+
+	require.Error(t, err)
+	assert.True(t, strings.HasSuffix(err.Error(), "timed out after 50ms"), err.Error())
+}
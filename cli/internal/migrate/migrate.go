@@ -0,0 +1,310 @@
+// Package migrate upgrades the .sow directory structure between versions.
+//
+// The structure version is stored in .sow/.version (see sow.StructureVersion).
+// Each Migration upgrades the structure to one version and consists of
+// ordered steps that rewrite a single well-known file. Migrations are
+// planned entirely in memory and only written once every step has
+// succeeded, which also makes dry runs exact.
+package migrate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	"github.com/jmgilman/sow/cli/internal/sow"
+)
+
+// VersionFile is the structure version file, relative to .sow/.
+const VersionFile = ".version"
+
+// BaseVersion is assumed for .sow directories without a version file.
+const BaseVersion = "1.0.0"
+
+// targetVersion is the structure version this sow writes and migrates to.
+// It is a variable to allow tests to override it.
+var targetVersion = sow.StructureVersion
+
+// ErrStructureTooNew indicates the .sow structure was written by a newer
+// sow than this one, which may not understand it.
+var ErrStructureTooNew = errors.New(".sow structure is newer than this version of sow supports")
+
+// Target identifies a file that migration steps can rewrite.
+type Target string
+
+const (
+	// TargetProjectState is the YAML project state file.
+	TargetProjectState Target = "project_state"
+	// TargetRefsIndex is the committed refs index (refs/index.json).
+	TargetRefsIndex Target = "refs_index"
+	// TargetKnowledgeIndex is the knowledge index (knowledge/index.yaml).
+	TargetKnowledgeIndex Target = "knowledge_index"
+)
+
+// Transform rewrites the contents of a file.
+// Returning the input unchanged means the step does not apply.
+type Transform func(data []byte) ([]byte, error)
+
+// Step rewrites one target file.
+type Step struct {
+	Target      Target
+	Description string
+	Transform   Transform
+}
+
+// Migration upgrades the structure to Version. Steps run in order and see
+// the output of earlier steps and earlier migrations.
+type Migration struct {
+	Version     string
+	Description string
+	Steps       []Step
+}
+
+// Options configures a migration run.
+type Options struct {
+	// StatePath is the YAML project state file relative to .sow/.
+	// Leave empty to skip project state steps, e.g. with the SQLite backend.
+	StatePath string
+
+	// DryRun plans the migration without writing any files.
+	DryRun bool
+}
+
+// Change describes a file rewritten by a migration step.
+type Change struct {
+	Version     string
+	Path        string
+	Description string
+}
+
+// Result summarizes a migration run.
+type Result struct {
+	// From is the structure version found on disk.
+	From string
+	// To is the structure version after the run.
+	To string
+	// Applied lists the migrations that ran, in order.
+	Applied []Migration
+	// Changes lists the files that were (or, for dry runs, would be) rewritten.
+	Changes []Change
+}
+
+// UpToDate reports whether the structure was already at the current version.
+func (r *Result) UpToDate() bool {
+	return r.From == r.To
+}
+
+// ReadVersion returns the structure version recorded in .sow/.version.
+// Returns BaseVersion if the file does not exist.
+func ReadVersion(sowFS sow.FS) (string, error) {
+	data, err := sowFS.ReadFile(VersionFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return BaseVersion, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("read structure version: %w", err)
+	}
+
+	version := strings.TrimSpace(string(data))
+	if _, err := parseVersion(version); err != nil {
+		return "", fmt.Errorf("read structure version: %w", err)
+	}
+	return version, nil
+}
+
+// Check verifies that this sow can operate on the .sow structure.
+// Returns an error wrapping ErrStructureTooNew if the structure is newer
+// than sow.StructureVersion, and reports whether migrations are pending.
+func Check(sowFS sow.FS) (bool, error) {
+	current, err := ReadVersion(sowFS)
+	if err != nil {
+		return false, err
+	}
+
+	cmp, err := compareVersions(current, targetVersion)
+	if err != nil {
+		return false, err
+	}
+	if cmp > 0 {
+		return false, fmt.Errorf("%w (found %s, supported up to %s); upgrade sow to continue",
+			ErrStructureTooNew, current, targetVersion)
+	}
+	return cmp < 0, nil
+}
+
+// Run upgrades the .sow structure to sow.StructureVersion by applying every
+// registered migration newer than the version on disk.
+//
+// All steps are applied in memory first; files and the version file are
+// only written if every step succeeds. Steps whose target file does not
+// exist are skipped. With opts.DryRun nothing is written.
+func Run(sowFS sow.FS, opts Options) (*Result, error) {
+	current, err := ReadVersion(sowFS)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := Check(sowFS); err != nil {
+		return nil, err
+	}
+
+	pending, err := pendingMigrations(current)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{From: current, To: targetVersion, Applied: pending}
+	if result.UpToDate() {
+		return result, nil
+	}
+
+	paths := targetPaths(opts)
+	files := map[string][]byte{}
+	var order []string
+
+	for _, m := range pending {
+		for _, step := range m.Steps {
+			path, ok := paths[step.Target]
+			if !ok {
+				continue
+			}
+
+			data, exists := files[path]
+			if !exists {
+				data, err = sowFS.ReadFile(path)
+				if errors.Is(err, fs.ErrNotExist) {
+					continue
+				}
+				if err != nil {
+					return nil, fmt.Errorf("migration %s: read %s: %w", m.Version, path, err)
+				}
+			}
+
+			out, err := step.Transform(data)
+			if err != nil {
+				return nil, fmt.Errorf("migration %s: %s: %w", m.Version, path, err)
+			}
+			if bytes.Equal(out, data) {
+				continue
+			}
+
+			if _, seen := files[path]; !seen {
+				order = append(order, path)
+			}
+			files[path] = out
+			result.Changes = append(result.Changes, Change{Version: m.Version, Path: path, Description: step.Description})
+		}
+	}
+
+	if opts.DryRun {
+		return result, nil
+	}
+
+	for _, path := range order {
+		if err := writeFile(sowFS, path, files[path]); err != nil {
+			return nil, err
+		}
+	}
+	if err := writeFile(sowFS, VersionFile, []byte(targetVersion+"\n")); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// targetPaths maps targets to files relative to .sow/.
+func targetPaths(opts Options) map[Target]string {
+	paths := map[Target]string{
+		TargetRefsIndex:      "refs/index.json",
+		TargetKnowledgeIndex: "knowledge/index.yaml",
+	}
+	if opts.StatePath != "" {
+		paths[TargetProjectState] = opts.StatePath
+	}
+	return paths
+}
+
+// pendingMigrations returns the registered migrations newer than current and
+// no newer than sow.StructureVersion, in order.
+func pendingMigrations(current string) ([]Migration, error) {
+	var pending []Migration
+	previous := ""
+	for _, m := range migrations {
+		if previous != "" {
+			cmp, err := compareVersions(m.Version, previous)
+			if err != nil {
+				return nil, err
+			}
+			if cmp <= 0 {
+				return nil, fmt.Errorf("migration %s is registered after %s", m.Version, previous)
+			}
+		}
+		previous = m.Version
+
+		newer, err := compareVersions(m.Version, current)
+		if err != nil {
+			return nil, err
+		}
+		supported, err := compareVersions(m.Version, targetVersion)
+		if err != nil {
+			return nil, err
+		}
+		if newer > 0 && supported <= 0 {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
+}
+
+// writeFile replaces a file via a temp file and rename so that readers never
+// see a partially written file.
+func writeFile(sowFS sow.FS, path string, data []byte) error {
+	tmp := path + ".migrate.tmp"
+	if err := sowFS.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	if err := sowFS.Rename(tmp, path); err != nil {
+		_ = sowFS.Remove(tmp)
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
+
+// compareVersions compares two MAJOR.MINOR.PATCH versions, returning -1, 0,
+// or 1 like strings.Compare.
+func compareVersions(a, b string) (int, error) {
+	va, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+	for i := range va {
+		if va[i] != vb[i] {
+			if va[i] < vb[i] {
+				return -1, nil
+			}
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+func parseVersion(v string) ([3]int, error) {
+	var parsed [3]int
+	parts := strings.Split(v, ".")
+	if len(parts) != 3 {
+		return parsed, fmt.Errorf("invalid structure version %q: expected MAJOR.MINOR.PATCH", v)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return parsed, fmt.Errorf("invalid structure version %q: expected MAJOR.MINOR.PATCH", v)
+		}
+		parsed[i] = n
+	}
+	return parsed, nil
+}
@@ -0,0 +1,296 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jmgilman/go/fs/billy"
+	"github.com/jmgilman/sow/cli/internal/sow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testState          = "name: test\nschema: old\n"
+	testRefsIndex      = "{\n  \"version\": \"1.0.0\",\n  \"refs\": []\n}\n"
+	testKnowledgeIndex = "# Knowledge Index\nexplorations: []\n"
+)
+
+// setupMigrations replaces the registered migrations and target version for
+// the duration of the test.
+func setupMigrations(t *testing.T, target string, ms ...Migration) {
+	t.Helper()
+
+	origTarget, origMigrations := targetVersion, migrations
+	targetVersion, migrations = target, ms
+	t.Cleanup(func() {
+		targetVersion, migrations = origTarget, origMigrations
+	})
+}
+
+// newSowFS returns an in-memory .sow filesystem at the given version with
+// the files Init creates plus a project state file.
+func newSowFS(t *testing.T, version string) sow.FS {
+	t.Helper()
+
+	memFS := billy.NewMemory()
+	require.NoError(t, memFS.MkdirAll("project", 0755))
+	require.NoError(t, memFS.MkdirAll("refs", 0755))
+	require.NoError(t, memFS.MkdirAll("knowledge", 0755))
+	require.NoError(t, memFS.WriteFile(VersionFile, []byte(version+"\n"), 0644))
+	require.NoError(t, memFS.WriteFile("project/state.yaml", []byte(testState), 0644))
+	require.NoError(t, memFS.WriteFile("refs/index.json", []byte(testRefsIndex), 0644))
+	require.NoError(t, memFS.WriteFile("knowledge/index.yaml", []byte(testKnowledgeIndex), 0644))
+	return memFS
+}
+
+// exampleMigrations touches every target across two versions, with the
+// second migration depending on the output of the first.
+func exampleMigrations() []Migration {
+	return []Migration{
+		{
+			Version:     "1.1.0",
+			Description: "Rename schema field",
+			Steps: []Step{
+				{
+					Target:      TargetProjectState,
+					Description: "rename schema to layout",
+					Transform: YAMLDocument(func(doc map[string]any) error {
+						doc["layout"] = doc["schema"]
+						delete(doc, "schema")
+						return nil
+					}),
+				},
+				{
+					Target:      TargetRefsIndex,
+					Description: "bump refs index version",
+					Transform: JSONDocument(func(doc map[string]any) error {
+						doc["version"] = "1.1.0"
+						return nil
+					}),
+				},
+			},
+		},
+		{
+			Version:     "2.0.0",
+			Description: "Upgrade layout",
+			Steps: []Step{
+				{
+					Target:      TargetProjectState,
+					Description: "upgrade layout value",
+					Transform: YAMLDocument(func(doc map[string]any) error {
+						doc["layout"] = fmt.Sprintf("%v-v2", doc["layout"])
+						return nil
+					}),
+				},
+				{
+					Target:      TargetKnowledgeIndex,
+					Description: "no-op",
+					Transform: YAMLDocument(func(map[string]any) error {
+						return nil
+					}),
+				},
+			},
+		},
+	}
+}
+
+func TestReadVersion(t *testing.T) {
+	t.Run("missing version file returns base version", func(t *testing.T) {
+		version, err := ReadVersion(billy.NewMemory())
+
+		require.NoError(t, err)
+		assert.Equal(t, BaseVersion, version)
+	})
+
+	t.Run("trims version file", func(t *testing.T) {
+		version, err := ReadVersion(newSowFS(t, "1.2.3"))
+
+		require.NoError(t, err)
+		assert.Equal(t, "1.2.3", version)
+	})
+
+	t.Run("rejects malformed version", func(t *testing.T) {
+		_, err := ReadVersion(newSowFS(t, "latest"))
+
+		assert.ErrorContains(t, err, "invalid structure version")
+	})
+}
+
+func TestCheck(t *testing.T) {
+	setupMigrations(t, "1.1.0")
+
+	tests := []struct {
+		name    string
+		version string
+		pending bool
+		tooNew  bool
+	}{
+		{"current", "1.1.0", false, false},
+		{"older", "1.0.0", true, false},
+		{"newer minor", "1.2.0", false, true},
+		{"newer major", "2.0.0", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pending, err := Check(newSowFS(t, tt.version))
+
+			if tt.tooNew {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, ErrStructureTooNew))
+				assert.Contains(t, err.Error(), "upgrade sow")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.pending, pending)
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Run("up to date structure is unchanged", func(t *testing.T) {
+		setupMigrations(t, "2.0.0", exampleMigrations()...)
+		sowFS := newSowFS(t, "2.0.0")
+
+		result, err := Run(sowFS, Options{StatePath: "project/state.yaml"})
+
+		require.NoError(t, err)
+		assert.True(t, result.UpToDate())
+		assert.Empty(t, result.Changes)
+	})
+
+	t.Run("applies pending migrations in order", func(t *testing.T) {
+		setupMigrations(t, "2.0.0", exampleMigrations()...)
+		sowFS := newSowFS(t, "1.0.0")
+
+		result, err := Run(sowFS, Options{StatePath: "project/state.yaml"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "1.0.0", result.From)
+		assert.Equal(t, "2.0.0", result.To)
+		assert.Len(t, result.Applied, 2)
+		assert.Equal(t, []Change{
+			{Version: "1.1.0", Path: "project/state.yaml", Description: "rename schema to layout"},
+			{Version: "1.1.0", Path: "refs/index.json", Description: "bump refs index version"},
+			{Version: "2.0.0", Path: "project/state.yaml", Description: "upgrade layout value"},
+		}, result.Changes)
+
+		state, err := sowFS.ReadFile("project/state.yaml")
+		require.NoError(t, err)
+		assert.Equal(t, "layout: old-v2\nname: test\n", string(state))
+
+		refs, err := sowFS.ReadFile("refs/index.json")
+		require.NoError(t, err)
+		assert.Contains(t, string(refs), `"version": "1.1.0"`)
+
+		knowledge, err := sowFS.ReadFile("knowledge/index.yaml")
+		require.NoError(t, err)
+		assert.Equal(t, testKnowledgeIndex, string(knowledge), "unchanged files keep their comments")
+
+		version, err := ReadVersion(sowFS)
+		require.NoError(t, err)
+		assert.Equal(t, "2.0.0", version)
+	})
+
+	t.Run("skips migrations already applied", func(t *testing.T) {
+		setupMigrations(t, "2.0.0", exampleMigrations()...)
+		sowFS := newSowFS(t, "1.1.0")
+
+		result, err := Run(sowFS, Options{StatePath: "project/state.yaml"})
+
+		require.NoError(t, err)
+		require.Len(t, result.Applied, 1)
+		assert.Equal(t, "2.0.0", result.Applied[0].Version)
+	})
+
+	t.Run("dry run writes nothing", func(t *testing.T) {
+		setupMigrations(t, "2.0.0", exampleMigrations()...)
+		sowFS := newSowFS(t, "1.0.0")
+
+		result, err := Run(sowFS, Options{StatePath: "project/state.yaml", DryRun: true})
+
+		require.NoError(t, err)
+		assert.Len(t, result.Changes, 3)
+
+		state, err := sowFS.ReadFile("project/state.yaml")
+		require.NoError(t, err)
+		assert.Equal(t, testState, string(state))
+		version, err := ReadVersion(sowFS)
+		require.NoError(t, err)
+		assert.Equal(t, "1.0.0", version)
+	})
+
+	t.Run("skips project state without a state path", func(t *testing.T) {
+		setupMigrations(t, "2.0.0", exampleMigrations()...)
+		sowFS := newSowFS(t, "1.0.0")
+
+		result, err := Run(sowFS, Options{})
+
+		require.NoError(t, err)
+		require.Len(t, result.Changes, 1)
+		assert.Equal(t, "refs/index.json", result.Changes[0].Path)
+	})
+
+	t.Run("skips missing files", func(t *testing.T) {
+		setupMigrations(t, "2.0.0", exampleMigrations()...)
+		sowFS := newSowFS(t, "1.0.0")
+		require.NoError(t, sowFS.Remove("project/state.yaml"))
+
+		result, err := Run(sowFS, Options{StatePath: "project/state.yaml"})
+
+		require.NoError(t, err)
+		require.Len(t, result.Changes, 1)
+		exists, err := sowFS.Exists("project/state.yaml")
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("failed step writes nothing", func(t *testing.T) {
+		ms := exampleMigrations()
+		ms[1].Steps[0].Transform = func([]byte) ([]byte, error) {
+			return nil, errors.New("boom")
+		}
+		setupMigrations(t, "2.0.0", ms...)
+		sowFS := newSowFS(t, "1.0.0")
+
+		_, err := Run(sowFS, Options{StatePath: "project/state.yaml"})
+
+		require.ErrorContains(t, err, "migration 2.0.0: project/state.yaml: boom")
+		refs, err := sowFS.ReadFile("refs/index.json")
+		require.NoError(t, err)
+		assert.Equal(t, testRefsIndex, string(refs))
+		version, err := ReadVersion(sowFS)
+		require.NoError(t, err)
+		assert.Equal(t, "1.0.0", version)
+	})
+
+	t.Run("refuses newer structure", func(t *testing.T) {
+		setupMigrations(t, "1.0.0")
+
+		_, err := Run(newSowFS(t, "1.1.0"), Options{})
+
+		assert.True(t, errors.Is(err, ErrStructureTooNew))
+	})
+
+	t.Run("rejects out of order migrations", func(t *testing.T) {
+		ms := exampleMigrations()
+		ms[0], ms[1] = ms[1], ms[0]
+		setupMigrations(t, "2.0.0", ms...)
+
+		_, err := Run(newSowFS(t, "1.0.0"), Options{})
+
+		assert.ErrorContains(t, err, "registered after")
+	})
+}
+
+func TestRegisteredMigrations(t *testing.T) {
+	// Every registered migration must be reachable from the base version and
+	// no newer than the structure version this sow writes.
+	pending, err := pendingMigrations(BaseVersion)
+
+	require.NoError(t, err)
+	assert.Len(t, pending, len(migrations))
+	assert.Equal(t, sow.StructureVersion, targetVersion)
+}
@@ -0,0 +1,84 @@
+package migrate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// migrations lists every structure migration in ascending version order.
+// To change an on-disk schema, bump sow.StructureVersion and append a
+// migration for the new version here. It is a variable to allow tests to
+// override it.
+var migrations = []Migration{}
+
+// YAMLDocument returns a Transform that decodes a YAML mapping, applies fn,
+// and encodes the result. Comments are not preserved. If fn leaves the
+// document unchanged the original bytes are returned untouched.
+func YAMLDocument(fn func(doc map[string]any) error) Transform {
+	return func(data []byte) ([]byte, error) {
+		var doc map[string]any
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("parse YAML: %w", err)
+		}
+		if doc == nil {
+			doc = map[string]any{}
+		}
+		before, err := yaml.Marshal(doc)
+		if err != nil {
+			return nil, fmt.Errorf("encode YAML: %w", err)
+		}
+
+		if err := fn(doc); err != nil {
+			return nil, err
+		}
+
+		after, err := yaml.Marshal(doc)
+		if err != nil {
+			return nil, fmt.Errorf("encode YAML: %w", err)
+		}
+		if bytes.Equal(before, after) {
+			return data, nil
+		}
+		return after, nil
+	}
+}
+
+// JSONDocument returns a Transform that decodes a JSON object, applies fn,
+// and encodes the result with two-space indentation. If fn leaves the
+// document unchanged the original bytes are returned untouched.
+func JSONDocument(fn func(doc map[string]any) error) Transform {
+	return func(data []byte) ([]byte, error) {
+		var doc map[string]any
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("parse JSON: %w", err)
+		}
+		if doc == nil {
+			doc = map[string]any{}
+		}
+		before, err := json.Marshal(doc)
+		if err != nil {
+			return nil, fmt.Errorf("encode JSON: %w", err)
+		}
+
+		if err := fn(doc); err != nil {
+			return nil, err
+		}
+
+		after, err := json.Marshal(doc)
+		if err != nil {
+			return nil, fmt.Errorf("encode JSON: %w", err)
+		}
+		if bytes.Equal(before, after) {
+			return data, nil
+		}
+
+		out, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("encode JSON: %w", err)
+		}
+		return append(out, '\n'), nil
+	}
+}
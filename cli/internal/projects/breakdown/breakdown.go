@@ -79,9 +79,7 @@ func configureTransitions(builder *project.ProjectTypeConfigBuilder) *project.Pr
 			project.State(Discovery),
 			project.State(Active),
 			project.Event(EventBeginActive),
-			project.WithProjectGuard("discovery document approved", func(p *state.Project) bool {
-				return hasApprovedDiscoveryDocument(p)
-			}),
+			project.WithProjectGuardResult("discovery document approved", checkDiscoveryDocumentApproved),
 			project.WithProjectOnEntry(func(p *state.Project) error {
 				// Update breakdown phase status to "active"
 				phase := p.Phases["breakdown"]
@@ -96,8 +94,10 @@ func configureTransitions(builder *project.ProjectTypeConfigBuilder) *project.Pr
 			project.State(Active),
 			project.State(Publishing),
 			project.Event(EventBeginPublishing),
-			project.WithProjectGuard("all work units approved and dependencies valid", func(p *state.Project) bool {
-				return allWorkUnitsApproved(p) && dependenciesValid(p)
+			project.WithProjectGuardResult("all work units approved and dependencies valid", func(p *state.Project) project.GuardResult {
+				result := checkAllWorkUnitsApproved(p)
+				result.Merge(checkDependenciesValid(p))
+				return result
 			}),
 			project.WithProjectOnEntry(func(p *state.Project) error {
 				// Update breakdown phase status to "publishing"
@@ -113,9 +113,7 @@ func configureTransitions(builder *project.ProjectTypeConfigBuilder) *project.Pr
 			project.State(Publishing),
 			project.State(Completed),
 			project.Event(EventCompleteBreakdown),
-			project.WithProjectGuard("all work units published", func(p *state.Project) bool {
-				return allWorkUnitsPublished(p)
-			}),
+			project.WithProjectGuardResult("all work units published", checkAllWorkUnitsPublished),
 			// Note: Breakdown phase completion is automatically managed by FireWithPhaseUpdates
 		)
 }
@@ -3,13 +3,14 @@ package breakdown
 import (
 	"fmt"
 
+	"github.com/jmgilman/sow/libs/project"
 	"github.com/jmgilman/sow/libs/project/state"
 	projschema "github.com/jmgilman/sow/libs/schemas/project"
 )
 
 // Guard functions for breakdown project state transitions.
-// Guards are pure functions that examine project state and return boolean values
-// indicating whether a transition is allowed.
+// The check* functions explain a blocked transition, e.g. by naming each
+// work unit that is not approved or has an unknown dependency.
 
 // hasApprovedDiscoveryDocument checks if an approved discovery document exists.
 // Guards Discovery → Active transition.
@@ -21,19 +22,36 @@ import (
 // Returns true if at least one discovery artifact is approved.
 // This ensures codebase/design context is gathered and validated before work unit identification.
 func hasApprovedDiscoveryDocument(p *state.Project) bool {
+	return checkDiscoveryDocumentApproved(p).Passed()
+}
+
+// checkDiscoveryDocumentApproved reports why no discovery document is
+// approved.
+func checkDiscoveryDocumentApproved(p *state.Project) project.GuardResult {
+	var result project.GuardResult
 	phase, exists := p.Phases["breakdown"]
 	if !exists {
-		return false
+		result.Addf("breakdown phase not found")
+		return result
 	}
 
 	// Check for approved discovery artifact
+	found := false
 	for _, artifact := range phase.Outputs {
-		if artifact.Type == "discovery" && artifact.Approved {
-			return true
+		if artifact.Type == "discovery" {
+			if artifact.Approved {
+				return result
+			}
+			found = true
 		}
 	}
 
-	return false
+	if found {
+		result.Addf("discovery output not approved")
+	} else {
+		result.Addf("discovery output missing")
+	}
+	return result
 }
 
 // allWorkUnitsApproved checks if all work unit tasks are completed or abandoned,
@@ -48,13 +66,21 @@ func hasApprovedDiscoveryDocument(p *state.Project) bool {
 // Returns true if all tasks are completed/abandoned AND at least one is completed.
 // This ensures the breakdown has meaningful output before advancing.
 func allWorkUnitsApproved(p *state.Project) bool {
+	return checkAllWorkUnitsApproved(p).Passed()
+}
+
+// checkAllWorkUnitsApproved lists the work units that are unresolved.
+func checkAllWorkUnitsApproved(p *state.Project) project.GuardResult {
+	var result project.GuardResult
 	phase, exists := p.Phases["breakdown"]
 	if !exists {
-		return false
+		result.Addf("breakdown phase not found")
+		return result
 	}
 
 	if len(phase.Tasks) == 0 {
-		return false
+		result.Addf("no work units planned")
+		return result
 	}
 
 	hasCompleted := false
@@ -62,18 +88,25 @@ func allWorkUnitsApproved(p *state.Project) bool {
 		if task.Status == "completed" {
 			hasCompleted = true
 		} else if task.Status != "abandoned" {
-			// Task is not completed or abandoned, so not all are resolved
-			return false
+			result.Addf("task %s not resolved (%s)", task.Id, task.Status)
 		}
 	}
 
 	// Must have at least one completed task
-	return hasCompleted
+	if result.Passed() && !hasCompleted {
+		result.Addf("no work unit completed (all tasks abandoned)")
+	}
+	return result
 }
 
-// extractTaskDependencies extracts dependency strings from task metadata.
+// extractTaskDependencies returns a task's dependencies: its depends_on
+// list, or for work units created before depends_on existed, the
+// dependencies array in its metadata.
 // Returns nil if no valid dependencies found.
 func extractTaskDependencies(task projschema.TaskState) []string {
+	if len(task.Depends_on) > 0 {
+		return task.Depends_on
+	}
 	if task.Metadata == nil {
 		return nil
 	}
@@ -112,19 +145,30 @@ func extractTaskDependencies(task projschema.TaskState) []string {
 // Returns false if cycles or invalid references detected.
 // Only completed tasks are validated; abandoned/pending tasks are ignored.
 func dependenciesValid(p *state.Project) bool {
+	return checkDependenciesValid(p).Passed()
+}
+
+// checkDependenciesValid lists invalid dependency references and the tasks
+// on a dependency cycle.
+func checkDependenciesValid(p *state.Project) project.GuardResult {
+	var result project.GuardResult
 	phase, exists := p.Phases["breakdown"]
 	if !exists {
-		return false
+		result.Addf("breakdown phase not found")
+		return result
 	}
 
-	// Build adjacency list and valid task ID set
+	// Build adjacency list and valid task ID set, keeping task order for
+	// deterministic output
 	graph := make(map[string][]string)
 	taskIDs := make(map[string]bool)
+	var order []string
 
 	for _, task := range phase.Tasks {
 		// Only validate completed tasks
 		if task.Status == "completed" {
 			taskIDs[task.Id] = true
+			order = append(order, task.Id)
 
 			// Extract dependencies from metadata
 			if deps := extractTaskDependencies(task); deps != nil {
@@ -134,13 +178,16 @@ func dependenciesValid(p *state.Project) bool {
 	}
 
 	// Check all dependencies point to valid task IDs
-	for _, deps := range graph {
-		for _, depID := range deps {
+	for _, taskID := range order {
+		for _, depID := range graph[taskID] {
 			if !taskIDs[depID] {
-				return false // Invalid dependency reference
+				result.Addf("task %s depends on %s, which is not a completed work unit", taskID, depID)
 			}
 		}
 	}
+	if !result.Passed() {
+		return result
+	}
 
 	// Check for cycles using depth-first search
 	visited := make(map[string]bool)
@@ -166,15 +213,14 @@ func dependenciesValid(p *state.Project) bool {
 	}
 
 	// Check all connected components for cycles
-	for taskID := range graph {
-		if !visited[taskID] {
-			if hasCycle(taskID) {
-				return false // Cyclic dependency found
-			}
+	for _, taskID := range order {
+		if !visited[taskID] && hasCycle(taskID) {
+			result.Addf("task %s is part of a dependency cycle", taskID)
+			return result
 		}
 	}
 
-	return true
+	return result
 }
 
 // allWorkUnitsPublished checks if all completed work units have been published to GitHub.
@@ -187,38 +233,38 @@ func dependenciesValid(p *state.Project) bool {
 // Returns true if all completed tasks have metadata.published == true.
 // Ignores abandoned and non-completed tasks.
 func allWorkUnitsPublished(p *state.Project) bool {
+	return checkAllWorkUnitsPublished(p).Passed()
+}
+
+// checkAllWorkUnitsPublished lists the completed work units that have not
+// been published.
+func checkAllWorkUnitsPublished(p *state.Project) project.GuardResult {
+	var result project.GuardResult
 	phase, exists := p.Phases["breakdown"]
 	if !exists {
-		return false
+		result.Addf("breakdown phase not found")
+		return result
 	}
 
 	hasCompleted := false
-
 	for _, task := range phase.Tasks {
 		// Only check completed tasks
-		if task.Status == "completed" {
-			hasCompleted = true
-
-			// Check metadata exists
-			if task.Metadata == nil {
-				return false
-			}
-
-			// Check published field exists and is true
-			publishedRaw, ok := task.Metadata["published"]
-			if !ok {
-				return false
-			}
+		if task.Status != "completed" {
+			continue
+		}
+		hasCompleted = true
 
-			published, ok := publishedRaw.(bool)
-			if !ok || !published {
-				return false
-			}
+		// Check published field exists and is true
+		if published, ok := task.Metadata["published"].(bool); !ok || !published {
+			result.Addf("task %s not published", task.Id)
 		}
 	}
 
 	// Must have at least one completed task
-	return hasCompleted
+	if !hasCompleted {
+		result.Addf("no completed work units")
+	}
+	return result
 }
 
 // Helper functions for task lifecycle management.
@@ -1,9 +1,11 @@
 package breakdown
 
 import (
+	"slices"
 	"testing"
 	"time"
 
+	"github.com/jmgilman/sow/libs/project"
 	"github.com/jmgilman/sow/libs/project/state"
 	projschema "github.com/jmgilman/sow/libs/schemas/project"
 )
@@ -384,6 +386,32 @@ func TestDependenciesValid_ValidLinearDependencies(t *testing.T) {
 	}
 }
 
+func TestDependenciesValid_DependsOn(t *testing.T) {
+	p := newTestProject()
+	afterFirst := newTask("020", "completed")
+	afterFirst.Depends_on = []string{"010"}
+	afterAbandoned := newTask("030", "completed")
+	afterAbandoned.Depends_on = []string{"040"}
+	p.Phases["breakdown"] = projschema.PhaseState{
+		Status:     "active",
+		Enabled:    true,
+		Created_at: time.Now(),
+		Tasks: []projschema.TaskState{
+			newTask("010", "completed"),
+			afterFirst,
+			afterAbandoned,
+			newTask("040", "abandoned"),
+		},
+		Outputs: []projschema.ArtifactState{},
+	}
+
+	result := dependenciesValid(p)
+
+	if result {
+		t.Error("Expected false when depends_on names a work unit that is not completed, got true")
+	}
+}
+
 func TestDependenciesValid_ValidComplexDAG(t *testing.T) {
 	p := newTestProject()
 	p.Phases["breakdown"] = projschema.PhaseState{
@@ -1265,3 +1293,75 @@ func TestAutoApproveArtifact_UpdatesProjectState(t *testing.T) {
 		t.Errorf("Expected exactly 1 approved artifact, got %d", approvedCount)
 	}
 }
+
+// Tests for guard results
+
+func TestCheckDiscoveryDocumentApproved(t *testing.T) {
+	p := newTestProject()
+	p.Phases["breakdown"] = projschema.PhaseState{}
+	assertUnmet(t, checkDiscoveryDocumentApproved(p), "discovery output missing")
+
+	p.Phases["breakdown"] = projschema.PhaseState{
+		Outputs: []projschema.ArtifactState{{Type: "discovery", Path: "discovery.md"}},
+	}
+	assertUnmet(t, checkDiscoveryDocumentApproved(p), "discovery output not approved")
+}
+
+func TestCheckAllWorkUnitsApproved_ListsUnresolvedTasks(t *testing.T) {
+	p := newTestProject()
+	p.Phases["breakdown"] = projschema.PhaseState{
+		Tasks: []projschema.TaskState{
+			newTask("010", "completed"),
+			newTask("020", "needs_review"),
+		},
+	}
+
+	assertUnmet(t, checkAllWorkUnitsApproved(p), "task 020 not resolved (needs_review)")
+}
+
+func TestCheckDependenciesValid(t *testing.T) {
+	p := newTestProject()
+	p.Phases["breakdown"] = projschema.PhaseState{
+		Tasks: []projschema.TaskState{
+			newTaskWithMetadata("010", "completed", map[string]any{
+				"dependencies": []interface{}{"020", "040"},
+			}),
+			newTask("020", "completed"),
+			newTask("040", "pending"),
+		},
+	}
+	assertUnmet(t, checkDependenciesValid(p), "task 010 depends on 040, which is not a completed work unit")
+
+	p.Phases["breakdown"] = projschema.PhaseState{
+		Tasks: []projschema.TaskState{
+			newTaskWithMetadata("010", "completed", map[string]any{
+				"dependencies": []interface{}{"020"},
+			}),
+			newTaskWithMetadata("020", "completed", map[string]any{
+				"dependencies": []interface{}{"010"},
+			}),
+		},
+	}
+	assertUnmet(t, checkDependenciesValid(p), "task 010 is part of a dependency cycle")
+}
+
+func TestCheckAllWorkUnitsPublished_ListsUnpublishedTasks(t *testing.T) {
+	p := newTestProject()
+	p.Phases["breakdown"] = projschema.PhaseState{
+		Tasks: []projschema.TaskState{
+			newTaskWithMetadata("010", "completed", map[string]any{"published": true}),
+			newTask("020", "completed"),
+			newTask("030", "abandoned"),
+		},
+	}
+
+	assertUnmet(t, checkAllWorkUnitsPublished(p), "task 020 not published")
+}
+
+// assertUnmet checks that a guard result lists exactly the given unmet conditions.
+func assertUnmet(t *testing.T, result project.GuardResult, want ...string) {
+	t.Helper()
+	if !slices.Equal(result.Unmet, want) {
+		t.Errorf("Unmet = %q, want %q", result.Unmet, want)
+	}
+}
@@ -206,21 +206,11 @@ func generateActivePrompt(p *state.Project) string {
 			buf.WriteString(fmt.Sprintf("%s %s - %s (%s)\n", statusIcon, task.Id, task.Name, task.Status))
 
 			// Show dependencies if any
-			if task.Metadata != nil {
-				if depsRaw, ok := task.Metadata["dependencies"]; ok {
-					if deps, ok := depsRaw.([]interface{}); ok && len(deps) > 0 {
-						depStrs := make([]string, 0, len(deps))
-						for _, d := range deps {
-							if depStr, ok := d.(string); ok {
-								depStrs = append(depStrs, depStr)
-							}
-						}
-						if len(depStrs) > 0 {
-							buf.WriteString(fmt.Sprintf("    Depends on: %v\n", depStrs))
-						}
-					}
-				}
+			if deps := extractTaskDependencies(task); deps != nil {
+				buf.WriteString(fmt.Sprintf("    Depends on: %v\n", deps))
+			}
 
+			if task.Metadata != nil {
 				// Show artifact path if linked
 				if artifactPath, ok := task.Metadata["artifact_path"].(string); ok && artifactPath != "" {
 					buf.WriteString(fmt.Sprintf("    Spec: %s\n", artifactPath))
@@ -0,0 +1,193 @@
+// Package custom loads project types declared in .sow/types/<name>.cue.
+//
+// Built-in project types are Go packages that register themselves in init().
+// Custom types are described declaratively (see
+// schemas.ProjectTypeDefinition) and registered by Load when the CLI starts,
+// after which they behave like any other registered type.
+package custom
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/jmgilman/go/fs/core"
+	"github.com/jmgilman/sow/cli/internal/templates"
+	"github.com/jmgilman/sow/libs/project"
+	"github.com/jmgilman/sow/libs/project/state"
+	"github.com/jmgilman/sow/libs/schemas"
+)
+
+// Dir is the directory holding project type definitions, relative to .sow/.
+// Prompt template paths in definitions are relative to it as well.
+const Dir = "types"
+
+// Type is a project type loaded from a definition file.
+type Type struct {
+	// Definition is the parsed definition.
+	Definition *schemas.ProjectTypeDefinition
+	// Path is the definition file, relative to .sow/.
+	Path string
+	// Config is the project type configuration built from the definition.
+	Config *project.ProjectTypeConfig
+}
+
+// loaded tracks the custom types registered by Load, keyed by type name.
+var (
+	loaded   = make(map[string]*Type)
+	loadedMu sync.RWMutex
+)
+
+// Load registers the project types defined in .sow/types/*.cue.
+//
+// A file that fails to load does not prevent the others from loading; one
+// error is returned per failed file. Types whose name is already registered
+// (for example a built-in type) are rejected. Loading the same directory
+// again is a no-op for types it already registered.
+func Load(sowFS core.FS) []error {
+	entries, err := sowFS.ReadDir(Dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return []error{fmt.Errorf("read %s: %w", Dir, err)}
+	}
+
+	var errs []error
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".cue" {
+			continue
+		}
+		if err := register(sowFS, path.Join(Dir, entry.Name())); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// register loads one definition file and adds it to the registry.
+func register(sowFS core.FS, file string) error {
+	typ, err := LoadFile(sowFS, file)
+	if err != nil {
+		return err
+	}
+
+	loadedMu.Lock()
+	defer loadedMu.Unlock()
+
+	name := typ.Definition.Name
+	if existing, ok := loaded[name]; ok && existing.Path == file {
+		return nil
+	}
+	if _, exists := state.GetConfig(name); exists {
+		return fmt.Errorf("%s: project type %q is already registered", file, name)
+	}
+
+	state.Register(name, typ.Config)
+	loaded[name] = typ
+	return nil
+}
+
+// LoadFile parses a definition file and builds its project type
+// configuration without registering it. The file path is relative to .sow/.
+func LoadFile(sowFS core.FS, file string) (*Type, error) {
+	src, err := sowFS.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", file, err)
+	}
+
+	def, err := project.ParseTypeDefinition(file, src)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // already names the file
+	}
+	if want := strings.TrimSuffix(path.Base(file), ".cue"); def.Name != want {
+		return nil, fmt.Errorf("%s: type name %q must match the file name %q", file, def.Name, want)
+	}
+
+	builder, err := project.NewDefinitionBuilder(def)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	if err := configurePrompts(sowFS, builder, def); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	return &Type{
+		Definition: def,
+		Path:       file,
+		Config:     builder.Build(),
+	}, nil
+}
+
+// Get returns a custom type registered by Load.
+func Get(name string) (*Type, bool) {
+	loadedMu.RLock()
+	defer loadedMu.RUnlock()
+	typ, ok := loaded[name]
+	return typ, ok
+}
+
+// Types returns the custom types registered by Load, sorted by name.
+func Types() []*Type {
+	loadedMu.RLock()
+	defer loadedMu.RUnlock()
+
+	types := make([]*Type, 0, len(loaded))
+	for _, typ := range loaded {
+		types = append(types, typ)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].Definition.Name < types[j].Definition.Name
+	})
+	return types
+}
+
+// configurePrompts reads and parses the prompt templates named by the
+// definition and adds prompt generators for them to the builder.
+func configurePrompts(sowFS core.FS, builder *project.ProjectTypeConfigBuilder, def *schemas.ProjectTypeDefinition) error {
+	if def.Orchestrator_prompt != "" {
+		gen, err := promptGenerator(sowFS, def.Orchestrator_prompt)
+		if err != nil {
+			return err
+		}
+		builder.WithOrchestratorPrompt(gen)
+	}
+
+	for name, stateDef := range def.States {
+		if stateDef.Prompt == "" {
+			continue
+		}
+		gen, err := promptGenerator(sowFS, stateDef.Prompt)
+		if err != nil {
+			return err
+		}
+		builder.WithPrompt(project.State(name), gen)
+	}
+	return nil
+}
+
+// promptGenerator returns a generator rendering the template at rel, a
+// path relative to .sow/types/. The template is parsed up front so that
+// syntax errors are reported when the type is loaded.
+func promptGenerator(sowFS core.FS, rel string) (project.PromptGenerator, error) {
+	file := path.Join(Dir, rel)
+	content, err := sowFS.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read prompt template: %w", err)
+	}
+	if _, err := templates.Parse(file, string(content)); err != nil {
+		return nil, err //nolint:wrapcheck // already names the template
+	}
+
+	return func(p *state.Project) string {
+		prompt, err := templates.RenderText(file, string(content), p)
+		if err != nil {
+			return fmt.Sprintf("Error rendering prompt: %v", err)
+		}
+		return prompt
+	}, nil
+}
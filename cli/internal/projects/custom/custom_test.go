@@ -0,0 +1,151 @@
+package custom
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jmgilman/go/fs/billy"
+	"github.com/jmgilman/go/fs/core"
+	"github.com/jmgilman/sow/libs/project"
+	"github.com/jmgilman/sow/libs/project/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The state registry is global, so each test registers types under names
+// no other test uses.
+
+// testDefinition returns a one-phase definition for the named type.
+func testDefinition(name string) string {
+	return `
+name:                "` + name + `"
+description:         "Custom ` + name + `"
+branch_prefix:       "` + name + `/"
+initial_state:       "Working"
+orchestrator_prompt: "orchestrator.md"
+phases: work: {
+	start_state: "Working"
+	end_state:   "Working"
+	outputs: ["report"]
+}
+states: Working: prompt: "working.md"
+transitions: [{
+	from:  "Working"
+	to:    "Done"
+	event: "finish"
+	guards: [{predicate: "output_approved", phase: "work", type: "report"}]
+}]
+`
+}
+
+// newTypesFS returns an in-memory .sow filesystem holding the given files
+// under types/.
+func newTypesFS(t *testing.T, files map[string]string) core.FS {
+	t.Helper()
+
+	memFS := billy.NewMemory()
+	require.NoError(t, memFS.MkdirAll(Dir, 0755))
+	require.NoError(t, memFS.WriteFile(Dir+"/orchestrator.md", []byte("Orchestrating {{.Name}}"), 0644))
+	require.NoError(t, memFS.WriteFile(Dir+"/working.md", []byte("Working on {{.Name}}"), 0644))
+	for name, content := range files {
+		require.NoError(t, memFS.WriteFile(Dir+"/"+name, []byte(content), 0644))
+	}
+	return memFS
+}
+
+func TestLoad(t *testing.T) {
+	sowFS := newTypesFS(t, map[string]string{
+		"load-a.cue": testDefinition("load-a"),
+		"load-b.cue": testDefinition("load-b"),
+		"notes.txt":  "not a definition",
+	})
+
+	errs := Load(sowFS)
+	require.Empty(t, errs)
+
+	for _, name := range []string{"load-a", "load-b"} {
+		config, exists := state.GetConfig(name)
+		require.True(t, exists, "%s should be registered", name)
+		assert.Equal(t, "Working", config.InitialState())
+
+		typ, ok := Get(name)
+		require.True(t, ok)
+		assert.Equal(t, Dir+"/"+name+".cue", typ.Path)
+		assert.Equal(t, name+"/", typ.Definition.Branch_prefix)
+	}
+
+	var names []string
+	for _, typ := range Types() {
+		if strings.HasPrefix(typ.Definition.Name, "load-") {
+			names = append(names, typ.Definition.Name)
+		}
+	}
+	assert.Equal(t, []string{"load-a", "load-b"}, names)
+
+	// Loading again must not report the types as duplicates
+	assert.Empty(t, Load(sowFS))
+}
+
+func TestLoad_MissingDirectory(t *testing.T) {
+	assert.Empty(t, Load(billy.NewMemory()))
+}
+
+func TestLoad_ReportsFailuresPerFile(t *testing.T) {
+	state.Register("dup-builtin", project.NewProjectTypeConfigBuilder("dup-builtin").Build())
+
+	sowFS := newTypesFS(t, map[string]string{
+		"dup-builtin.cue": testDefinition("dup-builtin"),
+		"mismatch.cue":    testDefinition("other-name"),
+		"partial-ok.cue":  testDefinition("partial-ok"),
+	})
+
+	errs := Load(sowFS)
+
+	require.Len(t, errs, 2)
+	assert.ErrorContains(t, errs[0], `project type "dup-builtin" is already registered`)
+	assert.ErrorContains(t, errs[1], `type name "other-name" must match the file name "mismatch"`)
+
+	_, exists := state.GetConfig("partial-ok")
+	assert.True(t, exists, "valid files should load despite failures")
+}
+
+func TestLoadFile(t *testing.T) {
+	t.Run("renders prompt templates", func(t *testing.T) {
+		sowFS := newTypesFS(t, map[string]string{"prompts.cue": testDefinition("prompts")})
+
+		typ, err := LoadFile(sowFS, Dir+"/prompts.cue")
+		require.NoError(t, err)
+
+		proj := &state.Project{}
+		proj.Name = "demo"
+		assert.Equal(t, "Working on demo", typ.Config.GetStatePrompt("Working", proj))
+		assert.Equal(t, "Orchestrating demo", typ.Config.OrchestratorPrompt(proj))
+
+		_, registered := state.GetConfig("prompts")
+		assert.False(t, registered, "LoadFile must not register the type")
+	})
+
+	t.Run("rejects missing prompt templates", func(t *testing.T) {
+		sowFS := newTypesFS(t, map[string]string{"missing.cue": testDefinition("missing")})
+		require.NoError(t, sowFS.Remove(Dir+"/working.md"))
+
+		_, err := LoadFile(sowFS, Dir+"/missing.cue")
+		assert.ErrorContains(t, err, "read prompt template")
+	})
+
+	t.Run("rejects invalid prompt templates", func(t *testing.T) {
+		sowFS := newTypesFS(t, map[string]string{"broken.cue": testDefinition("broken")})
+		require.NoError(t, sowFS.WriteFile(Dir+"/working.md", []byte("{{.Name"), 0644))
+
+		_, err := LoadFile(sowFS, Dir+"/broken.cue")
+		assert.ErrorContains(t, err, "working.md")
+	})
+
+	t.Run("rejects inconsistent definitions", func(t *testing.T) {
+		def := strings.Replace(testDefinition("inconsistent"), `phase: "work"`, `phase: "nope"`, 1)
+		sowFS := newTypesFS(t, map[string]string{"inconsistent.cue": def})
+
+		_, err := LoadFile(sowFS, Dir+"/inconsistent.cue")
+		assert.ErrorContains(t, err, `guard phase "nope" is not a phase`)
+	})
+}
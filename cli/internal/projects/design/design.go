@@ -118,9 +118,7 @@ func configureTransitions(builder *project.ProjectTypeConfigBuilder) *project.Pr
 			project.State(Active),
 			project.State(Finalizing),
 			project.Event(EventCompleteDesign),
-			project.WithProjectGuard("all documents approved", func(p *state.Project) bool {
-				return allDocumentsApproved(p)
-			}),
+			project.WithProjectGuardResult("all documents approved", checkAllDocumentsApproved),
 			project.WithProjectOnEntry(func(p *state.Project) error {
 				// Enable finalization phase
 				// Note: Phase status and timestamps are automatically managed by FireWithPhaseUpdates
@@ -136,9 +134,7 @@ func configureTransitions(builder *project.ProjectTypeConfigBuilder) *project.Pr
 			project.State(Finalizing),
 			project.State(Completed),
 			project.Event(EventCompleteFinalization),
-			project.WithProjectGuard("all finalization tasks complete", func(p *state.Project) bool {
-				return allFinalizationTasksComplete(p)
-			}),
+			project.WithProjectGuardResult("all finalization tasks complete", checkAllFinalizationTasksComplete),
 			// Note: Finalization phase completion is automatically managed by FireWithPhaseUpdates
 		)
 }
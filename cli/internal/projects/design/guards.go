@@ -3,13 +3,14 @@ package design
 import (
 	"fmt"
 
+	"github.com/jmgilman/sow/libs/project"
 	"github.com/jmgilman/sow/libs/project/state"
 	projschema "github.com/jmgilman/sow/libs/schemas/project"
 )
 
 // Guard functions for design project state transitions.
-// Guards are pure functions that examine project state and return boolean values
-// indicating whether a transition is allowed.
+// Transitions in design.go register the check* forms, which list what is
+// still missing; prompts.go reports progress with the boolean forms.
 
 // allDocumentsApproved checks if all design tasks are completed or abandoned,
 // with at least one completed.
@@ -23,13 +24,21 @@ import (
 // Returns true if all tasks are completed/abandoned AND at least one is completed.
 // This ensures the design has meaningful output before advancing.
 func allDocumentsApproved(p *state.Project) bool {
+	return checkAllDocumentsApproved(p).Passed()
+}
+
+// checkAllDocumentsApproved lists the design tasks that are unresolved.
+func checkAllDocumentsApproved(p *state.Project) project.GuardResult {
+	var result project.GuardResult
 	phase, exists := p.Phases["design"]
 	if !exists {
-		return false
+		result.Addf("design phase not found")
+		return result
 	}
 
 	if len(phase.Tasks) == 0 {
-		return false
+		result.Addf("no design documents planned")
+		return result
 	}
 
 	hasCompleted := false
@@ -37,13 +46,15 @@ func allDocumentsApproved(p *state.Project) bool {
 		if task.Status == "completed" {
 			hasCompleted = true
 		} else if task.Status != "abandoned" {
-			// Task is not completed or abandoned, so not all are resolved
-			return false
+			result.Addf("task %s not resolved (%s)", task.Id, task.Status)
 		}
 	}
 
 	// Must have at least one completed task
-	return hasCompleted
+	if result.Passed() && !hasCompleted {
+		result.Addf("no design document completed (all tasks abandoned)")
+	}
+	return result
 }
 
 // allFinalizationTasksComplete checks if all finalization tasks are completed.
@@ -56,21 +67,30 @@ func allDocumentsApproved(p *state.Project) bool {
 // Returns true if all tasks are completed.
 // Note: Unlike design tasks, finalization tasks must be "completed" - "abandoned" is not accepted.
 func allFinalizationTasksComplete(p *state.Project) bool {
+	return checkAllFinalizationTasksComplete(p).Passed()
+}
+
+// checkAllFinalizationTasksComplete lists the finalization tasks that are
+// not completed.
+func checkAllFinalizationTasksComplete(p *state.Project) project.GuardResult {
+	var result project.GuardResult
 	phase, exists := p.Phases["finalization"]
 	if !exists {
-		return false
+		result.Addf("finalization phase not found")
+		return result
 	}
 
 	if len(phase.Tasks) == 0 {
-		return false
+		result.Addf("no finalization tasks")
+		return result
 	}
 
 	for _, task := range phase.Tasks {
 		if task.Status != "completed" {
-			return false
+			result.Addf("task %s not completed (%s)", task.Id, task.Status)
 		}
 	}
-	return true
+	return result
 }
 
 // Helper functions for task lifecycle management.
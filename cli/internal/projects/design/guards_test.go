@@ -1,9 +1,11 @@
 package design
 
 import (
+	"slices"
 	"testing"
 	"time"
 
+	"github.com/jmgilman/sow/libs/project"
 	"github.com/jmgilman/sow/libs/project/state"
 	projschema "github.com/jmgilman/sow/libs/schemas/project"
 )
@@ -752,3 +754,50 @@ func TestAutoApproveArtifact_UpdatesProjectState(t *testing.T) {
 		t.Errorf("Expected exactly 1 approved artifact, got %d", approvedCount)
 	}
 }
+
+// Tests for guard results
+
+func TestCheckAllDocumentsApproved_ListsUnresolvedTasks(t *testing.T) {
+	p := newTestProject()
+	p.Phases["design"] = projschema.PhaseState{
+		Tasks: []projschema.TaskState{
+			newTask("010", "completed"),
+			newTask("020", "pending"),
+			newTask("030", "in_progress"),
+		},
+	}
+
+	assertUnmet(t, checkAllDocumentsApproved(p),
+		"task 020 not resolved (pending)",
+		"task 030 not resolved (in_progress)",
+	)
+}
+
+func TestCheckAllDocumentsApproved_AllAbandoned(t *testing.T) {
+	p := newTestProject()
+	p.Phases["design"] = projschema.PhaseState{
+		Tasks: []projschema.TaskState{newTask("010", "abandoned")},
+	}
+
+	assertUnmet(t, checkAllDocumentsApproved(p), "no design document completed (all tasks abandoned)")
+}
+
+func TestCheckAllFinalizationTasksComplete_ListsIncompleteTasks(t *testing.T) {
+	p := newTestProject()
+	p.Phases["finalization"] = projschema.PhaseState{
+		Tasks: []projschema.TaskState{
+			newTask("010", "completed"),
+			newTask("020", "abandoned"),
+		},
+	}
+
+	assertUnmet(t, checkAllFinalizationTasksComplete(p), "task 020 not completed (abandoned)")
+}
+
+// assertUnmet checks that a guard result lists exactly the given unmet conditions.
+func assertUnmet(t *testing.T, result project.GuardResult, want ...string) {
+	t.Helper()
+	if !slices.Equal(result.Unmet, want) {
+		t.Errorf("Unmet = %q, want %q", result.Unmet, want)
+	}
+}
@@ -111,9 +111,7 @@ func configureTransitions(builder *project.ProjectTypeConfigBuilder) *project.Pr
 			project.State(Active),
 			project.State(Summarizing),
 			project.Event(EventBeginSummarizing),
-			project.WithProjectGuard("all tasks resolved", func(p *state.Project) bool {
-				return allTasksResolved(p)
-			}),
+			project.WithProjectGuardResult("all tasks resolved", checkAllTasksResolved),
 			project.WithProjectOnEntry(func(p *state.Project) error {
 				// Update exploration phase status to "summarizing"
 				phase := p.Phases["exploration"]
@@ -128,9 +126,7 @@ func configureTransitions(builder *project.ProjectTypeConfigBuilder) *project.Pr
 			project.State(Summarizing),
 			project.State(Finalizing),
 			project.Event(EventCompleteSummarizing),
-			project.WithProjectGuard("all summaries approved", func(p *state.Project) bool {
-				return allSummariesApproved(p)
-			}),
+			project.WithProjectGuardResult("all summaries approved", checkAllSummariesApproved),
 			project.WithProjectOnEntry(func(p *state.Project) error {
 				// Enable finalization phase
 				// Note: Phase status and timestamps are automatically managed by FireWithPhaseUpdates
@@ -146,9 +142,7 @@ func configureTransitions(builder *project.ProjectTypeConfigBuilder) *project.Pr
 			project.State(Finalizing),
 			project.State(Completed),
 			project.Event(EventCompleteFinalization),
-			project.WithProjectGuard("all finalization tasks complete", func(p *state.Project) bool {
-				return allFinalizationTasksComplete(p)
-			}),
+			project.WithProjectGuardResult("all finalization tasks complete", checkAllFinalizationTasksComplete),
 			// Note: Finalization phase completion is automatically managed by FireWithPhaseUpdates
 		)
 }
@@ -1,13 +1,14 @@
 package exploration
 
 import (
+	"github.com/jmgilman/sow/libs/project"
 	"github.com/jmgilman/sow/libs/project/state"
 	projschema "github.com/jmgilman/sow/libs/schemas/project"
 )
 
 // Guard functions for exploration project state transitions.
-// Guards are pure functions that examine project state and return boolean values
-// indicating whether a transition is allowed.
+// A blocked advance lists each unresolved topic or unapproved summary
+// through the check* functions registered in exploration.go.
 
 // allTasksResolved checks if all research topics in exploration phase are completed or abandoned.
 // Guards Active → Summarizing transition.
@@ -18,21 +19,29 @@ import (
 //
 // Returns true if all tasks are completed or abandoned.
 func allTasksResolved(p *state.Project) bool {
+	return checkAllTasksResolved(p).Passed()
+}
+
+// checkAllTasksResolved lists the research topics that are unresolved.
+func checkAllTasksResolved(p *state.Project) project.GuardResult {
+	var result project.GuardResult
 	phase, exists := p.Phases["exploration"]
 	if !exists {
-		return false
+		result.Addf("exploration phase not found")
+		return result
 	}
 
 	if len(phase.Tasks) == 0 {
-		return false
+		result.Addf("no research topics")
+		return result
 	}
 
 	for _, task := range phase.Tasks {
 		if task.Status != "completed" && task.Status != "abandoned" {
-			return false
+			result.Addf("task %s not resolved (%s)", task.Id, task.Status)
 		}
 	}
-	return true
+	return result
 }
 
 // allSummariesApproved checks if at least one summary artifact exists and all summaries are approved.
@@ -45,9 +54,17 @@ func allTasksResolved(p *state.Project) bool {
 //
 // Returns true if at least one summary exists and all are approved.
 func allSummariesApproved(p *state.Project) bool {
+	return checkAllSummariesApproved(p).Passed()
+}
+
+// checkAllSummariesApproved lists the summary artifacts that are not
+// approved.
+func checkAllSummariesApproved(p *state.Project) project.GuardResult {
+	var result project.GuardResult
 	phase, exists := p.Phases["exploration"]
 	if !exists {
-		return false
+		result.Addf("exploration phase not found")
+		return result
 	}
 
 	// Collect all summary artifacts
@@ -60,17 +77,17 @@ func allSummariesApproved(p *state.Project) bool {
 
 	// Must have at least one summary
 	if len(summaries) == 0 {
-		return false
+		result.Addf("no summary output")
+		return result
 	}
 
 	// All summaries must be approved
 	for _, summary := range summaries {
 		if !summary.Approved {
-			return false
+			result.Addf("summary %s not approved", summary.Path)
 		}
 	}
-
-	return true
+	return result
 }
 
 // allFinalizationTasksComplete checks if all finalization tasks are completed.
@@ -83,21 +100,30 @@ func allSummariesApproved(p *state.Project) bool {
 // Returns true if all tasks are completed.
 // Note: Unlike exploration tasks, finalization tasks must be "completed" - "abandoned" is not accepted.
 func allFinalizationTasksComplete(p *state.Project) bool {
+	return checkAllFinalizationTasksComplete(p).Passed()
+}
+
+// checkAllFinalizationTasksComplete lists the finalization tasks that are
+// not completed.
+func checkAllFinalizationTasksComplete(p *state.Project) project.GuardResult {
+	var result project.GuardResult
 	phase, exists := p.Phases["finalization"]
 	if !exists {
-		return false
+		result.Addf("finalization phase not found")
+		return result
 	}
 
 	if len(phase.Tasks) == 0 {
-		return false
+		result.Addf("no finalization tasks")
+		return result
 	}
 
 	for _, task := range phase.Tasks {
 		if task.Status != "completed" {
-			return false
+			result.Addf("task %s not completed (%s)", task.Id, task.Status)
 		}
 	}
-	return true
+	return result
 }
 
 // countUnresolvedTasks returns count of pending/in_progress tasks in exploration phase.
@@ -1,9 +1,11 @@
 package exploration
 
 import (
+	"slices"
 	"testing"
 	"time"
 
+	"github.com/jmgilman/sow/libs/project"
 	"github.com/jmgilman/sow/libs/project/state"
 	projschema "github.com/jmgilman/sow/libs/schemas/project"
 )
@@ -599,3 +601,39 @@ func TestCountUnapprovedSummaries_SomeUnapproved(t *testing.T) {
 		t.Errorf("Expected 2 unapproved summaries, got %d", count)
 	}
 }
+
+// Tests for guard results
+
+func TestCheckAllTasksResolved_ListsUnresolvedTasks(t *testing.T) {
+	p := newTestProject()
+	p.Phases["exploration"] = projschema.PhaseState{
+		Tasks: []projschema.TaskState{
+			newTask("010", "completed"),
+			newTask("020", "in_progress"),
+		},
+	}
+
+	assertUnmet(t, checkAllTasksResolved(p), "task 020 not resolved (in_progress)")
+}
+
+func TestCheckAllSummariesApproved(t *testing.T) {
+	p := newTestProject()
+	p.Phases["exploration"] = projschema.PhaseState{}
+	assertUnmet(t, checkAllSummariesApproved(p), "no summary output")
+
+	p.Phases["exploration"] = projschema.PhaseState{
+		Outputs: []projschema.ArtifactState{
+			newArtifact("summary", false),
+			newArtifact("notes", false),
+		},
+	}
+	assertUnmet(t, checkAllSummariesApproved(p), "summary test/summary.md not approved")
+}
+
+// assertUnmet checks that a guard result lists exactly the given unmet conditions.
+func assertUnmet(t *testing.T, result project.GuardResult, want ...string) {
+	t.Helper()
+	if !slices.Equal(result.Unmet, want) {
+		t.Errorf("Unmet = %q, want %q", result.Unmet, want)
+	}
+}
@@ -1,133 +1,13 @@
 package standard
 
 import (
+	"github.com/jmgilman/sow/libs/project"
 	"github.com/jmgilman/sow/libs/project/state"
 )
 
-// Guard helper functions check transition conditions.
-// These are called by guard closures defined in standard.go.
-
-// phaseOutputApproved checks if a specific output artifact type is approved.
-// Returns false if phase not found, artifact not found, or artifact not approved.
-func phaseOutputApproved(p *state.Project, phaseName, outputType string) bool {
-	phase, exists := p.Phases[phaseName]
-	if !exists {
-		return false
-	}
-
-	for _, output := range phase.Outputs {
-		if output.Type == outputType && output.Approved {
-			return true
-		}
-	}
-	return false
-}
-
-// allTaskDescriptionsApproved checks if planning has been approved via metadata.
-// Returns false if implementation phase missing or planning_approved not set to true.
-// This replaces the old approach of checking individual task_description artifacts.
-//
-// The planning_approved flag is set by the orchestrator after user reviews
-// task descriptions in .sow/project/context/tasks/ and says "approved".
-// Command: sow phase set metadata.planning_approved true --phase implementation.
-func allTaskDescriptionsApproved(p *state.Project) bool {
-	phase, exists := p.Phases["implementation"]
-	if !exists {
-		return false
-	}
-
-	// Check planning_approved metadata flag
-	if phase.Metadata == nil {
-		return false
-	}
-
-	approved, ok := phase.Metadata["planning_approved"].(bool)
-	return ok && approved
-}
-
-// phaseMetadataBool gets a boolean value from phase metadata.
-// Returns false if key missing, wrong type, or phase missing.
-func phaseMetadataBool(p *state.Project, phaseName, key string) bool {
-	phase, exists := p.Phases[phaseName]
-	if !exists {
-		return false
-	}
-
-	if phase.Metadata == nil {
-		return false
-	}
-
-	val, ok := phase.Metadata[key]
-	if !ok {
-		return false
-	}
-
-	boolVal, ok := val.(bool)
-	if !ok {
-		return false
-	}
-
-	return boolVal
-}
-
-// allTasksComplete checks if all implementation tasks are completed or abandoned.
-// Returns false if implementation phase missing or if no tasks exist.
-func allTasksComplete(p *state.Project) bool {
-	phase, exists := p.Phases["implementation"]
-	if !exists {
-		return false
-	}
-
-	if len(phase.Tasks) == 0 {
-		return false
-	}
-
-	for _, task := range phase.Tasks {
-		if task.Status != "completed" && task.Status != "abandoned" {
-			return false
-		}
-	}
-	return true
-}
-
-// latestReviewApproved checks if the most recent review artifact is approved.
-// Returns false if review phase missing or if no review artifacts found.
-func latestReviewApproved(p *state.Project) bool {
-	phase, exists := p.Phases["review"]
-	if !exists {
-		return false
-	}
-
-	// Find latest review output by iterating backwards
-	for i := len(phase.Outputs) - 1; i >= 0; i-- {
-		if phase.Outputs[i].Type == "review" {
-			return phase.Outputs[i].Approved
-		}
-	}
-
-	return false
-}
-
-// prBodyApproved checks if the pr_body output artifact is approved in finalize phase.
-func prBodyApproved(p *state.Project) bool {
-	return phaseOutputApproved(p, "finalize", "pr_body")
-}
-
-// prChecksPassed checks if the pr_checks_passed flag is set in finalize metadata.
-func prChecksPassed(p *state.Project) bool {
-	return phaseMetadataBool(p, "finalize", "pr_checks_passed")
-}
-
-// projectDeleted checks if the project_deleted flag is set in finalize metadata.
-func projectDeleted(p *state.Project) bool {
-	return phaseMetadataBool(p, "finalize", "project_deleted")
-}
-
-// draftPRCreated checks if a draft PR has been created and metadata stored.
-// Returns false if implementation phase missing or draft_pr_created not set to true.
-func draftPRCreated(p *state.Project) bool {
-	return phaseMetadataBool(p, "implementation", "draft_pr_created")
-}
+// Most standard transitions use the SDK's shared guards (see standard.go).
+// The review assessment is specific to this project type: it picks the
+// branch out of ReviewActive.
 
 // getReviewAssessment extracts the assessment ("pass" or "fail") from the latest approved review.
 // This is used as a discriminator function for the ReviewActive branching state.
@@ -137,20 +17,49 @@ func draftPRCreated(p *state.Project) bool {
 //   - "fail" if latest approved review has assessment="fail"
 //   - "" (empty string) if no approved review found or assessment missing
 func getReviewAssessment(p *state.Project) string {
+	assessment, _ := reviewAssessment(p)
+	return assessment
+}
+
+// reviewAssessment returns the assessment of the latest approved review
+// that has one, and whether the review phase has any approved review.
+func reviewAssessment(p *state.Project) (string, bool) {
 	phase, exists := p.Phases["review"]
 	if !exists {
-		return ""
+		return "", false
 	}
 
 	// Find latest approved review by iterating backwards
+	approved := false
 	for i := len(phase.Outputs) - 1; i >= 0; i-- {
 		artifact := phase.Outputs[i]
-		if artifact.Type == "review" && artifact.Approved {
-			if assessment, ok := artifact.Metadata["assessment"].(string); ok {
-				return assessment // "pass" or "fail"
-			}
+		if artifact.Type != "review" || !artifact.Approved {
+			continue
+		}
+		approved = true
+		if assessment, ok := artifact.Metadata["assessment"].(string); ok {
+			return assessment, true
 		}
 	}
+	return "", approved
+}
 
-	return "" // No approved review or assessment missing
+// checkReviewAssessment returns the guard of the ReviewActive branch taken
+// for the given assessment. It reports a missing approved review or
+// assessment as unmet conditions, so a review that cannot be branched on
+// is explained like any other blocked transition.
+func checkReviewAssessment(want string) func(*state.Project) project.GuardResult {
+	return func(p *state.Project) project.GuardResult {
+		var result project.GuardResult
+		assessment, approved := reviewAssessment(p)
+		switch {
+		case !approved:
+			result.Addf("no approved review output in review phase")
+		case assessment == "":
+			result.Addf("approved review has no assessment (set metadata.assessment to pass or fail)")
+		case assessment != want:
+			result.Addf("review assessment is %q, want %s", assessment, want)
+		}
+		return result
+	}
 }
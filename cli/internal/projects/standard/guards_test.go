@@ -1,9 +1,11 @@
 package standard
 
 import (
+	"slices"
 	"testing"
 	"time"
 
+	sdkproject "github.com/jmgilman/sow/libs/project"
 	"github.com/jmgilman/sow/libs/project/state"
 	"github.com/jmgilman/sow/libs/schemas/project"
 )
@@ -13,8 +15,9 @@ func TestPhaseOutputApproved(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := phaseOutputApproved(tt.project, tt.phaseName, tt.outputType); got != tt.want {
-				t.Errorf("phaseOutputApproved() = %v, want %v", got, tt.want)
+			guard := sdkproject.OutputApprovedGuard(tt.phaseName, tt.outputType)
+			if got := guard.Evaluate(tt.project).Passed(); got != tt.want {
+				t.Errorf("OutputApprovedGuard() passed = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -124,8 +127,9 @@ func TestPhaseMetadataBool(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := phaseMetadataBool(tt.project, tt.phaseName, tt.key); got != tt.want {
-				t.Errorf("phaseMetadataBool() = %v, want %v", got, tt.want)
+			guard := sdkproject.MetadataBoolGuard(tt.phaseName, tt.key, true)
+			if got := guard.Evaluate(tt.project).Passed(); got != tt.want {
+				t.Errorf("MetadataBoolGuard() passed = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -265,8 +269,9 @@ func TestAllTasksComplete(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := allTasksComplete(tt.project); got != tt.want {
-				t.Errorf("allTasksComplete() = %v, want %v", got, tt.want)
+			guard := sdkproject.AllTasksCompleteGuard("implementation")
+			if got := guard.Evaluate(tt.project).Passed(); got != tt.want {
+				t.Errorf("AllTasksCompleteGuard() passed = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -420,179 +425,6 @@ func getAllTasksCompleteTests() []struct {
 	}
 }
 
-func TestLatestReviewApproved(t *testing.T) {
-	tests := getLatestReviewApprovedTests()
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if got := latestReviewApproved(tt.project); got != tt.want {
-				t.Errorf("latestReviewApproved() = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
-//nolint:funlen // Test data generation function
-func getLatestReviewApprovedTests() []struct {
-	name    string
-	project *state.Project
-	want    bool
-} {
-	now := time.Now()
-	return []struct {
-		name    string
-		project *state.Project
-		want    bool
-	}{
-		{
-			name: "returns true when latest review approved",
-			project: &state.Project{
-				ProjectState: project.ProjectState{
-					Phases: map[string]project.PhaseState{
-						"review": {
-							Outputs: []project.ArtifactState{
-								{
-									Type:       "review",
-									Path:       "review1.md",
-									Approved:   true,
-									Created_at: now,
-								},
-							},
-						},
-					},
-				},
-			},
-			want: true,
-		},
-		{
-			name: "returns false when latest review not approved",
-			project: &state.Project{
-				ProjectState: project.ProjectState{
-					Phases: map[string]project.PhaseState{
-						"review": {
-							Outputs: []project.ArtifactState{
-								{
-									Type:       "review",
-									Path:       "review1.md",
-									Approved:   false,
-									Created_at: now,
-								},
-							},
-						},
-					},
-				},
-			},
-			want: false,
-		},
-		{
-			name: "returns true when multiple reviews and latest approved",
-			project: &state.Project{
-				ProjectState: project.ProjectState{
-					Phases: map[string]project.PhaseState{
-						"review": {
-							Outputs: []project.ArtifactState{
-								{
-									Type:       "review",
-									Path:       "review1.md",
-									Approved:   false,
-									Created_at: now.Add(-2 * time.Hour),
-								},
-								{
-									Type:       "review",
-									Path:       "review2.md",
-									Approved:   true,
-									Created_at: now.Add(-1 * time.Hour),
-								},
-							},
-						},
-					},
-				},
-			},
-			want: true,
-		},
-		{
-			name: "returns false when multiple reviews and latest not approved",
-			project: &state.Project{
-				ProjectState: project.ProjectState{
-					Phases: map[string]project.PhaseState{
-						"review": {
-							Outputs: []project.ArtifactState{
-								{
-									Type:       "review",
-									Path:       "review1.md",
-									Approved:   true,
-									Created_at: now.Add(-2 * time.Hour),
-								},
-								{
-									Type:       "review",
-									Path:       "review2.md",
-									Approved:   false,
-									Created_at: now.Add(-1 * time.Hour),
-								},
-							},
-						},
-					},
-				},
-			},
-			want: false,
-		},
-		{
-			name: "returns false when no reviews",
-			project: &state.Project{
-				ProjectState: project.ProjectState{
-					Phases: map[string]project.PhaseState{
-						"review": {
-							Outputs: []project.ArtifactState{},
-						},
-					},
-				},
-			},
-			want: false,
-		},
-		{
-			name: "returns false when review phase missing",
-			project: &state.Project{
-				ProjectState: project.ProjectState{
-					Phases: map[string]project.PhaseState{},
-				},
-			},
-			want: false,
-		},
-		{
-			name: "returns true when mix of artifact types and latest review approved",
-			project: &state.Project{
-				ProjectState: project.ProjectState{
-					Phases: map[string]project.PhaseState{
-						"review": {
-							Outputs: []project.ArtifactState{
-								{
-									Type:       "review",
-									Path:       "review1.md",
-									Approved:   false,
-									Created_at: now.Add(-3 * time.Hour),
-								},
-								{
-									Type:       "other",
-									Path:       "other.md",
-									Approved:   true,
-									Created_at: now.Add(-2 * time.Hour),
-								},
-								{
-									Type:       "review",
-									Path:       "review2.md",
-									Approved:   true,
-									Created_at: now.Add(-1 * time.Hour),
-								},
-							},
-						},
-					},
-				},
-			},
-			want: true,
-		},
-	}
-}
-
 func TestProjectDeleted(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -670,8 +502,79 @@ func TestProjectDeleted(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := projectDeleted(tt.project); got != tt.want {
-				t.Errorf("projectDeleted() = %v, want %v", got, tt.want)
+			guard := sdkproject.MetadataBoolGuard("finalize", "project_deleted", true)
+			if got := guard.Evaluate(tt.project).Passed(); got != tt.want {
+				t.Errorf("MetadataBoolGuard() passed = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGuards_ListUnmetConditions(t *testing.T) {
+	config := NewStandardProjectConfig()
+	proj := &state.Project{
+		ProjectState: project.ProjectState{
+			Phases: map[string]project.PhaseState{
+				"implementation": {
+					Metadata: map[string]interface{}{"draft_pr_created": false},
+					Tasks: []project.TaskState{
+						{Id: "010", Status: "completed"},
+						{Id: "020", Status: "in_progress"},
+						{Id: "030", Status: "pending"},
+					},
+				},
+				"finalize": {
+					Outputs: []project.ArtifactState{{Type: "pr_body", Path: "pr_body.md"}},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		from  sdkproject.State
+		event sdkproject.Event
+		want  []string
+	}{
+		{"task descriptions", ImplementationPlanning, EventPlanningComplete, []string{"implementation metadata planning_approved not set"}},
+		{"draft PR", ImplementationDraftPRCreation, EventDraftPRCreated, []string{"implementation metadata draft_pr_created is false, want true"}},
+		{"tasks", ImplementationExecuting, EventAllTasksComplete, []string{"task 020 not complete (in_progress)", "task 030 not complete (pending)"}},
+		{"review", ReviewActive, EventReviewPass, []string{"no approved review output in review phase"}},
+		{"PR body", FinalizePRReady, EventPRReady, []string{"pr_body output not approved in finalize phase"}},
+		{"PR checks", FinalizePRChecks, EventPRChecksPass, []string{"finalize metadata pr_checks_passed not set"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := config.EvaluateGuard(tt.from, tt.event, proj).Unmet
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Unmet = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckReviewAssessment(t *testing.T) {
+	review := func(approved bool, metadata map[string]interface{}) project.ArtifactState {
+		return project.ArtifactState{Type: "review", Path: "review.md", Approved: approved, Metadata: metadata}
+	}
+	tests := []struct {
+		name    string
+		outputs []project.ArtifactState
+		want    []string
+	}{
+		{"no review", nil, []string{"no approved review output in review phase"}},
+		{"review not approved", []project.ArtifactState{review(false, map[string]interface{}{"assessment": "pass"})}, []string{"no approved review output in review phase"}},
+		{"no assessment", []project.ArtifactState{review(true, nil)}, []string{"approved review has no assessment (set metadata.assessment to pass or fail)"}},
+		{"other assessment", []project.ArtifactState{review(true, map[string]interface{}{"assessment": "fail"})}, []string{`review assessment is "fail", want pass`}},
+		{"pass", []project.ArtifactState{review(true, map[string]interface{}{"assessment": "pass"})}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proj := &state.Project{ProjectState: project.ProjectState{
+				Phases: map[string]project.PhaseState{"review": {Outputs: tt.outputs}},
+			}}
+			if got := checkReviewAssessment("pass")(proj).Unmet; !slices.Equal(got, tt.want) {
+				t.Errorf("Unmet = %q, want %q", got, tt.want)
 			}
 		})
 	}
@@ -379,6 +379,24 @@ func TestPromptGeneration(t *testing.T) {
 	}
 }
 
+// TestStateAgents tests the agents bound to states without tasks to run.
+func TestStateAgents(t *testing.T) {
+	config := NewStandardProjectConfig()
+
+	agents := map[project.State]string{
+		ImplementationPlanning:        "planner",
+		ImplementationDraftPRCreation: "",
+		ImplementationExecuting:       "",
+		ReviewActive:                  "reviewer",
+		FinalizeChecks:                "",
+	}
+	for st, want := range agents {
+		if got := config.GetStateAgent(string(st)); got != want {
+			t.Errorf("GetStateAgent(%s) = %q, want %q", st, got, want)
+		}
+	}
+}
+
 // TestOnAdvanceEventDetermination tests event determiners work correctly.
 func TestOnAdvanceEventDetermination(t *testing.T) {
 	t.Run("ReviewActive determines pass event", func(t *testing.T) {
@@ -20,6 +20,7 @@ func NewStandardProjectConfig() *project.ProjectTypeConfig {
 	builder = configureTransitions(builder)
 	builder = configureEventDeterminers(builder)
 	builder = configurePrompts(builder)
+	builder = configureAgents(builder)
 	builder = builder.WithInitializer(initializeStandardProject)
 	return builder.Build()
 }
@@ -101,9 +102,10 @@ func configureTransitions(builder *project.ProjectTypeConfigBuilder) *project.Pr
 			project.State(ImplementationDraftPRCreation),
 			project.Event(EventPlanningComplete),
 			project.WithProjectDescription("Task descriptions approved, create draft PR"),
-			project.WithProjectGuard("task descriptions approved", func(p *state.Project) bool {
-				return allTaskDescriptionsApproved(p)
-			}),
+			// The orchestrator sets planning_approved once the user approves
+			// the task descriptions in .sow/project/context/tasks/
+			project.WithProjectGuardResult("task descriptions approved",
+				project.MetadataBoolGuard("implementation", "planning_approved", true).Evaluate),
 		).
 
 		// Draft PR creation → execution
@@ -112,9 +114,8 @@ func configureTransitions(builder *project.ProjectTypeConfigBuilder) *project.Pr
 			project.State(ImplementationExecuting),
 			project.Event(EventDraftPRCreated),
 			project.WithProjectDescription("Draft PR created, begin task execution"),
-			project.WithProjectGuard("draft PR created", func(p *state.Project) bool {
-				return draftPRCreated(p)
-			}),
+			project.WithProjectGuardResult("draft PR created",
+				project.MetadataBoolGuard("implementation", "draft_pr_created", true).Evaluate),
 		).
 
 		// Implementation → Review
@@ -123,9 +124,8 @@ func configureTransitions(builder *project.ProjectTypeConfigBuilder) *project.Pr
 			project.State(ReviewActive),
 			project.Event(EventAllTasksComplete),
 			project.WithProjectDescription("All implementation tasks completed, ready for review"),
-			project.WithProjectGuard("all tasks complete", func(p *state.Project) bool {
-				return allTasksComplete(p)
-			}),
+			project.WithProjectGuardResult("all tasks complete",
+				project.AllTasksCompleteGuard("implementation").Evaluate),
 		).
 
 		// Review → Finalize/Implementation (branching on review assessment)
@@ -137,11 +137,13 @@ func configureTransitions(builder *project.ProjectTypeConfigBuilder) *project.Pr
 				project.Event(EventReviewPass),
 				project.State(FinalizeChecks),
 				project.WithProjectDescription("Review approved, proceed to finalization checks"),
+				project.WithProjectGuardResult("review passed", checkReviewAssessment("pass")),
 			),
 			project.When("fail",
 				project.Event(EventReviewFail),
 				project.State(ImplementationPlanning),
 				project.WithProjectDescription("Review failed, return to implementation planning for rework"),
+				project.WithProjectGuardResult("review failed", checkReviewAssessment("fail")),
 				project.WithProjectFailedPhase("review"), // Mark review as failed instead of completed
 				project.WithProjectOnEntry(func(p *state.Project) error {
 					// Only execute rework logic if review phase exists and has failed
@@ -186,27 +188,24 @@ func configureTransitions(builder *project.ProjectTypeConfigBuilder) *project.Pr
 			project.State(FinalizePRChecks),
 			project.Event(EventPRReady),
 			project.WithProjectDescription("PR body approved, monitoring PR checks"),
-			project.WithProjectGuard("PR body approved", func(p *state.Project) bool {
-				return prBodyApproved(p)
-			}),
+			project.WithProjectGuardResult("PR body approved",
+				project.OutputApprovedGuard("finalize", "pr_body").Evaluate),
 		).
 		AddTransition(
 			project.State(FinalizePRChecks),
 			project.State(FinalizeCleanup),
 			project.Event(EventPRChecksPass),
 			project.WithProjectDescription("All PR checks passed, begin cleanup"),
-			project.WithProjectGuard("all PR checks passed", func(p *state.Project) bool {
-				return prChecksPassed(p)
-			}),
+			project.WithProjectGuardResult("all PR checks passed",
+				project.MetadataBoolGuard("finalize", "pr_checks_passed", true).Evaluate),
 		).
 		AddTransition(
 			project.State(FinalizeCleanup),
 			project.State(NoProject),
 			project.Event(EventCleanupComplete),
 			project.WithProjectDescription("Cleanup complete, project finalized"),
-			project.WithProjectGuard("project deleted", func(p *state.Project) bool {
-				return projectDeleted(p)
-			}),
+			project.WithProjectGuardResult("project deleted",
+				project.MetadataBoolGuard("finalize", "project_deleted", true).Evaluate),
 		)
 }
 
@@ -252,3 +251,11 @@ func configurePrompts(builder *project.ProjectTypeConfigBuilder) *project.Projec
 		WithPrompt(project.State(FinalizePRChecks), generateFinalizePRChecksPrompt).
 		WithPrompt(project.State(FinalizeCleanup), generateFinalizeCleanupPrompt)
 }
+
+// configureAgents binds the agents that do the work of states without
+// tasks to run, for 'sow run' to spawn.
+func configureAgents(builder *project.ProjectTypeConfigBuilder) *project.ProjectTypeConfigBuilder {
+	return builder.
+		WithAgent(project.State(ImplementationPlanning), "planner").
+		WithAgent(project.State(ReviewActive), "reviewer")
+}
@@ -0,0 +1,230 @@
+// Package runs records the agent processes sow starts, so that other sow
+// processes can list and stop them (sow agent ps, sow agent kill) even
+// after the sow process that started an agent has crashed.
+//
+// Each running execution is a JSON file named after its session in
+// .sow/project/run/. The record is removed when the agent exits; a record
+// whose process is gone means sow did not see the agent exit, and an
+// in-progress task left behind by it is orphaned (see FindOrphans).
+package runs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jmgilman/sow/cli/internal/agents"
+	"github.com/jmgilman/sow/libs/project/state"
+)
+
+// Run is a recorded agent execution.
+type Run struct {
+	PID       int       `json:"pid"`
+	PGID      int       `json:"pgid"` // Process group of the agent and its children
+	StartedAt time.Time `json:"started_at"`
+	Session   string    `json:"session"`
+	Phase     string    `json:"phase,omitempty"` // Empty for taskless sessions
+	Task      string    `json:"task,omitempty"`  // Empty for taskless sessions
+	Agent     string    `json:"agent"`
+	Owner     int       `json:"owner"` // PID of the sow process waiting for the agent
+
+	// OwnerStartedAt is when the owner started, to tell it apart from a
+	// later process given the same PID. Zero if it could not be read.
+	OwnerStartedAt time.Time `json:"owner_started_at,omitzero"`
+}
+
+// Dir returns the directory holding the run records of the project in
+// repoRoot.
+func Dir(repoRoot string) string {
+	return filepath.Join(repoRoot, ".sow", "project", "run")
+}
+
+// Alive reports whether the agent process is still running. A process
+// that took over the PID after the agent exited does not count; it is
+// recognized by its start time (see agents.ProcessRunning).
+func (r Run) Alive() bool {
+	return agents.ProcessRunning(r.PID, r.StartedAt)
+}
+
+// Detached reports whether the sow process that started the agent is gone
+// while the agent still runs.
+func (r Run) Detached() bool {
+	return r.Alive() && !agents.ProcessRunning(r.Owner, r.OwnerStartedAt)
+}
+
+// Write records run in dir, replacing any record of its session.
+func Write(dir string, run Run) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("create run directory: %w", err)
+	}
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode run: %w", err)
+	}
+
+	// Write then rename, so readers never see a partial record
+	tmp, err := os.CreateTemp(dir, ".run-*")
+	if err != nil {
+		return fmt.Errorf("write run: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write run: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write run: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), recordPath(dir, run.Session)); err != nil {
+		return fmt.Errorf("write run: %w", err)
+	}
+	return nil
+}
+
+// Remove deletes the record of run's session if it still records run's
+// process; a later run of the session may have replaced it.
+func Remove(dir string, run Run) error {
+	recorded, err := read(recordPath(dir, run.Session))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if recorded.PID != run.PID {
+		return nil
+	}
+	if err := os.Remove(recordPath(dir, run.Session)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("remove run: %w", err)
+	}
+	return nil
+}
+
+// List returns the runs recorded in dir, oldest first.
+func List(dir string) ([]Run, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("list runs: %w", err)
+	}
+
+	var runs []Run
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		run, err := read(filepath.Join(dir, entry.Name()))
+		if errors.Is(err, fs.ErrNotExist) {
+			// Removed since the directory was read
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	sort.Slice(runs, func(i, j int) bool { return runs[i].StartedAt.Before(runs[j].StartedAt) })
+	return runs, nil
+}
+
+// Find returns the run recorded in dir for a session, or nil if there is
+// none.
+func Find(dir, session string) (*Run, error) {
+	run, err := read(recordPath(dir, session))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// Terminate stops the run's process group: it asks the processes to exit,
+// and kills them if the agent is still running after grace. A zero grace
+// kills them right away. Nothing is signaled once the agent is no longer
+// alive, since its PID, and so the group ID, may have been reused.
+func (r Run) Terminate(grace time.Duration) error {
+	if !r.Alive() {
+		return nil
+	}
+	if err := agents.TerminateProcessGroup(r.PGID, grace <= 0); err != nil {
+		return fmt.Errorf("signal process group %d: %w", r.PGID, err)
+	}
+	deadline := time.Now().Add(grace)
+	for r.Alive() {
+		if time.Now().After(deadline) {
+			if err := agents.TerminateProcessGroup(r.PGID, true); err != nil {
+				return fmt.Errorf("kill process group %d: %w", r.PGID, err)
+			}
+			return nil
+		}
+		time.Sleep(pollInterval)
+	}
+	return nil
+}
+
+// pollInterval is how often Terminate checks whether the agent has exited.
+const pollInterval = 100 * time.Millisecond
+
+// Orphan is an in-progress task whose agent process is gone without sow
+// seeing it exit, e.g. because the sow process waiting for it crashed.
+type Orphan struct {
+	Phase string
+	Task  string
+	Run   Run
+}
+
+// FindOrphans returns the in-progress tasks of proj whose recorded run is
+// no longer alive, ordered by phase and task.
+func FindOrphans(proj *state.Project, runs []Run) []Orphan {
+	bySession := make(map[string]Run, len(runs))
+	for _, run := range runs {
+		bySession[run.Session] = run
+	}
+
+	var orphans []Orphan
+	for phaseName, phase := range proj.Phases {
+		for _, task := range phase.Tasks {
+			if task.Status != "in_progress" || task.Session_id == "" {
+				continue
+			}
+			if run, ok := bySession[task.Session_id]; ok && !run.Alive() {
+				orphans = append(orphans, Orphan{Phase: phaseName, Task: task.Id, Run: run})
+			}
+		}
+	}
+	sort.Slice(orphans, func(i, j int) bool {
+		if orphans[i].Phase != orphans[j].Phase {
+			return orphans[i].Phase < orphans[j].Phase
+		}
+		return orphans[i].Task < orphans[j].Task
+	})
+	return orphans
+}
+
+// recordPath returns the path of a session's record in dir.
+func recordPath(dir, session string) string {
+	return filepath.Join(dir, session+".json")
+}
+
+// read reads one run record.
+func read(path string) (Run, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Run{}, err
+	}
+	var run Run
+	if err := json.Unmarshal(data, &run); err != nil {
+		return Run{}, fmt.Errorf("read run %s: %w", filepath.Base(path), err)
+	}
+	return run, nil
+}
@@ -0,0 +1,137 @@
+package runs
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jmgilman/sow/cli/internal/agents"
+	"github.com/jmgilman/sow/libs/project/state"
+	projschema "github.com/jmgilman/sow/libs/schemas/project"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exitedPID returns the PID of a process that has exited.
+func exitedPID(t *testing.T) int {
+	t.Helper()
+	cmd := exec.Command("true")
+	require.NoError(t, cmd.Run())
+	return cmd.Process.Pid
+}
+
+func TestWriteListFind(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "run")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	later := Run{PID: 200, PGID: 200, StartedAt: now, Session: "s2", Agent: "planner", Owner: 2}
+	earlier := Run{PID: 100, PGID: 100, StartedAt: now.Add(-time.Minute), Session: "s1", Phase: "implementation", Task: "010", Agent: "implementer", Owner: 1}
+	require.NoError(t, Write(dir, later))
+	require.NoError(t, Write(dir, earlier))
+
+	listed, err := List(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []Run{earlier, later}, listed)
+
+	found, err := Find(dir, "s1")
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, earlier, *found)
+
+	found, err = Find(dir, "missing")
+	require.NoError(t, err)
+	assert.Nil(t, found)
+}
+
+func TestList_NoDirectory(t *testing.T) {
+	listed, err := List(filepath.Join(t.TempDir(), "run"))
+	require.NoError(t, err)
+	assert.Empty(t, listed)
+}
+
+func TestRemove(t *testing.T) {
+	dir := t.TempDir()
+	first := Run{PID: 100, Session: "s1"}
+	second := Run{PID: 101, Session: "s1"}
+	require.NoError(t, Write(dir, second))
+
+	// A record replaced by a later run of the session is kept
+	require.NoError(t, Remove(dir, first))
+	found, err := Find(dir, "s1")
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, 101, found.PID)
+
+	require.NoError(t, Remove(dir, second))
+	_, err = os.Stat(filepath.Join(dir, "s1.json"))
+	assert.True(t, os.IsNotExist(err))
+
+	// Removing again is not an error
+	require.NoError(t, Remove(dir, second))
+}
+
+func TestRun_Alive(t *testing.T) {
+	self := Run{PID: os.Getpid(), Owner: os.Getpid()}
+	assert.True(t, self.Alive())
+	assert.False(t, self.Detached())
+
+	orphaned := Run{PID: os.Getpid(), Owner: exitedPID(t)}
+	assert.True(t, orphaned.Detached())
+
+	exited := Run{PID: exitedPID(t), Owner: os.Getpid()}
+	assert.False(t, exited.Alive())
+	assert.False(t, exited.Detached())
+}
+
+func TestRun_AliveReusedPID(t *testing.T) {
+	started, ok := agents.ProcessStartTime(os.Getpid())
+	if !ok {
+		t.Skip("process start times are not available on this platform")
+	}
+
+	self := Run{PID: os.Getpid(), StartedAt: started, Owner: os.Getpid(), OwnerStartedAt: started}
+	assert.True(t, self.Alive())
+	assert.False(t, self.Detached())
+
+	// The PID now belongs to a process started after the recorded one
+	reused := Run{PID: os.Getpid(), StartedAt: started.Add(-time.Hour), Owner: os.Getpid()}
+	assert.False(t, reused.Alive())
+	assert.NoError(t, reused.Terminate(0))
+
+	// The owner's PID was reused while the agent runs
+	orphaned := Run{PID: os.Getpid(), StartedAt: started, Owner: os.Getpid(), OwnerStartedAt: started.Add(-time.Hour)}
+	assert.True(t, orphaned.Detached())
+}
+
+func TestFindOrphans(t *testing.T) {
+	dead := exitedPID(t)
+	task := func(id, status, session string) projschema.TaskState {
+		return projschema.TaskState{Id: id, Status: status, Session_id: session}
+	}
+	proj := &state.Project{ProjectState: projschema.ProjectState{
+		Phases: map[string]projschema.PhaseState{
+			"implementation": {Tasks: []projschema.TaskState{
+				task("020", "in_progress", "s-020"), // Record of a dead process
+				task("010", "in_progress", "s-010"), // Record of a dead process
+				task("030", "in_progress", "s-030"), // Still running
+				task("040", "in_progress", "s-040"), // No record (e.g. paused)
+				task("050", "completed", "s-050"),   // Not in progress
+			}},
+		},
+	}}
+	recorded := []Run{
+		{PID: dead, Session: "s-010"},
+		{PID: dead, Session: "s-020"},
+		{PID: os.Getpid(), Session: "s-030"},
+		{PID: dead, Session: "s-050"},
+	}
+
+	orphans := FindOrphans(proj, recorded)
+	require.Len(t, orphans, 2)
+	assert.Equal(t, "010", orphans[0].Task)
+	assert.Equal(t, "020", orphans[1].Task)
+	assert.Equal(t, "implementation", orphans[0].Phase)
+	assert.Equal(t, "s-010", orphans[0].Run.Session)
+}
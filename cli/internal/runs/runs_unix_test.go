@@ -0,0 +1,34 @@
+//go:build !windows
+
+package runs
+
+import (
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun_Terminate(t *testing.T) {
+	for name, grace := range map[string]time.Duration{"graceful": 5 * time.Second, "forced": 0} {
+		t.Run(name, func(t *testing.T) {
+			cmd := exec.Command("sleep", "60")
+			cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+			require.NoError(t, cmd.Start())
+			done := make(chan error, 1)
+			go func() { done <- cmd.Wait() }()
+
+			run := Run{PID: cmd.Process.Pid, PGID: cmd.Process.Pid}
+			// Terminate polls liveness, which a zombie would fool; reap it
+			// concurrently as the sow process waiting for it would
+			require.NoError(t, run.Terminate(grace))
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("process still running after Terminate")
+			}
+		})
+	}
+}
@@ -10,6 +10,11 @@ import (
 	"github.com/jmgilman/sow/libs/git"
 )
 
+// RepoRootEnv names the environment variable that, when set, overrides the
+// repository root sow commands operate on. Agents running in a task's own
+// worktree use it so their sow commands update the shared project state.
+const RepoRootEnv = "SOW_REPO_ROOT"
+
 // Context provides unified access to sow subsystems: filesystem, git, and GitHub.
 // It is created once per CLI command invocation and passed to all subsystems.
 type Context struct {
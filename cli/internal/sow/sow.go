@@ -22,6 +22,8 @@ import (
 const (
 	// StructureVersion is the current .sow structure version.
 	// This version is written to .sow/.version during initialization.
+	// Bump it together with a new migration in internal/migrate whenever
+	// an on-disk format changes.
 	StructureVersion = "1.0.0"
 )
 
@@ -115,6 +117,40 @@ explorations: []
 # - ADRs: .sow/knowledge/adrs/
 # - Design docs: .sow/knowledge/design/
 # - Exploration summaries: .sow/knowledge/explorations/
+
+# Project state storage (default: yaml at .sow/project/state.yaml):
+# state:
+#   backend: sqlite          # yaml | sqlite | http
+#   path: project/state.db   # relative to .sow/ (yaml and sqlite)
+#   url: http://localhost:7070  # state server for http (see 'sow serve')
+#   lock_timeout: 30s        # wait for other sow processes (default: 10s)
+
+# Shell hooks run by 'sow advance' from the repository root. A non-zero exit
+# from pre_advance, on_exit, or on_enter vetoes the transition. Output is
+# recorded in .sow/project/log.md.
+# hooks:
+#   pre_advance: ["make fmt-check"]
+#   on_exit:
+#     ImplementationExecuting: ["make test"]
+#   on_enter:
+#     ReviewActive: ["make lint"]
+#   post_advance: ["./scripts/notify.sh"]
+#   timeout: 5m              # per command (default: 5m)
+#
+# Verification commands run by 'sow advance' before a transition completes
+# the listed phase. Any failure blocks the transition; results are recorded
+# in the phase's metadata under "verification".
+# verify:
+#   implementation: ["go test ./...", "golangci-lint run"]
+#
+# Agent spend limits, in US dollars and/or tokens, from the session logs (see
+# 'sow agent stats'). 'sow agent spawn' and 'sow agent resume' refuse to start
+# an agent once a limit is reached and warn when one is nearly spent. Totals
+# are recorded in the phase's metadata under "budget".
+# budget:
+#   project: {usd: 25, tokens: 5000000}
+#   task: {usd: 5}
+#   warn_at: 0.8             # warn at this fraction of a limit (default: 0.8)
 `)
 	if err := os.WriteFile(configPath, configContent, 0644); err != nil {
 		return fmt.Errorf("failed to create config file: %w", err)
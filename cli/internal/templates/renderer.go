@@ -58,10 +58,18 @@ func Render(embedFS embed.FS, path string, data interface{}) (string, error) {
 		return "", fmt.Errorf("failed to read template %s: %w", path, err)
 	}
 
-	// Parse template with helper functions
-	tmpl, err := template.New(path).Funcs(DefaultFuncMap()).Parse(string(content))
+	return RenderText(path, string(content), data)
+}
+
+// RenderText renders template text with the same helper functions and data
+// handling as Render. The name identifies the template in error messages.
+//
+// This is used for templates that are not embedded, such as the prompt
+// templates of project types declared in .sow/types/.
+func RenderText(name, text string, data interface{}) (string, error) {
+	tmpl, err := Parse(name, text)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse template %s: %w", path, err)
+		return "", err
 	}
 
 	// Handle nil data by providing empty context
@@ -73,12 +81,22 @@ func Render(embedFS embed.FS, path string, data interface{}) (string, error) {
 	// Execute template with data
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, ctx); err != nil {
-		return "", fmt.Errorf("failed to render template %s: %w", path, err)
+		return "", fmt.Errorf("failed to render template %s: %w", name, err)
 	}
 
 	return buf.String(), nil
 }
 
+// Parse parses template text with the helper functions from DefaultFuncMap.
+// Use it to check a template for syntax errors before rendering it.
+func Parse(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(DefaultFuncMap()).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+	return tmpl, nil
+}
+
 // ListTemplates lists all .md templates in the given directory of the embedded filesystem.
 // Returns paths relative to baseDir with .md suffix removed.
 //
@@ -0,0 +1,189 @@
+// Package verify runs the verification commands configured per phase in
+// .sow/config.yaml. sow advance runs them before any transition that
+// completes the phase, so a phase cannot be left with, for example, a
+// failing test suite.
+//
+// Each command's combined output is written to
+// .sow/project/phases/<phase>/verification/<n>.log, and the results are
+// recorded in the phase's metadata under MetadataKey.
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	osexec "os/exec"
+	"path"
+	"time"
+
+	"github.com/jmgilman/go/fs/core"
+	"github.com/jmgilman/sow/cli/internal/sow"
+	"github.com/jmgilman/sow/libs/config"
+	"github.com/jmgilman/sow/libs/exec"
+	"github.com/jmgilman/sow/libs/schemas"
+)
+
+// MetadataKey is the phase metadata key holding the latest verification.
+const MetadataKey = "verification"
+
+// waitDelay bounds how long a timed-out command's children may keep its
+// output open after the command is killed.
+const waitDelay = time.Second
+
+// CommandResult is the outcome of one verification command.
+type CommandResult struct {
+	// Command is the shell command that ran.
+	Command string
+
+	// Passed is true if the command exited zero.
+	Passed bool
+
+	// ExitCode is the command's exit code, or -1 if it did not exit
+	// normally (for example, it could not be started or timed out).
+	ExitCode int
+
+	// TimedOut is true if the command was killed for running longer than
+	// the verification timeout.
+	TimedOut bool
+
+	// Duration is how long the command ran.
+	Duration time.Duration
+
+	// Output is the path of the file holding the command's output,
+	// relative to the repository root.
+	Output string
+}
+
+// Result is the outcome of verifying a phase.
+type Result struct {
+	Phase      string
+	Passed     bool
+	VerifiedAt time.Time
+	Commands   []CommandResult
+}
+
+// Metadata returns the result in the form stored in phase metadata.
+func (r *Result) Metadata() map[string]interface{} {
+	commands := make([]interface{}, 0, len(r.Commands))
+	for _, c := range r.Commands {
+		command := map[string]interface{}{
+			"command":   c.Command,
+			"passed":    c.Passed,
+			"exit_code": c.ExitCode,
+			"duration":  c.Duration.Round(time.Millisecond).String(),
+			"output":    c.Output,
+		}
+		if c.TimedOut {
+			command["timed_out"] = true
+		}
+		commands = append(commands, command)
+	}
+	return map[string]interface{}{
+		"passed":      r.Passed,
+		"verified_at": r.VerifiedAt.UTC().Format(time.RFC3339),
+		"commands":    commands,
+	}
+}
+
+// Runner runs the configured verification commands.
+type Runner struct {
+	commands map[string][]string
+	timeout  time.Duration
+	dir      string
+	sowFS    core.FS
+	out      io.Writer
+}
+
+// New returns a runner for the verification commands in repoConfig.
+// Commands run in dir for at most the configured verify_timeout each, their
+// output is written to sowFS, a filesystem rooted at .sow/, and progress
+// lines are written to out.
+func New(repoConfig *schemas.Config, dir string, sowFS core.FS, out io.Writer) *Runner {
+	r := &Runner{timeout: config.GetVerifyTimeout(repoConfig), dir: dir, sowFS: sowFS, out: out}
+	if repoConfig != nil {
+		r.commands = repoConfig.Verify
+	}
+	return r
+}
+
+// FromContext returns a runner for the verification commands configured in
+// the repository of sowCtx. Commands run from the repository root.
+func FromContext(sowCtx *sow.Context, out io.Writer) (*Runner, error) {
+	repoConfig, err := config.LoadRepoConfig(sowCtx.FS())
+	if err != nil {
+		return nil, fmt.Errorf("load verification commands: %w", err)
+	}
+	return New(repoConfig, sowCtx.RepoRoot(), sowCtx.FS(), out), nil
+}
+
+// Commands returns the verification commands configured for a phase.
+func (r *Runner) Commands(phase string) []string {
+	return r.commands[phase]
+}
+
+// Run runs every verification command of a phase, even after one fails,
+// so the result shows the full picture. The returned error reports
+// failures to record output; failing commands are reported in the result.
+func (r *Runner) Run(ctx context.Context, phase string) (*Result, error) {
+	outDir := path.Join("project", "phases", phase, "verification")
+	if err := r.sowFS.MkdirAll(outDir, 0755); err != nil {
+		return nil, fmt.Errorf("create verification output directory: %w", err)
+	}
+
+	result := &Result{Phase: phase, Passed: true, VerifiedAt: time.Now()}
+	for i, command := range r.Commands(phase) {
+		_, _ = fmt.Fprintf(r.out, "Verifying %s: %s\n", phase, command)
+
+		start := time.Now()
+		output, timedOut, err := r.runCommand(ctx, command)
+
+		outFile := path.Join(outDir, fmt.Sprintf("%d.log", i+1))
+		if writeErr := r.sowFS.WriteFile(outFile, []byte(output), 0644); writeErr != nil {
+			return nil, fmt.Errorf("write verification output: %w", writeErr)
+		}
+
+		cr := CommandResult{
+			Command:  command,
+			Passed:   err == nil,
+			TimedOut: timedOut,
+			Duration: time.Since(start),
+			Output:   path.Join(".sow", outFile),
+		}
+		var exitErr *osexec.ExitError
+		switch {
+		case err == nil:
+		case !timedOut && errors.As(err, &exitErr):
+			cr.ExitCode = exitErr.ExitCode()
+		default:
+			cr.ExitCode = -1
+		}
+
+		switch {
+		case cr.Passed:
+			_, _ = fmt.Fprintf(r.out, "  ✓ passed (%s)\n", cr.Duration.Round(time.Millisecond))
+		case cr.TimedOut:
+			_, _ = fmt.Fprintf(r.out, "  ✗ timed out after %s, output in %s\n", r.timeout, cr.Output)
+			result.Passed = false
+		default:
+			_, _ = fmt.Fprintf(r.out, "  ✗ failed with exit code %d, output in %s\n", cr.ExitCode, cr.Output)
+			result.Passed = false
+		}
+		result.Commands = append(result.Commands, cr)
+	}
+
+	return result, nil
+}
+
+// runCommand runs one verification command with sh and returns its
+// combined output. The command is killed once it runs longer than the
+// runner's timeout, which is reported as timedOut.
+func (r *Runner) runCommand(ctx context.Context, command string) (string, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	sh := exec.NewLocalExecutor("sh", exec.WithDir(r.dir), exec.WithWaitDelay(waitDelay))
+	stdout, stderr, err := sh.RunContext(ctx, "-c", command)
+	timedOut := err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded)
+	return stdout + stderr, timedOut, err
+}
@@ -0,0 +1,114 @@
+package verify
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmgilman/go/fs/billy"
+	"github.com/jmgilman/sow/libs/schemas"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunner_Run(t *testing.T) {
+	t.Run("runs every command and records output", func(t *testing.T) {
+		sowFS := billy.NewMemory()
+		var out bytes.Buffer
+		runner := New(&schemas.Config{Verify: map[string][]string{
+			"implementation": {"echo tests ok", "echo lint failed >&2; exit 2", "echo formatted"},
+		}}, t.TempDir(), sowFS, &out)
+
+		result, err := runner.Run(context.Background(), "implementation")
+		require.NoError(t, err)
+
+		assert.Equal(t, "implementation", result.Phase)
+		assert.False(t, result.Passed)
+		require.Len(t, result.Commands, 3)
+
+		assert.True(t, result.Commands[0].Passed)
+		assert.Equal(t, 0, result.Commands[0].ExitCode)
+		assert.Equal(t, ".sow/project/phases/implementation/verification/1.log", result.Commands[0].Output)
+
+		assert.False(t, result.Commands[1].Passed)
+		assert.Equal(t, 2, result.Commands[1].ExitCode)
+		assert.True(t, result.Commands[2].Passed, "commands after a failure still run")
+
+		output, err := sowFS.ReadFile("project/phases/implementation/verification/2.log")
+		require.NoError(t, err)
+		assert.Equal(t, "lint failed\n", string(output))
+
+		assert.Contains(t, out.String(), "Verifying implementation: echo tests ok\n")
+		assert.Contains(t, out.String(), "✗ failed with exit code 2, output in .sow/project/phases/implementation/verification/2.log")
+	})
+
+	t.Run("passes when every command passes", func(t *testing.T) {
+		runner := New(&schemas.Config{Verify: map[string][]string{"review": {"true"}}},
+			t.TempDir(), billy.NewMemory(), &bytes.Buffer{})
+
+		result, err := runner.Run(context.Background(), "review")
+		require.NoError(t, err)
+		assert.True(t, result.Passed)
+	})
+
+	t.Run("kills commands that run past the timeout", func(t *testing.T) {
+		timeout := "50ms"
+		var out bytes.Buffer
+		runner := New(&schemas.Config{
+			Verify:         map[string][]string{"implementation": {"sleep 5; true", "true"}},
+			Verify_timeout: &timeout,
+		}, t.TempDir(), billy.NewMemory(), &out)
+
+		start := time.Now()
+		result, err := runner.Run(context.Background(), "implementation")
+		require.NoError(t, err)
+		assert.Less(t, time.Since(start), 3*time.Second)
+
+		assert.False(t, result.Passed)
+		require.Len(t, result.Commands, 2)
+		assert.True(t, result.Commands[0].TimedOut)
+		assert.Equal(t, -1, result.Commands[0].ExitCode)
+		assert.True(t, result.Commands[1].Passed, "commands after a timeout still run")
+		assert.Contains(t, out.String(), "✗ timed out after 50ms")
+	})
+
+	t.Run("phase without commands", func(t *testing.T) {
+		runner := New(nil, t.TempDir(), billy.NewMemory(), &bytes.Buffer{})
+		assert.Empty(t, runner.Commands("implementation"))
+
+		result, err := runner.Run(context.Background(), "implementation")
+		require.NoError(t, err)
+		assert.True(t, result.Passed)
+		assert.Empty(t, result.Commands)
+	})
+}
+
+func TestResult_Metadata(t *testing.T) {
+	result := &Result{
+		Phase:      "implementation",
+		Passed:     false,
+		VerifiedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Commands: []CommandResult{{
+			Command:  "go test ./...",
+			Passed:   false,
+			ExitCode: 1,
+			Duration: 1500 * time.Millisecond,
+			Output:   ".sow/project/phases/implementation/verification/1.log",
+		}},
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"passed":      false,
+		"verified_at": "2025-01-02T03:04:05Z",
+		"commands": []interface{}{
+			map[string]interface{}{
+				"command":   "go test ./...",
+				"passed":    false,
+				"exit_code": 1,
+				"duration":  "1.5s",
+				"output":    ".sow/project/phases/implementation/verification/1.log",
+			},
+		},
+	}, result.Metadata())
+}
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/jmgilman/sow/libs/schemas"
 )
 
@@ -19,6 +21,41 @@ const (
 	DefaultExecutorName = "claude-code"
 )
 
+// Project state backends selectable via state.backend in .sow/config.yaml.
+const (
+	// StateBackendYAML stores project state in a single YAML file.
+	StateBackendYAML = "yaml"
+
+	// StateBackendSQLite stores project state in a SQLite database.
+	StateBackendSQLite = "sqlite"
+
+	// StateBackendHTTP stores project state on a remote state server.
+	StateBackendHTTP = "http"
+
+	// DefaultStateBackend is the backend used when none is configured.
+	DefaultStateBackend = StateBackendYAML
+
+	// DefaultYAMLStatePath is the default YAML state file, relative to .sow/.
+	DefaultYAMLStatePath = "project/state.yaml"
+
+	// DefaultSQLiteStatePath is the default SQLite database file, relative to .sow/.
+	DefaultSQLiteStatePath = "project/state.db"
+
+	// DefaultStateLockTimeout is how long commands wait for the project state lock.
+	DefaultStateLockTimeout = 10 * time.Second
+)
+
+// DefaultHookTimeout is how long each hook command in .sow/config.yaml may run.
+const DefaultHookTimeout = 5 * time.Minute
+
+// DefaultVerifyTimeout is how long each verification command in
+// .sow/config.yaml may run.
+const DefaultVerifyTimeout = 10 * time.Minute
+
+// DefaultBudgetWarnAt is the fraction of an agent budget at which sow warns
+// that it is nearly spent.
+const DefaultBudgetWarnAt = 0.8
+
 // DefaultConfig returns a Config with all default values applied.
 func DefaultConfig() *schemas.Config {
 	adrs := DefaultADRsPath
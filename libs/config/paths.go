@@ -2,6 +2,7 @@ package config
 
 import (
 	"path/filepath"
+	"time"
 
 	"github.com/jmgilman/sow/libs/schemas"
 )
@@ -28,6 +29,95 @@ func GetDesignDocsPath(repoRoot string, config *schemas.Config) string {
 	return filepath.Join(repoRoot, ".sow", "knowledge", path)
 }
 
+// GetStateBackend returns the configured project state backend.
+// If config is nil or the backend is not configured, uses DefaultStateBackend.
+func GetStateBackend(config *schemas.Config) string {
+	if config != nil && config.State != nil && config.State.Backend != nil {
+		return *config.State.Backend
+	}
+	return DefaultStateBackend
+}
+
+// GetStatePath returns the project state location relative to the .sow directory.
+// If config is nil or the path is not configured, uses the default path for the
+// configured backend (DefaultYAMLStatePath or DefaultSQLiteStatePath).
+func GetStatePath(config *schemas.Config) string {
+	if config != nil && config.State != nil && config.State.Path != nil {
+		return *config.State.Path
+	}
+	if GetStateBackend(config) == StateBackendSQLite {
+		return DefaultSQLiteStatePath
+	}
+	return DefaultYAMLStatePath
+}
+
+// GetStateURL returns the state server URL used by the http backend.
+// Returns an empty string if config is nil or no URL is configured.
+func GetStateURL(config *schemas.Config) string {
+	if config != nil && config.State != nil && config.State.Url != nil {
+		return *config.State.Url
+	}
+	return ""
+}
+
+// GetStateLockTimeout returns how long to wait for the project state lock.
+// If config is nil or the timeout is not configured, returns DefaultStateLockTimeout.
+// The value is validated when the config is loaded, so an unparsable timeout
+// here also falls back to the default.
+func GetStateLockTimeout(config *schemas.Config) time.Duration {
+	if config != nil && config.State != nil && config.State.Lock_timeout != nil {
+		if timeout, err := time.ParseDuration(*config.State.Lock_timeout); err == nil {
+			return timeout
+		}
+	}
+	return DefaultStateLockTimeout
+}
+
+// GetHookTimeout returns how long each hook command may run.
+// If config is nil or the timeout is not configured, returns DefaultHookTimeout.
+// The value is validated when the config is loaded, so an unparsable timeout
+// here also falls back to the default.
+func GetHookTimeout(config *schemas.Config) time.Duration {
+	if config != nil && config.Hooks != nil && config.Hooks.Timeout != nil {
+		if timeout, err := time.ParseDuration(*config.Hooks.Timeout); err == nil {
+			return timeout
+		}
+	}
+	return DefaultHookTimeout
+}
+
+// GetVerifyTimeout returns how long each verification command may run.
+// If config is nil or the timeout is not configured, returns
+// DefaultVerifyTimeout. As with GetHookTimeout, an unparsable timeout falls
+// back to the default.
+func GetVerifyTimeout(config *schemas.Config) time.Duration {
+	if config != nil && config.Verify_timeout != nil {
+		if timeout, err := time.ParseDuration(*config.Verify_timeout); err == nil {
+			return timeout
+		}
+	}
+	return DefaultVerifyTimeout
+}
+
+// GetBudgetWarnAt returns the fraction of an agent budget at which sow warns.
+// If config is nil or the threshold is not configured, returns
+// DefaultBudgetWarnAt.
+func GetBudgetWarnAt(config *schemas.Config) float64 {
+	if config != nil && config.Budget != nil && config.Budget.Warn_at != nil {
+		return *config.Budget.Warn_at
+	}
+	return DefaultBudgetWarnAt
+}
+
+// GetVerifyCommands returns the verification commands configured for a phase.
+// Returns nil if config is nil or the phase has none.
+func GetVerifyCommands(config *schemas.Config, phase string) []string {
+	if config == nil {
+		return nil
+	}
+	return config.Verify[phase]
+}
+
 // GetExplorationsPath returns the absolute path to the explorations directory.
 // This path is not configurable and always uses DefaultExplorationsPath.
 // The path is computed as: repoRoot/.sow/knowledge/explorations.
@@ -3,6 +3,7 @@ package config
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/jmgilman/sow/libs/schemas"
 	"github.com/stretchr/testify/assert"
@@ -219,3 +220,136 @@ func TestGetKnowledgePath(t *testing.T) {
 		})
 	}
 }
+
+func TestGetStateBackend(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *schemas.Config
+		want   string
+	}{
+		{
+			name:   "nil config uses default",
+			config: nil,
+			want:   StateBackendYAML,
+		},
+		{
+			name:   "nil State uses default",
+			config: &schemas.Config{},
+			want:   StateBackendYAML,
+		},
+		{
+			name:   "configured backend",
+			config: &schemas.Config{State: &schemas.StateConfig{Backend: ptr(StateBackendSQLite)}},
+			want:   StateBackendSQLite,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, GetStateBackend(tt.config))
+		})
+	}
+}
+
+func TestGetStatePath(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *schemas.Config
+		want   string
+	}{
+		{
+			name:   "nil config uses YAML default",
+			config: nil,
+			want:   DefaultYAMLStatePath,
+		},
+		{
+			name:   "sqlite backend uses SQLite default",
+			config: &schemas.Config{State: &schemas.StateConfig{Backend: ptr(StateBackendSQLite)}},
+			want:   DefaultSQLiteStatePath,
+		},
+		{
+			name: "custom path overrides backend default",
+			config: &schemas.Config{State: &schemas.StateConfig{
+				Backend: ptr(StateBackendSQLite),
+				Path:    ptr("state/project.sqlite"),
+			}},
+			want: "state/project.sqlite",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, GetStatePath(tt.config))
+		})
+	}
+}
+
+func TestGetStateURL(t *testing.T) {
+	assert.Empty(t, GetStateURL(nil))
+	assert.Empty(t, GetStateURL(&schemas.Config{State: &schemas.StateConfig{Backend: ptr(StateBackendYAML)}}))
+	assert.Equal(t, "http://localhost:7070",
+		GetStateURL(&schemas.Config{State: &schemas.StateConfig{Url: ptr("http://localhost:7070")}}))
+}
+
+func TestGetStateLockTimeout(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *schemas.Config
+		want   time.Duration
+	}{
+		{
+			name:   "nil config uses default",
+			config: nil,
+			want:   DefaultStateLockTimeout,
+		},
+		{
+			name:   "unset timeout uses default",
+			config: &schemas.Config{State: &schemas.StateConfig{Backend: ptr(StateBackendYAML)}},
+			want:   DefaultStateLockTimeout,
+		},
+		{
+			name:   "configured timeout",
+			config: &schemas.Config{State: &schemas.StateConfig{Lock_timeout: ptr("30s")}},
+			want:   30 * time.Second,
+		},
+		{
+			name:   "zero timeout",
+			config: &schemas.Config{State: &schemas.StateConfig{Lock_timeout: ptr("0s")}},
+			want:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, GetStateLockTimeout(tt.config))
+		})
+	}
+}
+
+func TestGetHookTimeout(t *testing.T) {
+	assert.Equal(t, DefaultHookTimeout, GetHookTimeout(nil))
+	assert.Equal(t, DefaultHookTimeout, GetHookTimeout(&schemas.Config{Hooks: &schemas.HooksConfig{}}))
+	assert.Equal(t, 2*time.Minute,
+		GetHookTimeout(&schemas.Config{Hooks: &schemas.HooksConfig{Timeout: ptr("2m")}}))
+}
+
+func TestGetVerifyTimeout(t *testing.T) {
+	assert.Equal(t, DefaultVerifyTimeout, GetVerifyTimeout(nil))
+	assert.Equal(t, DefaultVerifyTimeout, GetVerifyTimeout(&schemas.Config{}))
+	assert.Equal(t, 30*time.Minute, GetVerifyTimeout(&schemas.Config{Verify_timeout: ptr("30m")}))
+}
+
+func TestGetBudgetWarnAt(t *testing.T) {
+	warnAt := 0.5
+	assert.Equal(t, DefaultBudgetWarnAt, GetBudgetWarnAt(nil))
+	assert.Equal(t, DefaultBudgetWarnAt, GetBudgetWarnAt(&schemas.Config{Budget: &schemas.BudgetConfig{}}))
+	assert.Equal(t, 0.5, GetBudgetWarnAt(&schemas.Config{Budget: &schemas.BudgetConfig{Warn_at: &warnAt}}))
+}
+
+func TestGetVerifyCommands(t *testing.T) {
+	config := &schemas.Config{Verify: map[string][]string{"implementation": {"go test ./..."}}}
+
+	assert.Equal(t, []string{"go test ./..."}, GetVerifyCommands(config, "implementation"))
+	assert.Nil(t, GetVerifyCommands(config, "review"))
+	assert.Nil(t, GetVerifyCommands(nil, "implementation"))
+}
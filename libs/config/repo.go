@@ -5,6 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/jmgilman/go/fs/core"
 	"github.com/jmgilman/sow/libs/schemas"
@@ -42,12 +46,162 @@ func LoadRepoConfigFromBytes(data []byte) (*schemas.Config, error) {
 		return nil, fmt.Errorf("load repo config: %w", ErrInvalidYAML)
 	}
 
+	if err := validateRepoConfig(&config); err != nil {
+		return nil, fmt.Errorf("load repo config: %w", err)
+	}
+
 	// Apply defaults for missing values
 	ApplyDefaults(&config)
 
 	return &config, nil
 }
 
+// validateRepoConfig checks values that the YAML decoder cannot enforce.
+func validateRepoConfig(config *schemas.Config) error {
+	if config.State != nil {
+		if err := validateStateConfig(config.State); err != nil {
+			return err
+		}
+	}
+	if config.Hooks != nil {
+		if err := validateHooksConfig(config.Hooks); err != nil {
+			return err
+		}
+	}
+	if err := validateVerifyConfig(config.Verify); err != nil {
+		return err
+	}
+	if config.Verify_timeout != nil {
+		timeout, err := time.ParseDuration(*config.Verify_timeout)
+		if err != nil || timeout <= 0 {
+			return fmt.Errorf("%w: invalid verify_timeout %q (must be a positive duration such as \"30m\")",
+				ErrInvalidConfig, *config.Verify_timeout)
+		}
+	}
+	if config.Budget != nil {
+		return validateBudgetConfig(config.Budget)
+	}
+	return nil
+}
+
+// validateStateConfig checks the state backend selection and its options.
+func validateStateConfig(state *schemas.StateConfig) error {
+	if state.Backend != nil {
+		switch *state.Backend {
+		case StateBackendYAML, StateBackendSQLite:
+		case StateBackendHTTP:
+			if state.Url == nil {
+				return fmt.Errorf("%w: state backend %q requires state.url", ErrInvalidConfig, StateBackendHTTP)
+			}
+		default:
+			return fmt.Errorf("%w: unknown state backend %q (must be %q, %q, or %q)",
+				ErrInvalidConfig, *state.Backend, StateBackendYAML, StateBackendSQLite, StateBackendHTTP)
+		}
+	}
+	if state.Url != nil {
+		u, err := url.Parse(*state.Url)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: invalid state url %q (must be an http or https URL)",
+				ErrInvalidConfig, *state.Url)
+		}
+	}
+	if state.Lock_timeout != nil {
+		timeout, err := time.ParseDuration(*state.Lock_timeout)
+		if err != nil || timeout < 0 {
+			return fmt.Errorf("%w: invalid state lock_timeout %q (must be a non-negative duration such as \"10s\")",
+				ErrInvalidConfig, *state.Lock_timeout)
+		}
+	}
+	return nil
+}
+
+// validateHooksConfig checks the hook timeout and rejects empty commands.
+func validateHooksConfig(hooks *schemas.HooksConfig) error {
+	if hooks.Timeout != nil {
+		timeout, err := time.ParseDuration(*hooks.Timeout)
+		if err != nil || timeout <= 0 {
+			return fmt.Errorf("%w: invalid hooks timeout %q (must be a positive duration such as \"2m\")",
+				ErrInvalidConfig, *hooks.Timeout)
+		}
+	}
+
+	if err := validateHookCommands("pre_advance", hooks.Pre_advance); err != nil {
+		return err
+	}
+	if err := validateHookCommands("post_advance", hooks.Post_advance); err != nil {
+		return err
+	}
+	for _, byState := range []struct {
+		name  string
+		hooks map[string][]string
+	}{{"on_enter", hooks.On_enter}, {"on_exit", hooks.On_exit}} {
+		states := make([]string, 0, len(byState.hooks))
+		for state := range byState.hooks {
+			states = append(states, state)
+		}
+		sort.Strings(states)
+		for _, state := range states {
+			if err := validateHookCommands(byState.name+"."+state, byState.hooks[state]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateVerifyConfig rejects empty verification commands.
+func validateVerifyConfig(verify map[string][]string) error {
+	phases := make([]string, 0, len(verify))
+	for phase := range verify {
+		phases = append(phases, phase)
+	}
+	sort.Strings(phases)
+	for _, phase := range phases {
+		for _, command := range verify[phase] {
+			if strings.TrimSpace(command) == "" {
+				return fmt.Errorf("%w: empty command in verify.%s", ErrInvalidConfig, phase)
+			}
+		}
+	}
+	return nil
+}
+
+// validateBudgetConfig checks that budget limits are positive and the
+// warning threshold is a fraction.
+func validateBudgetConfig(budget *schemas.BudgetConfig) error {
+	for _, limit := range []struct {
+		name  string
+		limit *schemas.BudgetLimit
+	}{{"project", budget.Project}, {"task", budget.Task}} {
+		if limit.limit == nil {
+			continue
+		}
+		if limit.limit.Usd != nil && *limit.limit.Usd <= 0 {
+			return fmt.Errorf("%w: invalid budget.%s.usd %v (must be positive)",
+				ErrInvalidConfig, limit.name, *limit.limit.Usd)
+		}
+		if limit.limit.Tokens != nil && *limit.limit.Tokens <= 0 {
+			return fmt.Errorf("%w: invalid budget.%s.tokens %d (must be positive)",
+				ErrInvalidConfig, limit.name, *limit.limit.Tokens)
+		}
+	}
+	if budget.Warn_at != nil && (*budget.Warn_at <= 0 || *budget.Warn_at > 1) {
+		return fmt.Errorf("%w: invalid budget warn_at %v (must be greater than 0 and at most 1)",
+			ErrInvalidConfig, *budget.Warn_at)
+	}
+	return nil
+}
+
+// validateHookCommands rejects empty commands in the named hook list.
+func validateHookCommands(name string, commands []string) error {
+	for _, command := range commands {
+		if strings.TrimSpace(command) == "" {
+			return fmt.Errorf("%w: empty command in hooks.%s", ErrInvalidConfig, name)
+		}
+	}
+	return nil
+}
+
 // isNotExist checks if an error indicates a file does not exist.
 func isNotExist(err error) bool {
 	return errors.Is(err, fs.ErrNotExist)
@@ -84,6 +84,144 @@ func TestLoadRepoConfigFromBytes(t *testing.T) {
 			input: []byte("   \n\t  "),
 			want:  DefaultConfig(),
 		},
+		{
+			name:  "state backend configured",
+			input: []byte("state:\n  backend: sqlite\n  path: project/custom.db"),
+			want: func() *schemas.Config {
+				c := DefaultConfig()
+				c.State = &schemas.StateConfig{
+					Backend: ptr(StateBackendSQLite),
+					Path:    ptr("project/custom.db"),
+				}
+				return c
+			}(),
+		},
+		{
+			name:    "unknown state backend",
+			input:   []byte("state:\n  backend: postgres"),
+			wantErr: ErrInvalidConfig,
+		},
+		{
+			name:  "http state backend configured",
+			input: []byte("state:\n  backend: http\n  url: http://localhost:7070"),
+			want: func() *schemas.Config {
+				c := DefaultConfig()
+				c.State = &schemas.StateConfig{
+					Backend: ptr(StateBackendHTTP),
+					Url:     ptr("http://localhost:7070"),
+				}
+				return c
+			}(),
+		},
+		{
+			name:    "http state backend without url",
+			input:   []byte("state:\n  backend: http"),
+			wantErr: ErrInvalidConfig,
+		},
+		{
+			name:    "invalid state url",
+			input:   []byte("state:\n  backend: http\n  url: localhost:7070"),
+			wantErr: ErrInvalidConfig,
+		},
+		{
+			name:    "invalid state lock timeout",
+			input:   []byte("state:\n  lock_timeout: soon"),
+			wantErr: ErrInvalidConfig,
+		},
+		{
+			name:    "negative state lock timeout",
+			input:   []byte("state:\n  lock_timeout: -5s"),
+			wantErr: ErrInvalidConfig,
+		},
+		{
+			name: "hooks configured",
+			input: []byte(`hooks:
+  pre_advance: ["make fmt-check"]
+  post_advance: ["./notify.sh"]
+  on_enter:
+    ReviewActive: ["make lint", "make test"]
+  on_exit:
+    ImplementationExecuting: ["make build"]
+  timeout: 2m`),
+			want: func() *schemas.Config {
+				c := DefaultConfig()
+				c.Hooks = &schemas.HooksConfig{
+					Pre_advance:  []string{"make fmt-check"},
+					Post_advance: []string{"./notify.sh"},
+					On_enter:     map[string][]string{"ReviewActive": {"make lint", "make test"}},
+					On_exit:      map[string][]string{"ImplementationExecuting": {"make build"}},
+					Timeout:      ptr("2m"),
+				}
+				return c
+			}(),
+		},
+		{
+			name:    "invalid hooks timeout",
+			input:   []byte("hooks:\n  timeout: 0s"),
+			wantErr: ErrInvalidConfig,
+		},
+		{
+			name:    "empty hook command",
+			input:   []byte("hooks:\n  on_enter:\n    ReviewActive: [\"\"]"),
+			wantErr: ErrInvalidConfig,
+		},
+		{
+			name:  "verify commands configured",
+			input: []byte("verify:\n  implementation:\n    - go test ./...\n    - golangci-lint run"),
+			want: func() *schemas.Config {
+				c := DefaultConfig()
+				c.Verify = map[string][]string{"implementation": {"go test ./...", "golangci-lint run"}}
+				return c
+			}(),
+		},
+		{
+			name:  "verify timeout configured",
+			input: []byte("verify_timeout: 30m"),
+			want: func() *schemas.Config {
+				c := DefaultConfig()
+				c.Verify_timeout = ptr("30m")
+				return c
+			}(),
+		},
+		{
+			name:    "invalid verify timeout",
+			input:   []byte("verify_timeout: soon"),
+			wantErr: ErrInvalidConfig,
+		},
+		{
+			name:    "empty verify command",
+			input:   []byte("verify:\n  implementation: [\" \"]"),
+			wantErr: ErrInvalidConfig,
+		},
+		{
+			name:  "budget configured",
+			input: []byte("budget:\n  project:\n    usd: 25\n    tokens: 5000000\n  task:\n    usd: 2.5\n  warn_at: 0.9"),
+			want: func() *schemas.Config {
+				c := DefaultConfig()
+				projectUSD, taskUSD, tokens, warnAt := 25.0, 2.5, int64(5000000), 0.9
+				c.Budget = &schemas.BudgetConfig{
+					Project: &schemas.BudgetLimit{Usd: &projectUSD, Tokens: &tokens},
+					Task:    &schemas.BudgetLimit{Usd: &taskUSD},
+					Warn_at: &warnAt,
+				}
+				return c
+			}(),
+		},
+		{
+			name:    "non-positive budget",
+			input:   []byte("budget:\n  task:\n    usd: 0"),
+			wantErr: ErrInvalidConfig,
+		},
+		{
+			name:    "negative budget tokens",
+			input:   []byte("budget:\n  project:\n    tokens: -1"),
+			wantErr: ErrInvalidConfig,
+		},
+		{
+			name:    "budget warn_at above one",
+			input:   []byte("budget:\n  warn_at: 80"),
+			wantErr: ErrInvalidConfig,
+		},
 		{
 			name:    "invalid yaml - unclosed bracket",
 			input:   []byte("invalid: [yaml: without: closing"),
@@ -4,17 +4,39 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
 
 	"github.com/jmgilman/go/fs/core"
 	"github.com/jmgilman/sow/libs/schemas"
 	"gopkg.in/yaml.v3"
 )
 
+// StandardAgentNames lists the built-in agent roles. Each is bound to the
+// default executor unless the config binds it elsewhere.
+var StandardAgentNames = []string{
+	"orchestrator",
+	"implementer",
+	"architect",
+	"reviewer",
+	"planner",
+	"researcher",
+	"decomposer",
+}
+
+// EnvAgentsPrefix is the prefix of the environment variables that override
+// agent bindings (see applyEnvOverrides).
+const EnvAgentsPrefix = "SOW_AGENTS_"
+
 // ValidExecutorTypes defines the allowed executor types.
 var ValidExecutorTypes = map[string]bool{
 	"claude":   true,
 	"cursor":   true,
 	"windsurf": true,
+	"command":  true,
+	"scripted": true,
 }
 
 // GetUserConfigPath returns the path to the user configuration file.
@@ -107,7 +129,9 @@ func LoadUserConfigFromPath(fsys core.FS, path string) (*schemas.UserConfig, err
 
 // ValidateUserConfig validates the user configuration.
 // Checks:
-//   - Executor types are valid ("claude", "cursor", "windsurf")
+//   - Executor types are valid ("claude", "cursor", "windsurf", "command", "scripted")
+//   - Command executors define a program and valid argument templates
+//   - Scripted executors name a script
 //   - Bindings reference defined executors (or default "claude-code")
 //
 // Returns nil if valid, error with details if invalid.
@@ -124,6 +148,22 @@ func ValidateUserConfig(config *schemas.UserConfig) error {
 				exec.Type, name, ErrInvalidConfig,
 			)
 		}
+		if err := validateCommandExecutor(name, exec); err != nil {
+			return err
+		}
+		if exec.Type == "scripted" && exec.Script == "" {
+			return fmt.Errorf("scripted executor %q must set script: %w", name, ErrInvalidConfig)
+		}
+		if err := validateExecutionPolicy(fmt.Sprintf("executor %q", name), exec.Policy); err != nil {
+			return err
+		}
+	}
+
+	// Validate agent policies
+	for agent, policy := range config.Agents.Policies {
+		if err := validateExecutionPolicy(fmt.Sprintf("agent %q", agent), &policy); err != nil {
+			return err
+		}
 	}
 
 	// Validate bindings reference defined executors
@@ -136,40 +176,107 @@ func ValidateUserConfig(config *schemas.UserConfig) error {
 	return nil
 }
 
-// validateBindings checks that all bindings reference defined executors.
-func validateBindings(config *schemas.UserConfig) error {
-	bindings := map[string]*string{
-		"orchestrator": config.Agents.Bindings.Orchestrator,
-		"implementer":  config.Agents.Bindings.Implementer,
-		"architect":    config.Agents.Bindings.Architect,
-		"reviewer":     config.Agents.Bindings.Reviewer,
-		"planner":      config.Agents.Bindings.Planner,
-		"researcher":   config.Agents.Bindings.Researcher,
-		"decomposer":   config.Agents.Bindings.Decomposer,
-	}
-
-	for agentRole, executorPtr := range bindings {
-		if executorPtr == nil {
-			continue // Nil bindings are ok, will get defaults
+// validateCommandExecutor checks the command block of an executor. Command
+// executors need a program, spawn arguments, and argument templates that
+// parse; other types must not have a command block.
+func validateCommandExecutor(name string, exec schemas.ExecutorConfig) error {
+	if exec.Type != "command" {
+		if exec.Command != nil {
+			return fmt.Errorf(
+				"executor %q has a command block but type %q: %w",
+				name, exec.Type, ErrInvalidConfig,
+			)
+		}
+		return nil
+	}
+
+	cmd := exec.Command
+	if cmd == nil || cmd.Program == "" {
+		return fmt.Errorf("command executor %q must set command.program: %w", name, ErrInvalidConfig)
+	}
+	if len(cmd.Spawn_args) == 0 {
+		return fmt.Errorf("command executor %q must set command.spawn_args: %w", name, ErrInvalidConfig)
+	}
+	if cmd.Prompt != nil && *cmd.Prompt != "stdin" && *cmd.Prompt != "argv" {
+		return fmt.Errorf(
+			"command executor %q has invalid prompt %q (want \"stdin\" or \"argv\"): %w",
+			name, *cmd.Prompt, ErrInvalidConfig,
+		)
+	}
+	if cmd.Output != nil && *cmd.Output != "stream-json" && *cmd.Output != "text" {
+		return fmt.Errorf(
+			"command executor %q has invalid output %q (want \"stream-json\" or \"text\"): %w",
+			name, *cmd.Output, ErrInvalidConfig,
+		)
+	}
+	for _, arg := range append(append([]string{}, cmd.Spawn_args...), cmd.Resume_args...) {
+		if _, err := template.New(name).Funcs(commandTemplateFuncs).Parse(arg); err != nil {
+			return fmt.Errorf("command executor %q has invalid argument %q: %v: %w", name, arg, err, ErrInvalidConfig)
+		}
+	}
+
+	return nil
+}
+
+// commandTemplateFuncs declares the functions the command executor makes
+// available to argument templates, so that templates using them parse.
+var commandTemplateFuncs = template.FuncMap{"join": strings.Join}
+
+// retryOnPattern matches the failure kinds accepted in a policy's retry_on.
+var retryOnPattern = regexp.MustCompile(`^(timeout|error|exit:[0-9]+)$`)
+
+// validateExecutionPolicy checks that the durations of a policy parse and
+// that its retry settings are valid. owner names the policy in errors.
+func validateExecutionPolicy(owner string, policy *schemas.ExecutionPolicy) error {
+	if policy == nil {
+		return nil
+	}
+
+	durations := map[string]*string{
+		"timeout":     policy.Timeout,
+		"backoff":     policy.Backoff,
+		"max_backoff": policy.Max_backoff,
+	}
+	for field, value := range durations {
+		if value == nil {
+			continue
+		}
+		d, err := time.ParseDuration(*value)
+		if err != nil || d < 0 {
+			return fmt.Errorf(
+				"policy of %s has invalid %s %q (want a duration such as \"30m\"): %w",
+				owner, field, *value, ErrInvalidConfig,
+			)
+		}
+	}
+	if policy.Max_retries != nil && *policy.Max_retries < 0 {
+		return fmt.Errorf("policy of %s has negative max_retries: %w", owner, ErrInvalidConfig)
+	}
+	for _, kind := range policy.Retry_on {
+		if !retryOnPattern.MatchString(kind) {
+			return fmt.Errorf(
+				"policy of %s has invalid retry_on %q (want \"timeout\", \"error\", or \"exit:<code>\"): %w",
+				owner, kind, ErrInvalidConfig,
+			)
 		}
-		executorName := *executorPtr
+	}
 
+	return nil
+}
+
+// validateBindings checks that all bindings reference defined executors.
+func validateBindings(config *schemas.UserConfig) error {
+	for agentName, executorName := range config.Agents.Bindings {
 		// "claude-code" is always valid (implicit default executor)
 		if executorName == DefaultExecutorName {
 			continue
 		}
 
 		// Check if executor is defined
-		if config.Agents.Executors == nil {
-			return fmt.Errorf(
-				"binding %q references undefined executor %q: %w",
-				agentRole, executorName, ErrInvalidConfig,
-			)
-		}
 		if _, ok := config.Agents.Executors[executorName]; !ok {
 			return fmt.Errorf(
 				"binding %q references undefined executor %q: %w",
-				agentRole, executorName, ErrInvalidConfig,
+				agentName, executorName, ErrInvalidConfig,
 			)
 		}
 	}
@@ -183,36 +290,10 @@ func validateBindings(config *schemas.UserConfig) error {
 //nolint:revive // Field names must match generated schemas.UserConfig structure
 func getDefaultUserConfig() *schemas.UserConfig {
 	yoloMode := false
-	claudeCode := DefaultExecutorName
-
-	return &schemas.UserConfig{
-		Agents: &struct {
-			Executors map[string]struct {
-				Type     string `json:"type"`
-				Settings *struct {
-					Yolo_mode *bool   `json:"yolo_mode,omitempty"`
-					Model     *string `json:"model,omitempty"`
-				} `json:"settings,omitempty"`
-				Custom_args []string `json:"custom_args,omitempty"`
-			} `json:"executors,omitempty"`
-			Bindings *struct {
-				Orchestrator *string `json:"orchestrator,omitempty"`
-				Implementer  *string `json:"implementer,omitempty"`
-				Architect    *string `json:"architect,omitempty"`
-				Reviewer     *string `json:"reviewer,omitempty"`
-				Planner      *string `json:"planner,omitempty"`
-				Researcher   *string `json:"researcher,omitempty"`
-				Decomposer   *string `json:"decomposer,omitempty"`
-			} `json:"bindings,omitempty"`
-		}{
-			Executors: map[string]struct {
-				Type     string `json:"type"`
-				Settings *struct {
-					Yolo_mode *bool   `json:"yolo_mode,omitempty"`
-					Model     *string `json:"model,omitempty"`
-				} `json:"settings,omitempty"`
-				Custom_args []string `json:"custom_args,omitempty"`
-			}{
+
+	config := &schemas.UserConfig{
+		Agents: &schemas.AgentsConfig{
+			Executors: map[string]schemas.ExecutorConfig{
 				DefaultExecutorName: {
 					Type: "claude",
 					Settings: &struct {
@@ -223,25 +304,11 @@ func getDefaultUserConfig() *schemas.UserConfig {
 					},
 				},
 			},
-			Bindings: &struct {
-				Orchestrator *string `json:"orchestrator,omitempty"`
-				Implementer  *string `json:"implementer,omitempty"`
-				Architect    *string `json:"architect,omitempty"`
-				Reviewer     *string `json:"reviewer,omitempty"`
-				Planner      *string `json:"planner,omitempty"`
-				Researcher   *string `json:"researcher,omitempty"`
-				Decomposer   *string `json:"decomposer,omitempty"`
-			}{
-				Orchestrator: &claudeCode,
-				Implementer:  &claudeCode,
-				Architect:    &claudeCode,
-				Reviewer:     &claudeCode,
-				Planner:      &claudeCode,
-				Researcher:   &claudeCode,
-				Decomposer:   &claudeCode,
-			},
+			Bindings: make(map[string]string),
 		},
 	}
+	applyBindingDefaults(config.Agents.Bindings, DefaultExecutorName)
+	return config
 }
 
 // applyUserConfigDefaults fills in missing configuration values with defaults.
@@ -249,54 +316,21 @@ func getDefaultUserConfig() *schemas.UserConfig {
 //
 //nolint:revive // Field names must match generated schemas.UserConfig structure
 func applyUserConfigDefaults(config *schemas.UserConfig) {
-	claudeCode := DefaultExecutorName
 	yoloMode := false
 
 	// Initialize Agents if nil
 	if config.Agents == nil {
-		config.Agents = &struct {
-			Executors map[string]struct {
-				Type     string `json:"type"`
-				Settings *struct {
-					Yolo_mode *bool   `json:"yolo_mode,omitempty"`
-					Model     *string `json:"model,omitempty"`
-				} `json:"settings,omitempty"`
-				Custom_args []string `json:"custom_args,omitempty"`
-			} `json:"executors,omitempty"`
-			Bindings *struct {
-				Orchestrator *string `json:"orchestrator,omitempty"`
-				Implementer  *string `json:"implementer,omitempty"`
-				Architect    *string `json:"architect,omitempty"`
-				Reviewer     *string `json:"reviewer,omitempty"`
-				Planner      *string `json:"planner,omitempty"`
-				Researcher   *string `json:"researcher,omitempty"`
-				Decomposer   *string `json:"decomposer,omitempty"`
-			} `json:"bindings,omitempty"`
-		}{}
+		config.Agents = &schemas.AgentsConfig{}
 	}
 
 	// Initialize Executors if nil
 	if config.Agents.Executors == nil {
-		config.Agents.Executors = make(map[string]struct {
-			Type     string `json:"type"`
-			Settings *struct {
-				Yolo_mode *bool   `json:"yolo_mode,omitempty"`
-				Model     *string `json:"model,omitempty"`
-			} `json:"settings,omitempty"`
-			Custom_args []string `json:"custom_args,omitempty"`
-		})
+		config.Agents.Executors = make(map[string]schemas.ExecutorConfig)
 	}
 
 	// Add default claude-code executor if not present
 	if _, ok := config.Agents.Executors[DefaultExecutorName]; !ok {
-		config.Agents.Executors[DefaultExecutorName] = struct {
-			Type     string `json:"type"`
-			Settings *struct {
-				Yolo_mode *bool   `json:"yolo_mode,omitempty"`
-				Model     *string `json:"model,omitempty"`
-			} `json:"settings,omitempty"`
-			Custom_args []string `json:"custom_args,omitempty"`
-		}{
+		config.Agents.Executors[DefaultExecutorName] = schemas.ExecutorConfig{
 			Type: "claude",
 			Settings: &struct {
 				Yolo_mode *bool   `json:"yolo_mode,omitempty"`
@@ -309,118 +343,63 @@ func applyUserConfigDefaults(config *schemas.UserConfig) {
 
 	// Initialize Bindings if nil
 	if config.Agents.Bindings == nil {
-		config.Agents.Bindings = &struct {
-			Orchestrator *string `json:"orchestrator,omitempty"`
-			Implementer  *string `json:"implementer,omitempty"`
-			Architect    *string `json:"architect,omitempty"`
-			Reviewer     *string `json:"reviewer,omitempty"`
-			Planner      *string `json:"planner,omitempty"`
-			Researcher   *string `json:"researcher,omitempty"`
-			Decomposer   *string `json:"decomposer,omitempty"`
-		}{}
-	}
-
-	// Apply defaults for each nil binding
-	applyBindingDefaults(config.Agents.Bindings, claudeCode)
+		config.Agents.Bindings = make(map[string]string)
+	}
+
+	// Apply defaults for each unbound standard agent
+	applyBindingDefaults(config.Agents.Bindings, DefaultExecutorName)
 }
 
-// applyBindingDefaults fills in nil binding fields with the default executor.
-//
-//nolint:revive // Field names must match generated schemas.UserConfig structure
-func applyBindingDefaults(bindings *struct {
-	Orchestrator *string `json:"orchestrator,omitempty"`
-	Implementer  *string `json:"implementer,omitempty"`
-	Architect    *string `json:"architect,omitempty"`
-	Reviewer     *string `json:"reviewer,omitempty"`
-	Planner      *string `json:"planner,omitempty"`
-	Researcher   *string `json:"researcher,omitempty"`
-	Decomposer   *string `json:"decomposer,omitempty"`
-}, defaultExec string) {
-	if bindings.Orchestrator == nil {
-		bindings.Orchestrator = &defaultExec
-	}
-	if bindings.Implementer == nil {
-		bindings.Implementer = &defaultExec
-	}
-	if bindings.Architect == nil {
-		bindings.Architect = &defaultExec
-	}
-	if bindings.Reviewer == nil {
-		bindings.Reviewer = &defaultExec
-	}
-	if bindings.Planner == nil {
-		bindings.Planner = &defaultExec
-	}
-	if bindings.Researcher == nil {
-		bindings.Researcher = &defaultExec
-	}
-	if bindings.Decomposer == nil {
-		bindings.Decomposer = &defaultExec
+// applyBindingDefaults binds each standard agent that has no binding to
+// the default executor. Custom agents without a binding fall back to the
+// default executor when they are looked up.
+func applyBindingDefaults(bindings map[string]string, defaultExec string) {
+	for _, agentName := range StandardAgentNames {
+		if _, ok := bindings[agentName]; !ok {
+			bindings[agentName] = defaultExec
+		}
 	}
 }
 
 // applyEnvOverrides applies environment variable overrides to the configuration.
 // Environment variables take precedence over file configuration.
-// Format: SOW_AGENTS_{ROLE}={executor_name}
-// Example: SOW_AGENTS_IMPLEMENTER=cursor.
-//
-//nolint:revive // Field names must match generated schemas.UserConfig structure
+// Format: SOW_AGENTS_{NAME}={executor_name}, where NAME is the agent name
+// in upper case with dashes written as underscores. Any agent name works,
+// including custom agents.
+// Example: SOW_AGENTS_IMPLEMENTER=cursor, SOW_AGENTS_SECURITY_REVIEWER=cursor.
 func applyEnvOverrides(config *schemas.UserConfig) {
 	// Ensure config.Agents exists
 	if config.Agents == nil {
-		config.Agents = &struct {
-			Executors map[string]struct {
-				Type     string `json:"type"`
-				Settings *struct {
-					Yolo_mode *bool   `json:"yolo_mode,omitempty"`
-					Model     *string `json:"model,omitempty"`
-				} `json:"settings,omitempty"`
-				Custom_args []string `json:"custom_args,omitempty"`
-			} `json:"executors,omitempty"`
-			Bindings *struct {
-				Orchestrator *string `json:"orchestrator,omitempty"`
-				Implementer  *string `json:"implementer,omitempty"`
-				Architect    *string `json:"architect,omitempty"`
-				Reviewer     *string `json:"reviewer,omitempty"`
-				Planner      *string `json:"planner,omitempty"`
-				Researcher   *string `json:"researcher,omitempty"`
-				Decomposer   *string `json:"decomposer,omitempty"`
-			} `json:"bindings,omitempty"`
-		}{}
+		config.Agents = &schemas.AgentsConfig{}
 	}
 
 	// Ensure config.Agents.Bindings exists
 	if config.Agents.Bindings == nil {
-		config.Agents.Bindings = &struct {
-			Orchestrator *string `json:"orchestrator,omitempty"`
-			Implementer  *string `json:"implementer,omitempty"`
-			Architect    *string `json:"architect,omitempty"`
-			Reviewer     *string `json:"reviewer,omitempty"`
-			Planner      *string `json:"planner,omitempty"`
-			Researcher   *string `json:"researcher,omitempty"`
-			Decomposer   *string `json:"decomposer,omitempty"`
-		}{}
-	}
-
-	// Map of environment variables to binding fields
-	envVars := []struct {
-		envVar string
-		field  **string
-	}{
-		{"SOW_AGENTS_ORCHESTRATOR", &config.Agents.Bindings.Orchestrator},
-		{"SOW_AGENTS_IMPLEMENTER", &config.Agents.Bindings.Implementer},
-		{"SOW_AGENTS_ARCHITECT", &config.Agents.Bindings.Architect},
-		{"SOW_AGENTS_REVIEWER", &config.Agents.Bindings.Reviewer},
-		{"SOW_AGENTS_PLANNER", &config.Agents.Bindings.Planner},
-		{"SOW_AGENTS_RESEARCHER", &config.Agents.Bindings.Researcher},
-		{"SOW_AGENTS_DECOMPOSER", &config.Agents.Bindings.Decomposer},
-	}
-
-	for _, ev := range envVars {
-		if value := os.Getenv(ev.envVar); value != "" {
-			// Create a copy of the value for the pointer
-			valueCopy := value
-			*ev.field = &valueCopy
+		config.Agents.Bindings = make(map[string]string)
+	}
+
+	for _, env := range os.Environ() {
+		key, value, _ := strings.Cut(env, "=")
+		agentName, ok := agentFromEnvVar(key)
+		if !ok || value == "" {
+			continue
 		}
+		config.Agents.Bindings[agentName] = value
 	}
 }
+
+// agentFromEnvVar returns the agent name a SOW_AGENTS_{NAME} variable
+// binds, or false if key is not such a variable.
+func agentFromEnvVar(key string) (string, bool) {
+	name, ok := strings.CutPrefix(key, EnvAgentsPrefix)
+	if !ok || name == "" {
+		return "", false
+	}
+	return strings.ReplaceAll(strings.ToLower(name), "_", "-"), true
+}
+
+// AgentEnvVar returns the environment variable that overrides the binding
+// of agentName (e.g. "security-reviewer" -> "SOW_AGENTS_SECURITY_REVIEWER").
+func AgentEnvVar(agentName string) string {
+	return EnvAgentsPrefix + strings.ToUpper(strings.ReplaceAll(agentName, "-", "_"))
+}
@@ -119,10 +119,117 @@ func TestLoadUserConfigFromPath(t *testing.T) {
 				require.Contains(t, got.Agents.Executors, "my-cursor")
 				assert.Equal(t, "cursor", got.Agents.Executors["my-cursor"].Type)
 				require.NotNil(t, got.Agents.Bindings)
-				require.NotNil(t, got.Agents.Bindings.Implementer)
-				assert.Equal(t, "my-cursor", *got.Agents.Bindings.Implementer)
+				require.Contains(t, got.Agents.Bindings, "implementer")
+				assert.Equal(t, "my-cursor", got.Agents.Bindings["implementer"])
 			},
 		},
+		{
+			name: "valid config with command executor",
+			setupFS: func() (core.FS, string) {
+				memfs := billy.NewMemory()
+				path := "home/.config/sow/config.yaml"
+				_ = memfs.MkdirAll("home/.config/sow", 0755)
+				content := `agents:
+  executors:
+    aider:
+      type: command
+      settings:
+        model: gpt-4o
+      command:
+        program: aider
+        spawn_args: ["--yes", "--message", "{{.Prompt}}", "--model", "{{.Model}}"]
+        prompt: argv
+        output: text
+  bindings:
+    implementer: aider
+`
+				_ = memfs.WriteFile(path, []byte(content), 0644)
+				return memfs, path
+			},
+			checkFunc: func(t *testing.T, got *schemas.UserConfig) {
+				require.Contains(t, got.Agents.Executors, "aider")
+				cmd := got.Agents.Executors["aider"].Command
+				require.NotNil(t, cmd)
+				assert.Equal(t, "aider", cmd.Program)
+				assert.Equal(t, []string{"--yes", "--message", "{{.Prompt}}", "--model", "{{.Model}}"}, cmd.Spawn_args)
+				assert.Nil(t, cmd.Resume_args)
+				require.NotNil(t, cmd.Prompt)
+				assert.Equal(t, "argv", *cmd.Prompt)
+				require.NotNil(t, cmd.Output)
+				assert.Equal(t, "text", *cmd.Output)
+			},
+		},
+		{
+			name: "valid config with execution policies",
+			setupFS: func() (core.FS, string) {
+				memfs := billy.NewMemory()
+				path := "home/.config/sow/config.yaml"
+				_ = memfs.MkdirAll("home/.config/sow", 0755)
+				content := `agents:
+  executors:
+    claude-code:
+      type: claude
+      policy:
+        timeout: 30m
+        max_retries: 2
+        backoff: 30s
+        retry_on: ["timeout", "exit:1"]
+  policies:
+    reviewer:
+      timeout: 10m
+`
+				_ = memfs.WriteFile(path, []byte(content), 0644)
+				return memfs, path
+			},
+			checkFunc: func(t *testing.T, got *schemas.UserConfig) {
+				policy := got.Agents.Executors["claude-code"].Policy
+				require.NotNil(t, policy)
+				require.NotNil(t, policy.Timeout)
+				assert.Equal(t, "30m", *policy.Timeout)
+				require.NotNil(t, policy.Max_retries)
+				assert.Equal(t, int64(2), *policy.Max_retries)
+				require.NotNil(t, policy.Backoff)
+				assert.Equal(t, "30s", *policy.Backoff)
+				assert.Nil(t, policy.Max_backoff)
+				assert.Equal(t, []string{"timeout", "exit:1"}, policy.Retry_on)
+
+				require.Contains(t, got.Agents.Policies, "reviewer")
+				require.NotNil(t, got.Agents.Policies["reviewer"].Timeout)
+				assert.Equal(t, "10m", *got.Agents.Policies["reviewer"].Timeout)
+			},
+		},
+		{
+			name: "agent policy with invalid timeout",
+			setupFS: func() (core.FS, string) {
+				memfs := billy.NewMemory()
+				path := "home/.config/sow/config.yaml"
+				_ = memfs.MkdirAll("home/.config/sow", 0755)
+				content := `agents:
+  policies:
+    implementer:
+      timeout: forever
+`
+				_ = memfs.WriteFile(path, []byte(content), 0644)
+				return memfs, path
+			},
+			wantErr: ErrInvalidConfig,
+		},
+		{
+			name: "scripted executor without script",
+			setupFS: func() (core.FS, string) {
+				memfs := billy.NewMemory()
+				path := "home/.config/sow/config.yaml"
+				_ = memfs.MkdirAll("home/.config/sow", 0755)
+				content := `agents:
+  executors:
+    fake:
+      type: scripted
+`
+				_ = memfs.WriteFile(path, []byte(content), 0644)
+				return memfs, path
+			},
+			wantErr: ErrInvalidConfig,
+		},
 		{
 			name: "file not found returns defaults",
 			setupFS: func() (core.FS, string) {
@@ -135,8 +242,8 @@ func TestLoadUserConfigFromPath(t *testing.T) {
 				require.NotNil(t, got.Agents.Executors)
 				require.Contains(t, got.Agents.Executors, DefaultExecutorName)
 				require.NotNil(t, got.Agents.Bindings)
-				require.NotNil(t, got.Agents.Bindings.Orchestrator)
-				assert.Equal(t, DefaultExecutorName, *got.Agents.Bindings.Orchestrator)
+				require.Contains(t, got.Agents.Bindings, "orchestrator")
+				assert.Equal(t, DefaultExecutorName, got.Agents.Bindings["orchestrator"])
 			},
 		},
 		{
@@ -169,11 +276,11 @@ func TestLoadUserConfigFromPath(t *testing.T) {
 				require.NotNil(t, got.Agents)
 				require.NotNil(t, got.Agents.Bindings)
 				// Implementer from file
-				require.NotNil(t, got.Agents.Bindings.Implementer)
-				assert.Equal(t, DefaultExecutorName, *got.Agents.Bindings.Implementer)
+				require.Contains(t, got.Agents.Bindings, "implementer")
+				assert.Equal(t, DefaultExecutorName, got.Agents.Bindings["implementer"])
 				// Other bindings get defaults
-				require.NotNil(t, got.Agents.Bindings.Orchestrator)
-				assert.Equal(t, DefaultExecutorName, *got.Agents.Bindings.Orchestrator)
+				require.Contains(t, got.Agents.Bindings, "orchestrator")
+				assert.Equal(t, DefaultExecutorName, got.Agents.Bindings["orchestrator"])
 				// Default executor should be added
 				require.Contains(t, got.Agents.Executors, DefaultExecutorName)
 			},
@@ -276,45 +383,12 @@ func TestValidateUserConfig(t *testing.T) {
 			name: "valid config with defined executors",
 			//nolint:revive // Field names must match generated schemas.UserConfig structure
 			config: &schemas.UserConfig{
-				Agents: &struct {
-					Executors map[string]struct {
-						Type     string `json:"type"`
-						Settings *struct {
-							Yolo_mode *bool   `json:"yolo_mode,omitempty"`
-							Model     *string `json:"model,omitempty"`
-						} `json:"settings,omitempty"`
-						Custom_args []string `json:"custom_args,omitempty"`
-					} `json:"executors,omitempty"`
-					Bindings *struct {
-						Orchestrator *string `json:"orchestrator,omitempty"`
-						Implementer  *string `json:"implementer,omitempty"`
-						Architect    *string `json:"architect,omitempty"`
-						Reviewer     *string `json:"reviewer,omitempty"`
-						Planner      *string `json:"planner,omitempty"`
-						Researcher   *string `json:"researcher,omitempty"`
-						Decomposer   *string `json:"decomposer,omitempty"`
-					} `json:"bindings,omitempty"`
-				}{
-					Executors: map[string]struct {
-						Type     string `json:"type"`
-						Settings *struct {
-							Yolo_mode *bool   `json:"yolo_mode,omitempty"`
-							Model     *string `json:"model,omitempty"`
-						} `json:"settings,omitempty"`
-						Custom_args []string `json:"custom_args,omitempty"`
-					}{
+				Agents: &schemas.AgentsConfig{
+					Executors: map[string]schemas.ExecutorConfig{
 						"my-cursor": {Type: "cursor"},
 					},
-					Bindings: &struct {
-						Orchestrator *string `json:"orchestrator,omitempty"`
-						Implementer  *string `json:"implementer,omitempty"`
-						Architect    *string `json:"architect,omitempty"`
-						Reviewer     *string `json:"reviewer,omitempty"`
-						Planner      *string `json:"planner,omitempty"`
-						Researcher   *string `json:"researcher,omitempty"`
-						Decomposer   *string `json:"decomposer,omitempty"`
-					}{
-						Implementer: strPtr("my-cursor"),
+					Bindings: map[string]string{
+						"implementer": "my-cursor",
 					},
 				},
 			},
@@ -323,33 +397,8 @@ func TestValidateUserConfig(t *testing.T) {
 			name: "unknown executor type returns error",
 			//nolint:revive // Field names must match generated schemas.UserConfig structure
 			config: &schemas.UserConfig{
-				Agents: &struct {
-					Executors map[string]struct {
-						Type     string `json:"type"`
-						Settings *struct {
-							Yolo_mode *bool   `json:"yolo_mode,omitempty"`
-							Model     *string `json:"model,omitempty"`
-						} `json:"settings,omitempty"`
-						Custom_args []string `json:"custom_args,omitempty"`
-					} `json:"executors,omitempty"`
-					Bindings *struct {
-						Orchestrator *string `json:"orchestrator,omitempty"`
-						Implementer  *string `json:"implementer,omitempty"`
-						Architect    *string `json:"architect,omitempty"`
-						Reviewer     *string `json:"reviewer,omitempty"`
-						Planner      *string `json:"planner,omitempty"`
-						Researcher   *string `json:"researcher,omitempty"`
-						Decomposer   *string `json:"decomposer,omitempty"`
-					} `json:"bindings,omitempty"`
-				}{
-					Executors: map[string]struct {
-						Type     string `json:"type"`
-						Settings *struct {
-							Yolo_mode *bool   `json:"yolo_mode,omitempty"`
-							Model     *string `json:"model,omitempty"`
-						} `json:"settings,omitempty"`
-						Custom_args []string `json:"custom_args,omitempty"`
-					}{
+				Agents: &schemas.AgentsConfig{
+					Executors: map[string]schemas.ExecutorConfig{
 						"bad": {Type: "unknown-type"},
 					},
 				},
@@ -360,35 +409,9 @@ func TestValidateUserConfig(t *testing.T) {
 			name: "binding references undefined executor returns error",
 			//nolint:revive // Field names must match generated schemas.UserConfig structure
 			config: &schemas.UserConfig{
-				Agents: &struct {
-					Executors map[string]struct {
-						Type     string `json:"type"`
-						Settings *struct {
-							Yolo_mode *bool   `json:"yolo_mode,omitempty"`
-							Model     *string `json:"model,omitempty"`
-						} `json:"settings,omitempty"`
-						Custom_args []string `json:"custom_args,omitempty"`
-					} `json:"executors,omitempty"`
-					Bindings *struct {
-						Orchestrator *string `json:"orchestrator,omitempty"`
-						Implementer  *string `json:"implementer,omitempty"`
-						Architect    *string `json:"architect,omitempty"`
-						Reviewer     *string `json:"reviewer,omitempty"`
-						Planner      *string `json:"planner,omitempty"`
-						Researcher   *string `json:"researcher,omitempty"`
-						Decomposer   *string `json:"decomposer,omitempty"`
-					} `json:"bindings,omitempty"`
-				}{
-					Bindings: &struct {
-						Orchestrator *string `json:"orchestrator,omitempty"`
-						Implementer  *string `json:"implementer,omitempty"`
-						Architect    *string `json:"architect,omitempty"`
-						Reviewer     *string `json:"reviewer,omitempty"`
-						Planner      *string `json:"planner,omitempty"`
-						Researcher   *string `json:"researcher,omitempty"`
-						Decomposer   *string `json:"decomposer,omitempty"`
-					}{
-						Implementer: strPtr("nonexistent"),
+				Agents: &schemas.AgentsConfig{
+					Bindings: map[string]string{
+						"implementer": "nonexistent",
 					},
 				},
 			},
@@ -398,36 +421,10 @@ func TestValidateUserConfig(t *testing.T) {
 			name: "claude-code binding is always valid (implicit default)",
 			//nolint:revive // Field names must match generated schemas.UserConfig structure
 			config: &schemas.UserConfig{
-				Agents: &struct {
-					Executors map[string]struct {
-						Type     string `json:"type"`
-						Settings *struct {
-							Yolo_mode *bool   `json:"yolo_mode,omitempty"`
-							Model     *string `json:"model,omitempty"`
-						} `json:"settings,omitempty"`
-						Custom_args []string `json:"custom_args,omitempty"`
-					} `json:"executors,omitempty"`
-					Bindings *struct {
-						Orchestrator *string `json:"orchestrator,omitempty"`
-						Implementer  *string `json:"implementer,omitempty"`
-						Architect    *string `json:"architect,omitempty"`
-						Reviewer     *string `json:"reviewer,omitempty"`
-						Planner      *string `json:"planner,omitempty"`
-						Researcher   *string `json:"researcher,omitempty"`
-						Decomposer   *string `json:"decomposer,omitempty"`
-					} `json:"bindings,omitempty"`
-				}{
+				Agents: &schemas.AgentsConfig{
 					// No executors defined
-					Bindings: &struct {
-						Orchestrator *string `json:"orchestrator,omitempty"`
-						Implementer  *string `json:"implementer,omitempty"`
-						Architect    *string `json:"architect,omitempty"`
-						Reviewer     *string `json:"reviewer,omitempty"`
-						Planner      *string `json:"planner,omitempty"`
-						Researcher   *string `json:"researcher,omitempty"`
-						Decomposer   *string `json:"decomposer,omitempty"`
-					}{
-						Implementer: strPtr(DefaultExecutorName), // claude-code is always valid
+					Bindings: map[string]string{
+						"implementer": DefaultExecutorName, // claude-code is always valid
 					},
 				},
 			},
@@ -436,33 +433,8 @@ func TestValidateUserConfig(t *testing.T) {
 			name: "all valid executor types",
 			//nolint:revive // Field names must match generated schemas.UserConfig structure
 			config: &schemas.UserConfig{
-				Agents: &struct {
-					Executors map[string]struct {
-						Type     string `json:"type"`
-						Settings *struct {
-							Yolo_mode *bool   `json:"yolo_mode,omitempty"`
-							Model     *string `json:"model,omitempty"`
-						} `json:"settings,omitempty"`
-						Custom_args []string `json:"custom_args,omitempty"`
-					} `json:"executors,omitempty"`
-					Bindings *struct {
-						Orchestrator *string `json:"orchestrator,omitempty"`
-						Implementer  *string `json:"implementer,omitempty"`
-						Architect    *string `json:"architect,omitempty"`
-						Reviewer     *string `json:"reviewer,omitempty"`
-						Planner      *string `json:"planner,omitempty"`
-						Researcher   *string `json:"researcher,omitempty"`
-						Decomposer   *string `json:"decomposer,omitempty"`
-					} `json:"bindings,omitempty"`
-				}{
-					Executors: map[string]struct {
-						Type     string `json:"type"`
-						Settings *struct {
-							Yolo_mode *bool   `json:"yolo_mode,omitempty"`
-							Model     *string `json:"model,omitempty"`
-						} `json:"settings,omitempty"`
-						Custom_args []string `json:"custom_args,omitempty"`
-					}{
+				Agents: &schemas.AgentsConfig{
+					Executors: map[string]schemas.ExecutorConfig{
 						"claude-exec":   {Type: "claude"},
 						"cursor-exec":   {Type: "cursor"},
 						"windsurf-exec": {Type: "windsurf"},
@@ -488,6 +460,157 @@ func TestValidateUserConfig(t *testing.T) {
 	}
 }
 
+func TestValidateCommandExecutor(t *testing.T) {
+	tests := []struct {
+		name    string
+		exec    schemas.ExecutorConfig
+		wantErr string
+	}{
+		{
+			name: "valid command executor",
+			exec: schemas.ExecutorConfig{
+				Type: "command",
+				Command: &schemas.CommandExecutorConfig{
+					Program:     "gemini",
+					Spawn_args:  []string{"--prompt", "{{.Prompt}}", `{{join .Permissions.DeniedTools ","}}`},
+					Resume_args: []string{"--resume", "{{.SessionID}}"},
+					Prompt:      strPtr("argv"),
+					Output:      strPtr("stream-json"),
+				},
+			},
+		},
+		{
+			name:    "command type without command block",
+			exec:    schemas.ExecutorConfig{Type: "command"},
+			wantErr: "must set command.program",
+		},
+		{
+			name: "command type without spawn args",
+			exec: schemas.ExecutorConfig{
+				Type:    "command",
+				Command: &schemas.CommandExecutorConfig{Program: "gemini"},
+			},
+			wantErr: "must set command.spawn_args",
+		},
+		{
+			name: "invalid prompt delivery",
+			exec: schemas.ExecutorConfig{
+				Type: "command",
+				Command: &schemas.CommandExecutorConfig{
+					Program:    "gemini",
+					Spawn_args: []string{"-"},
+					Prompt:     strPtr("file"),
+				},
+			},
+			wantErr: `invalid prompt "file"`,
+		},
+		{
+			name: "invalid output format",
+			exec: schemas.ExecutorConfig{
+				Type: "command",
+				Command: &schemas.CommandExecutorConfig{
+					Program:    "gemini",
+					Spawn_args: []string{"-"},
+					Output:     strPtr("xml"),
+				},
+			},
+			wantErr: `invalid output "xml"`,
+		},
+		{
+			name: "unparsable argument template",
+			exec: schemas.ExecutorConfig{
+				Type: "command",
+				Command: &schemas.CommandExecutorConfig{
+					Program:     "gemini",
+					Spawn_args:  []string{"-"},
+					Resume_args: []string{"{{.SessionID"},
+				},
+			},
+			wantErr: `invalid argument "{{.SessionID"`,
+		},
+		{
+			name: "command block on another type",
+			exec: schemas.ExecutorConfig{
+				Type:    "claude",
+				Command: &schemas.CommandExecutorConfig{Program: "claude"},
+			},
+			wantErr: `has a command block but type "claude"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCommandExecutor("test", tt.exec)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, ErrInvalidConfig)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestValidateExecutionPolicy(t *testing.T) {
+	negative := int64(-1)
+	tests := []struct {
+		name    string
+		policy  *schemas.ExecutionPolicy
+		wantErr string
+	}{
+		{
+			name: "nil policy",
+		},
+		{
+			name: "valid policy",
+			policy: &schemas.ExecutionPolicy{
+				Timeout:     strPtr("1h30m"),
+				Backoff:     strPtr("10s"),
+				Max_backoff: strPtr("2m"),
+				Retry_on:    []string{"timeout", "error", "exit:137"},
+			},
+		},
+		{
+			name:    "unparsable timeout",
+			policy:  &schemas.ExecutionPolicy{Timeout: strPtr("30")},
+			wantErr: `invalid timeout "30"`,
+		},
+		{
+			name:    "negative backoff",
+			policy:  &schemas.ExecutionPolicy{Backoff: strPtr("-5s")},
+			wantErr: `invalid backoff "-5s"`,
+		},
+		{
+			name:    "negative max retries",
+			policy:  &schemas.ExecutionPolicy{Max_retries: &negative},
+			wantErr: "negative max_retries",
+		},
+		{
+			name:    "unknown retry condition",
+			policy:  &schemas.ExecutionPolicy{Retry_on: []string{"exit:abc"}},
+			wantErr: `invalid retry_on "exit:abc"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateExecutionPolicy(`executor "test"`, tt.policy)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, ErrInvalidConfig)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
 //nolint:funlen // Table-driven test with multiple test cases
 func TestEnvironmentOverrides(t *testing.T) {
 	tests := []struct {
@@ -514,8 +637,8 @@ func TestEnvironmentOverrides(t *testing.T) {
 			checkFunc: func(t *testing.T, got *schemas.UserConfig) {
 				require.NotNil(t, got.Agents)
 				require.NotNil(t, got.Agents.Bindings)
-				require.NotNil(t, got.Agents.Bindings.Implementer)
-				assert.Equal(t, "custom-executor", *got.Agents.Bindings.Implementer)
+				require.Contains(t, got.Agents.Bindings, "implementer")
+				assert.Equal(t, "custom-executor", got.Agents.Bindings["implementer"])
 			},
 		},
 		{
@@ -532,9 +655,9 @@ func TestEnvironmentOverrides(t *testing.T) {
 			checkFunc: func(t *testing.T, got *schemas.UserConfig) {
 				require.NotNil(t, got.Agents)
 				require.NotNil(t, got.Agents.Bindings)
-				assert.Equal(t, "orch-executor", *got.Agents.Bindings.Orchestrator)
-				assert.Equal(t, "impl-executor", *got.Agents.Bindings.Implementer)
-				assert.Equal(t, "arch-executor", *got.Agents.Bindings.Architect)
+				assert.Equal(t, "orch-executor", got.Agents.Bindings["orchestrator"])
+				assert.Equal(t, "impl-executor", got.Agents.Bindings["implementer"])
+				assert.Equal(t, "arch-executor", got.Agents.Bindings["architect"])
 			},
 		},
 		{
@@ -560,9 +683,9 @@ func TestEnvironmentOverrides(t *testing.T) {
 				require.NotNil(t, got.Agents)
 				require.NotNil(t, got.Agents.Bindings)
 				// Implementer overridden by env
-				assert.Equal(t, "env-override", *got.Agents.Bindings.Implementer)
+				assert.Equal(t, "env-override", got.Agents.Bindings["implementer"])
 				// Orchestrator kept from file
-				assert.Equal(t, "my-cursor", *got.Agents.Bindings.Orchestrator)
+				assert.Equal(t, "my-cursor", got.Agents.Bindings["orchestrator"])
 			},
 		},
 		{
@@ -583,13 +706,41 @@ func TestEnvironmentOverrides(t *testing.T) {
 			checkFunc: func(t *testing.T, got *schemas.UserConfig) {
 				require.NotNil(t, got.Agents)
 				require.NotNil(t, got.Agents.Bindings)
-				assert.Equal(t, "env-orch", *got.Agents.Bindings.Orchestrator)
-				assert.Equal(t, "env-impl", *got.Agents.Bindings.Implementer)
-				assert.Equal(t, "env-arch", *got.Agents.Bindings.Architect)
-				assert.Equal(t, "env-rev", *got.Agents.Bindings.Reviewer)
-				assert.Equal(t, "env-plan", *got.Agents.Bindings.Planner)
-				assert.Equal(t, "env-res", *got.Agents.Bindings.Researcher)
-				assert.Equal(t, "env-dec", *got.Agents.Bindings.Decomposer)
+				assert.Equal(t, "env-orch", got.Agents.Bindings["orchestrator"])
+				assert.Equal(t, "env-impl", got.Agents.Bindings["implementer"])
+				assert.Equal(t, "env-arch", got.Agents.Bindings["architect"])
+				assert.Equal(t, "env-rev", got.Agents.Bindings["reviewer"])
+				assert.Equal(t, "env-plan", got.Agents.Bindings["planner"])
+				assert.Equal(t, "env-res", got.Agents.Bindings["researcher"])
+				assert.Equal(t, "env-dec", got.Agents.Bindings["decomposer"])
+			},
+		},
+		{
+			name: "env var overrides bind custom agents",
+			setupFS: func() (core.FS, string) {
+				memfs := billy.NewMemory()
+				path := "home/.config/sow/config.yaml"
+				_ = memfs.MkdirAll("home/.config/sow", 0755)
+				content := `agents:
+  executors:
+    my-cursor:
+      type: cursor
+  bindings:
+    security-reviewer: my-cursor
+    docs-writer: my-cursor
+`
+				_ = memfs.WriteFile(path, []byte(content), 0644)
+				return memfs, path
+			},
+			envSetup: func(t *testing.T) {
+				t.Setenv("SOW_AGENTS_SECURITY_REVIEWER", "env-sec")
+			},
+			checkFunc: func(t *testing.T, got *schemas.UserConfig) {
+				require.NotNil(t, got.Agents)
+				assert.Equal(t, "env-sec", got.Agents.Bindings["security-reviewer"])
+				assert.Equal(t, "my-cursor", got.Agents.Bindings["docs-writer"])
+				// Standard agents still get defaults
+				assert.Equal(t, DefaultExecutorName, got.Agents.Bindings["implementer"])
 			},
 		},
 	}
@@ -638,13 +789,13 @@ func TestDefaultValues(t *testing.T) {
 		require.NotNil(t, config.Agents)
 		require.NotNil(t, config.Agents.Bindings)
 
-		assert.Equal(t, DefaultExecutorName, *config.Agents.Bindings.Orchestrator)
-		assert.Equal(t, DefaultExecutorName, *config.Agents.Bindings.Implementer)
-		assert.Equal(t, DefaultExecutorName, *config.Agents.Bindings.Architect)
-		assert.Equal(t, DefaultExecutorName, *config.Agents.Bindings.Reviewer)
-		assert.Equal(t, DefaultExecutorName, *config.Agents.Bindings.Planner)
-		assert.Equal(t, DefaultExecutorName, *config.Agents.Bindings.Researcher)
-		assert.Equal(t, DefaultExecutorName, *config.Agents.Bindings.Decomposer)
+		assert.Equal(t, DefaultExecutorName, config.Agents.Bindings["orchestrator"])
+		assert.Equal(t, DefaultExecutorName, config.Agents.Bindings["implementer"])
+		assert.Equal(t, DefaultExecutorName, config.Agents.Bindings["architect"])
+		assert.Equal(t, DefaultExecutorName, config.Agents.Bindings["reviewer"])
+		assert.Equal(t, DefaultExecutorName, config.Agents.Bindings["planner"])
+		assert.Equal(t, DefaultExecutorName, config.Agents.Bindings["researcher"])
+		assert.Equal(t, DefaultExecutorName, config.Agents.Bindings["decomposer"])
 	})
 
 	t.Run("default yolo_mode is false", func(t *testing.T) {
@@ -690,11 +841,38 @@ func TestLoadUserConfig(t *testing.T) {
 	})
 }
 
+func TestAgentEnvVar(t *testing.T) {
+	tests := []struct {
+		agent  string
+		envVar string
+	}{
+		{agent: "implementer", envVar: "SOW_AGENTS_IMPLEMENTER"},
+		{agent: "security-reviewer", envVar: "SOW_AGENTS_SECURITY_REVIEWER"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.agent, func(t *testing.T) {
+			assert.Equal(t, tt.envVar, AgentEnvVar(tt.agent))
+
+			agent, ok := agentFromEnvVar(tt.envVar)
+			require.True(t, ok)
+			assert.Equal(t, tt.agent, agent)
+		})
+	}
+
+	_, ok := agentFromEnvVar("SOW_AGENTS_")
+	assert.False(t, ok)
+	_, ok = agentFromEnvVar("SOW_ACTOR")
+	assert.False(t, ok)
+}
+
 func TestValidExecutorTypes(t *testing.T) {
 	t.Run("contains expected types", func(t *testing.T) {
 		assert.True(t, ValidExecutorTypes["claude"])
 		assert.True(t, ValidExecutorTypes["cursor"])
 		assert.True(t, ValidExecutorTypes["windsurf"])
+		assert.True(t, ValidExecutorTypes["command"])
+		assert.True(t, ValidExecutorTypes["scripted"])
 	})
 
 	t.Run("rejects invalid types", func(t *testing.T) {
@@ -763,6 +941,6 @@ func TestLoadingPipelineOrder(t *testing.T) {
 
 		require.NoError(t, err)
 		// Env override should win
-		assert.Equal(t, "env-override", *config.Agents.Bindings.Implementer)
+		assert.Equal(t, "env-override", config.Agents.Bindings["implementer"])
 	})
 }
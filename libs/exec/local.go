@@ -4,12 +4,47 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"os"
 	"os/exec"
+	"time"
 )
 
 // LocalExecutor executes commands on the local system using os/exec.
 type LocalExecutor struct {
-	command string
+	command   string
+	dir       string
+	env       []string
+	waitDelay time.Duration
+}
+
+// LocalOption configures a LocalExecutor.
+type LocalOption func(*LocalExecutor)
+
+// WithDir sets the working directory commands run in. By default commands
+// run in the current process's working directory.
+func WithDir(dir string) LocalOption {
+	return func(e *LocalExecutor) {
+		e.dir = dir
+	}
+}
+
+// WithEnv adds environment variables, in "KEY=value" form, to the
+// environment inherited from the current process. Later values override
+// earlier ones with the same key.
+func WithEnv(env ...string) LocalOption {
+	return func(e *LocalExecutor) {
+		e.env = append(e.env, env...)
+	}
+}
+
+// WithWaitDelay bounds how long a command cancelled through its context may
+// keep its output open. Commands such as "sh -c" can start children that
+// outlive the killed process and hold its stdout; without a delay, RunContext
+// waits for them to exit.
+func WithWaitDelay(d time.Duration) LocalOption {
+	return func(e *LocalExecutor) {
+		e.waitDelay = d
+	}
 }
 
 // NewLocalExecutor creates a new LocalExecutor for the specified command.
@@ -21,10 +56,15 @@ type LocalExecutor struct {
 //
 //	gh := exec.NewLocalExecutor("gh")
 //	claude := exec.NewLocalExecutor("claude")
-func NewLocalExecutor(command string) *LocalExecutor {
-	return &LocalExecutor{
+//	sh := exec.NewLocalExecutor("sh", exec.WithDir(repoRoot), exec.WithEnv("CI=1"))
+func NewLocalExecutor(command string, opts ...LocalOption) *LocalExecutor {
+	e := &LocalExecutor{
 		command: command,
 	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
 }
 
 // Command returns the command name this executor wraps.
@@ -76,6 +116,11 @@ func (e *LocalExecutor) Run(args ...string) (stdout, stderr string, err error) {
 // err will be non-nil.
 func (e *LocalExecutor) RunContext(ctx context.Context, args ...string) (stdout, stderr string, err error) {
 	cmd := exec.CommandContext(ctx, e.command, args...)
+	cmd.Dir = e.dir
+	cmd.WaitDelay = e.waitDelay
+	if len(e.env) > 0 {
+		cmd.Env = append(os.Environ(), e.env...)
+	}
 
 	var stdoutBuf, stderrBuf bytes.Buffer
 	cmd.Stdout = &stdoutBuf
@@ -2,6 +2,7 @@ package exec
 
 import (
 	"context"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -86,6 +87,39 @@ func TestLocalExecutor_Run(t *testing.T) {
 	})
 }
 
+func TestLocalExecutor_Options(t *testing.T) {
+	t.Run("runs in the configured directory", func(t *testing.T) {
+		dir, err := filepath.EvalSymlinks(t.TempDir())
+		require.NoError(t, err)
+		e := NewLocalExecutor("pwd", WithDir(dir))
+		stdout, _, err := e.Run()
+
+		require.NoError(t, err)
+		assert.Equal(t, dir+"\n", stdout)
+	})
+
+	t.Run("adds environment variables", func(t *testing.T) {
+		t.Setenv("SOW_EXEC_INHERITED", "inherited")
+		e := NewLocalExecutor("sh", WithEnv("SOW_EXEC_A=a", "SOW_EXEC_B=b"), WithEnv("SOW_EXEC_A=override"))
+		stdout, _, err := e.Run("-c", "echo $SOW_EXEC_A $SOW_EXEC_B $SOW_EXEC_INHERITED")
+
+		require.NoError(t, err)
+		assert.Equal(t, "override b inherited\n", stdout)
+	})
+
+	t.Run("stops waiting for children of a cancelled command", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		e := NewLocalExecutor("sh", WithWaitDelay(50*time.Millisecond))
+		start := time.Now()
+		_, _, err := e.RunContext(ctx, "-c", "sleep 5; true")
+
+		require.Error(t, err)
+		assert.Less(t, time.Since(start), 2*time.Second)
+	})
+}
+
 func TestLocalExecutor_RunContext(t *testing.T) {
 	t.Run("respects context cancellation", func(t *testing.T) {
 		ctx, cancel := context.WithCancel(context.Background())
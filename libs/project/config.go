@@ -39,6 +39,10 @@ type ProjectTypeConfig struct {
 	// These generate contextual prompts for users in each state
 	prompts map[State]PromptGenerator
 
+	// agents are agent names mapped by state
+	// These do the work of states without tasks to run (e.g. review)
+	agents map[State]string
+
 	// orchestratorPrompt generates project-type-specific orchestrator guidance
 	// This explains how the project type works and how orchestrator coordinates work
 	orchestratorPrompt PromptGenerator
@@ -140,6 +144,12 @@ func (ptc *ProjectTypeConfig) GetStatePrompt(s string, p *state.Project) string
 	return gen(p)
 }
 
+// GetStateAgent returns the name of the agent bound to a state.
+// Returns empty string if no agent is bound to the state.
+func (ptc *ProjectTypeConfig) GetStateAgent(s string) string {
+	return ptc.agents[State(s)]
+}
+
 // OrchestratorPrompt returns the orchestrator prompt for this project type.
 // This explains how the project type works and how the orchestrator should coordinate work.
 // Returns empty string if no orchestrator prompt is configured.
@@ -299,6 +309,39 @@ func (ptc *ProjectTypeConfig) GetGuardDescription(from State, event Event) strin
 	return ""
 }
 
+// EvaluateGuard evaluates the guard of the transition fired by event from
+// the given state and returns the unmet conditions. Transitions without a
+// guard, and events not configured for the state, pass.
+func (ptc *ProjectTypeConfig) EvaluateGuard(from State, event Event, p *state.Project) GuardResult {
+	for _, tc := range ptc.transitions {
+		if tc.From == from && tc.Event == event {
+			return tc.guardTemplate.Evaluate(p)
+		}
+	}
+	return GuardResult{}
+}
+
+// CompletedPhase returns the phase that firing event from the given state
+// marks completed: the phase whose end state is from, unless the transition
+// marks it failed instead. Returns an empty string if the transition does
+// not complete a phase or the event is not configured for the state.
+func (ptc *ProjectTypeConfig) CompletedPhase(from State, event Event) string {
+	phaseName := ptc.GetPhaseForState(string(from))
+	if phaseName == "" || !ptc.IsPhaseEndState(phaseName, string(from)) {
+		return ""
+	}
+
+	for _, tc := range ptc.transitions {
+		if tc.From == from && tc.Event == event {
+			if tc.failedPhase == phaseName {
+				return ""
+			}
+			return phaseName
+		}
+	}
+	return ""
+}
+
 // GetTransitionDescription returns the description for a transition.
 // Returns an empty string if no description is configured.
 func (ptc *ProjectTypeConfig) GetTransitionDescription(from State, event Event) string {
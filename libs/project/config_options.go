@@ -73,6 +73,28 @@ func WithProjectGuard(description string, guardFunc func(*state.Project) bool) P
 	}
 }
 
+// WithProjectGuardResult sets a guard that reports why it fails. The check
+// returns a GuardResult listing each unmet condition, which the CLI shows
+// when the transition is blocked. The description summarizes the guard as
+// a whole.
+//
+// Example:
+//
+//	WithProjectGuardResult("all tasks complete", func(p *state.Project) GuardResult {
+//	    var result GuardResult
+//	    for _, task := range p.Phases["implementation"].Tasks {
+//	        if task.Status != "completed" {
+//	            result.Addf("task %s not complete (%s)", task.Id, task.Status)
+//	        }
+//	    }
+//	    return result
+//	})
+func WithProjectGuardResult(description string, check func(*state.Project) GuardResult) ProjectTransitionOption {
+	return func(tc *TransitionConfig) {
+		tc.guardTemplate = newGuardTemplate(description, check)
+	}
+}
+
 // WithProjectOnEntry sets the entry action for a transition.
 func WithProjectOnEntry(action Action) ProjectTransitionOption {
 	return func(tc *TransitionConfig) {
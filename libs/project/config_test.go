@@ -639,3 +639,89 @@ func TestProjectTypeConfig_IsBranchingState(t *testing.T) {
 		})
 	}
 }
+
+func TestProjectTypeConfig_EvaluateGuard(t *testing.T) {
+	t.Parallel()
+
+	config := NewProjectTypeConfigBuilder("test").
+		SetInitialState(configTestStatePlanningActive).
+		AddTransition(
+			configTestStatePlanningActive,
+			configTestStateImplPlanning,
+			configTestEventAdvancePlanning,
+			WithProjectGuardResult("plan approved", func(p *state.Project) GuardResult {
+				var result GuardResult
+				if approved, _ := p.Phases["planning"].Metadata["approved"].(bool); !approved {
+					result.Addf("planning not approved")
+				}
+				return result
+			}),
+		).
+		AddTransition(
+			configTestStateImplPlanning,
+			configTestStateImplExecuting,
+			configTestEventStartImpl,
+		).
+		Build()
+
+	proj := &state.Project{}
+	proj.Phases = map[string]project.PhaseState{
+		"planning": {Metadata: map[string]interface{}{}},
+	}
+
+	t.Run("lists unmet conditions", func(t *testing.T) {
+		t.Parallel()
+		result := config.EvaluateGuard(configTestStatePlanningActive, configTestEventAdvancePlanning, proj)
+		assert.Equal(t, []string{"planning not approved"}, result.Unmet)
+		assert.Equal(t, "plan approved", config.GetGuardDescription(configTestStatePlanningActive, configTestEventAdvancePlanning))
+	})
+
+	t.Run("machine uses the structured guard", func(t *testing.T) {
+		t.Parallel()
+		machine := config.BuildProjectMachine(proj, configTestStatePlanningActive)
+		assert.False(t, machine.CanFire(configTestEventAdvancePlanning))
+	})
+
+	t.Run("unguarded transition passes", func(t *testing.T) {
+		t.Parallel()
+		assert.True(t, config.EvaluateGuard(configTestStateImplPlanning, configTestEventStartImpl, proj).Passed())
+	})
+
+	t.Run("unknown event passes", func(t *testing.T) {
+		t.Parallel()
+		assert.True(t, config.EvaluateGuard(configTestStateImplPlanning, "unknown", proj).Passed())
+	})
+}
+
+func TestProjectTypeConfig_CompletedPhase(t *testing.T) {
+	t.Parallel()
+
+	config := NewProjectTypeConfigBuilder("test").
+		SetInitialState("Drafting").
+		WithPhase("draft", WithStartState("Drafting"), WithEndState("Polishing")).
+		WithPhase("review", WithStartState("Reviewing"), WithEndState("Reviewing")).
+		AddTransition("Drafting", "Polishing", "drafted").
+		AddTransition("Polishing", "Reviewing", "polished").
+		AddTransition("Reviewing", NoProject, "approve").
+		AddTransition("Reviewing", "Drafting", "reject", WithProjectFailedPhase("review")).
+		Build()
+
+	tests := []struct {
+		name  string
+		from  State
+		event Event
+		want  string
+	}{
+		{"leaving the end state", "Polishing", "polished", "draft"},
+		{"inside a phase", "Drafting", "drafted", ""},
+		{"single-state phase", "Reviewing", "approve", "review"},
+		{"transition marking the phase failed", "Reviewing", "reject", ""},
+		{"unconfigured event", "Polishing", "approve", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, config.CompletedPhase(tt.from, tt.event))
+		})
+	}
+}
@@ -0,0 +1,301 @@
+package project
+
+import (
+	"fmt"
+	"sort"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+	"github.com/jmgilman/sow/libs/project/state"
+	"github.com/jmgilman/sow/libs/schemas"
+	projschema "github.com/jmgilman/sow/libs/schemas/project"
+)
+
+// Guard predicates available to declarative project types.
+const (
+	PredicateOutputApproved   = "output_approved"
+	PredicateAllTasksComplete = "all_tasks_complete"
+	PredicateMetadataBool     = "metadata_bool"
+)
+
+// definitionSchemaFile is the embedded CUE file declaring #ProjectTypeDefinition.
+const definitionSchemaFile = "project_type.cue"
+
+// ParseTypeDefinition parses a project type declared in CUE (see
+// schemas.ProjectTypeDefinition). The source is unified with the
+// #ProjectTypeDefinition schema, so unknown fields and missing required
+// fields are reported. The filename is used in error messages.
+func ParseTypeDefinition(filename string, src []byte) (*schemas.ProjectTypeDefinition, error) {
+	ctx := cuecontext.New()
+
+	schemaSrc, err := schemas.CUESchemas.ReadFile(definitionSchemaFile)
+	if err != nil {
+		return nil, fmt.Errorf("read project type schema: %w", err)
+	}
+	schema := ctx.CompileBytes(schemaSrc, cue.Filename(definitionSchemaFile)).
+		LookupPath(cue.ParsePath("#ProjectTypeDefinition"))
+	if err := schema.Err(); err != nil {
+		return nil, fmt.Errorf("load project type schema: %w", err)
+	}
+
+	value := ctx.CompileBytes(src, cue.Filename(filename))
+	if err := value.Err(); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", filename, err)
+	}
+
+	unified := schema.Unify(value)
+	if err := unified.Validate(cue.Concrete(true)); err != nil {
+		return nil, fmt.Errorf("validate %s: %w", filename, err)
+	}
+
+	var def schemas.ProjectTypeDefinition
+	if err := unified.Decode(&def); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", filename, err)
+	}
+	return &def, nil
+}
+
+// NewDefinitionBuilder returns a builder configured from a declarative
+// project type definition. Callers add prompts (which need template
+// rendering) and then call Build.
+//
+// The definition is translated as follows:
+//   - Phases become WithPhase calls; a new project starts with the phase
+//     owning the initial state in progress and the others pending.
+//   - Transitions become AddTransition calls with their guards combined
+//     (see AllGuards). Entering a phase's start state enables the phase.
+//   - States with a single outgoing event get an OnAdvance determiner
+//     returning it; other states need the event named explicitly.
+//   - States naming an agent get a WithAgent call.
+//
+// Returns *ErrConfigValidation if the definition is inconsistent.
+func NewDefinitionBuilder(def *schemas.ProjectTypeDefinition) (*ProjectTypeConfigBuilder, error) {
+	if issues := validateDefinition(def); len(issues) > 0 {
+		return nil, &ErrConfigValidation{Issues: issues}
+	}
+
+	builder := NewProjectTypeConfigBuilder(def.Name).
+		SetInitialState(State(def.Initial_state)).
+		WithInitializer(definitionInitializer(def))
+
+	for _, name := range sortedKeys(def.Phases) {
+		builder.WithPhase(name, definitionPhaseOpts(def.Phases[name])...)
+	}
+
+	events := make(map[State][]Event)
+	for _, t := range def.Transitions {
+		builder.AddTransition(State(t.From), State(t.To), Event(t.Event), definitionTransitionOpts(def, t)...)
+
+		from := State(t.From)
+		if !containsEvent(events[from], Event(t.Event)) {
+			events[from] = append(events[from], Event(t.Event))
+		}
+	}
+
+	for name, stateDef := range def.States {
+		if stateDef.Agent != "" {
+			builder.WithAgent(State(name), stateDef.Agent)
+		}
+	}
+
+	for from, fromEvents := range events {
+		if len(fromEvents) != 1 {
+			continue
+		}
+		event := fromEvents[0]
+		builder.OnAdvance(from, func(_ *state.Project) (Event, error) {
+			return event, nil
+		})
+	}
+
+	return builder, nil
+}
+
+// DefinitionGuard returns the guard template for a guard definition.
+func DefinitionGuard(g schemas.GuardDefinition) (GuardTemplate, error) {
+	var guard GuardTemplate
+	switch g.Predicate {
+	case PredicateOutputApproved:
+		if g.Type == "" {
+			return GuardTemplate{}, fmt.Errorf("%s guard requires type", g.Predicate)
+		}
+		guard = OutputApprovedGuard(g.Phase, g.Type)
+	case PredicateAllTasksComplete:
+		guard = AllTasksCompleteGuard(g.Phase)
+	case PredicateMetadataBool:
+		if g.Key == "" {
+			return GuardTemplate{}, fmt.Errorf("%s guard requires key", g.Predicate)
+		}
+		value := true
+		if g.Value != nil {
+			value = *g.Value
+		}
+		guard = MetadataBoolGuard(g.Phase, g.Key, value)
+	default:
+		return GuardTemplate{}, fmt.Errorf("unknown guard predicate %q", g.Predicate)
+	}
+
+	if g.Description != "" {
+		guard.Description = g.Description
+	}
+	return guard, nil
+}
+
+// validateDefinition checks references that the CUE schema cannot express.
+func validateDefinition(def *schemas.ProjectTypeDefinition) []string {
+	var issues []string
+
+	usedStates := make(map[string]bool)
+	initialIsStart := false
+	for _, phase := range def.Phases {
+		usedStates[phase.Start_state] = true
+		usedStates[phase.End_state] = true
+		if phase.Start_state == def.Initial_state {
+			initialIsStart = true
+		}
+	}
+	if !initialIsStart {
+		issues = append(issues, fmt.Sprintf("initial state %q is not the start state of any phase", def.Initial_state))
+	}
+
+	for i, t := range def.Transitions {
+		usedStates[t.From] = true
+		usedStates[t.To] = true
+
+		if t.Failed_phase != "" {
+			if _, ok := def.Phases[t.Failed_phase]; !ok {
+				issues = append(issues, fmt.Sprintf("transition %d (%s): failed_phase %q is not a phase", i, t.Event, t.Failed_phase))
+			}
+		}
+		for _, g := range t.Guards {
+			if _, ok := def.Phases[g.Phase]; !ok {
+				issues = append(issues, fmt.Sprintf("transition %d (%s): guard phase %q is not a phase", i, t.Event, g.Phase))
+			}
+			if _, err := DefinitionGuard(g); err != nil {
+				issues = append(issues, fmt.Sprintf("transition %d (%s): %v", i, t.Event, err))
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(def.States) {
+		if !usedStates[name] {
+			issues = append(issues, fmt.Sprintf("state %q is not used by any phase or transition", name))
+		}
+	}
+
+	return issues
+}
+
+// definitionPhaseOpts translates a phase definition into phase options.
+func definitionPhaseOpts(phase schemas.PhaseDefinition) []PhaseOpt {
+	opts := []PhaseOpt{
+		WithStartState(State(phase.Start_state)),
+		WithEndState(State(phase.End_state)),
+	}
+	if len(phase.Inputs) > 0 {
+		opts = append(opts, WithInputs(phase.Inputs...))
+	}
+	if len(phase.Outputs) > 0 {
+		opts = append(opts, WithOutputs(phase.Outputs...))
+	}
+	if phase.Tasks {
+		opts = append(opts, WithTasks())
+	}
+	if phase.Metadata_schema != "" {
+		opts = append(opts, WithMetadataSchema(phase.Metadata_schema))
+	}
+	return opts
+}
+
+// definitionTransitionOpts translates a transition definition into
+// transition options. The definition must have been validated.
+func definitionTransitionOpts(def *schemas.ProjectTypeDefinition, t schemas.TransitionDefinition) []ProjectTransitionOption {
+	var opts []ProjectTransitionOption
+	if t.Description != "" {
+		opts = append(opts, WithProjectDescription(t.Description))
+	}
+	if t.Failed_phase != "" {
+		opts = append(opts, WithProjectFailedPhase(t.Failed_phase))
+	}
+
+	if len(t.Guards) > 0 {
+		guards := make([]GuardTemplate, 0, len(t.Guards))
+		for _, g := range t.Guards {
+			guard, _ := DefinitionGuard(g)
+			guards = append(guards, guard)
+		}
+		guard := AllGuards(guards...)
+		opts = append(opts, WithProjectGuardResult(guard.Description, guard.Evaluate))
+	}
+
+	// Enable the phases that start in the target state
+	var enable []string
+	for _, name := range sortedKeys(def.Phases) {
+		if def.Phases[name].Start_state == t.To {
+			enable = append(enable, name)
+		}
+	}
+	if len(enable) > 0 {
+		opts = append(opts, WithProjectOnEntry(func(p *state.Project) error {
+			for _, name := range enable {
+				phase := p.Phases[name]
+				phase.Enabled = true
+				p.Phases[name] = phase
+			}
+			return nil
+		}))
+	}
+
+	return opts
+}
+
+// definitionInitializer creates every phase of a new project. Phases that
+// start in the initial state begin in progress; the rest are pending.
+func definitionInitializer(def *schemas.ProjectTypeDefinition) Initializer {
+	return func(p *state.Project, initialInputs map[string][]projschema.ArtifactState) error {
+		now := p.Created_at
+		for name, phaseDef := range def.Phases {
+			inputs := initialInputs[name]
+			if inputs == nil {
+				inputs = []projschema.ArtifactState{}
+			}
+
+			phase := projschema.PhaseState{
+				Status:     "pending",
+				Enabled:    false,
+				Created_at: now,
+				Inputs:     inputs,
+				Outputs:    []projschema.ArtifactState{},
+				Tasks:      []projschema.TaskState{},
+				Metadata:   make(map[string]interface{}),
+			}
+			if phaseDef.Start_state == def.Initial_state {
+				phase.Status = "in_progress"
+				phase.Enabled = true
+				phase.Started_at = now
+			}
+			p.Phases[name] = phase
+		}
+		return nil
+	}
+}
+
+func containsEvent(events []Event, event Event) bool {
+	for _, e := range events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[K ~string, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i] < keys[j]
+	})
+	return keys
+}
@@ -0,0 +1,237 @@
+package project
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmgilman/sow/libs/project/state"
+	"github.com/jmgilman/sow/libs/schemas"
+	"github.com/jmgilman/sow/libs/schemas/project"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reviewDefinition is a two-phase declarative project type used by the
+// definition tests.
+const reviewDefinition = `
+name:          "review"
+description:   "Review an existing change"
+branch_prefix: "review/"
+initial_state: "Reviewing"
+phases: {
+	review: {
+		start_state: "Reviewing"
+		end_state:   "Reviewing"
+		outputs: ["report"]
+		metadata_schema: "{ approved?: bool }"
+	}
+	fixes: {
+		start_state: "Fixing"
+		end_state:   "Fixing"
+		tasks:       true
+	}
+}
+states: Reviewing: {
+	prompt: "reviewing.md"
+	agent:  "reviewer"
+}
+transitions: [{
+	from:  "Reviewing"
+	to:    "Fixing"
+	event: "start_fixes"
+	guards: [
+		{predicate: "output_approved", phase: "review", type: "report"},
+		{predicate: "metadata_bool", phase: "review", key: "approved"},
+	]
+}, {
+	from:  "Fixing"
+	to:    "Done"
+	event: "finish"
+	guards: [{predicate: "all_tasks_complete", phase: "fixes"}]
+}, {
+	from:         "Fixing"
+	to:           "Done"
+	event:        "abandon"
+	description:  "Give up on the fixes"
+	failed_phase: "fixes"
+}]
+`
+
+func parseReviewDefinition(t *testing.T) *schemas.ProjectTypeDefinition {
+	t.Helper()
+	def, err := ParseTypeDefinition("review.cue", []byte(reviewDefinition))
+	require.NoError(t, err)
+	return def
+}
+
+func TestParseTypeDefinition(t *testing.T) {
+	t.Parallel()
+
+	t.Run("decodes a valid definition", func(t *testing.T) {
+		t.Parallel()
+
+		def := parseReviewDefinition(t)
+
+		assert.Equal(t, "review", def.Name)
+		assert.Equal(t, "review/", def.Branch_prefix)
+		assert.Equal(t, "Reviewing", def.Initial_state)
+		require.Len(t, def.Phases, 2)
+		assert.Equal(t, []string{"report"}, def.Phases["review"].Outputs)
+		assert.True(t, def.Phases["fixes"].Tasks)
+		assert.Equal(t, "reviewing.md", def.States["Reviewing"].Prompt)
+		assert.Equal(t, "reviewer", def.States["Reviewing"].Agent)
+		require.Len(t, def.Transitions, 3)
+		assert.Equal(t, "approved", def.Transitions[0].Guards[1].Key)
+		assert.Nil(t, def.Transitions[0].Guards[1].Value)
+	})
+
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"syntax error", `name: "x`},
+		{"missing required field", `name: "x", phases: {}, transitions: []`},
+		{"unknown field", `name: "x", initial_state: "A", phases: {}, transitions: [], extra: 1`},
+		{"invalid name", `name: "X Y", initial_state: "A", phases: {}, transitions: []`},
+		{"unknown predicate", `
+name: "x"
+initial_state: "A"
+phases: {}
+transitions: [{from: "A", to: "B", event: "go", guards: [{predicate: "always", phase: "p"}]}]
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := ParseTypeDefinition("x.cue", []byte(tt.src))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "x.cue")
+		})
+	}
+}
+
+func TestNewDefinitionBuilder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("builds phases and transitions", func(t *testing.T) {
+		t.Parallel()
+
+		builder, err := NewDefinitionBuilder(parseReviewDefinition(t))
+		require.NoError(t, err)
+		config := builder.Build()
+
+		assert.Equal(t, "review", config.Name())
+		assert.Equal(t, "Reviewing", config.InitialState())
+		assert.Equal(t, "review", config.GetPhaseForState("Reviewing"))
+		assert.True(t, config.PhaseSupportsTasks("fixes"))
+		assert.False(t, config.PhaseSupportsTasks("review"))
+		assert.Equal(t, State("Done"), config.GetTargetState("Fixing", "finish"))
+		assert.Equal(t,
+			"report output approved in review phase and review metadata approved is true",
+			config.GetGuardDescription("Reviewing", "start_fixes"))
+		assert.Equal(t, "Give up on the fixes", config.GetTransitionDescription("Fixing", "abandon"))
+		assert.Equal(t, "reviewer", config.GetStateAgent("Reviewing"))
+		assert.Empty(t, config.GetStateAgent("Fixing"))
+	})
+
+	t.Run("determines events for states with one outgoing event", func(t *testing.T) {
+		t.Parallel()
+
+		builder, err := NewDefinitionBuilder(parseReviewDefinition(t))
+		require.NoError(t, err)
+		config := builder.Build()
+
+		proj := &state.Project{}
+		proj.Statechart.Current_state = "Reviewing"
+		event, err := config.DetermineEvent(proj)
+		require.NoError(t, err)
+		assert.Equal(t, Event("start_fixes"), event)
+
+		proj.Statechart.Current_state = "Fixing"
+		_, err = config.DetermineEvent(proj)
+		assert.Error(t, err)
+	})
+
+	t.Run("reports inconsistent definitions", func(t *testing.T) {
+		t.Parallel()
+
+		def := parseReviewDefinition(t)
+		def.Initial_state = "Nowhere"
+		def.Transitions[0].Guards[0].Phase = "missing"
+		def.Transitions[0].Guards[0].Type = ""
+		def.Transitions[2].Failed_phase = "missing"
+		def.States["Orphan"] = schemas.StateDefinition{}
+
+		_, err := NewDefinitionBuilder(def)
+
+		var validationErr *ErrConfigValidation
+		require.True(t, errors.As(err, &validationErr))
+		assert.Equal(t, []string{
+			`initial state "Nowhere" is not the start state of any phase`,
+			`transition 0 (start_fixes): guard phase "missing" is not a phase`,
+			`transition 0 (start_fixes): output_approved guard requires type`,
+			`transition 2 (abandon): failed_phase "missing" is not a phase`,
+			`state "Orphan" is not used by any phase or transition`,
+		}, validationErr.Issues)
+	})
+}
+
+func TestDefinitionInitializer(t *testing.T) {
+	t.Parallel()
+
+	builder, err := NewDefinitionBuilder(parseReviewDefinition(t))
+	require.NoError(t, err)
+	config := builder.Build()
+
+	proj := &state.Project{}
+	proj.Created_at = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	proj.Phases = map[string]project.PhaseState{}
+	inputs := map[string][]project.ArtifactState{
+		"review": {{Type: "context", Path: "pr.md"}},
+	}
+
+	require.NoError(t, config.Initialize(proj, inputs))
+
+	review := proj.Phases["review"]
+	assert.Equal(t, "in_progress", review.Status)
+	assert.True(t, review.Enabled)
+	assert.Equal(t, proj.Created_at, review.Started_at)
+	assert.Equal(t, inputs["review"], review.Inputs)
+
+	fixes := proj.Phases["fixes"]
+	assert.Equal(t, "pending", fixes.Status)
+	assert.False(t, fixes.Enabled)
+	assert.True(t, fixes.Started_at.IsZero())
+	assert.Empty(t, fixes.Inputs)
+}
+
+func TestDefinitionTransitions(t *testing.T) {
+	t.Parallel()
+
+	builder, err := NewDefinitionBuilder(parseReviewDefinition(t))
+	require.NoError(t, err)
+	config := builder.Build()
+
+	proj := &state.Project{}
+	proj.Phases = map[string]project.PhaseState{}
+	require.NoError(t, config.Initialize(proj, nil))
+	proj.Statechart.Current_state = "Reviewing"
+
+	machine := config.BuildProjectMachine(proj, "Reviewing")
+	assert.False(t, machine.CanFire("start_fixes"), "guards should block until the report is approved")
+
+	review := proj.Phases["review"]
+	review.Outputs = []project.ArtifactState{{Type: "report", Approved: true}}
+	review.Metadata["approved"] = true
+	proj.Phases["review"] = review
+
+	require.NoError(t, config.FireWithPhaseUpdates(machine, "start_fixes", proj))
+	assert.Equal(t, State("Fixing"), machine.State())
+	assert.Equal(t, "completed", proj.Phases["review"].Status)
+	assert.Equal(t, "in_progress", proj.Phases["fixes"].Status)
+	assert.True(t, proj.Phases["fixes"].Enabled)
+
+	require.NoError(t, config.FireWithPhaseUpdates(machine, "abandon", proj))
+	assert.Equal(t, "failed", proj.Phases["fixes"].Status)
+}
@@ -0,0 +1,156 @@
+package project
+
+import (
+	"sort"
+)
+
+// Graph is a static description of a project type's state machine: its
+// states, the phases grouping them, and every configured transition. It is
+// built from the configuration alone, without a project instance, and is
+// used to render diagrams of project types.
+type Graph struct {
+	// Type is the project type name.
+	Type string `json:"type"`
+
+	// InitialState is the state new projects start in.
+	InitialState State `json:"initial_state"`
+
+	// States lists every state named by a phase or transition, sorted.
+	States []State `json:"states"`
+
+	// Phases lists the phases, sorted by name.
+	Phases []GraphPhase `json:"phases"`
+
+	// Transitions lists every transition in configuration order.
+	Transitions []GraphTransition `json:"transitions"`
+}
+
+// GraphPhase describes a phase and the states it spans.
+type GraphPhase struct {
+	Name       string  `json:"name"`
+	StartState State   `json:"start_state"`
+	EndState   State   `json:"end_state"`
+	States     []State `json:"states"`
+}
+
+// GraphTransition describes one configured transition.
+type GraphTransition struct {
+	From        State  `json:"from"`
+	To          State  `json:"to"`
+	Event       Event  `json:"event"`
+	Description string `json:"description,omitempty"`
+	Guard       string `json:"guard,omitempty"`
+
+	// Branch is the discriminator value selecting this transition when it
+	// was added with AddBranch/When.
+	Branch string `json:"branch,omitempty"`
+
+	// FailedPhase is the phase marked failed when this transition fires.
+	FailedPhase string `json:"failed_phase,omitempty"`
+}
+
+// Graph returns the static state machine graph of the project type.
+//
+// A phase spans its start and end states plus the states between them:
+// those reachable from the start state without passing through the end
+// state or another phase's start or end state.
+func (ptc *ProjectTypeConfig) Graph() Graph {
+	g := Graph{
+		Type:         ptc.name,
+		InitialState: ptc.initialState,
+		Phases:       []GraphPhase{},
+		Transitions:  make([]GraphTransition, 0, len(ptc.transitions)),
+	}
+
+	states := map[State]bool{}
+	if ptc.initialState != "" {
+		states[ptc.initialState] = true
+	}
+
+	for _, tc := range ptc.transitions {
+		states[tc.From] = true
+		states[tc.To] = true
+
+		gt := GraphTransition{
+			From:        tc.From,
+			To:          tc.To,
+			Event:       tc.Event,
+			Description: tc.description,
+			Guard:       tc.guardTemplate.Description,
+			FailedPhase: tc.failedPhase,
+		}
+		if bc, ok := ptc.branches[tc.From]; ok {
+			for _, value := range sortedKeys(bc.branches) {
+				if path := bc.branches[value]; path.event == tc.Event && path.to == tc.To {
+					gt.Branch = value
+					break
+				}
+			}
+		}
+		g.Transitions = append(g.Transitions, gt)
+	}
+
+	// Phase boundaries stop the walk that collects a phase's inner states
+	boundaries := map[State]bool{}
+	for _, pc := range ptc.phaseConfigs {
+		states[pc.startState] = true
+		states[pc.endState] = true
+		boundaries[pc.startState] = true
+		boundaries[pc.endState] = true
+	}
+
+	for _, name := range sortedKeys(ptc.phaseConfigs) {
+		pc := ptc.phaseConfigs[name]
+		g.Phases = append(g.Phases, GraphPhase{
+			Name:       name,
+			StartState: pc.startState,
+			EndState:   pc.endState,
+			States:     ptc.phaseStates(pc, boundaries),
+		})
+	}
+
+	for s := range states {
+		if s != "" {
+			g.States = append(g.States, s)
+		}
+	}
+	sortStates(g.States)
+
+	return g
+}
+
+// phaseStates returns the states spanned by a phase, sorted.
+func (ptc *ProjectTypeConfig) phaseStates(pc *PhaseConfig, boundaries map[State]bool) []State {
+	inPhase := map[State]bool{pc.startState: true, pc.endState: true}
+
+	queue := []State{pc.startState}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == pc.endState {
+			continue
+		}
+		for _, tc := range ptc.transitions {
+			if tc.From != current || inPhase[tc.To] || boundaries[tc.To] {
+				continue
+			}
+			inPhase[tc.To] = true
+			queue = append(queue, tc.To)
+		}
+	}
+
+	result := make([]State, 0, len(inPhase))
+	for s := range inPhase {
+		if s != "" {
+			result = append(result, s)
+		}
+	}
+	sortStates(result)
+	return result
+}
+
+func sortStates(states []State) {
+	sort.Slice(states, func(i, j int) bool {
+		return states[i] < states[j]
+	})
+}
@@ -0,0 +1,67 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/jmgilman/sow/libs/project/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newGraphTestConfig builds a small type with an inner phase state, a
+// branch, and a transition leaving the machine.
+func newGraphTestConfig() *ProjectTypeConfig {
+	return NewProjectTypeConfigBuilder("graph").
+		SetInitialState("Planning").
+		WithPhase("planning", WithStartState("Planning"), WithEndState("Planning")).
+		WithPhase("implementation", WithStartState("Drafting"), WithEndState("Executing")).
+		WithPhase("review", WithStartState("Reviewing"), WithEndState("Reviewing")).
+		AddTransition("Planning", "Drafting", "plan_done",
+			WithProjectGuard("plan approved", func(*state.Project) bool { return true }),
+			WithProjectDescription("Start implementation"),
+		).
+		AddTransition("Drafting", "Building", "draft_done").
+		AddTransition("Building", "Executing", "build_done").
+		AddTransition("Executing", "Reviewing", "tasks_done").
+		AddBranch("Reviewing",
+			BranchOn(func(*state.Project) string { return "" }),
+			When("pass", "review_pass", NoProject),
+			When("fail", "review_fail", "Drafting", WithProjectFailedPhase("review")),
+		).
+		Build()
+}
+
+func TestProjectTypeConfig_Graph(t *testing.T) {
+	t.Parallel()
+
+	g := newGraphTestConfig().Graph()
+
+	assert.Equal(t, "graph", g.Type)
+	assert.Equal(t, State("Planning"), g.InitialState)
+	assert.Equal(t, []State{"Building", "Drafting", "Executing", NoProject, "Planning", "Reviewing"}, g.States)
+
+	require.Len(t, g.Phases, 3)
+	assert.Equal(t, "implementation", g.Phases[0].Name)
+	assert.Equal(t, []State{"Building", "Drafting", "Executing"}, g.Phases[0].States)
+	assert.Equal(t, []State{"Planning"}, g.Phases[1].States)
+	assert.Equal(t, []State{"Reviewing"}, g.Phases[2].States)
+
+	require.Len(t, g.Transitions, 6)
+	assert.Equal(t, GraphTransition{
+		From:        "Planning",
+		To:          "Drafting",
+		Event:       "plan_done",
+		Description: "Start implementation",
+		Guard:       "plan approved",
+	}, g.Transitions[0])
+
+	// Branch transitions follow in value order
+	assert.Equal(t, GraphTransition{
+		From:        "Reviewing",
+		To:          "Drafting",
+		Event:       "review_fail",
+		Branch:      "fail",
+		FailedPhase: "review",
+	}, g.Transitions[4])
+	assert.Equal(t, "pass", g.Transitions[5].Branch)
+}
@@ -0,0 +1,118 @@
+package project
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jmgilman/sow/libs/project/state"
+)
+
+// Built-in guard predicates. Declarative project types (see
+// NewDefinitionBuilder) express their guards with these, and Go project
+// types can use them in place of hand-written guard functions. Each guard
+// reports its met and unmet conditions through GuardTemplate.Evaluate.
+
+// OutputApprovedGuard returns a guard that passes when the phase has an
+// approved output artifact of the given type.
+func OutputApprovedGuard(phaseName, outputType string) GuardTemplate {
+	description := fmt.Sprintf("%s output approved in %s phase", outputType, phaseName)
+	return newGuardTemplate(description, func(p *state.Project) GuardResult {
+		var result GuardResult
+		phase, exists := p.Phases[phaseName]
+		if !exists {
+			result.Addf("%s phase not found", phaseName)
+			return result
+		}
+
+		found := false
+		for _, output := range phase.Outputs {
+			if output.Type != outputType {
+				continue
+			}
+			if output.Approved {
+				return result
+			}
+			found = true
+		}
+		if found {
+			result.Addf("%s output not approved in %s phase", outputType, phaseName)
+		} else {
+			result.Addf("no %s output in %s phase", outputType, phaseName)
+		}
+		return result
+	})
+}
+
+// AllTasksCompleteGuard returns a guard that passes when the phase has at
+// least one task and every task is completed or abandoned.
+func AllTasksCompleteGuard(phaseName string) GuardTemplate {
+	description := fmt.Sprintf("all %s tasks complete", phaseName)
+	return newGuardTemplate(description, func(p *state.Project) GuardResult {
+		var result GuardResult
+		phase, exists := p.Phases[phaseName]
+		if !exists {
+			result.Addf("%s phase not found", phaseName)
+			return result
+		}
+		if len(phase.Tasks) == 0 {
+			result.Addf("no tasks in %s phase", phaseName)
+			return result
+		}
+		for _, task := range phase.Tasks {
+			if task.Status != "completed" && task.Status != "abandoned" {
+				result.Addf("task %s not complete (%s)", task.Id, task.Status)
+			}
+		}
+		if result.Passed() {
+			result.Metf("all %d %s tasks complete", len(phase.Tasks), phaseName)
+		}
+		return result
+	})
+}
+
+// MetadataBoolGuard returns a guard that passes when the phase metadata
+// holds the boolean value under key. A missing key or non-boolean value
+// never passes.
+func MetadataBoolGuard(phaseName, key string, value bool) GuardTemplate {
+	description := fmt.Sprintf("%s metadata %s is %t", phaseName, key, value)
+	return newGuardTemplate(description, func(p *state.Project) GuardResult {
+		var result GuardResult
+		phase, exists := p.Phases[phaseName]
+		if !exists {
+			result.Addf("%s phase not found", phaseName)
+			return result
+		}
+
+		raw, ok := phase.Metadata[key]
+		if !ok {
+			result.Addf("%s metadata %s not set", phaseName, key)
+			return result
+		}
+		if actual, ok := raw.(bool); !ok || actual != value {
+			result.Addf("%s metadata %s is %v, want %t", phaseName, key, raw, value)
+		}
+		return result
+	})
+}
+
+// AllGuards combines guards into one that passes only when every guard
+// passes. The description joins the individual descriptions with "and",
+// and the result lists the conditions of every guard.
+func AllGuards(guards ...GuardTemplate) GuardTemplate {
+	if len(guards) == 1 {
+		return guards[0]
+	}
+
+	descriptions := make([]string, len(guards))
+	for i, g := range guards {
+		descriptions[i] = g.Description
+	}
+
+	return newGuardTemplate(strings.Join(descriptions, " and "), func(p *state.Project) GuardResult {
+		var result GuardResult
+		for _, g := range guards {
+			result.Merge(g.Evaluate(p))
+		}
+		return result
+	})
+}
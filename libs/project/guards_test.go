@@ -0,0 +1,182 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/jmgilman/sow/libs/project/state"
+	"github.com/jmgilman/sow/libs/schemas/project"
+	"github.com/stretchr/testify/assert"
+)
+
+// newGuardTestProject creates a project with a single "review" phase.
+func newGuardTestProject(phase project.PhaseState) *state.Project {
+	proj := &state.Project{}
+	proj.Phases = map[string]project.PhaseState{"review": phase}
+	return proj
+}
+
+func TestOutputApprovedGuard(t *testing.T) {
+	t.Parallel()
+
+	guard := OutputApprovedGuard("review", "report")
+	assert.Equal(t, "report output approved in review phase", guard.Description)
+
+	tests := []struct {
+		name    string
+		outputs []project.ArtifactState
+		want    bool
+	}{
+		{"no outputs", nil, false},
+		{"unapproved output", []project.ArtifactState{{Type: "report"}}, false},
+		{"approved output of another type", []project.ArtifactState{{Type: "notes", Approved: true}}, false},
+		{"approved output", []project.ArtifactState{{Type: "notes"}, {Type: "report", Approved: true}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			proj := newGuardTestProject(project.PhaseState{Outputs: tt.outputs})
+			assert.Equal(t, tt.want, guard.Func(proj))
+		})
+	}
+
+	t.Run("missing phase", func(t *testing.T) {
+		t.Parallel()
+		assert.False(t, OutputApprovedGuard("other", "report").Func(newGuardTestProject(project.PhaseState{})))
+	})
+}
+
+func TestAllTasksCompleteGuard(t *testing.T) {
+	t.Parallel()
+
+	guard := AllTasksCompleteGuard("review")
+	assert.Equal(t, "all review tasks complete", guard.Description)
+
+	tests := []struct {
+		name  string
+		tasks []project.TaskState
+		want  bool
+	}{
+		{"no tasks", nil, false},
+		{"pending task", []project.TaskState{{Status: "completed"}, {Status: "pending"}}, false},
+		{"completed and abandoned", []project.TaskState{{Status: "completed"}, {Status: "abandoned"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			proj := newGuardTestProject(project.PhaseState{Tasks: tt.tasks})
+			assert.Equal(t, tt.want, guard.Func(proj))
+		})
+	}
+}
+
+func TestMetadataBoolGuard(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		value    bool
+		metadata map[string]interface{}
+		want     bool
+	}{
+		{"missing key", true, nil, false},
+		{"non-boolean value", true, map[string]interface{}{"approved": "yes"}, false},
+		{"matching true", true, map[string]interface{}{"approved": true}, true},
+		{"mismatched value", true, map[string]interface{}{"approved": false}, false},
+		{"matching false", false, map[string]interface{}{"approved": false}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			proj := newGuardTestProject(project.PhaseState{Metadata: tt.metadata})
+			assert.Equal(t, tt.want, MetadataBoolGuard("review", "approved", tt.value).Func(proj))
+		})
+	}
+}
+
+func TestAllGuards(t *testing.T) {
+	t.Parallel()
+
+	pass := GuardTemplate{Description: "a", Func: func(_ *state.Project) bool { return true }}
+	fail := GuardTemplate{Description: "b", Func: func(_ *state.Project) bool { return false }}
+
+	t.Run("single guard is returned unchanged", func(t *testing.T) {
+		t.Parallel()
+		guard := AllGuards(pass)
+		assert.Equal(t, "a", guard.Description)
+		assert.True(t, guard.Func(nil))
+	})
+
+	t.Run("joins descriptions", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "a and b and a", AllGuards(pass, fail, pass).Description)
+	})
+
+	t.Run("passes only when all pass", func(t *testing.T) {
+		t.Parallel()
+		assert.True(t, AllGuards(pass, pass).Func(nil))
+		assert.False(t, AllGuards(pass, fail).Func(nil))
+	})
+}
+
+func TestGuards_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	proj := newGuardTestProject(project.PhaseState{
+		Outputs: []project.ArtifactState{{Type: "report"}},
+		Tasks: []project.TaskState{
+			{Id: "010", Status: "completed"},
+			{Id: "020", Status: "in_progress"},
+		},
+		Metadata: map[string]interface{}{"approved": "yes"},
+	})
+
+	tests := []struct {
+		name  string
+		guard GuardTemplate
+		want  []string
+	}{
+		{"output not approved", OutputApprovedGuard("review", "report"), []string{"report output not approved in review phase"}},
+		{"output missing", OutputApprovedGuard("review", "notes"), []string{"no notes output in review phase"}},
+		{"incomplete tasks", AllTasksCompleteGuard("review"), []string{"task 020 not complete (in_progress)"}},
+		{"metadata mismatch", MetadataBoolGuard("review", "approved", true), []string{"review metadata approved is yes, want true"}},
+		{"metadata missing", MetadataBoolGuard("review", "done", true), []string{"review metadata done not set"}},
+		{"missing phase", AllTasksCompleteGuard("other"), []string{"other phase not found"}},
+		{
+			"combined",
+			AllGuards(OutputApprovedGuard("review", "report"), AllTasksCompleteGuard("review")),
+			[]string{"report output not approved in review phase", "task 020 not complete (in_progress)"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.guard.Evaluate(proj).Unmet)
+		})
+	}
+}
+
+func TestGuards_EvaluateMet(t *testing.T) {
+	t.Parallel()
+
+	proj := newGuardTestProject(project.PhaseState{
+		Outputs: []project.ArtifactState{{Type: "report", Approved: true}},
+		Tasks: []project.TaskState{
+			{Id: "010", Status: "completed"},
+			{Id: "020", Status: "abandoned"},
+		},
+		Metadata: map[string]interface{}{"approved": true},
+	})
+
+	guard := AllGuards(
+		OutputApprovedGuard("review", "report"),
+		AllTasksCompleteGuard("review"),
+		MetadataBoolGuard("review", "approved", true),
+	)
+	result := guard.Evaluate(proj)
+	assert.True(t, result.Passed())
+	assert.Equal(t, []string{
+		"report output approved in review phase",
+		"all 2 review tasks complete",
+		"review metadata approved is true",
+	}, result.Met)
+}
@@ -0,0 +1,175 @@
+package project
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Lint rules reported by ProjectTypeConfig.Lint.
+const (
+	// LintUnreachableState reports states no transition path from the
+	// initial state reaches.
+	LintUnreachableState = "unreachable-state"
+
+	// LintDeadEnd reports phase states without outgoing transitions. States
+	// outside every phase, such as NoProject, are terminal and exempt.
+	LintDeadEnd = "dead-end"
+
+	// LintNondeterministicEvent reports events that have more than one
+	// transition from the same state.
+	LintNondeterministicEvent = "nondeterministic-event"
+
+	// LintMissingDeterminer reports states with several outgoing events but
+	// no OnAdvance determiner, so sow advance cannot pick one.
+	LintMissingDeterminer = "missing-determiner"
+
+	// LintBranchWithoutPath reports branches whose discriminator values
+	// cannot lead anywhere.
+	LintBranchWithoutPath = "branch-without-path"
+
+	// LintPhaseNeverEntered reports phases whose start state is unreachable.
+	LintPhaseNeverEntered = "phase-never-entered"
+)
+
+// LintIssue is a problem found by static analysis of a project type.
+type LintIssue struct {
+	// Rule is the lint rule that reported the issue.
+	Rule string `json:"rule"`
+
+	// State is the state the issue is about, if any.
+	State State `json:"state,omitempty"`
+
+	// Message describes the issue.
+	Message string `json:"message"`
+}
+
+// String returns the issue as "rule: message".
+func (i LintIssue) String() string {
+	return fmt.Sprintf("%s: %s", i.Rule, i.Message)
+}
+
+// Lint checks the project type's state machine for modeling mistakes that
+// BuildWithValidation cannot see because they depend on the shape of the
+// whole graph. Guards are ignored: a transition counts as a path even if
+// its guard can never pass.
+//
+// Issues are grouped by rule, in the order the Lint* constants are
+// declared, and deterministically ordered within a rule.
+func (ptc *ProjectTypeConfig) Lint() []LintIssue {
+	g := ptc.Graph()
+	reachable := g.reachableStates()
+
+	phaseStarts := map[State]string{}
+	inPhase := map[State]bool{}
+	for _, phase := range g.Phases {
+		if phase.StartState != "" {
+			phaseStarts[phase.StartState] = phase.Name
+		}
+		for _, s := range phase.States {
+			inPhase[s] = true
+		}
+	}
+
+	outgoing := map[State][]GraphTransition{}
+	for _, t := range g.Transitions {
+		outgoing[t.From] = append(outgoing[t.From], t)
+	}
+
+	var issues []LintIssue
+	add := func(rule string, s State, format string, args ...interface{}) {
+		issues = append(issues, LintIssue{Rule: rule, State: s, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, s := range g.States {
+		if _, isStart := phaseStarts[s]; !reachable[s] && !isStart {
+			add(LintUnreachableState, s, "state %s is not reachable from initial state %s", s, g.InitialState)
+		}
+	}
+
+	for _, s := range g.States {
+		if inPhase[s] && len(outgoing[s]) == 0 {
+			add(LintDeadEnd, s, "state %s belongs to a phase but has no outgoing transitions", s)
+		}
+	}
+
+	for _, s := range g.States {
+		targets := map[Event][]string{}
+		for _, t := range outgoing[s] {
+			targets[t.Event] = append(targets[t.Event], string(t.To))
+		}
+		for _, event := range sortedKeys(targets) {
+			if len(targets[event]) > 1 {
+				add(LintNondeterministicEvent, s, "event %s from state %s has %d transitions (to %s)",
+					event, s, len(targets[event]), strings.Join(targets[event], ", "))
+			}
+		}
+	}
+
+	for _, s := range g.States {
+		events := map[Event]bool{}
+		for _, t := range outgoing[s] {
+			events[t.Event] = true
+		}
+		if _, ok := ptc.onAdvance[s]; !ok && len(events) > 1 {
+			add(LintMissingDeterminer, s, "state %s has %d outgoing events but no OnAdvance determiner", s, len(events))
+		}
+	}
+
+	for _, from := range sortedKeys(ptc.branches) {
+		issues = append(issues, ptc.branches[from].lint()...)
+	}
+
+	for _, phase := range g.Phases {
+		if phase.StartState != "" && !reachable[phase.StartState] {
+			add(LintPhaseNeverEntered, phase.StartState, "phase %s is never entered: start state %s is not reachable from initial state %s",
+				phase.Name, phase.StartState, g.InitialState)
+		}
+	}
+
+	return issues
+}
+
+// lint reports branch values that cannot lead anywhere.
+func (bc *BranchConfig) lint() []LintIssue {
+	issue := func(format string, args ...interface{}) LintIssue {
+		return LintIssue{Rule: LintBranchWithoutPath, State: bc.from, Message: fmt.Sprintf(format, args...)}
+	}
+
+	if len(bc.branches) == 0 {
+		return []LintIssue{issue("branch in state %s has no When values", bc.from)}
+	}
+
+	var issues []LintIssue
+	if bc.discriminator == nil {
+		issues = append(issues, issue("branch in state %s has no discriminator (use BranchOn), so no value is ever selected", bc.from))
+	}
+	for _, value := range sortedKeys(bc.branches) {
+		path := bc.branches[value]
+		if path.event == "" || path.to == "" {
+			issues = append(issues, issue("branch value %q in state %s has no event or target state", value, bc.from))
+		}
+	}
+	return issues
+}
+
+// reachableStates returns the states reachable from the initial state.
+func (g Graph) reachableStates() map[State]bool {
+	reachable := map[State]bool{}
+	if g.InitialState == "" {
+		return reachable
+	}
+
+	reachable[g.InitialState] = true
+	queue := []State{g.InitialState}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, t := range g.Transitions {
+			if t.From == current && !reachable[t.To] {
+				reachable[t.To] = true
+				queue = append(queue, t.To)
+			}
+		}
+	}
+	return reachable
+}
@@ -0,0 +1,101 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/jmgilman/sow/libs/project/state"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectTypeConfig_Lint(t *testing.T) {
+	t.Parallel()
+
+	t.Run("clean configuration", func(t *testing.T) {
+		t.Parallel()
+		assert.Empty(t, newGraphTestConfig().Lint())
+	})
+
+	t.Run("reports every rule", func(t *testing.T) {
+		t.Parallel()
+
+		config := NewProjectTypeConfigBuilder("broken").
+			SetInitialState("Planning").
+			WithPhase("planning", WithStartState("Planning"), WithEndState("Planning")).
+			WithPhase("implementation", WithStartState("Executing"), WithEndState("Executing")).
+			WithPhase("review", WithStartState("Reviewing"), WithEndState("Reviewing")).
+			WithPhase("orphaned", WithStartState("Orphaned"), WithEndState("Orphaned")).
+			AddTransition("Planning", "Executing", "plan_done").
+			AddTransition("Planning", "Reviewing", "plan_done").
+			AddTransition("Planning", NoProject, "abandon").
+			AddTransition("Orphaned", "Limbo", "go").
+			AddBranch("Executing",
+				When("pass", "", "Reviewing"),
+			).
+			AddBranch("Reviewing").
+			Build()
+
+		assert.Equal(t, []LintIssue{
+			{
+				Rule:    LintUnreachableState,
+				State:   "Limbo",
+				Message: "state Limbo is not reachable from initial state Planning",
+			},
+			{
+				Rule:    LintDeadEnd,
+				State:   "Reviewing",
+				Message: "state Reviewing belongs to a phase but has no outgoing transitions",
+			},
+			{
+				Rule:    LintNondeterministicEvent,
+				State:   "Planning",
+				Message: "event plan_done from state Planning has 2 transitions (to Executing, Reviewing)",
+			},
+			{
+				Rule:    LintMissingDeterminer,
+				State:   "Planning",
+				Message: "state Planning has 2 outgoing events but no OnAdvance determiner",
+			},
+			{
+				Rule:    LintBranchWithoutPath,
+				State:   "Executing",
+				Message: "branch in state Executing has no discriminator (use BranchOn), so no value is ever selected",
+			},
+			{
+				Rule:    LintBranchWithoutPath,
+				State:   "Executing",
+				Message: `branch value "pass" in state Executing has no event or target state`,
+			},
+			{
+				Rule:    LintBranchWithoutPath,
+				State:   "Reviewing",
+				Message: "branch in state Reviewing has no When values",
+			},
+			{
+				Rule:    LintPhaseNeverEntered,
+				State:   "Orphaned",
+				Message: "phase orphaned is never entered: start state Orphaned is not reachable from initial state Planning",
+			},
+		}, config.Lint())
+	})
+
+	t.Run("determiners satisfy states with several events", func(t *testing.T) {
+		t.Parallel()
+
+		config := NewProjectTypeConfigBuilder("determined").
+			SetInitialState("Working").
+			WithPhase("work", WithStartState("Working"), WithEndState("Working")).
+			AddTransition("Working", NoProject, "finish").
+			AddTransition("Working", NoProject, "abandon").
+			OnAdvance("Working", func(*state.Project) (Event, error) { return "finish", nil }).
+			Build()
+
+		assert.Empty(t, config.Lint())
+	})
+}
+
+func TestLintIssue_String(t *testing.T) {
+	t.Parallel()
+
+	issue := LintIssue{Rule: LintDeadEnd, State: "A", Message: "state A is stuck"}
+	assert.Equal(t, "dead-end: state A is stuck", issue.String())
+}
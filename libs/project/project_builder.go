@@ -15,6 +15,7 @@ type ProjectTypeConfigBuilder struct {
 	transitions        []TransitionConfig
 	onAdvance          map[State]EventDeterminer
 	prompts            map[State]PromptGenerator
+	agents             map[State]string
 	orchestratorPrompt PromptGenerator
 	initializer        Initializer
 	branches           map[State]*BranchConfig
@@ -27,6 +28,7 @@ func NewProjectTypeConfigBuilder(name string) *ProjectTypeConfigBuilder {
 		phaseConfigs: make(map[string]*PhaseConfig),
 		onAdvance:    make(map[State]EventDeterminer),
 		prompts:      make(map[State]PromptGenerator),
+		agents:       make(map[State]string),
 		branches:     make(map[State]*BranchConfig),
 	}
 }
@@ -82,8 +84,10 @@ func (b *ProjectTypeConfigBuilder) AddBranch(from State, opts ...BranchOption) *
 	// Store the branch config
 	b.branches[from] = bc
 
-	// Generate transitions from branch paths
-	for _, path := range bc.branches {
+	// Generate transitions from branch paths, ordered by value so that
+	// introspection output is deterministic
+	for _, value := range sortedKeys(bc.branches) {
+		path := bc.branches[value]
 		tc := TransitionConfig{
 			From:          from,
 			To:            path.to,
@@ -117,6 +121,13 @@ func (b *ProjectTypeConfigBuilder) WithPrompt(state State, gen PromptGenerator)
 	return b
 }
 
+// WithAgent binds the agent that does the work of a state that has no
+// tasks to run, such as a planner or reviewer. 'sow run' spawns it there.
+func (b *ProjectTypeConfigBuilder) WithAgent(state State, agent string) *ProjectTypeConfigBuilder {
+	b.agents[state] = agent
+	return b
+}
+
 // WithOrchestratorPrompt sets the orchestrator prompt generator.
 func (b *ProjectTypeConfigBuilder) WithOrchestratorPrompt(gen PromptGenerator) *ProjectTypeConfigBuilder {
 	b.orchestratorPrompt = gen
@@ -155,6 +166,11 @@ func (b *ProjectTypeConfigBuilder) build() (*ProjectTypeConfig, error) {
 		prompts[k] = v
 	}
 
+	agents := make(map[State]string, len(b.agents))
+	for k, v := range b.agents {
+		agents[k] = v
+	}
+
 	branches := make(map[State]*BranchConfig, len(b.branches))
 	for k, v := range b.branches {
 		branches[k] = v
@@ -171,6 +187,7 @@ func (b *ProjectTypeConfigBuilder) build() (*ProjectTypeConfig, error) {
 		transitions:        transitions,
 		onAdvance:          onAdvance,
 		prompts:            prompts,
+		agents:             agents,
 		orchestratorPrompt: b.orchestratorPrompt,
 		initializer:        b.initializer,
 		branches:           branches,
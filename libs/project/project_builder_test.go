@@ -370,6 +370,30 @@ func TestProjectTypeConfigBuilder_WithPrompt(t *testing.T) {
 	})
 }
 
+func TestProjectTypeConfigBuilder_WithAgent(t *testing.T) {
+	t.Parallel()
+
+	t.Run("binds agent to state", func(t *testing.T) {
+		t.Parallel()
+
+		config := NewProjectTypeConfigBuilder("test").
+			SetInitialState(builderTestStatePlanningActive).
+			WithAgent(builderTestStatePlanningActive, "planner").
+			Build()
+
+		assert.Equal(t, "planner", config.GetStateAgent(string(builderTestStatePlanningActive)))
+		assert.Empty(t, config.GetStateAgent("Unbound"))
+	})
+
+	t.Run("returns builder for chaining", func(t *testing.T) {
+		t.Parallel()
+
+		builder := NewProjectTypeConfigBuilder("test")
+
+		assert.Same(t, builder, builder.WithAgent(builderTestStatePlanningActive, "planner"))
+	})
+}
+
 func TestProjectTypeConfigBuilder_WithOrchestratorPrompt(t *testing.T) {
 	t.Parallel()
 
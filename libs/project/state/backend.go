@@ -18,6 +18,13 @@ type Backend interface {
 	// Save writes project state to storage.
 	// Takes the raw ProjectState (CUE-generated type).
 	// Implementation should handle atomic writes where possible.
+	//
+	// Save is a compare-and-swap: it only succeeds if state.Revision matches
+	// the revision currently in storage (zero when nothing is stored).
+	// Otherwise it returns a *ConflictError and leaves storage untouched.
+	// On success the stored revision is incremented and state.Revision is
+	// updated to the new value. A nil state is rejected with
+	// ErrInvalidState.
 	Save(ctx context.Context, state *project.ProjectState) error
 
 	// Exists checks if a project exists in storage.
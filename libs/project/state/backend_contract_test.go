@@ -0,0 +1,347 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jmgilman/go/fs/billy"
+	"github.com/jmgilman/sow/libs/schemas/project"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// backendFactory creates a fresh, empty backend for a contract test.
+type backendFactory func(t *testing.T) Backend
+
+// contractBackends lists every Backend implementation that must satisfy the
+// shared contract. New backends should be added here.
+func contractBackends() map[string]backendFactory {
+	return map[string]backendFactory{
+		"yaml": func(t *testing.T) Backend {
+			t.Helper()
+			memFS := billy.NewMemory()
+			require.NoError(t, memFS.MkdirAll("project", 0755))
+			return NewYAMLBackend(memFS)
+		},
+		"memory": func(t *testing.T) Backend {
+			t.Helper()
+			return NewMemoryBackend()
+		},
+		"sqlite": func(t *testing.T) Backend {
+			t.Helper()
+			return NewSQLiteBackend(filepath.Join(t.TempDir(), "project", "state.db"))
+		},
+		"http": func(t *testing.T) Backend {
+			t.Helper()
+			return newHTTPTestBackend(t, "feat/contract")
+		},
+	}
+}
+
+// contractState returns a fully populated project state.
+// Timestamps are UTC so they compare equal after a serialization round trip.
+func contractState() *project.ProjectState {
+	created := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	started := created.Add(time.Hour)
+	completed := created.Add(2 * time.Hour)
+
+	return &project.ProjectState{
+		Name:        "contract-test",
+		Type:        "standard",
+		Branch:      "feat/contract",
+		Description: "Backend contract test project",
+		Created_at:  created,
+		Updated_at:  completed,
+		Phases: map[string]project.PhaseState{
+			"implementation": {
+				Status:       "completed",
+				Enabled:      true,
+				Created_at:   created,
+				Started_at:   started,
+				Completed_at: completed,
+				Iteration:    2,
+				Metadata: map[string]interface{}{
+					"planning_approved": true,
+					"tasks_total":       3,
+					"nested": map[string]interface{}{
+						"key": "value",
+					},
+				},
+				Inputs: []project.ArtifactState{
+					{
+						Type:       "review",
+						Path:       "phases/review/review.md",
+						Approved:   true,
+						Created_at: created,
+						Metadata:   map[string]interface{}{"assessment": "fail"},
+					},
+				},
+				Outputs: []project.ArtifactState{
+					{
+						Type:       "task_list",
+						Path:       "phases/implementation/tasks.md",
+						Approved:   true,
+						Created_at: started,
+						Metadata:   map[string]interface{}{},
+					},
+				},
+				Tasks: []project.TaskState{
+					{
+						Id:             "020",
+						Name:           "Second task listed first",
+						Phase:          "implementation",
+						Status:         "in_progress",
+						Created_at:     created,
+						Started_at:     started,
+						Updated_at:     started,
+						Iteration:      1,
+						Assigned_agent: "implementer",
+						Session_id:     "sess-123",
+						Depends_on:     []string{"010"},
+						Inputs: []project.ArtifactState{
+							{
+								Type:       "reference",
+								Path:       "context/spec.md",
+								Created_at: created,
+								Metadata:   map[string]interface{}{},
+							},
+						},
+						Outputs:  []project.ArtifactState{},
+						Metadata: map[string]interface{}{"files_modified": []interface{}{"a.go", "b.go"}},
+					},
+					{
+						Id:             "010",
+						Name:           "First task",
+						Phase:          "implementation",
+						Status:         "completed",
+						Created_at:     created,
+						Started_at:     started,
+						Updated_at:     completed,
+						Completed_at:   completed,
+						Iteration:      3,
+						Assigned_agent: "implementer",
+						Depends_on:     []string{},
+						Inputs:         []project.ArtifactState{},
+						Outputs: []project.ArtifactState{
+							{
+								Type:       "modified",
+								Path:       "src/main.go",
+								Created_at: completed,
+								Metadata:   map[string]interface{}{},
+							},
+						},
+						Metadata: map[string]interface{}{},
+					},
+				},
+			},
+			"review": {
+				Status:     "pending",
+				Created_at: created,
+				Metadata:   map[string]interface{}{},
+				Inputs:     []project.ArtifactState{},
+				Outputs:    []project.ArtifactState{},
+				Tasks:      []project.TaskState{},
+			},
+		},
+		Statechart: project.StatechartState{
+			Current_state: "ImplementationExecuting",
+			Updated_at:    completed,
+		},
+		Agent_sessions: map[string]string{
+			"planner":  "sess-001",
+			"reviewer": "sess-002",
+		},
+	}
+}
+
+// TestBackendContract runs the shared Backend contract against every implementation.
+func TestBackendContract(t *testing.T) {
+	for name, newBackend := range contractBackends() {
+		t.Run(name, func(t *testing.T) {
+			runBackendContract(t, newBackend)
+		})
+	}
+}
+
+func runBackendContract(t *testing.T, newBackend backendFactory) {
+	t.Helper()
+	ctx := context.Background()
+
+	t.Run("Load on empty backend returns ErrNotFound", func(t *testing.T) {
+		backend := newBackend(t)
+
+		_, err := backend.Load(ctx)
+
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrNotFound))
+	})
+
+	t.Run("Exists on empty backend returns false", func(t *testing.T) {
+		backend := newBackend(t)
+
+		exists, err := backend.Exists(ctx)
+
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("Exists returns true after Save", func(t *testing.T) {
+		backend := newBackend(t)
+		require.NoError(t, backend.Save(ctx, contractState()))
+
+		exists, err := backend.Exists(ctx)
+
+		require.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("round trip preserves all fields", func(t *testing.T) {
+		backend := newBackend(t)
+		original := contractState()
+		require.NoError(t, backend.Save(ctx, original))
+
+		loaded, err := backend.Load(ctx)
+
+		require.NoError(t, err)
+		expected := contractState()
+		expected.Revision = 1
+		assert.Equal(t, expected, loaded)
+	})
+
+	t.Run("Save replaces existing state", func(t *testing.T) {
+		backend := newBackend(t)
+		require.NoError(t, backend.Save(ctx, contractState()))
+
+		updated := contractState()
+		updated.Revision = 1
+		updated.Description = "Updated"
+		updated.Statechart.Current_state = "ReviewActive"
+		impl := updated.Phases["implementation"]
+		impl.Tasks = impl.Tasks[1:]
+		impl.Outputs = []project.ArtifactState{}
+		updated.Phases["implementation"] = impl
+		delete(updated.Phases, "review")
+		delete(updated.Agent_sessions, "planner")
+		require.NoError(t, backend.Save(ctx, updated))
+
+		loaded, err := backend.Load(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Updated", loaded.Description)
+		assert.Equal(t, "ReviewActive", loaded.Statechart.Current_state)
+		require.Len(t, loaded.Phases, 1)
+		require.Len(t, loaded.Phases["implementation"].Tasks, 1)
+		assert.Equal(t, "010", loaded.Phases["implementation"].Tasks[0].Id)
+		assert.Empty(t, loaded.Phases["implementation"].Outputs)
+		assert.Equal(t, map[string]string{"reviewer": "sess-002"}, loaded.Agent_sessions)
+	})
+
+	t.Run("Save increments revision", func(t *testing.T) {
+		backend := newBackend(t)
+		state := contractState()
+
+		require.NoError(t, backend.Save(ctx, state))
+		assert.Equal(t, int64(1), state.Revision)
+
+		require.NoError(t, backend.Save(ctx, state))
+		assert.Equal(t, int64(2), state.Revision)
+
+		loaded, err := backend.Load(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), loaded.Revision)
+	})
+
+	t.Run("Save with stale revision returns ErrConflict", func(t *testing.T) {
+		backend := newBackend(t)
+		require.NoError(t, backend.Save(ctx, contractState()))
+
+		first, err := backend.Load(ctx)
+		require.NoError(t, err)
+		second, err := backend.Load(ctx)
+		require.NoError(t, err)
+
+		first.Description = "first writer"
+		require.NoError(t, backend.Save(ctx, first))
+
+		second.Description = "second writer"
+		err = backend.Save(ctx, second)
+
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrConflict))
+		var conflict *ConflictError
+		require.True(t, errors.As(err, &conflict))
+		assert.Equal(t, int64(1), conflict.Expected)
+		assert.Equal(t, int64(2), conflict.Actual)
+		assert.Equal(t, int64(1), second.Revision, "failed save must not change revision")
+
+		loaded, err := backend.Load(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "first writer", loaded.Description)
+	})
+
+	t.Run("Save of new state over existing state returns ErrConflict", func(t *testing.T) {
+		backend := newBackend(t)
+		require.NoError(t, backend.Save(ctx, contractState()))
+
+		err := backend.Save(ctx, contractState())
+
+		assert.True(t, errors.Is(err, ErrConflict))
+	})
+
+	t.Run("Save of nil state returns ErrInvalidState", func(t *testing.T) {
+		backend := newBackend(t)
+		require.NoError(t, backend.Save(ctx, contractState()))
+
+		err := backend.Save(ctx, nil)
+
+		require.ErrorIs(t, err, ErrInvalidState)
+		loaded, err := backend.Load(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), loaded.Revision)
+	})
+
+	t.Run("Load returns an independent copy", func(t *testing.T) {
+		backend := newBackend(t)
+		require.NoError(t, backend.Save(ctx, contractState()))
+
+		first, err := backend.Load(ctx)
+		require.NoError(t, err)
+		first.Name = "modified"
+		first.Phases["implementation"].Tasks[0].Name = "modified"
+
+		second, err := backend.Load(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, "contract-test", second.Name)
+		assert.Equal(t, "Second task listed first", second.Phases["implementation"].Tasks[0].Name)
+	})
+
+	t.Run("Delete removes state", func(t *testing.T) {
+		backend := newBackend(t)
+		require.NoError(t, backend.Save(ctx, contractState()))
+
+		require.NoError(t, backend.Delete(ctx))
+
+		exists, err := backend.Exists(ctx)
+		require.NoError(t, err)
+		assert.False(t, exists)
+
+		_, err = backend.Load(ctx)
+		assert.True(t, errors.Is(err, ErrNotFound))
+	})
+
+	t.Run("Save after Delete recreates state", func(t *testing.T) {
+		backend := newBackend(t)
+		require.NoError(t, backend.Save(ctx, contractState()))
+		require.NoError(t, backend.Delete(ctx))
+
+		require.NoError(t, backend.Save(ctx, contractState()))
+
+		loaded, err := backend.Load(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "contract-test", loaded.Name)
+	})
+}
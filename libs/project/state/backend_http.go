@@ -0,0 +1,223 @@
+package state
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/jmgilman/sow/libs/schemas/project"
+	"gopkg.in/yaml.v3"
+)
+
+// HTTPContentType is the media type of project state exchanged with a state
+// server. The body is the same YAML document the YAML backend writes.
+const HTTPContentType = "application/yaml"
+
+// HTTPBackend implements Backend against a state server such as the one
+// served by NewHTTPHandler (see `sow serve`).
+//
+// The protocol is a single resource per project at {base}/projects/{key}:
+//
+//	GET    returns the state as YAML with an ETag of the quoted revision
+//	HEAD   reports whether the project exists (200 or 404)
+//	PUT    saves the state; requires If-Match: "<revision>", or
+//	       If-None-Match: * when creating, and answers 412 on conflict
+//	DELETE removes the project (204 or 404)
+//
+// The server owns the revision counter, so Save keeps the same
+// compare-and-swap semantics as the local backends.
+type HTTPBackend struct {
+	url    string // Resource URL of the project
+	client *http.Client
+}
+
+// NewHTTPBackend creates a backend that stores state for the project
+// identified by key (typically its branch) on the state server at baseURL.
+// If client is nil, http.DefaultClient is used.
+func NewHTTPBackend(baseURL, key string, client *http.Client) *HTTPBackend {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTPBackend{
+		url:    strings.TrimSuffix(baseURL, "/") + "/projects/" + url.PathEscape(key),
+		client: client,
+	}
+}
+
+// URL returns the resource URL of the project.
+func (b *HTTPBackend) URL() string {
+	return b.url
+}
+
+// Load fetches project state from the server.
+// Returns ErrNotFound if the server has no state for the project.
+func (b *HTTPBackend) Load(ctx context.Context) (*project.ProjectState, error) {
+	resp, err := b.do(ctx, http.MethodGet, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("load project state: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("load project state: %w", ErrNotFound)
+	default:
+		return nil, fmt.Errorf("load project state: %w", unexpectedStatus(resp))
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("load project state: %w", err)
+	}
+
+	var state project.ProjectState
+	if err := yaml.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("unmarshal project state: %w", ErrInvalidState)
+	}
+	if revision, ok := parseETag(resp.Header.Get("ETag")); ok {
+		state.Revision = revision
+	}
+
+	return &state, nil
+}
+
+// Save uploads project state to the server, conditional on state.Revision
+// matching the revision the server holds.
+// Returns a *ConflictError if the server answers 412 Precondition Failed.
+func (b *HTTPBackend) Save(ctx context.Context, state *project.ProjectState) error {
+	if state == nil {
+		return fmt.Errorf("save project state: nil state: %w", ErrInvalidState)
+	}
+
+	data, err := yaml.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("marshal project state: %w", err)
+	}
+
+	header := http.Header{"Content-Type": {HTTPContentType}}
+	if state.Revision == 0 {
+		header.Set("If-None-Match", "*")
+	} else {
+		header.Set("If-Match", formatETag(state.Revision))
+	}
+
+	resp, err := b.do(ctx, http.MethodPut, header, data)
+	if err != nil {
+		return fmt.Errorf("save project state: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+	case http.StatusPreconditionFailed:
+		actual, _ := parseETag(resp.Header.Get("ETag"))
+		return &ConflictError{Expected: state.Revision, Actual: actual}
+	default:
+		return fmt.Errorf("save project state: %w", unexpectedStatus(resp))
+	}
+
+	revision, ok := parseETag(resp.Header.Get("ETag"))
+	if !ok {
+		return fmt.Errorf("save project state: response has no revision ETag")
+	}
+	state.Revision = revision
+	return nil
+}
+
+// Exists checks if the server holds state for the project.
+func (b *HTTPBackend) Exists(ctx context.Context) (bool, error) {
+	resp, err := b.do(ctx, http.MethodHead, nil, nil)
+	if err != nil {
+		return false, fmt.Errorf("check project state exists: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("check project state exists: %w", unexpectedStatus(resp))
+	}
+}
+
+// Delete removes the project state from the server.
+// Returns ErrNotFound if the server has no state for the project.
+func (b *HTTPBackend) Delete(ctx context.Context) error {
+	resp, err := b.do(ctx, http.MethodDelete, nil, nil)
+	if err != nil {
+		return fmt.Errorf("delete project state: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		return nil
+	case http.StatusNotFound:
+		return fmt.Errorf("delete project state: %w", ErrNotFound)
+	default:
+		return fmt.Errorf("delete project state: %w", unexpectedStatus(resp))
+	}
+}
+
+// do sends a request to the project resource.
+func (b *HTTPBackend) do(ctx context.Context, method string, header http.Header, body []byte) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, b.url, reader)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", method, b.url, err)
+	}
+	return resp, nil
+}
+
+// unexpectedStatus describes a response the protocol does not allow,
+// including the start of its body, which carries the server's error message.
+func unexpectedStatus(resp *http.Response) error {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	msg := strings.TrimSpace(string(data))
+	if msg == "" {
+		return fmt.Errorf("%s %s: %s", resp.Request.Method, resp.Request.URL, resp.Status)
+	}
+	return fmt.Errorf("%s %s: %s: %s", resp.Request.Method, resp.Request.URL, resp.Status, msg)
+}
+
+// formatETag returns the strong ETag for a revision.
+func formatETag(revision int64) string {
+	return strconv.Quote(strconv.FormatInt(revision, 10))
+}
+
+// parseETag returns the revision from an ETag written by formatETag.
+func parseETag(etag string) (int64, bool) {
+	etag = strings.TrimPrefix(strings.TrimSpace(etag), "W/")
+	unquoted, err := strconv.Unquote(etag)
+	if err != nil {
+		return 0, false
+	}
+	revision, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || revision < 0 {
+		return 0, false
+	}
+	return revision, true
+}
+
+// Compile-time interface check.
+var _ Backend = (*HTTPBackend)(nil)
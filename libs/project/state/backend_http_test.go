@@ -0,0 +1,160 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testStateServer serves the HTTP protocol backed by one MemoryBackend per
+// project key and records the keys it was asked to open.
+type testStateServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	backends map[string]*MemoryBackend
+}
+
+func newTestStateServer(t *testing.T) *testStateServer {
+	t.Helper()
+
+	s := &testStateServer{backends: map[string]*MemoryBackend{}}
+	s.Server = httptest.NewServer(NewHTTPHandler(s.open))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *testStateServer) open(key string) (Backend, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	backend, ok := s.backends[key]
+	if !ok {
+		backend = NewMemoryBackend()
+		s.backends[key] = backend
+	}
+	return backend, nil
+}
+
+// newHTTPTestBackend returns an HTTPBackend for key on a fresh test server.
+func newHTTPTestBackend(t *testing.T, key string) *HTTPBackend {
+	t.Helper()
+	return NewHTTPBackend(newTestStateServer(t).URL, key, nil)
+}
+
+func TestNewHTTPBackend(t *testing.T) {
+	t.Run("escapes the project key", func(t *testing.T) {
+		backend := NewHTTPBackend("http://localhost:7070/", "feat/http state", nil)
+
+		assert.Equal(t, "http://localhost:7070/projects/feat%2Fhttp%20state", backend.URL())
+	})
+}
+
+func TestHTTPBackend(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("stores projects under their key", func(t *testing.T) {
+		server := newTestStateServer(t)
+		first := NewHTTPBackend(server.URL, "feat/one", nil)
+		second := NewHTTPBackend(server.URL, "feat/two", nil)
+		require.NoError(t, first.Save(ctx, contractState()))
+
+		exists, err := second.Exists(ctx)
+
+		require.NoError(t, err)
+		assert.False(t, exists)
+		assert.Contains(t, server.backends, "feat/one")
+		assert.NotNil(t, server.backends["feat/one"].State())
+	})
+
+	t.Run("Delete of missing project returns ErrNotFound", func(t *testing.T) {
+		backend := newHTTPTestBackend(t, "feat/missing")
+
+		err := backend.Delete(ctx)
+
+		assert.True(t, errors.Is(err, ErrNotFound))
+	})
+
+	t.Run("unreachable server returns error", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+		backend := NewHTTPBackend(server.URL, "feat/down", nil)
+
+		_, err := backend.Load(ctx)
+
+		require.Error(t, err)
+		assert.False(t, errors.Is(err, ErrNotFound))
+	})
+
+	t.Run("unexpected status includes server message", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, "disk full", http.StatusInternalServerError)
+		}))
+		t.Cleanup(server.Close)
+		backend := NewHTTPBackend(server.URL, "feat/broken", nil)
+
+		err := backend.Save(ctx, contractState())
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "500 Internal Server Error: disk full")
+	})
+}
+
+func TestHTTPHandler(t *testing.T) {
+	put := func(t *testing.T, url string, header map[string]string, body string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPut, url, strings.NewReader(body))
+		require.NoError(t, err)
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = resp.Body.Close() })
+		return resp
+	}
+
+	t.Run("save requires a precondition", func(t *testing.T) {
+		server := newTestStateServer(t)
+
+		resp := put(t, server.URL+"/projects/feat%2Fx", nil, "name: x\n")
+
+		assert.Equal(t, http.StatusPreconditionRequired, resp.StatusCode)
+	})
+
+	t.Run("save rejects malformed If-Match", func(t *testing.T) {
+		server := newTestStateServer(t)
+
+		resp := put(t, server.URL+"/projects/feat%2Fx", map[string]string{"If-Match": "abc"}, "name: x\n")
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("conflict reports current revision", func(t *testing.T) {
+		server := newTestStateServer(t)
+		header := map[string]string{"If-None-Match": "*"}
+		require.Equal(t, http.StatusNoContent, put(t, server.URL+"/projects/feat%2Fx", header, "name: x\n").StatusCode)
+
+		resp := put(t, server.URL+"/projects/feat%2Fx", header, "name: x\n")
+
+		assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+		assert.Equal(t, `"1"`, resp.Header.Get("ETag"))
+	})
+
+	t.Run("rejects other methods", func(t *testing.T) {
+		server := newTestStateServer(t)
+
+		resp, err := http.Post(server.URL+"/projects/feat%2Fx", HTTPContentType, strings.NewReader(""))
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	})
+}
@@ -2,6 +2,7 @@ package state
 
 import (
 	"context"
+	"fmt"
 	"sync"
 
 	"github.com/jmgilman/sow/libs/schemas/project"
@@ -45,11 +46,25 @@ func (b *MemoryBackend) Load(_ context.Context) (*project.ProjectState, error) {
 
 // Save stores the project state in memory.
 // A deep copy of the input state is stored to ensure isolation.
-// Replaces any existing state completely.
+// Replaces any existing state completely, provided the revision matches.
+// Use Delete to clear the stored state.
 func (b *MemoryBackend) Save(_ context.Context, state *project.ProjectState) error {
+	if state == nil {
+		return fmt.Errorf("save project state: nil state: %w", ErrInvalidState)
+	}
+
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
+	var current int64
+	if b.state != nil {
+		current = b.state.Revision
+	}
+	if state.Revision != current {
+		return &ConflictError{Expected: state.Revision, Actual: current}
+	}
+
+	state.Revision = current + 1
 	b.state = copyProjectState(state)
 	return nil
 }
@@ -99,6 +114,7 @@ func copyProjectState(src *project.ProjectState) *project.ProjectState {
 		Description: src.Description,
 		Created_at:  src.Created_at,
 		Updated_at:  src.Updated_at,
+		Revision:    src.Revision,
 		Statechart: project.StatechartState{
 			Current_state: src.Statechart.Current_state,
 			Updated_at:    src.Statechart.Updated_at,
@@ -207,6 +223,12 @@ func copyTaskState(src project.TaskState) project.TaskState {
 		Session_id:     src.Session_id,
 	}
 
+	// Copy dependencies
+	if src.Depends_on != nil {
+		dst.Depends_on = make([]string, len(src.Depends_on))
+		copy(dst.Depends_on, src.Depends_on)
+	}
+
 	// Copy inputs
 	if src.Inputs != nil {
 		dst.Inputs = make([]project.ArtifactState, len(src.Inputs))
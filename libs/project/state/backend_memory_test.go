@@ -219,18 +219,18 @@ func TestMemoryBackend_Save(t *testing.T) {
 		assert.Equal(t, "exploration", loaded.Type)
 	})
 
-	t.Run("handles nil state", func(t *testing.T) {
+	t.Run("rejects nil state", func(t *testing.T) {
 		backend := NewMemoryBackendWithState(&project.ProjectState{
 			Name: "existing",
 		})
 		ctx := context.Background()
 
 		err := backend.Save(ctx, nil)
-		require.NoError(t, err)
+		require.ErrorIs(t, err, ErrInvalidState)
 
-		exists, err := backend.Exists(ctx)
+		loaded, err := backend.Load(ctx)
 		require.NoError(t, err)
-		assert.False(t, exists)
+		assert.Equal(t, "existing", loaded.Name)
 	})
 }
 
@@ -0,0 +1,898 @@
+package state
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmgilman/sow/libs/schemas/project"
+
+	// Register the pure-Go "sqlite" database/sql driver.
+	_ "modernc.org/sqlite"
+)
+
+// sqliteSchemaVersion is stored in PRAGMA user_version and bumped whenever
+// the table layout below or the encoding of its columns changes.
+const sqliteSchemaVersion = 3
+
+// sqliteSchema creates the normalized tables used by SQLiteBackend.
+//
+// The project table holds a single row (id = 1). Phases are keyed by name;
+// tasks and artifacts keep their slice position so that Load reproduces the
+// original ordering. Phase-level artifacts use task_position = -1.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS project (
+	id                    INTEGER PRIMARY KEY CHECK (id = 1),
+	revision              INTEGER NOT NULL,
+	name                  TEXT NOT NULL,
+	type                  TEXT NOT NULL,
+	branch                TEXT NOT NULL,
+	description           TEXT NOT NULL,
+	created_at            TEXT NOT NULL,
+	updated_at            TEXT NOT NULL,
+	current_state         TEXT NOT NULL,
+	statechart_updated_at TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS phases (
+	name         TEXT PRIMARY KEY,
+	status       TEXT NOT NULL,
+	enabled      INTEGER NOT NULL,
+	created_at   TEXT NOT NULL,
+	started_at   TEXT NOT NULL,
+	completed_at TEXT NOT NULL,
+	failed_at    TEXT NOT NULL,
+	iteration    INTEGER NOT NULL,
+	metadata     TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS tasks (
+	phase_name     TEXT NOT NULL REFERENCES phases(name) ON DELETE CASCADE,
+	position       INTEGER NOT NULL,
+	id             TEXT NOT NULL,
+	name           TEXT NOT NULL,
+	phase          TEXT NOT NULL,
+	status         TEXT NOT NULL,
+	created_at     TEXT NOT NULL,
+	started_at     TEXT NOT NULL,
+	updated_at     TEXT NOT NULL,
+	completed_at   TEXT NOT NULL,
+	iteration      INTEGER NOT NULL,
+	assigned_agent TEXT NOT NULL,
+	session_id     TEXT NOT NULL,
+	metadata       TEXT NOT NULL,
+	PRIMARY KEY (phase_name, position)
+);
+
+CREATE TABLE IF NOT EXISTS task_dependencies (
+	phase_name    TEXT NOT NULL REFERENCES phases(name) ON DELETE CASCADE,
+	task_position INTEGER NOT NULL,
+	position      INTEGER NOT NULL,
+	depends_on    TEXT NOT NULL,
+	PRIMARY KEY (phase_name, task_position, position)
+);
+
+CREATE TABLE IF NOT EXISTS artifacts (
+	phase_name    TEXT NOT NULL REFERENCES phases(name) ON DELETE CASCADE,
+	task_position INTEGER NOT NULL,
+	direction     TEXT NOT NULL CHECK (direction IN ('input', 'output')),
+	position      INTEGER NOT NULL,
+	type          TEXT NOT NULL,
+	path          TEXT NOT NULL,
+	approved      INTEGER NOT NULL,
+	created_at    TEXT NOT NULL,
+	metadata      TEXT NOT NULL,
+	PRIMARY KEY (phase_name, task_position, direction, position)
+);
+
+CREATE TABLE IF NOT EXISTS agent_sessions (
+	agent      TEXT PRIMARY KEY,
+	session_id TEXT NOT NULL
+);
+`
+
+// Artifact directions and the task position used for phase-level artifacts.
+const (
+	artifactInput  = "input"
+	artifactOutput = "output"
+	phaseArtifact  = -1
+)
+
+// SQLiteBackend implements Backend using a SQLite database with normalized
+// tables for the project, phases, tasks, artifacts, and agent sessions.
+//
+// Unlike YAMLBackend, SQLite requires a real file on disk, so the backend is
+// configured with an OS path rather than a core.FS. Each operation opens and
+// closes its own connection; no handles are held between calls.
+//
+// Metadata maps are stored as JSON so that they can be queried in SQL with
+// json_extract. Whole numbers are decoded as int, so that values round-trip
+// with the same Go types as the YAML backend.
+type SQLiteBackend struct {
+	path string // OS path to the database file
+}
+
+// NewSQLiteBackend creates a backend that stores state in the SQLite database
+// at path. The database and its parent directory are created on first Save.
+func NewSQLiteBackend(path string) *SQLiteBackend {
+	return &SQLiteBackend{
+		path: path,
+	}
+}
+
+// Path returns the OS path of the database file.
+func (b *SQLiteBackend) Path() string {
+	return b.path
+}
+
+// Load reads project state from the database.
+// Returns ErrNotFound if the database does not exist or holds no project,
+// and ErrInvalidState if it was written with another schema version.
+func (b *SQLiteBackend) Load(ctx context.Context) (*project.ProjectState, error) {
+	if _, err := os.Stat(b.path); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("load project state: %w", ErrNotFound)
+		}
+		return nil, fmt.Errorf("load project state: %w", err)
+	}
+
+	db, err := b.open()
+	if err != nil {
+		return nil, fmt.Errorf("load project state: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("load project state: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := checkSQLiteSchema(ctx, tx); err != nil {
+		return nil, fmt.Errorf("load project state: %w", err)
+	}
+
+	state, err := loadProjectRow(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if state.Phases, err = loadPhases(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	if state.Agent_sessions, err = loadAgentSessions(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// Save writes project state to the database in a single transaction.
+// Existing rows are replaced, so the database always mirrors the given state.
+// The revision check and the write happen under the same write lock, so
+// concurrent writers cannot both succeed from the same revision.
+func (b *SQLiteBackend) Save(ctx context.Context, state *project.ProjectState) error {
+	if state == nil {
+		return fmt.Errorf("save project state: nil state: %w", ErrInvalidState)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(b.path), 0755); err != nil {
+		return fmt.Errorf("create database directory: %w", err)
+	}
+
+	db, err := b.openForWrite()
+	if err != nil {
+		return fmt.Errorf("save project state: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := ensureSQLiteSchema(ctx, tx); err != nil {
+		return err
+	}
+
+	current, err := storedSQLiteRevision(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if state.Revision != current {
+		return &ConflictError{Expected: state.Revision, Actual: current}
+	}
+
+	if err := clearSQLiteTables(ctx, tx); err != nil {
+		return err
+	}
+
+	if err := saveProjectRow(ctx, tx, state, current+1); err != nil {
+		return err
+	}
+
+	if err := savePhases(ctx, tx, state.Phases); err != nil {
+		return err
+	}
+
+	if err := saveAgentSessions(ctx, tx, state.Agent_sessions); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	state.Revision = current + 1
+	return nil
+}
+
+// Exists checks if the database exists and contains a project.
+func (b *SQLiteBackend) Exists(ctx context.Context) (bool, error) {
+	if _, err := os.Stat(b.path); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("check project state exists: %w", err)
+	}
+
+	db, err := b.open()
+	if err != nil {
+		return false, fmt.Errorf("check project state exists: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	var count int
+	err = db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'project'`,
+	).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("check project state exists: %w", err)
+	}
+	if count == 0 {
+		return false, nil
+	}
+
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM project`).Scan(&count); err != nil {
+		return false, fmt.Errorf("check project state exists: %w", err)
+	}
+	return count > 0, nil
+}
+
+// Delete removes the database file.
+func (b *SQLiteBackend) Delete(_ context.Context) error {
+	if err := os.Remove(b.path); err != nil {
+		return fmt.Errorf("delete project state: %w", err)
+	}
+	return nil
+}
+
+// open opens a connection pool to the database file.
+// The busy timeout lets concurrent writers wait instead of failing immediately.
+func (b *SQLiteBackend) open() (*sql.DB, error) {
+	return openSQLite(b.path, "")
+}
+
+// openForWrite opens a connection pool whose transactions start with
+// BEGIN IMMEDIATE, taking the write lock before the revision is read.
+func (b *SQLiteBackend) openForWrite() (*sql.DB, error) {
+	return openSQLite(b.path, "&_txlock=immediate")
+}
+
+// sqliteURIEscaper escapes the characters that SQLite interprets in the
+// path of a file: URI, so that file names containing them are opened as-is.
+var sqliteURIEscaper = strings.NewReplacer("%", "%25", "?", "%3f", "#", "%23")
+
+func openSQLite(path, params string) (*sql.DB, error) {
+	dsn := "file:" + sqliteURIEscaper.Replace(path) + "?_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)" + params
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	return db, nil
+}
+
+// ensureSQLiteSchema creates the tables in a database that has none and
+// rejects databases written with another schema version. There are no
+// migrations between versions, so an older database is not overwritten
+// with rows its layout was not created for.
+func ensureSQLiteSchema(ctx context.Context, tx *sql.Tx) error {
+	var version int
+	if err := tx.QueryRowContext(ctx, `PRAGMA user_version`).Scan(&version); err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+	switch version {
+	case sqliteSchemaVersion:
+		return nil
+	case 0:
+	default:
+		return unsupportedSQLiteSchema(version)
+	}
+
+	if _, err := tx.ExecContext(ctx, sqliteSchema); err != nil {
+		return fmt.Errorf("create schema: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`PRAGMA user_version = %d`, sqliteSchemaVersion)); err != nil {
+		return fmt.Errorf("set schema version: %w", err)
+	}
+	return nil
+}
+
+// checkSQLiteSchema rejects databases written with another schema version,
+// whose rows would be misread. A database without a version was never
+// saved to and holds no project.
+func checkSQLiteSchema(ctx context.Context, tx *sql.Tx) error {
+	var version int
+	if err := tx.QueryRowContext(ctx, `PRAGMA user_version`).Scan(&version); err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+	switch version {
+	case sqliteSchemaVersion:
+		return nil
+	case 0:
+		return ErrNotFound
+	default:
+		return unsupportedSQLiteSchema(version)
+	}
+}
+
+// unsupportedSQLiteSchema returns the error for a database written with
+// another schema version.
+func unsupportedSQLiteSchema(version int) error {
+	return fmt.Errorf("database schema version %d is not supported (want %d): %w",
+		version, sqliteSchemaVersion, ErrInvalidState)
+}
+
+// storedSQLiteRevision returns the revision of the stored project, or zero
+// if the database holds no project.
+func storedSQLiteRevision(ctx context.Context, tx *sql.Tx) (int64, error) {
+	var revision int64
+	err := tx.QueryRowContext(ctx, `SELECT revision FROM project WHERE id = 1`).Scan(&revision)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("read current revision: %w", err)
+	}
+	return revision, nil
+}
+
+// clearSQLiteTables removes all rows so Save can rewrite the full state.
+func clearSQLiteTables(ctx context.Context, tx *sql.Tx) error {
+	for _, table := range []string{"artifacts", "task_dependencies", "tasks", "phases", "agent_sessions", "project"} {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+table); err != nil {
+			return fmt.Errorf("clear %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+func saveProjectRow(ctx context.Context, tx *sql.Tx, state *project.ProjectState, revision int64) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO project (id, revision, name, type, branch, description, created_at, updated_at,
+			current_state, statechart_updated_at)
+		VALUES (1, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		revision,
+		state.Name,
+		state.Type,
+		state.Branch,
+		state.Description,
+		formatTime(state.Created_at),
+		formatTime(state.Updated_at),
+		state.Statechart.Current_state,
+		formatTime(state.Statechart.Updated_at),
+	)
+	if err != nil {
+		return fmt.Errorf("insert project: %w", err)
+	}
+	return nil
+}
+
+func savePhases(ctx context.Context, tx *sql.Tx, phases map[string]project.PhaseState) error {
+	// Insert in a stable order so the database contents are deterministic
+	names := make([]string, 0, len(phases))
+	for name := range phases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		phase := phases[name]
+
+		metadata, err := encodeMetadata(phase.Metadata)
+		if err != nil {
+			return fmt.Errorf("encode phase %s metadata: %w", name, err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO phases (name, status, enabled, created_at, started_at, completed_at,
+				failed_at, iteration, metadata)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			name,
+			phase.Status,
+			phase.Enabled,
+			formatTime(phase.Created_at),
+			formatTime(phase.Started_at),
+			formatTime(phase.Completed_at),
+			formatTime(phase.Failed_at),
+			phase.Iteration,
+			metadata,
+		)
+		if err != nil {
+			return fmt.Errorf("insert phase %s: %w", name, err)
+		}
+
+		if err := saveArtifacts(ctx, tx, name, phaseArtifact, artifactInput, phase.Inputs); err != nil {
+			return err
+		}
+		if err := saveArtifacts(ctx, tx, name, phaseArtifact, artifactOutput, phase.Outputs); err != nil {
+			return err
+		}
+
+		for i, task := range phase.Tasks {
+			if err := saveTask(ctx, tx, name, i, task); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func saveTask(ctx context.Context, tx *sql.Tx, phaseName string, position int, task project.TaskState) error {
+	metadata, err := encodeMetadata(task.Metadata)
+	if err != nil {
+		return fmt.Errorf("encode task %s metadata: %w", task.Id, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO tasks (phase_name, position, id, name, phase, status, created_at, started_at,
+			updated_at, completed_at, iteration, assigned_agent, session_id, metadata)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		phaseName,
+		position,
+		task.Id,
+		task.Name,
+		task.Phase,
+		task.Status,
+		formatTime(task.Created_at),
+		formatTime(task.Started_at),
+		formatTime(task.Updated_at),
+		formatTime(task.Completed_at),
+		task.Iteration,
+		task.Assigned_agent,
+		task.Session_id,
+		metadata,
+	)
+	if err != nil {
+		return fmt.Errorf("insert task %s: %w", task.Id, err)
+	}
+
+	for i, dep := range task.Depends_on {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO task_dependencies (phase_name, task_position, position, depends_on)
+			VALUES (?, ?, ?, ?)`,
+			phaseName, position, i, dep)
+		if err != nil {
+			return fmt.Errorf("insert task %s dependency: %w", task.Id, err)
+		}
+	}
+
+	if err := saveArtifacts(ctx, tx, phaseName, position, artifactInput, task.Inputs); err != nil {
+		return err
+	}
+	return saveArtifacts(ctx, tx, phaseName, position, artifactOutput, task.Outputs)
+}
+
+func saveArtifacts(
+	ctx context.Context,
+	tx *sql.Tx,
+	phaseName string,
+	taskPosition int,
+	direction string,
+	artifacts []project.ArtifactState,
+) error {
+	for i, artifact := range artifacts {
+		metadata, err := encodeMetadata(artifact.Metadata)
+		if err != nil {
+			return fmt.Errorf("encode artifact %s metadata: %w", artifact.Path, err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO artifacts (phase_name, task_position, direction, position, type, path,
+				approved, created_at, metadata)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			phaseName,
+			taskPosition,
+			direction,
+			i,
+			artifact.Type,
+			artifact.Path,
+			artifact.Approved,
+			formatTime(artifact.Created_at),
+			metadata,
+		)
+		if err != nil {
+			return fmt.Errorf("insert artifact %s: %w", artifact.Path, err)
+		}
+	}
+	return nil
+}
+
+func saveAgentSessions(ctx context.Context, tx *sql.Tx, sessions map[string]string) error {
+	for agent, sessionID := range sessions {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO agent_sessions (agent, session_id) VALUES (?, ?)`,
+			agent, sessionID,
+		)
+		if err != nil {
+			return fmt.Errorf("insert agent session %s: %w", agent, err)
+		}
+	}
+	return nil
+}
+
+func loadProjectRow(ctx context.Context, tx *sql.Tx) (*project.ProjectState, error) {
+	var (
+		state                                     project.ProjectState
+		createdAt, updatedAt, statechartUpdatedAt string
+	)
+
+	err := tx.QueryRowContext(ctx, `
+		SELECT revision, name, type, branch, description, created_at, updated_at,
+			current_state, statechart_updated_at
+		FROM project WHERE id = 1`,
+	).Scan(
+		&state.Revision,
+		&state.Name,
+		&state.Type,
+		&state.Branch,
+		&state.Description,
+		&createdAt,
+		&updatedAt,
+		&state.Statechart.Current_state,
+		&statechartUpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("load project state: %w", ErrNotFound)
+		}
+		return nil, fmt.Errorf("query project: %w", ErrInvalidState)
+	}
+
+	if err := parseTimes(
+		timeField{createdAt, &state.Created_at},
+		timeField{updatedAt, &state.Updated_at},
+		timeField{statechartUpdatedAt, &state.Statechart.Updated_at},
+	); err != nil {
+		return nil, err
+	}
+
+	return &state, nil
+}
+
+func loadPhases(ctx context.Context, tx *sql.Tx) (map[string]project.PhaseState, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT name, status, enabled, created_at, started_at, completed_at, failed_at,
+			iteration, metadata
+		FROM phases ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("query phases: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	phases := make(map[string]project.PhaseState)
+	for rows.Next() {
+		var (
+			name, metadata                              string
+			createdAt, startedAt, completedAt, failedAt string
+			phase                                       project.PhaseState
+		)
+
+		err := rows.Scan(
+			&name,
+			&phase.Status,
+			&phase.Enabled,
+			&createdAt,
+			&startedAt,
+			&completedAt,
+			&failedAt,
+			&phase.Iteration,
+			&metadata,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan phase: %w", err)
+		}
+
+		if err := parseTimes(
+			timeField{createdAt, &phase.Created_at},
+			timeField{startedAt, &phase.Started_at},
+			timeField{completedAt, &phase.Completed_at},
+			timeField{failedAt, &phase.Failed_at},
+		); err != nil {
+			return nil, err
+		}
+
+		if phase.Metadata, err = decodeMetadata(metadata); err != nil {
+			return nil, fmt.Errorf("decode phase %s metadata: %w", name, err)
+		}
+
+		phases[name] = phase
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query phases: %w", err)
+	}
+
+	// Tasks and artifacts are attached after the phase rows are closed
+	for name, phase := range phases {
+		if phase.Tasks, err = loadTasks(ctx, tx, name); err != nil {
+			return nil, err
+		}
+		if phase.Inputs, err = loadArtifacts(ctx, tx, name, phaseArtifact, artifactInput); err != nil {
+			return nil, err
+		}
+		if phase.Outputs, err = loadArtifacts(ctx, tx, name, phaseArtifact, artifactOutput); err != nil {
+			return nil, err
+		}
+		phases[name] = phase
+	}
+
+	return phases, nil
+}
+
+func loadTasks(ctx context.Context, tx *sql.Tx, phaseName string) ([]project.TaskState, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT position, id, name, phase, status, created_at, started_at, updated_at,
+			completed_at, iteration, assigned_agent, session_id, metadata
+		FROM tasks WHERE phase_name = ? ORDER BY position`, phaseName)
+	if err != nil {
+		return nil, fmt.Errorf("query tasks: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	tasks := []project.TaskState{}
+	var positions []int
+	for rows.Next() {
+		var (
+			position                                     int
+			metadata                                     string
+			createdAt, startedAt, updatedAt, completedAt string
+			task                                         project.TaskState
+		)
+
+		err := rows.Scan(
+			&position,
+			&task.Id,
+			&task.Name,
+			&task.Phase,
+			&task.Status,
+			&createdAt,
+			&startedAt,
+			&updatedAt,
+			&completedAt,
+			&task.Iteration,
+			&task.Assigned_agent,
+			&task.Session_id,
+			&metadata,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan task: %w", err)
+		}
+
+		if err := parseTimes(
+			timeField{createdAt, &task.Created_at},
+			timeField{startedAt, &task.Started_at},
+			timeField{updatedAt, &task.Updated_at},
+			timeField{completedAt, &task.Completed_at},
+		); err != nil {
+			return nil, err
+		}
+
+		if task.Metadata, err = decodeMetadata(metadata); err != nil {
+			return nil, fmt.Errorf("decode task %s metadata: %w", task.Id, err)
+		}
+
+		tasks = append(tasks, task)
+		positions = append(positions, position)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query tasks: %w", err)
+	}
+
+	for i, position := range positions {
+		if tasks[i].Inputs, err = loadArtifacts(ctx, tx, phaseName, position, artifactInput); err != nil {
+			return nil, err
+		}
+		if tasks[i].Outputs, err = loadArtifacts(ctx, tx, phaseName, position, artifactOutput); err != nil {
+			return nil, err
+		}
+		if tasks[i].Depends_on, err = loadTaskDependencies(ctx, tx, phaseName, position); err != nil {
+			return nil, err
+		}
+	}
+
+	return tasks, nil
+}
+
+// loadTaskDependencies returns the depends_on list of a task.
+func loadTaskDependencies(ctx context.Context, tx *sql.Tx, phaseName string, taskPosition int) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT depends_on FROM task_dependencies
+		WHERE phase_name = ? AND task_position = ?
+		ORDER BY position`, phaseName, taskPosition)
+	if err != nil {
+		return nil, fmt.Errorf("query task dependencies: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	deps := []string{}
+	for rows.Next() {
+		var dep string
+		if err := rows.Scan(&dep); err != nil {
+			return nil, fmt.Errorf("scan task dependency: %w", err)
+		}
+		deps = append(deps, dep)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query task dependencies: %w", err)
+	}
+
+	return deps, nil
+}
+
+func loadArtifacts(
+	ctx context.Context,
+	tx *sql.Tx,
+	phaseName string,
+	taskPosition int,
+	direction string,
+) ([]project.ArtifactState, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT type, path, approved, created_at, metadata
+		FROM artifacts
+		WHERE phase_name = ? AND task_position = ? AND direction = ?
+		ORDER BY position`, phaseName, taskPosition, direction)
+	if err != nil {
+		return nil, fmt.Errorf("query artifacts: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	artifacts := []project.ArtifactState{}
+	for rows.Next() {
+		var (
+			createdAt, metadata string
+			artifact            project.ArtifactState
+		)
+
+		if err := rows.Scan(&artifact.Type, &artifact.Path, &artifact.Approved, &createdAt, &metadata); err != nil {
+			return nil, fmt.Errorf("scan artifact: %w", err)
+		}
+
+		if err := parseTimes(timeField{createdAt, &artifact.Created_at}); err != nil {
+			return nil, err
+		}
+
+		if artifact.Metadata, err = decodeMetadata(metadata); err != nil {
+			return nil, fmt.Errorf("decode artifact %s metadata: %w", artifact.Path, err)
+		}
+
+		artifacts = append(artifacts, artifact)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query artifacts: %w", err)
+	}
+
+	return artifacts, nil
+}
+
+func loadAgentSessions(ctx context.Context, tx *sql.Tx) (map[string]string, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT agent, session_id FROM agent_sessions`)
+	if err != nil {
+		return nil, fmt.Errorf("query agent sessions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	sessions := make(map[string]string)
+	for rows.Next() {
+		var agent, sessionID string
+		if err := rows.Scan(&agent, &sessionID); err != nil {
+			return nil, fmt.Errorf("scan agent session: %w", err)
+		}
+		sessions[agent] = sessionID
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query agent sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// formatTime encodes a timestamp for storage.
+// Zero values are stored as well so that Load returns exactly what was saved.
+func formatTime(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
+
+// timeField pairs a stored timestamp with its destination.
+type timeField struct {
+	value string
+	dest  *time.Time
+}
+
+// parseTimes decodes stored timestamps into their destinations.
+func parseTimes(fields ...timeField) error {
+	for _, f := range fields {
+		t, err := time.Parse(time.RFC3339Nano, f.value)
+		if err != nil {
+			return fmt.Errorf("parse timestamp %q: %w", f.value, ErrInvalidState)
+		}
+		*f.dest = t
+	}
+	return nil
+}
+
+// encodeMetadata serializes a metadata map for storage as JSON.
+func encodeMetadata(metadata map[string]interface{}) (string, error) {
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return "", err //nolint:wrapcheck // Callers add context
+	}
+	return string(data), nil
+}
+
+// decodeMetadata parses a stored metadata map.
+func decodeMetadata(data string) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(strings.NewReader(data))
+	decoder.UseNumber()
+	metadata := make(map[string]interface{})
+	if err := decoder.Decode(&metadata); err != nil {
+		return nil, ErrInvalidState
+	}
+	for key, value := range metadata {
+		metadata[key] = decodeJSONNumbers(value)
+	}
+	return metadata, nil
+}
+
+// decodeJSONNumbers replaces the json.Numbers in a decoded value with int
+// for whole numbers and float64 otherwise, the types YAML decoding yields.
+func decodeJSONNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if n, err := strconv.Atoi(v.String()); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = decodeJSONNumbers(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = decodeJSONNumbers(item)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+// Compile-time interface check.
+var _ Backend = (*SQLiteBackend)(nil)
@@ -0,0 +1,207 @@
+package state
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSQLiteBackend_Save_CreatesDatabase tests that Save creates the parent directory and database.
+func TestSQLiteBackend_Save_CreatesDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "project", "state.db")
+	backend := NewSQLiteBackend(path)
+
+	require.NoError(t, backend.Save(context.Background(), contractState()))
+
+	_, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, path, backend.Path())
+}
+
+// TestSQLiteBackend_Path_URICharacters tests that paths containing URI syntax are used literally.
+func TestSQLiteBackend_Path_URICharacters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feat%2Fx#1.db")
+	backend := NewSQLiteBackend(path)
+	ctx := context.Background()
+
+	require.NoError(t, backend.Save(ctx, contractState()))
+
+	_, err := os.Stat(path)
+	require.NoError(t, err)
+	loaded, err := backend.Load(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "contract-test", loaded.Name)
+}
+
+// TestSQLiteBackend_Save_Normalized tests that state is spread across the normalized tables.
+func TestSQLiteBackend_Save_Normalized(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+	backend := NewSQLiteBackend(path)
+	require.NoError(t, backend.Save(context.Background(), contractState()))
+
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	counts := map[string]int{
+		"project":        1,
+		"phases":         2,
+		"tasks":          2,
+		"artifacts":      4,
+		"agent_sessions": 2,
+	}
+	for table, want := range counts {
+		var got int
+		require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM "+table).Scan(&got))
+		assert.Equal(t, want, got, "row count for %s", table)
+	}
+
+	var version int
+	require.NoError(t, db.QueryRow("PRAGMA user_version").Scan(&version))
+	assert.Equal(t, sqliteSchemaVersion, version)
+}
+
+// TestSQLiteBackend_Save_Transactional tests that a failed Save leaves the previous state intact.
+func TestSQLiteBackend_Save_Transactional(t *testing.T) {
+	backend := NewSQLiteBackend(filepath.Join(t.TempDir(), "state.db"))
+	require.NoError(t, backend.Save(context.Background(), contractState()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	updated := contractState()
+	updated.Description = "never written"
+	require.Error(t, backend.Save(ctx, updated))
+
+	loaded, err := backend.Load(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "Backend contract test project", loaded.Description)
+}
+
+// TestSQLiteBackend_Save_RejectsNewerSchema tests that databases from newer versions are not overwritten.
+func TestSQLiteBackend_Save_RejectsNewerSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	_, err = db.Exec("PRAGMA user_version = 99")
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	err = NewSQLiteBackend(path).Save(context.Background(), contractState())
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrInvalidState))
+}
+
+// TestSQLiteBackend_Save_RejectsOlderSchema tests that databases of an older schema version are
+// not overwritten, since their table layout was never migrated.
+func TestSQLiteBackend_Save_RejectsOlderSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+	backend := NewSQLiteBackend(path)
+	require.NoError(t, backend.Save(context.Background(), contractState()))
+
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	_, err = db.Exec("PRAGMA user_version = 2")
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	err = backend.Save(context.Background(), contractState())
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrInvalidState))
+	assert.Contains(t, err.Error(), "database schema version 2 is not supported (want 3)")
+
+	db, err = sql.Open("sqlite", path)
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+	var version int
+	require.NoError(t, db.QueryRow("PRAGMA user_version").Scan(&version))
+	assert.Equal(t, 2, version)
+}
+
+// TestSQLiteBackend_Metadata_JSON tests that metadata is stored as JSON queryable in SQL
+// and round-trips with the Go types of the YAML backend.
+func TestSQLiteBackend_Metadata_JSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+	backend := NewSQLiteBackend(path)
+	ctx := context.Background()
+
+	saved := contractState()
+	phase := saved.Phases["implementation"]
+	phase.Metadata["cost_usd"] = 1.5
+	phase.Metadata["attempts"] = []interface{}{map[string]interface{}{"attempt": 2}}
+	saved.Phases["implementation"] = phase
+	require.NoError(t, backend.Save(ctx, saved))
+
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+	var total int
+	require.NoError(t, db.QueryRow(
+		`SELECT json_extract(metadata, '$.tasks_total') FROM phases WHERE name = 'implementation'`,
+	).Scan(&total))
+	assert.Equal(t, 3, total)
+
+	loaded, err := backend.Load(ctx)
+	require.NoError(t, err)
+	metadata := loaded.Phases["implementation"].Metadata
+	assert.Equal(t, 3, metadata["tasks_total"])
+	assert.Equal(t, 1.5, metadata["cost_usd"])
+	assert.Equal(t, true, metadata["planning_approved"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"attempt": 2}}, metadata["attempts"])
+}
+
+// TestSQLiteBackend_Load_RejectsOtherSchema tests that databases of another schema version are not misread.
+func TestSQLiteBackend_Load_RejectsOtherSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+	backend := NewSQLiteBackend(path)
+	require.NoError(t, backend.Save(context.Background(), contractState()))
+
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	_, err = db.Exec("PRAGMA user_version = 2")
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	_, err = backend.Load(context.Background())
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrInvalidState))
+	assert.Contains(t, err.Error(), "schema version 2")
+}
+
+// TestSQLiteBackend_Load_EmptyDatabase tests that a database without a project row is treated as missing.
+func TestSQLiteBackend_Load_EmptyDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	_, err = db.Exec(sqliteSchema)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	backend := NewSQLiteBackend(path)
+
+	_, err = backend.Load(context.Background())
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	exists, err := backend.Exists(context.Background())
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+// TestSQLiteBackend_Delete_Missing tests that deleting a missing database wraps the fs error.
+func TestSQLiteBackend_Delete_Missing(t *testing.T) {
+	backend := NewSQLiteBackend(filepath.Join(t.TempDir(), "state.db"))
+
+	err := backend.Delete(context.Background())
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
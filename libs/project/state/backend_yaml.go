@@ -59,8 +59,25 @@ func (b *YAMLBackend) Load(_ context.Context) (*project.ProjectState, error) {
 
 // Save writes project state to the YAML file atomically.
 // Uses temp file + rename pattern to ensure atomic writes.
+// Returns a *ConflictError if the file was saved by someone else since
+// state was loaded.
 func (b *YAMLBackend) Save(_ context.Context, state *project.ProjectState) error {
-	data, err := yaml.Marshal(state)
+	if state == nil {
+		return fmt.Errorf("save project state: nil state: %w", ErrInvalidState)
+	}
+
+	current, err := b.storedRevision()
+	if err != nil {
+		return err
+	}
+	if state.Revision != current {
+		return &ConflictError{Expected: state.Revision, Actual: current}
+	}
+
+	next := *state
+	next.Revision = current + 1
+
+	data, err := yaml.Marshal(&next)
 	if err != nil {
 		return fmt.Errorf("marshal project state: %w", err)
 	}
@@ -75,9 +92,31 @@ func (b *YAMLBackend) Save(_ context.Context, state *project.ProjectState) error
 		return fmt.Errorf("rename temp file: %w", err)
 	}
 
+	state.Revision = next.Revision
 	return nil
 }
 
+// storedRevision returns the revision of the state file on disk.
+// A missing file is treated as revision zero.
+func (b *YAMLBackend) storedRevision() (int64, error) {
+	data, err := b.fs.ReadFile(b.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("read current project state: %w", err)
+	}
+
+	var stored struct {
+		Revision int64 `yaml:"revision"`
+	}
+	if err := yaml.Unmarshal(data, &stored); err != nil {
+		return 0, fmt.Errorf("unmarshal current project state: %w", ErrInvalidState)
+	}
+
+	return stored.Revision, nil
+}
+
 // Exists checks if the project state file exists.
 func (b *YAMLBackend) Exists(_ context.Context) (bool, error) {
 	_, err := b.fs.Stat(b.path)
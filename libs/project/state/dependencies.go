@@ -0,0 +1,173 @@
+package state
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jmgilman/sow/libs/schemas/project"
+)
+
+// Dependencies between tasks are declared with depends_on and are scoped to
+// a phase: a task may only depend on other tasks in the same phase.
+//
+// A dependency is satisfied once the task it names is completed or
+// abandoned. A pending task whose dependencies are all satisfied is ready.
+
+// DependencySatisfied reports whether a task with the given status no longer
+// blocks the tasks that depend on it.
+func DependencySatisfied(status string) bool {
+	return status == "completed" || status == "abandoned"
+}
+
+// ValidateDependencies checks the depends_on lists of a phase's tasks.
+// It returns an error wrapping ErrInvalidDependency if a task depends on an
+// ID that is not in tasks, or if the dependencies form a cycle.
+func ValidateDependencies(tasks []project.TaskState) error {
+	_, err := TopologicalOrder(tasks)
+	return err
+}
+
+// TopologicalOrder returns tasks ordered so that every task comes after the
+// tasks it depends on. Tasks that do not depend on each other keep their
+// order in tasks. It returns an error wrapping ErrInvalidDependency if a
+// dependency is dangling or the dependencies form a cycle.
+func TopologicalOrder(tasks []project.TaskState) ([]project.TaskState, error) {
+	index := make(map[string]int, len(tasks))
+	for i, task := range tasks {
+		index[task.Id] = i
+	}
+
+	// deps[i] holds the positions of the tasks task i depends on.
+	deps := make([][]int, len(tasks))
+	for i, task := range tasks {
+		seen := make(map[int]bool)
+		for _, id := range task.Depends_on {
+			j, ok := index[id]
+			if !ok {
+				return nil, fmt.Errorf("%w: task %s depends on unknown task %s", ErrInvalidDependency, task.Id, id)
+			}
+			if !seen[j] {
+				seen[j] = true
+				deps[i] = append(deps[i], j)
+			}
+		}
+	}
+
+	remaining := make([]int, len(tasks))
+	dependents := make([][]int, len(tasks))
+	for i := range tasks {
+		remaining[i] = len(deps[i])
+		for _, j := range deps[i] {
+			dependents[j] = append(dependents[j], i)
+		}
+	}
+
+	// Repeatedly take the earliest task with no unplaced dependencies.
+	placed := make([]bool, len(tasks))
+	ordered := make([]project.TaskState, 0, len(tasks))
+	for len(ordered) < len(tasks) {
+		next := -1
+		for i := range tasks {
+			if !placed[i] && remaining[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			return nil, fmt.Errorf("%w: dependency cycle %s", ErrInvalidDependency, findCycle(tasks, deps, placed))
+		}
+
+		placed[next] = true
+		ordered = append(ordered, tasks[next])
+		for _, i := range dependents[next] {
+			remaining[i]--
+		}
+	}
+
+	return ordered, nil
+}
+
+// findCycle returns a cycle among the unplaced tasks, formatted as
+// "010 -> 020 -> 010". Every unplaced task is on or behind a cycle, so
+// following dependencies from the first one must revisit a task.
+func findCycle(tasks []project.TaskState, deps [][]int, placed []bool) string {
+	start := 0
+	for placed[start] {
+		start++
+	}
+
+	position := make(map[int]int)
+	var path []int
+	for i := start; ; {
+		if p, ok := position[i]; ok {
+			path = append(path[p:], i)
+			break
+		}
+		position[i] = len(path)
+		path = append(path, i)
+		for _, j := range deps[i] {
+			if !placed[j] {
+				i = j
+				break
+			}
+		}
+	}
+
+	ids := make([]string, len(path))
+	for k, i := range path {
+		ids[k] = tasks[i].Id
+	}
+	return strings.Join(ids, " -> ")
+}
+
+// UnmetDependencies returns the IDs of the tasks that task depends on and
+// that are not yet completed or abandoned, in the order they are declared.
+// IDs not found in tasks are reported as unmet.
+func UnmetDependencies(tasks []project.TaskState, task project.TaskState) []string {
+	status := make(map[string]string, len(tasks))
+	for _, t := range tasks {
+		status[t.Id] = t.Status
+	}
+
+	var unmet []string
+	for _, id := range task.Depends_on {
+		if s, ok := status[id]; !ok || !DependencySatisfied(s) {
+			unmet = append(unmet, id)
+		}
+	}
+	return unmet
+}
+
+// ReadyTasks returns the pending tasks whose dependencies are all satisfied,
+// in topological order.
+func ReadyTasks(tasks []project.TaskState) ([]project.TaskState, error) {
+	ordered, err := TopologicalOrder(tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	var ready []project.TaskState
+	for _, task := range ordered {
+		if task.Status == "pending" && len(UnmetDependencies(tasks, task)) == 0 {
+			ready = append(ready, task)
+		}
+	}
+	return ready, nil
+}
+
+// validateDependencies validates the task dependencies of every phase.
+func validateDependencies(projectState *project.ProjectState) error {
+	names := make([]string, 0, len(projectState.Phases))
+	for name := range projectState.Phases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := ValidateDependencies(projectState.Phases[name].Tasks); err != nil {
+			return fmt.Errorf("phase %s: %w", name, err)
+		}
+	}
+	return nil
+}
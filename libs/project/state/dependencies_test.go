@@ -0,0 +1,94 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/jmgilman/sow/libs/schemas/project"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// depTask returns a task with the given ID, status, and dependencies.
+func depTask(id, status string, dependsOn ...string) project.TaskState {
+	return project.TaskState{Id: id, Status: status, Depends_on: dependsOn}
+}
+
+// taskIDs returns the IDs of tasks in order.
+func taskIDs(tasks []project.TaskState) []string {
+	ids := make([]string, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.Id
+	}
+	return ids
+}
+
+func TestTopologicalOrder(t *testing.T) {
+	t.Run("orders tasks after their dependencies", func(t *testing.T) {
+		tasks := []project.TaskState{
+			depTask("010", "pending", "030"),
+			depTask("020", "pending"),
+			depTask("030", "pending", "020"),
+			depTask("040", "pending"),
+		}
+
+		ordered, err := TopologicalOrder(tasks)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"020", "030", "010", "040"}, taskIDs(ordered))
+	})
+
+	t.Run("keeps phase order without dependencies", func(t *testing.T) {
+		tasks := []project.TaskState{depTask("020", "pending"), depTask("010", "pending")}
+
+		ordered, err := TopologicalOrder(tasks)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"020", "010"}, taskIDs(ordered))
+	})
+
+	t.Run("rejects dangling dependencies", func(t *testing.T) {
+		_, err := TopologicalOrder([]project.TaskState{depTask("010", "pending", "099")})
+
+		require.ErrorIs(t, err, ErrInvalidDependency)
+		assert.EqualError(t, err, "invalid task dependency: task 010 depends on unknown task 099")
+	})
+
+	t.Run("rejects cycles", func(t *testing.T) {
+		tasks := []project.TaskState{
+			depTask("010", "pending"),
+			depTask("020", "pending", "010", "040"),
+			depTask("030", "pending", "020"),
+			depTask("040", "pending", "030"),
+		}
+
+		_, err := TopologicalOrder(tasks)
+
+		require.ErrorIs(t, err, ErrInvalidDependency)
+		assert.EqualError(t, err, "invalid task dependency: dependency cycle 020 -> 040 -> 030 -> 020")
+	})
+
+	t.Run("rejects self dependencies", func(t *testing.T) {
+		err := ValidateDependencies([]project.TaskState{depTask("010", "pending", "010")})
+
+		assert.EqualError(t, err, "invalid task dependency: dependency cycle 010 -> 010")
+	})
+}
+
+func TestReadyTasks(t *testing.T) {
+	tasks := []project.TaskState{
+		depTask("010", "completed"),
+		depTask("020", "abandoned"),
+		depTask("030", "pending", "050"),
+		depTask("040", "in_progress", "010"),
+		depTask("050", "pending", "010", "020"),
+		depTask("060", "pending", "040"),
+		depTask("070", "pending"),
+	}
+
+	ready, err := ReadyTasks(tasks)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"050", "070"}, taskIDs(ready))
+	assert.Equal(t, []string{"050"}, UnmetDependencies(tasks, tasks[2]))
+	assert.Empty(t, UnmetDependencies(tasks, tasks[4]))
+}
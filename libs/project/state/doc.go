@@ -5,6 +5,6 @@
 //   - Phase, Task, and Artifact types
 //   - Collection types for phases, tasks, and artifacts
 //   - Backend interface for storage abstraction
-//   - YAML and memory backend implementations
+//   - YAML, SQLite, and memory backend implementations
 //   - Load/Save operations with CUE validation
 package state
@@ -1,6 +1,9 @@
 package state
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	// ErrNotFound indicates the project state does not exist in storage.
@@ -14,4 +17,35 @@ var (
 
 	// ErrInvalidArtifactType indicates an artifact type is not allowed.
 	ErrInvalidArtifactType = errors.New("invalid artifact type")
+
+	// ErrConflict indicates the stored state was modified after it was loaded.
+	ErrConflict = errors.New("project state conflict")
+
+	// ErrSnapshotNotFound indicates a requested state snapshot does not exist.
+	ErrSnapshotNotFound = errors.New("snapshot not found")
+
+	// ErrInvalidDependency indicates a task depends on an unknown task or
+	// the task dependencies form a cycle.
+	ErrInvalidDependency = errors.New("invalid task dependency")
 )
+
+// ConflictError is returned by Backend.Save when the revision of the state
+// being saved does not match the revision currently in storage.
+// It wraps ErrConflict so callers can check for it with errors.Is.
+type ConflictError struct {
+	// Expected is the revision the caller loaded and attempted to save over.
+	Expected int64
+	// Actual is the revision currently in storage.
+	Actual int64
+}
+
+// Error implements the error interface.
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("project state was modified concurrently (expected revision %d, found %d)",
+		e.Expected, e.Actual)
+}
+
+// Unwrap returns ErrConflict.
+func (e *ConflictError) Unwrap() error {
+	return ErrConflict
+}
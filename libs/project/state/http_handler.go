@@ -0,0 +1,168 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/jmgilman/sow/libs/schemas/project"
+	"gopkg.in/yaml.v3"
+)
+
+// maxHTTPStateSize bounds the size of a project state upload.
+const maxHTTPStateSize = 16 << 20
+
+// BackendOpener returns the backend that stores state for the project
+// identified by key. It is called for every request.
+type BackendOpener func(key string) (Backend, error)
+
+// NewHTTPHandler returns an http.Handler that serves the HTTPBackend
+// protocol, storing each project in the backend returned by open.
+//
+// Conditional saves are delegated to the wrapped backend: the revision from
+// If-Match (or zero for If-None-Match: *) is passed to its compare-and-swap
+// Save, so the server never holds state of its own.
+func NewHTTPHandler(open BackendOpener) http.Handler {
+	h := &httpHandler{open: open}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/projects/{key...}", h.serveProject)
+	return mux
+}
+
+type httpHandler struct {
+	open BackendOpener
+}
+
+func (h *httpHandler) serveProject(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+	if key == "" {
+		http.Error(w, "missing project key", http.StatusBadRequest)
+		return
+	}
+
+	backend, err := h.open(key)
+	if err != nil {
+		httpError(w, err)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.get(w, r, backend)
+	case http.MethodHead:
+		h.head(w, r, backend)
+	case http.MethodPut:
+		h.put(w, r, backend)
+	case http.MethodDelete:
+		h.delete(w, r, backend)
+	default:
+		w.Header().Set("Allow", "GET, HEAD, PUT, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *httpHandler) get(w http.ResponseWriter, r *http.Request, backend Backend) {
+	state, err := backend.Load(r.Context())
+	if err != nil {
+		httpError(w, err)
+		return
+	}
+
+	data, err := yaml.Marshal(state)
+	if err != nil {
+		httpError(w, fmt.Errorf("marshal project state: %w", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", HTTPContentType)
+	w.Header().Set("ETag", formatETag(state.Revision))
+	_, _ = w.Write(data)
+}
+
+func (h *httpHandler) head(w http.ResponseWriter, r *http.Request, backend Backend) {
+	exists, err := backend.Exists(r.Context())
+	if err != nil {
+		httpError(w, err)
+		return
+	}
+	if !exists {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *httpHandler) put(w http.ResponseWriter, r *http.Request, backend Backend) {
+	var expected int64
+	switch ifMatch, ifNoneMatch := r.Header.Get("If-Match"), r.Header.Get("If-None-Match"); {
+	case ifMatch != "":
+		revision, ok := parseETag(ifMatch)
+		if !ok {
+			http.Error(w, fmt.Sprintf("invalid If-Match revision %q", ifMatch), http.StatusBadRequest)
+			return
+		}
+		expected = revision
+	case strings.TrimSpace(ifNoneMatch) == "*":
+		expected = 0
+	default:
+		http.Error(w, "saves require If-Match or If-None-Match: *", http.StatusPreconditionRequired)
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxHTTPStateSize))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("read project state: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	var state project.ProjectState
+	if err := yaml.Unmarshal(data, &state); err != nil {
+		http.Error(w, fmt.Sprintf("unmarshal project state: %v", err), http.StatusBadRequest)
+		return
+	}
+	state.Revision = expected
+
+	if err := backend.Save(r.Context(), &state); err != nil {
+		httpError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", formatETag(state.Revision))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *httpHandler) delete(w http.ResponseWriter, r *http.Request, backend Backend) {
+	exists, err := backend.Exists(r.Context())
+	if err != nil {
+		httpError(w, err)
+		return
+	}
+	if !exists {
+		httpError(w, ErrNotFound)
+		return
+	}
+
+	if err := backend.Delete(r.Context()); err != nil {
+		httpError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// httpError maps backend errors to protocol status codes.
+func httpError(w http.ResponseWriter, err error) {
+	var conflict *ConflictError
+	switch {
+	case errors.As(err, &conflict):
+		w.Header().Set("ETag", formatETag(conflict.Actual))
+		http.Error(w, err.Error(), http.StatusPreconditionFailed)
+	case errors.Is(err, ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrInvalidState):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
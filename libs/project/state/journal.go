@@ -0,0 +1,235 @@
+package state
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/jmgilman/sow/libs/schemas/project"
+)
+
+// DefaultJournalFile is the journal location relative to the .sow directory.
+const DefaultJournalFile = "project/journal.jsonl"
+
+// ActorEnvVar names the environment variable that identifies who is changing
+// the project, e.g. an agent name. When unset the OS user name is used.
+const ActorEnvVar = "SOW_ACTOR"
+
+// JournalKind identifies what a journal entry records.
+type JournalKind string
+
+const (
+	// JournalAdvance records a state machine transition.
+	JournalAdvance JournalKind = "advance"
+	// JournalTaskStatus records a task status change.
+	JournalTaskStatus JournalKind = "task_status"
+	// JournalArtifactApproved records an artifact being approved.
+	JournalArtifactApproved JournalKind = "artifact_approved"
+	// JournalRollback records the project being restored from a snapshot.
+	JournalRollback JournalKind = "rollback"
+)
+
+// GuardResult records a guard condition that was evaluated for a transition.
+type GuardResult struct {
+	Description string `json:"description"`
+	Passed      bool   `json:"passed"`
+}
+
+// JournalEntry is a single line of the project journal.
+//
+// From and To hold states for advance and rollback entries and task
+// statuses for task_status entries. Snapshot is the snapshot taken before an
+// advance, or the one restored by a rollback. Fields that do not apply to
+// the kind are omitted.
+type JournalEntry struct {
+	Time         time.Time     `json:"time"`
+	Kind         JournalKind   `json:"kind"`
+	Actor        string        `json:"actor,omitempty"`
+	Phase        string        `json:"phase,omitempty"`
+	Task         string        `json:"task,omitempty"`
+	Event        string        `json:"event,omitempty"`
+	From         string        `json:"from,omitempty"`
+	To           string        `json:"to,omitempty"`
+	Guards       []GuardResult `json:"guards,omitempty"`
+	ArtifactType string        `json:"artifact_type,omitempty"`
+	ArtifactPath string        `json:"artifact_path,omitempty"`
+	Snapshot     int           `json:"snapshot,omitempty"`
+	Revision     int64         `json:"revision,omitempty"`
+}
+
+// Journal is an append-only log of project changes stored as JSON lines.
+// Entries are never rewritten, so the file is a complete history of the
+// project even though state.yaml only holds the latest state.
+type Journal struct {
+	path string
+}
+
+// NewJournal creates a journal backed by the file at path.
+// The file and its directory are created on first append.
+func NewJournal(path string) *Journal {
+	return &Journal{path: path}
+}
+
+// Path returns the journal file path.
+func (j *Journal) Path() string {
+	return j.path
+}
+
+// Append writes entries to the end of the journal.
+// All entries are written with a single write so concurrent appends from
+// other processes do not interleave within a batch.
+func (j *Journal) Append(entries ...JournalEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, entry := range entries {
+		if err := enc.Encode(entry); err != nil {
+			return fmt.Errorf("encode journal entry: %w", err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(j.path), 0755); err != nil {
+		return fmt.Errorf("create journal directory: %w", err)
+	}
+	f, err := os.OpenFile(j.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open journal: %w", err)
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write journal: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("close journal: %w", err)
+	}
+	return nil
+}
+
+// Read returns all journal entries in the order they were appended.
+// A missing journal has no entries.
+func (j *Journal) Read() ([]JournalEntry, error) {
+	f, err := os.Open(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open journal: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var entries []JournalEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var entry JournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("parse journal line %d: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read journal: %w", err)
+	}
+	return entries, nil
+}
+
+// Actor returns the identity recorded in journal entries.
+// Uses $SOW_ACTOR if set, otherwise the OS user name.
+func Actor() string {
+	if actor := os.Getenv(ActorEnvVar); actor != "" {
+		return actor
+	}
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return "unknown"
+}
+
+// JournalChanges compares two project states and returns entries for the
+// task status changes and artifact approvals between them. Entries carry
+// the revision of after but no time or actor; callers fill those in.
+// Newly added tasks are not reported as status changes.
+func JournalChanges(before, after *project.ProjectState) []JournalEntry {
+	if before == nil || after == nil {
+		return nil
+	}
+
+	phaseNames := make([]string, 0, len(after.Phases))
+	for name := range after.Phases {
+		phaseNames = append(phaseNames, name)
+	}
+	sort.Strings(phaseNames)
+
+	var entries []JournalEntry
+	for _, name := range phaseNames {
+		newPhase := after.Phases[name]
+		oldPhase := before.Phases[name]
+
+		entries = append(entries, approvalChanges(name, "", oldPhase.Inputs, newPhase.Inputs)...)
+		entries = append(entries, approvalChanges(name, "", oldPhase.Outputs, newPhase.Outputs)...)
+
+		oldTasks := make(map[string]project.TaskState, len(oldPhase.Tasks))
+		for _, task := range oldPhase.Tasks {
+			oldTasks[task.Id] = task
+		}
+		for _, task := range newPhase.Tasks {
+			old, existed := oldTasks[task.Id]
+			if existed && old.Status != task.Status {
+				entries = append(entries, JournalEntry{
+					Kind:  JournalTaskStatus,
+					Phase: name,
+					Task:  task.Id,
+					From:  old.Status,
+					To:    task.Status,
+				})
+			}
+			entries = append(entries, approvalChanges(name, task.Id, old.Inputs, task.Inputs)...)
+			entries = append(entries, approvalChanges(name, task.Id, old.Outputs, task.Outputs)...)
+		}
+	}
+
+	for i := range entries {
+		entries[i].Revision = after.Revision
+	}
+	return entries
+}
+
+// approvalChanges returns entries for artifacts that are approved in after
+// but were missing or unapproved in before. Artifacts are matched by type
+// and path since they have no stable identifier.
+func approvalChanges(phase, task string, before, after []project.ArtifactState) []JournalEntry {
+	approved := make(map[[2]string]bool, len(before))
+	for _, a := range before {
+		if a.Approved {
+			approved[[2]string{a.Type, a.Path}] = true
+		}
+	}
+
+	var entries []JournalEntry
+	for _, a := range after {
+		if !a.Approved || approved[[2]string{a.Type, a.Path}] {
+			continue
+		}
+		entries = append(entries, JournalEntry{
+			Kind:         JournalArtifactApproved,
+			Phase:        phase,
+			Task:         task,
+			ArtifactType: a.Type,
+			ArtifactPath: a.Path,
+		})
+	}
+	return entries
+}
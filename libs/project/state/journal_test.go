@@ -0,0 +1,117 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jmgilman/sow/libs/schemas/project"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJournal(t *testing.T) {
+	t.Run("Read on missing journal returns no entries", func(t *testing.T) {
+		journal := NewJournal(filepath.Join(t.TempDir(), "journal.jsonl"))
+
+		entries, err := journal.Read()
+
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("Append creates directory and preserves order", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "project", "journal.jsonl")
+		journal := NewJournal(path)
+		now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+
+		require.NoError(t, journal.Append(JournalEntry{
+			Time:   now,
+			Kind:   JournalAdvance,
+			Actor:  "alice",
+			Phase:  "planning",
+			Event:  "complete_planning",
+			From:   "PlanningActive",
+			To:     "ImplementationPlanning",
+			Guards: []GuardResult{{Description: "task list approved", Passed: true}},
+		}))
+		require.NoError(t, journal.Append(
+			JournalEntry{Time: now, Kind: JournalTaskStatus, Task: "010", From: "pending", To: "in_progress"},
+			JournalEntry{Time: now, Kind: JournalArtifactApproved, ArtifactType: "task_list", ArtifactPath: "tasks.md"},
+		))
+
+		entries, err := journal.Read()
+
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+		assert.Equal(t, JournalAdvance, entries[0].Kind)
+		assert.Equal(t, "alice", entries[0].Actor)
+		assert.Equal(t, []GuardResult{{Description: "task list approved", Passed: true}}, entries[0].Guards)
+		assert.Equal(t, JournalTaskStatus, entries[1].Kind)
+		assert.Equal(t, JournalArtifactApproved, entries[2].Kind)
+		assert.Equal(t, path, journal.Path())
+	})
+
+	t.Run("Read rejects malformed lines", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal.jsonl")
+		require.NoError(t, os.WriteFile(path, []byte("{\"kind\":\"advance\"}\nnot json\n"), 0644))
+
+		_, err := NewJournal(path).Read()
+
+		assert.ErrorContains(t, err, "line 2")
+	})
+}
+
+func TestActor(t *testing.T) {
+	t.Setenv(ActorEnvVar, "implementer")
+	assert.Equal(t, "implementer", Actor())
+
+	t.Setenv(ActorEnvVar, "")
+	assert.NotEmpty(t, Actor())
+}
+
+func TestJournalChanges(t *testing.T) {
+	before := contractState()
+	before.Phases["implementation"].Tasks[0].Status = "pending"
+	before.Phases["implementation"].Outputs[0].Approved = false
+
+	after := copyProjectState(before)
+	after.Revision = 7
+	impl := after.Phases["implementation"]
+	impl.Tasks[0].Status = "in_progress"
+	impl.Tasks[1].Outputs[0].Approved = true
+	impl.Outputs[0].Approved = true
+	impl.Tasks = append(impl.Tasks, project.TaskState{Id: "030", Status: "pending"})
+	after.Phases["implementation"] = impl
+
+	entries := JournalChanges(before, after)
+
+	assert.Equal(t, []JournalEntry{
+		{
+			Kind:         JournalArtifactApproved,
+			Phase:        "implementation",
+			ArtifactType: "task_list",
+			ArtifactPath: "phases/implementation/tasks.md",
+			Revision:     7,
+		},
+		{
+			Kind:     JournalTaskStatus,
+			Phase:    "implementation",
+			Task:     "020",
+			From:     "pending",
+			To:       "in_progress",
+			Revision: 7,
+		},
+		{
+			Kind:         JournalArtifactApproved,
+			Phase:        "implementation",
+			Task:         "010",
+			ArtifactType: "modified",
+			ArtifactPath: "src/main.go",
+			Revision:     7,
+		},
+	}, entries)
+
+	assert.Empty(t, JournalChanges(after, after))
+}
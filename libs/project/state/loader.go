@@ -72,8 +72,9 @@ func LoadFromFS(ctx context.Context, fs core.FS) (*Project, error) {
 //  1. Sync statechart state from machine (if present)
 //  2. Update timestamps
 //  3. Validate structure with CUE
-//  4. Validate metadata with embedded schemas
-//  5. Save to backend
+//  4. Validate task dependencies
+//  5. Validate metadata with embedded schemas
+//  6. Save to backend
 //
 // Returns an error if any step fails. Validation errors prevent writing,
 // ensuring the state file always contains valid data.
@@ -95,14 +96,19 @@ func Save(ctx context.Context, p *Project) error {
 		return fmt.Errorf("validate structure: %w", err)
 	}
 
-	// 4. Validate metadata with embedded schemas
+	// 4. Validate task dependencies
+	if err := validateDependencies(&p.ProjectState); err != nil {
+		return fmt.Errorf("validate dependencies: %w", err)
+	}
+
+	// 5. Validate metadata with embedded schemas
 	if p.config != nil {
 		if err := p.config.Validate(p); err != nil {
 			return fmt.Errorf("validate metadata: %w", err)
 		}
 	}
 
-	// 5. Save to backend
+	// 6. Save to backend
 	if err := p.backend.Save(ctx, &p.ProjectState); err != nil {
 		return fmt.Errorf("save to backend: %w", err)
 	}
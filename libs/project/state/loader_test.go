@@ -467,6 +467,51 @@ func TestSave(t *testing.T) {
 		assert.Contains(t, err.Error(), "validate structure")
 	})
 
+	t.Run("validates task dependencies before saving", func(t *testing.T) {
+		// Setup
+		ClearRegistry()
+		mockCfg := &mockConfig{
+			name:         "standard",
+			initialState: "planning",
+		}
+		RegisterConfig(mockCfg)
+
+		state := validProjectState()
+		backend := NewMemoryBackendWithState(state)
+
+		proj, err := Load(context.Background(), backend)
+		require.NoError(t, err)
+
+		now := time.Now()
+		proj.Phases["implementation"] = project.PhaseState{
+			Status:     "in_progress",
+			Enabled:    true,
+			Created_at: now,
+			Inputs:     []project.ArtifactState{},
+			Outputs:    []project.ArtifactState{},
+			Tasks: []project.TaskState{{
+				Id:             "010",
+				Name:           "Task",
+				Phase:          "implementation",
+				Status:         "pending",
+				Created_at:     now,
+				Updated_at:     now,
+				Iteration:      1,
+				Assigned_agent: "implementer",
+				Depends_on:     []string{"020"},
+				Inputs:         []project.ArtifactState{},
+				Outputs:        []project.ArtifactState{},
+			}},
+		}
+
+		// Act
+		err = Save(context.Background(), proj)
+
+		// Assert
+		require.ErrorIs(t, err, ErrInvalidDependency)
+		assert.EqualError(t, err, "validate dependencies: phase implementation: invalid task dependency: task 010 depends on unknown task 020")
+	})
+
 	t.Run("validates metadata before saving", func(t *testing.T) {
 		// Setup
 		ClearRegistry()
@@ -0,0 +1,230 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultLockFile is the lock file location relative to the .sow directory.
+const DefaultLockFile = "project/state.lock"
+
+// lockPollInterval is how often AcquireLock retries a held lock.
+// It is a variable to allow tests to override it.
+var lockPollInterval = 50 * time.Millisecond
+
+// ErrLocked indicates the project state lock is held by another process.
+var ErrLocked = errors.New("project state is locked")
+
+// LockInfo identifies the process holding a state lock.
+// It is written into the lock file so other processes can report who holds
+// the lock and detect holders that no longer exist.
+type LockInfo struct {
+	PID        int       `json:"pid"`
+	Hostname   string    `json:"hostname"`
+	AcquiredAt time.Time `json:"acquired_at"`
+}
+
+// Stale reports whether the holder is known to be gone: it ran on this host
+// and its PID is no longer alive. Holders on other hosts are never stale,
+// since their liveness cannot be checked.
+func (i *LockInfo) Stale() bool {
+	hostname, err := os.Hostname()
+	if err != nil || hostname != i.Hostname {
+		return false
+	}
+	return !processAlive(i.PID)
+}
+
+// LockedError is returned by AcquireLock when the lock could not be
+// acquired before the timeout. It wraps ErrLocked.
+type LockedError struct {
+	// Path is the lock file.
+	Path string
+	// Holder describes the current holder, or nil if it could not be read.
+	Holder *LockInfo
+}
+
+// Error implements the error interface.
+func (e *LockedError) Error() string {
+	if e.Holder == nil {
+		return fmt.Sprintf("project state is locked (%s)", e.Path)
+	}
+	return fmt.Sprintf("project state is locked by PID %d on %s since %s (%s)",
+		e.Holder.PID, e.Holder.Hostname, e.Holder.AcquiredAt.Format(time.RFC3339), e.Path)
+}
+
+// Unwrap returns ErrLocked.
+func (e *LockedError) Unwrap() error {
+	return ErrLocked
+}
+
+// Lock is an exclusive, cross-process advisory lock on the project state.
+//
+// The lock is taken with flock (LockFileEx on Windows), so the operating
+// system releases it automatically if the holding process dies. The lock
+// file itself is left in place and records the holder's PID for diagnostics.
+type Lock struct {
+	path string
+	file *os.File
+}
+
+// AcquireLock takes the lock at path, creating the file and its parent
+// directory if needed. If another process holds the lock, it polls until
+// the lock is free, the timeout elapses, or ctx is canceled. A timeout of
+// zero makes a single attempt. Locks whose recorded holder is stale are
+// broken rather than waited on.
+//
+// Returns a *LockedError if the lock is still held when the timeout elapses.
+func AcquireLock(ctx context.Context, path string, timeout time.Duration) (*Lock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("create lock directory: %w", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		lock, err := tryAcquireLock(path)
+		if err != nil {
+			return nil, err
+		}
+		if lock != nil {
+			return lock, nil
+		}
+
+		// A lock recorded by a process that no longer exists can only be
+		// left behind where the OS failed to release it (e.g. some network
+		// filesystems). Break it and try again straight away.
+		holder, _ := ReadLockInfo(path)
+		if holder != nil && holder.Stale() {
+			if err := RemoveLock(path); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if !time.Now().Before(deadline) {
+			return nil, &LockedError{Path: path, Holder: holder}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("wait for state lock: %w", ctx.Err())
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+// tryAcquireLock makes a single attempt to take the lock.
+// Returns a nil Lock and nil error if the lock is held elsewhere.
+func tryAcquireLock(path string) (*Lock, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("open lock file: %w", err)
+	}
+
+	locked, err := lockFile(file)
+	if err != nil || !locked {
+		_ = file.Close()
+		return nil, err
+	}
+
+	lock := &Lock{path: path, file: file}
+	if err := lock.writeInfo(); err != nil {
+		_ = lock.Release()
+		return nil, err
+	}
+	return lock, nil
+}
+
+// writeInfo records the current process as the lock holder.
+func (l *Lock) writeInfo() error {
+	hostname, _ := os.Hostname()
+	data, err := json.Marshal(LockInfo{
+		PID:        os.Getpid(),
+		Hostname:   hostname,
+		AcquiredAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("marshal lock info: %w", err)
+	}
+
+	if err := l.file.Truncate(0); err != nil {
+		return fmt.Errorf("write lock info: %w", err)
+	}
+	if _, err := l.file.WriteAt(data, 0); err != nil {
+		return fmt.Errorf("write lock info: %w", err)
+	}
+	return nil
+}
+
+// Path returns the lock file path.
+func (l *Lock) Path() string {
+	return l.path
+}
+
+// Release clears the holder information and releases the lock.
+// The lock file is kept so that waiting processes keep contending on the
+// same file. Release is safe to call more than once, and on a nil Lock.
+func (l *Lock) Release() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+	file := l.file
+	l.file = nil
+
+	_ = file.Truncate(0)
+	unlockErr := unlockFile(file)
+	closeErr := file.Close()
+	if unlockErr != nil {
+		return fmt.Errorf("release state lock: %w", unlockErr)
+	}
+	if closeErr != nil {
+		return fmt.Errorf("release state lock: %w", closeErr)
+	}
+	return nil
+}
+
+// ReadLockInfo returns the holder recorded in the lock file at path.
+// Returns nil (and no error) if the file does not exist or records no holder,
+// which means the lock is free.
+func ReadLockInfo(path string) (*LockInfo, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read lock file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("read lock file: %w", err)
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var info LockInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("parse lock file: %w", err)
+	}
+	return &info, nil
+}
+
+// RemoveLock deletes the lock file at path, breaking any lock held on it.
+// This is an escape hatch for hung processes: a process still holding the
+// old file keeps running unaware, so callers should confirm the holder is
+// gone (see LockInfo.Stale) before removing a lock. Removing a missing
+// lock file is not an error.
+func RemoveLock(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove lock file: %w", err)
+	}
+	return nil
+}
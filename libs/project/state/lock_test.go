@@ -0,0 +1,178 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquireLock(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("creates lock file and records holder", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "project", "state.lock")
+
+		lock, err := AcquireLock(ctx, path, 0)
+		require.NoError(t, err)
+		defer func() { _ = lock.Release() }()
+
+		info, err := ReadLockInfo(path)
+		require.NoError(t, err)
+		require.NotNil(t, info)
+		assert.Equal(t, os.Getpid(), info.PID)
+		assert.False(t, info.Stale())
+		assert.Equal(t, path, lock.Path())
+	})
+
+	t.Run("times out while lock is held", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "state.lock")
+		lock, err := AcquireLock(ctx, path, 0)
+		require.NoError(t, err)
+		defer func() { _ = lock.Release() }()
+
+		_, err = AcquireLock(ctx, path, 100*time.Millisecond)
+
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrLocked))
+		var locked *LockedError
+		require.True(t, errors.As(err, &locked))
+		require.NotNil(t, locked.Holder)
+		assert.Equal(t, os.Getpid(), locked.Holder.PID)
+	})
+
+	t.Run("succeeds once lock is released", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "state.lock")
+		lock, err := AcquireLock(ctx, path, 0)
+		require.NoError(t, err)
+
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			_ = lock.Release()
+		}()
+
+		second, err := AcquireLock(ctx, path, 5*time.Second)
+		require.NoError(t, err)
+		assert.NoError(t, second.Release())
+	})
+
+	t.Run("release clears holder and is idempotent", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "state.lock")
+		lock, err := AcquireLock(ctx, path, 0)
+		require.NoError(t, err)
+
+		require.NoError(t, lock.Release())
+		require.NoError(t, lock.Release())
+
+		info, err := ReadLockInfo(path)
+		require.NoError(t, err)
+		assert.Nil(t, info)
+	})
+
+	t.Run("stops waiting when context is canceled", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "state.lock")
+		lock, err := AcquireLock(ctx, path, 0)
+		require.NoError(t, err)
+		defer func() { _ = lock.Release() }()
+
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err = AcquireLock(canceled, path, time.Minute)
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("takes over lock left by a dead process", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "state.lock")
+		writeLockInfo(t, path, LockInfo{PID: deadPID(t), Hostname: hostname(t), AcquiredAt: time.Now()})
+
+		lock, err := AcquireLock(ctx, path, 0)
+		require.NoError(t, err)
+		defer func() { _ = lock.Release() }()
+
+		info, err := ReadLockInfo(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.Getpid(), info.PID)
+	})
+}
+
+func TestLockInfo_Stale(t *testing.T) {
+	tests := []struct {
+		name string
+		info LockInfo
+		want bool
+	}{
+		{
+			name: "live process on this host",
+			info: LockInfo{PID: os.Getpid(), Hostname: hostname(t)},
+			want: false,
+		},
+		{
+			name: "dead process on this host",
+			info: LockInfo{PID: deadPID(t), Hostname: hostname(t)},
+			want: true,
+		},
+		{
+			name: "process on another host",
+			info: LockInfo{PID: deadPID(t), Hostname: "some-other-host"},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.info.Stale())
+		})
+	}
+}
+
+func TestReadLockInfo_Missing(t *testing.T) {
+	info, err := ReadLockInfo(filepath.Join(t.TempDir(), "state.lock"))
+
+	require.NoError(t, err)
+	assert.Nil(t, info)
+}
+
+func TestRemoveLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.lock")
+	writeLockInfo(t, path, LockInfo{PID: 1, Hostname: "host"})
+
+	require.NoError(t, RemoveLock(path))
+	_, err := os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
+	// Removing again is not an error
+	assert.NoError(t, RemoveLock(path))
+}
+
+func writeLockInfo(t *testing.T, path string, info LockInfo) {
+	t.Helper()
+	data, err := json.Marshal(info)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0644))
+}
+
+// deadPID returns the PID of a process that has already exited.
+func deadPID(t *testing.T) int {
+	t.Helper()
+	exe, err := os.Executable()
+	require.NoError(t, err)
+	cmd := exec.Command(exe, "-test.run=^$")
+	require.NoError(t, cmd.Run())
+	return cmd.ProcessState.Pid()
+}
+
+func hostname(t *testing.T) string {
+	t.Helper()
+	name, err := os.Hostname()
+	require.NoError(t, err)
+	return name
+}
@@ -0,0 +1,63 @@
+//go:build windows
+
+package state
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockRegionOffset is where the locked byte range starts. Windows file locks
+// are mandatory, so the range sits past the holder information to keep the
+// file readable by other processes.
+const lockRegionOffset = 1 << 30
+
+// lockFile attempts a non-blocking exclusive LockFileEx on file.
+// Returns false if another process holds the lock.
+func lockFile(file *os.File) (bool, error) {
+	overlapped := &windows.Overlapped{Offset: lockRegionOffset}
+	err := windows.LockFileEx(
+		windows.Handle(file.Fd()),
+		windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY,
+		0, 1, 0, overlapped,
+	)
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return false, nil
+	}
+	return false, fmt.Errorf("lock file: %w", err)
+}
+
+// unlockFile releases the LockFileEx lock on file.
+func unlockFile(file *os.File) error {
+	overlapped := &windows.Overlapped{Offset: lockRegionOffset}
+	if err := windows.UnlockFileEx(windows.Handle(file.Fd()), 0, 1, 0, overlapped); err != nil {
+		return fmt.Errorf("unlock file: %w", err)
+	}
+	return nil
+}
+
+// processAlive reports whether a process with the given PID exists.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		// Access denied means the process exists but we cannot query it
+		return errors.Is(err, windows.ERROR_ACCESS_DENIED)
+	}
+	defer func() { _ = windows.CloseHandle(handle) }()
+
+	var code uint32
+	if err := windows.GetExitCodeProcess(handle, &code); err != nil {
+		return true
+	}
+	const stillActive = 259
+	return code == stillActive
+}
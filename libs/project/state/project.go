@@ -154,13 +154,45 @@ func (p *Project) PhaseOutputApproved(phaseName, outputType string) bool {
 }
 
 // Save persists the current project state to the backend.
+// Task dependencies are validated first, so a dangling or cyclic depends_on
+// is never written.
 func (p *Project) Save(ctx context.Context) error {
+	if err := validateDependencies(&p.ProjectState); err != nil {
+		return fmt.Errorf("validate dependencies: %w", err)
+	}
 	if err := p.backend.Save(ctx, &p.ProjectState); err != nil {
 		return fmt.Errorf("save project state: %w", err)
 	}
 	return nil
 }
 
+// Snapshot returns a deep copy of the serializable project state.
+// The copy is unaffected by later changes to the project.
+func (p *Project) Snapshot() *project.ProjectState {
+	return copyProjectState(&p.ProjectState)
+}
+
+// Restore replaces the project state with a copy of snapshot and rebuilds
+// the state machine at the restored state, so that Save does not sync the
+// old machine state back. The revision is kept so the next Save is still
+// checked against the stored state.
+//
+// Returns an error if the snapshot belongs to a different project.
+func (p *Project) Restore(snapshot *project.ProjectState) error {
+	if snapshot.Name != p.Name || snapshot.Branch != p.Branch {
+		return fmt.Errorf("snapshot belongs to project %s on branch %s", snapshot.Name, snapshot.Branch)
+	}
+
+	revision := p.Revision
+	p.ProjectState = *copyProjectState(snapshot)
+	p.Revision = revision
+
+	if p.config != nil {
+		p.SetMachine(p.config.BuildMachine(p, p.Statechart.Current_state))
+	}
+	return nil
+}
+
 // SetConfig sets the project type configuration.
 // This is called during Load/Create to attach the config from the registry.
 func (p *Project) SetConfig(config ProjectTypeConfig) {
@@ -412,4 +412,58 @@ func TestProject_Save(t *testing.T) {
 		assert.Equal(t, "test-project", loaded.Name)
 		assert.Equal(t, "standard", loaded.Type)
 	})
+
+	t.Run("rejects invalid task dependencies", func(t *testing.T) {
+		backend := NewMemoryBackend()
+		state := project.ProjectState{
+			Name:   "test-project",
+			Type:   "standard",
+			Branch: "feat/test",
+			Phases: map[string]project.PhaseState{
+				"implementation": {Tasks: []project.TaskState{
+					{Id: "010", Status: "pending", Depends_on: []string{"020"}},
+					{Id: "020", Status: "pending", Depends_on: []string{"010"}},
+				}},
+			},
+		}
+		proj := NewProject(state, backend)
+
+		err := proj.Save(context.Background())
+		require.ErrorIs(t, err, ErrInvalidDependency)
+
+		_, err = backend.Load(context.Background())
+		assert.ErrorIs(t, err, ErrNotFound, "nothing should be saved")
+	})
+}
+
+func TestProject_Restore(t *testing.T) {
+	t.Run("restores state and rebuilds machine", func(t *testing.T) {
+		snapshot := validProjectState()
+		proj := NewProject(*validProjectState(), NewMemoryBackend())
+		proj.SetConfig(&mockConfig{name: "standard"})
+		proj.Revision = 4
+		proj.Statechart.Current_state = "implementation"
+		proj.Description = "Changed"
+
+		require.NoError(t, proj.Restore(snapshot))
+
+		assert.Equal(t, "planning", proj.Statechart.Current_state)
+		assert.Equal(t, "Test project", proj.Description)
+		assert.Equal(t, int64(4), proj.Revision, "restore must keep the loaded revision")
+		require.NotNil(t, proj.Machine())
+		assert.Equal(t, "planning", proj.Machine().MustState())
+
+		proj.Description = "Changed again"
+		assert.Equal(t, "Test project", snapshot.Description, "restore must copy the snapshot")
+	})
+
+	t.Run("rejects snapshot of another project", func(t *testing.T) {
+		snapshot := validProjectState()
+		snapshot.Name = "other-project"
+		proj := NewProject(*validProjectState(), NewMemoryBackend())
+
+		err := proj.Restore(snapshot)
+
+		assert.ErrorContains(t, err, "other-project")
+	})
 }
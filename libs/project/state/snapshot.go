@@ -0,0 +1,172 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmgilman/sow/libs/schemas/project"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultSnapshotDir is the snapshot directory relative to the .sow directory.
+const DefaultSnapshotDir = "project/snapshots"
+
+// Snapshot is a copy of the project state taken before a transition.
+// Snapshots are numbered in the order they were taken, starting at 1.
+type Snapshot struct {
+	Number int                  `yaml:"number"`
+	Time   time.Time            `yaml:"time"`
+	Actor  string               `yaml:"actor,omitempty"`
+	Event  string               `yaml:"event,omitempty"`
+	State  project.ProjectState `yaml:"state"`
+}
+
+// SnapshotStore keeps numbered snapshots as YAML files in a directory.
+type SnapshotStore struct {
+	dir string
+}
+
+// NewSnapshotStore creates a store backed by dir.
+// The directory is created on first save.
+func NewSnapshotStore(dir string) *SnapshotStore {
+	return &SnapshotStore{dir: dir}
+}
+
+// Dir returns the snapshot directory.
+func (s *SnapshotStore) Dir() string {
+	return s.dir
+}
+
+// Save writes snap as the next snapshot and sets its Number.
+func (s *SnapshotStore) Save(snap *Snapshot) error {
+	numbers, err := s.numbers()
+	if err != nil {
+		return err
+	}
+	snap.Number = 1
+	if len(numbers) > 0 {
+		snap.Number = numbers[len(numbers)-1] + 1
+	}
+
+	data, err := yaml.Marshal(snap)
+	if err != nil {
+		return fmt.Errorf("marshal snapshot: %w", err)
+	}
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("create snapshot directory: %w", err)
+	}
+
+	// Write to a temp file and rename so a partial snapshot is never visible
+	path := s.path(snap.Number)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+	return nil
+}
+
+// List returns all snapshots ordered by number.
+func (s *SnapshotStore) List() ([]Snapshot, error) {
+	numbers, err := s.numbers()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]Snapshot, 0, len(numbers))
+	for _, n := range numbers {
+		snap, err := s.Get(n)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, *snap)
+	}
+	return snapshots, nil
+}
+
+// Get returns the snapshot with the given number.
+// Returns ErrSnapshotNotFound if it does not exist.
+func (s *SnapshotStore) Get(number int) (*Snapshot, error) {
+	data, err := os.ReadFile(s.path(number))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("snapshot %d: %w", number, ErrSnapshotNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read snapshot %d: %w", number, err)
+	}
+
+	var snap Snapshot
+	if err := yaml.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("parse snapshot %d: %w", number, err)
+	}
+	return &snap, nil
+}
+
+// Latest returns the most recent snapshot.
+// Returns ErrSnapshotNotFound if there are none.
+func (s *SnapshotStore) Latest() (*Snapshot, error) {
+	numbers, err := s.numbers()
+	if err != nil {
+		return nil, err
+	}
+	if len(numbers) == 0 {
+		return nil, ErrSnapshotNotFound
+	}
+	return s.Get(numbers[len(numbers)-1])
+}
+
+// DiscardFrom removes the snapshot with the given number and all later ones.
+// Used after a rollback, since those snapshots describe states that no
+// longer lead to the current one.
+func (s *SnapshotStore) DiscardFrom(number int) error {
+	numbers, err := s.numbers()
+	if err != nil {
+		return err
+	}
+	for _, n := range numbers {
+		if n < number {
+			continue
+		}
+		if err := os.Remove(s.path(n)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove snapshot %d: %w", n, err)
+		}
+	}
+	return nil
+}
+
+// numbers returns the numbers of stored snapshots in ascending order.
+func (s *SnapshotStore) numbers() ([]int, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read snapshot directory: %w", err)
+	}
+
+	var numbers []int
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".yaml")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		if n, err := strconv.Atoi(name); err == nil && n > 0 {
+			numbers = append(numbers, n)
+		}
+	}
+	sort.Ints(numbers)
+	return numbers, nil
+}
+
+func (s *SnapshotStore) path(number int) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%d.yaml", number))
+}
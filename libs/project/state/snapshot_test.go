@@ -0,0 +1,76 @@
+package state
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotStore(t *testing.T) {
+	t.Run("Latest on empty store returns ErrSnapshotNotFound", func(t *testing.T) {
+		store := NewSnapshotStore(filepath.Join(t.TempDir(), "snapshots"))
+
+		_, err := store.Latest()
+
+		assert.True(t, errors.Is(err, ErrSnapshotNotFound))
+		snapshots, err := store.List()
+		require.NoError(t, err)
+		assert.Empty(t, snapshots)
+	})
+
+	t.Run("Save numbers snapshots and round trips state", func(t *testing.T) {
+		store := NewSnapshotStore(filepath.Join(t.TempDir(), "snapshots"))
+		now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+
+		first := &Snapshot{Time: now, Actor: "alice", Event: "planning_complete", State: *contractState()}
+		require.NoError(t, store.Save(first))
+		second := &Snapshot{Time: now, Event: "all_tasks_complete", State: *contractState()}
+		second.State.Statechart.Current_state = "ImplementationExecuting"
+		require.NoError(t, store.Save(second))
+
+		assert.Equal(t, 1, first.Number)
+		assert.Equal(t, 2, second.Number)
+
+		got, err := store.Get(1)
+		require.NoError(t, err)
+		assert.Equal(t, *first, *got)
+
+		latest, err := store.Latest()
+		require.NoError(t, err)
+		assert.Equal(t, 2, latest.Number)
+		assert.Equal(t, "all_tasks_complete", latest.Event)
+	})
+
+	t.Run("Get missing snapshot returns ErrSnapshotNotFound", func(t *testing.T) {
+		store := NewSnapshotStore(t.TempDir())
+
+		_, err := store.Get(3)
+
+		assert.True(t, errors.Is(err, ErrSnapshotNotFound))
+	})
+
+	t.Run("DiscardFrom removes later snapshots", func(t *testing.T) {
+		dir := t.TempDir()
+		store := NewSnapshotStore(dir)
+		for i := 0; i < 3; i++ {
+			require.NoError(t, store.Save(&Snapshot{State: *contractState()}))
+		}
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0644))
+
+		require.NoError(t, store.DiscardFrom(2))
+
+		snapshots, err := store.List()
+		require.NoError(t, err)
+		require.Len(t, snapshots, 1)
+		assert.Equal(t, 1, snapshots[0].Number)
+
+		next := &Snapshot{State: *contractState()}
+		require.NoError(t, store.Save(next))
+		assert.Equal(t, 2, next.Number)
+	})
+}
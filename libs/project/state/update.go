@@ -0,0 +1,78 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultUpdateAttempts is the number of times UpdateWithRetry applies a
+// mutation before giving up on repeated conflicts.
+const DefaultUpdateAttempts = 5
+
+// retryBackoff returns how long to wait before the given retry attempt.
+// It is a variable to allow tests to override it.
+var retryBackoff = func(attempt int) time.Duration {
+	return time.Duration(attempt) * 20 * time.Millisecond
+}
+
+// UpdateWithRetry applies mutate to the project and saves it.
+//
+// If the save fails with ErrConflict because another process saved in the
+// meantime, the project is reloaded from its backend and mutate is applied
+// again to the fresh copy, up to DefaultUpdateAttempts times. mutate must
+// therefore be safe to run more than once and should only touch the project
+// it is given.
+//
+// Returns the project that was successfully saved, which may be a different
+// instance than p. Errors returned by mutate are returned unchanged.
+func UpdateWithRetry(ctx context.Context, p *Project, mutate func(*Project) error) (*Project, error) {
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = mutate(p); err != nil {
+			return nil, err
+		}
+
+		err = p.Save(ctx)
+		if err == nil {
+			return p, nil
+		}
+		if !errors.Is(err, ErrConflict) {
+			// Only conflicts are worth retrying; validation and storage
+			// errors would fail the same way again
+			return nil, err
+		}
+		if attempt == DefaultUpdateAttempts {
+			break
+		}
+
+		if err := waitForRetry(ctx, retryBackoff(attempt)); err != nil {
+			return nil, err
+		}
+
+		if p, err = Load(ctx, p.backend); err != nil {
+			return nil, fmt.Errorf("reload project: %w", err)
+		}
+	}
+
+	return nil, fmt.Errorf("save project after %d attempts: %w", DefaultUpdateAttempts, err)
+}
+
+// waitForRetry sleeps for the backoff duration, returning early with an
+// error if the context is canceled.
+func waitForRetry(ctx context.Context, backoff time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("retry project update: %w", err)
+	}
+
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("retry project update: %w", ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
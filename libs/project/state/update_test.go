@@ -0,0 +1,160 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmgilman/sow/libs/schemas/project"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupUpdateTest registers a mock config, disables retry backoff, and
+// returns a backend holding a saved project.
+func setupUpdateTest(t *testing.T) *MemoryBackend {
+	t.Helper()
+
+	ClearRegistry()
+	RegisterConfig(&mockConfig{name: "standard", initialState: "planning"})
+
+	original := retryBackoff
+	retryBackoff = func(int) time.Duration { return 0 }
+	t.Cleanup(func() { retryBackoff = original })
+
+	backend := NewMemoryBackend()
+	require.NoError(t, backend.Save(context.Background(), validProjectState()))
+	return backend
+}
+
+// concurrentWrite loads the project separately and saves a change to it,
+// simulating another process writing between our load and save.
+func concurrentWrite(t *testing.T, backend Backend, agent string) {
+	t.Helper()
+	other, err := Load(context.Background(), backend)
+	require.NoError(t, err)
+	other.Agent_sessions = map[string]string{agent: "sess-" + agent}
+	require.NoError(t, other.Save(context.Background()))
+}
+
+func TestUpdateWithRetry(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("saves on first attempt without conflict", func(t *testing.T) {
+		backend := setupUpdateTest(t)
+		proj, err := Load(ctx, backend)
+		require.NoError(t, err)
+
+		calls := 0
+		saved, err := UpdateWithRetry(ctx, proj, func(p *Project) error {
+			calls++
+			p.Description = "updated"
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Same(t, proj, saved)
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, "updated", backend.State().Description)
+		assert.Equal(t, int64(2), backend.State().Revision)
+	})
+
+	t.Run("reloads and reapplies after a concurrent write", func(t *testing.T) {
+		backend := setupUpdateTest(t)
+		proj, err := Load(ctx, backend)
+		require.NoError(t, err)
+
+		concurrentWrite(t, backend, "reviewer")
+
+		calls := 0
+		saved, err := UpdateWithRetry(ctx, proj, func(p *Project) error {
+			calls++
+			p.Description = "updated"
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, calls)
+		assert.NotSame(t, proj, saved)
+		assert.Equal(t, "updated", backend.State().Description)
+		assert.Equal(t, map[string]string{"reviewer": "sess-reviewer"}, backend.State().Agent_sessions,
+			"concurrent write must not be lost")
+		assert.Equal(t, int64(3), backend.State().Revision)
+	})
+
+	t.Run("returns mutate error without saving", func(t *testing.T) {
+		backend := setupUpdateTest(t)
+		proj, err := Load(ctx, backend)
+		require.NoError(t, err)
+		mutateErr := errors.New("task not found")
+
+		_, err = UpdateWithRetry(ctx, proj, func(p *Project) error {
+			p.Description = "never saved"
+			return mutateErr
+		})
+
+		assert.Same(t, mutateErr, err)
+		assert.Equal(t, "Test project", backend.State().Description)
+		assert.Equal(t, int64(1), backend.State().Revision)
+	})
+
+	t.Run("returns save error without retrying", func(t *testing.T) {
+		backend := setupUpdateTest(t)
+		proj, err := Load(ctx, backend)
+		require.NoError(t, err)
+
+		calls := 0
+		_, err = UpdateWithRetry(ctx, proj, func(p *Project) error {
+			calls++
+			p.Phases["implementation"] = project.PhaseState{Tasks: []project.TaskState{
+				{Id: "010", Status: "pending", Depends_on: []string{"099"}},
+			}}
+			return nil
+		})
+
+		require.ErrorIs(t, err, ErrInvalidDependency)
+		assert.NotContains(t, err.Error(), "attempts")
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, int64(1), backend.State().Revision)
+	})
+
+	t.Run("gives up after repeated conflicts", func(t *testing.T) {
+		backend := setupUpdateTest(t)
+		proj, err := Load(ctx, backend)
+		require.NoError(t, err)
+
+		calls := 0
+		_, err = UpdateWithRetry(ctx, proj, func(p *Project) error {
+			calls++
+			concurrentWrite(t, backend, "writer")
+			p.Description = "never saved"
+			return nil
+		})
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrConflict)
+		assert.Equal(t, DefaultUpdateAttempts, calls)
+		assert.Equal(t, "Test project", backend.State().Description)
+	})
+
+	t.Run("stops retrying when context is canceled", func(t *testing.T) {
+		backend := setupUpdateTest(t)
+		proj, err := Load(ctx, backend)
+		require.NoError(t, err)
+		concurrentWrite(t, backend, "reviewer")
+
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		calls := 0
+		_, err = UpdateWithRetry(canceled, proj, func(_ *Project) error {
+			calls++
+			return nil
+		})
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, calls)
+	})
+}
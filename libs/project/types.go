@@ -1,6 +1,8 @@
 package project
 
 import (
+	"fmt"
+
 	"github.com/jmgilman/sow/libs/project/state"
 )
 
@@ -38,9 +40,76 @@ type Guard func() bool
 // via closure. It receives the project and returns whether transition is allowed.
 // The Description provides a human-readable explanation of what the guard checks,
 // which appears in error messages when the guard fails.
+//
+// Check optionally explains a failure. Guards configured with
+// WithProjectGuardResult set it so that callers can list each unmet
+// condition instead of only the description.
 type GuardTemplate struct {
 	Description string
 	Func        func(*state.Project) bool
+	Check       func(*state.Project) GuardResult
+}
+
+// newGuardTemplate returns a guard template whose Func is derived from a
+// structured check.
+func newGuardTemplate(description string, check func(*state.Project) GuardResult) GuardTemplate {
+	return GuardTemplate{
+		Description: description,
+		Func: func(p *state.Project) bool {
+			return check(p).Passed()
+		},
+		Check: check,
+	}
+}
+
+// Evaluate runs the guard against a project and returns the met and unmet
+// conditions. Guards without a Check report their description as the
+// condition. A passing check that records no met condition reports its
+// description as met. A template with no guard function always passes.
+func (g GuardTemplate) Evaluate(p *state.Project) GuardResult {
+	var result GuardResult
+	switch {
+	case g.Check != nil:
+		result = g.Check(p)
+	case g.Func != nil && !g.Func(p):
+		result.Addf("%s", g.Description)
+	}
+	if g.Description != "" && result.Passed() && len(result.Met) == 0 {
+		result.Metf("%s", g.Description)
+	}
+	return result
+}
+
+// GuardResult is the outcome of evaluating a guard. It lists each condition
+// that is not met, e.g. "task 020 not complete (in_progress)", and each
+// condition that is, which explains why a transition was allowed. A result
+// with no unmet conditions passes.
+type GuardResult struct {
+	Unmet []string
+	Met   []string
+}
+
+// Passed reports whether every condition was met.
+func (r GuardResult) Passed() bool {
+	return len(r.Unmet) == 0
+}
+
+// Addf records an unmet condition.
+func (r *GuardResult) Addf(format string, args ...any) {
+	r.Unmet = append(r.Unmet, fmt.Sprintf(format, args...))
+}
+
+// Metf records a met condition.
+func (r *GuardResult) Metf(format string, args ...any) {
+	r.Met = append(r.Met, fmt.Sprintf(format, args...))
+}
+
+// Merge records the met and unmet conditions of other results.
+func (r *GuardResult) Merge(others ...GuardResult) {
+	for _, other := range others {
+		r.Unmet = append(r.Unmet, other.Unmet...)
+		r.Met = append(r.Met, other.Met...)
+	}
 }
 
 // Action is a function that mutates project state during transitions.
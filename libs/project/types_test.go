@@ -3,6 +3,7 @@ package project
 import (
 	"testing"
 
+	"github.com/jmgilman/sow/libs/project/state"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -40,3 +41,79 @@ func TestEvent_String(t *testing.T) {
 		})
 	}
 }
+
+func TestGuardResult(t *testing.T) {
+	var result GuardResult
+	assert.True(t, result.Passed())
+
+	result.Addf("task %s not complete (%s)", "020", "pending")
+	result.Merge(GuardResult{}, GuardResult{Unmet: []string{"review output missing"}})
+
+	assert.False(t, result.Passed())
+	assert.Equal(t, []string{"task 020 not complete (pending)", "review output missing"}, result.Unmet)
+
+	result.Metf("plan approved")
+	result.Merge(GuardResult{Met: []string{"all 2 implementation tasks complete"}})
+	assert.Equal(t, []string{"plan approved", "all 2 implementation tasks complete"}, result.Met)
+}
+
+func TestGuardTemplate_Evaluate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template GuardTemplate
+		want     []string
+	}{
+		{
+			name:     "no guard passes",
+			template: GuardTemplate{},
+		},
+		{
+			name: "failing plain guard reports its description",
+			template: GuardTemplate{
+				Description: "plan approved",
+				Func:        func(*state.Project) bool { return false },
+			},
+			want: []string{"plan approved"},
+		},
+		{
+			name: "passing plain guard",
+			template: GuardTemplate{
+				Description: "plan approved",
+				Func:        func(*state.Project) bool { return true },
+			},
+		},
+		{
+			name: "check reports unmet conditions",
+			template: newGuardTemplate("plan approved", func(*state.Project) GuardResult {
+				return GuardResult{Unmet: []string{"task 010 description not approved"}}
+			}),
+			want: []string{"task 010 description not approved"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.template.Evaluate(&state.Project{})
+			assert.Equal(t, tt.want, got.Unmet)
+		})
+	}
+}
+
+func TestGuardTemplate_EvaluateMet(t *testing.T) {
+	plain := GuardTemplate{
+		Description: "plan approved",
+		Func:        func(*state.Project) bool { return true },
+	}
+	assert.Equal(t, []string{"plan approved"}, plain.Evaluate(&state.Project{}).Met)
+
+	detailed := newGuardTemplate("all tasks complete", func(*state.Project) GuardResult {
+		return GuardResult{Met: []string{"all 3 implementation tasks complete"}}
+	})
+	assert.Equal(t, []string{"all 3 implementation tasks complete"}, detailed.Evaluate(&state.Project{}).Met)
+
+	failing := newGuardTemplate("plan approved", func(*state.Project) GuardResult {
+		return GuardResult{Unmet: []string{"plan not approved"}}
+	})
+	assert.Empty(t, failing.Evaluate(&state.Project{}).Met)
+
+	assert.Empty(t, GuardTemplate{}.Evaluate(&state.Project{}).Met)
+}
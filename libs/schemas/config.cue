@@ -3,7 +3,8 @@ package schemas
 // Config defines the schema for the sow configuration file at:
 // .sow/config.yaml
 //
-// This allows teams to customize where formal artifacts are stored.
+// This allows teams to customize where formal artifacts are stored and
+// how project state is persisted.
 #Config: {
 	// Artifact storage locations
 	// All paths are relative to repository root
@@ -16,4 +17,102 @@ package schemas
 		// Default: ".sow/knowledge/design"
 		design_docs?: string @go(,optional=nillable)
 	} @go(,optional=nillable)
+
+	// Project state storage
+	state?: #StateConfig @go(,optional=nillable)
+
+	// Shell hooks run around `sow advance` transitions
+	hooks?: #HooksConfig @go(,optional=nillable)
+
+	// Verification commands keyed by phase name. `sow advance` runs them
+	// from the repository root before any transition that completes the
+	// phase, and refuses the transition if one exits non-zero. Results are
+	// recorded in the phase's metadata.verification.
+	// Example: {implementation: ["go test ./...", "golangci-lint run"]}
+	verify?: [string]: [...string]
+
+	// Maximum time each verification command may run, as a Go duration
+	// (e.g. "30m"). A command still running is killed and fails.
+	// Default: "10m"
+	verify_timeout?: string @go(,optional=nillable)
+
+	// Agent spend limits. `sow agent spawn` and `sow agent resume` refuse to
+	// start an agent once the spend recorded in the session logs reaches a
+	// limit, and warn when it nears one.
+	budget?: #BudgetConfig @go(,optional=nillable)
+}
+
+// StateConfig selects where project state is persisted.
+#StateConfig: {
+	// Storage backend for project state
+	// Default: "yaml"
+	backend?: "yaml" | "sqlite" | "http" @go(,optional=nillable)
+
+	// Location of the state file, relative to .sow/
+	// Default: "project/state.yaml" (yaml) or "project/state.db" (sqlite)
+	path?: string @go(,optional=nillable)
+
+	// Base URL of a state server (see `sow serve`) for the http backend.
+	// Projects are stored under their branch name.
+	// Example: "http://localhost:7070"
+	url?: string @go(,optional=nillable)
+
+	// How long to wait for the project state lock held by another sow
+	// process, as a Go duration (e.g. "30s"). "0s" fails immediately.
+	// Default: "10s"
+	lock_timeout?: string @go(,optional=nillable)
+}
+
+// HooksConfig lists shell commands run when `sow advance` moves the project
+// between states. Each command runs with `sh -c` from the repository root,
+// with the project and transition described in SOW_* environment variables.
+// Hook output is recorded in the project log (.sow/project/log.md).
+// Hooks run without the project state lock held, so they may run sow
+// commands that change the project.
+#HooksConfig: {
+	// Commands run before every transition. A non-zero exit vetoes it.
+	pre_advance?: [...string]
+
+	// Commands run after a transition has been saved. A failure is reported
+	// but does not undo the transition.
+	post_advance?: [...string]
+
+	// Commands run before leaving a state, keyed by state name.
+	// A non-zero exit vetoes the transition.
+	// Example: {ImplementationExecuting: ["make test"]}
+	on_exit?: [string]: [...string]
+
+	// Commands run when entering a state, keyed by state name. They run
+	// before the transition is saved, so a non-zero exit vetoes it.
+	// Example: {ReviewActive: ["make lint"]}
+	on_enter?: [string]: [...string]
+
+	// Maximum time each command may run, as a Go duration (e.g. "2m").
+	// Default: "5m"
+	timeout?: string @go(,optional=nillable)
+}
+
+// BudgetConfig limits what agents may spend on a project and on each task.
+// Spend is summed from the result events of the sessions' stream-json logs
+// (see `sow agent stats`).
+#BudgetConfig: {
+	// Limit on the spend of all agent sessions of the project
+	project?: #BudgetLimit @go(,optional=nillable)
+
+	// Limit on the spend of each task's session
+	task?: #BudgetLimit @go(,optional=nillable)
+
+	// Fraction of a limit at which sow warns that it is nearly spent
+	// Default: 0.8
+	warn_at?: number @go(,type=*float64)
+}
+
+// BudgetLimit caps spend in US dollars, tokens, or both. Tokens include
+// cache tokens.
+#BudgetLimit: {
+	// Maximum cost in US dollars
+	usd?: number @go(,type=*float64)
+
+	// Maximum number of tokens
+	tokens?: int @go(,optional=nillable)
 }
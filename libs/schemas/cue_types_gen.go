@@ -9,7 +9,8 @@ import (
 // Config defines the schema for the sow configuration file at:
 // .sow/config.yaml
 //
-// This allows teams to customize where formal artifacts are stored.
+// This allows teams to customize where formal artifacts are stored and
+// how project state is persisted.
 type Config struct {
 	// Artifact storage locations
 	// All paths are relative to repository root
@@ -22,6 +23,104 @@ type Config struct {
 		// Default: ".sow/knowledge/design"
 		Design_docs *string `json:"design_docs,omitempty"`
 	} `json:"artifacts,omitempty"`
+
+	// Project state storage
+	State *StateConfig `json:"state,omitempty"`
+
+	// Shell hooks run around `sow advance` transitions
+	Hooks *HooksConfig `json:"hooks,omitempty"`
+
+	// Verification commands keyed by phase name. `sow advance` runs them
+	// from the repository root before any transition that completes the
+	// phase, and refuses the transition if one exits non-zero. Results are
+	// recorded in the phase's metadata.verification.
+	// Example: {implementation: ["go test ./...", "golangci-lint run"]}
+	Verify map[string][]string `json:"verify,omitempty"`
+
+	// Maximum time each verification command may run, as a Go duration
+	// (e.g. "30m"). A command still running is killed and fails.
+	// Default: "10m"
+	Verify_timeout *string `json:"verify_timeout,omitempty"`
+
+	// Agent spend limits. `sow agent spawn` and `sow agent resume` refuse to
+	// start an agent once the spend recorded in the session logs reaches a
+	// limit, and warn when it nears one.
+	Budget *BudgetConfig `json:"budget,omitempty"`
+}
+
+// StateConfig selects where project state is persisted.
+type StateConfig struct {
+	// Storage backend for project state
+	// Default: "yaml"
+	Backend *string `json:"backend,omitempty"`
+
+	// Location of the state file, relative to .sow/
+	// Default: "project/state.yaml" (yaml) or "project/state.db" (sqlite)
+	Path *string `json:"path,omitempty"`
+
+	// Base URL of a state server (see `sow serve`) for the http backend.
+	// Projects are stored under their branch name.
+	// Example: "http://localhost:7070"
+	Url *string `json:"url,omitempty"`
+
+	// How long to wait for the project state lock held by another sow
+	// process, as a Go duration (e.g. "30s"). "0s" fails immediately.
+	// Default: "10s"
+	Lock_timeout *string `json:"lock_timeout,omitempty"`
+}
+
+// HooksConfig lists shell commands run when `sow advance` moves the project
+// between states. Each command runs with `sh -c` from the repository root,
+// with the project and transition described in SOW_* environment variables.
+// Hook output is recorded in the project log (.sow/project/log.md).
+// Hooks run without the project state lock held, so they may run sow
+// commands that change the project.
+type HooksConfig struct {
+	// Commands run before every transition. A non-zero exit vetoes it.
+	Pre_advance []string `json:"pre_advance,omitempty"`
+
+	// Commands run after a transition has been saved. A failure is reported
+	// but does not undo the transition.
+	Post_advance []string `json:"post_advance,omitempty"`
+
+	// Commands run before leaving a state, keyed by state name.
+	// A non-zero exit vetoes the transition.
+	// Example: {ImplementationExecuting: ["make test"]}
+	On_exit map[string][]string `json:"on_exit,omitempty"`
+
+	// Commands run when entering a state, keyed by state name. They run
+	// before the transition is saved, so a non-zero exit vetoes it.
+	// Example: {ReviewActive: ["make lint"]}
+	On_enter map[string][]string `json:"on_enter,omitempty"`
+
+	// Maximum time each command may run, as a Go duration (e.g. "2m").
+	// Default: "5m"
+	Timeout *string `json:"timeout,omitempty"`
+}
+
+// BudgetConfig limits what agents may spend on a project and on each task.
+// Spend is summed from the result events of the sessions' stream-json logs
+// (see `sow agent stats`).
+type BudgetConfig struct {
+	// Limit on the spend of all agent sessions of the project
+	Project *BudgetLimit `json:"project,omitempty"`
+
+	// Limit on the spend of each task's session
+	Task *BudgetLimit `json:"task,omitempty"`
+
+	// Fraction of a limit at which sow warns that it is nearly spent
+	// Default: 0.8
+	Warn_at *float64 `json:"warn_at,omitempty"`
+}
+
+// BudgetLimit caps spend in US dollars, tokens, or both. Tokens include
+// cache tokens.
+type BudgetLimit struct {
+	// Maximum cost in US dollars
+	Usd *float64 `json:"usd,omitempty"`
+
+	// Maximum number of tokens
+	Tokens *int64 `json:"tokens,omitempty"`
 }
 
 // KnowledgeIndex defines the schema for the knowledge index at:
@@ -72,6 +171,138 @@ type ArtifactReference struct {
 	Tags []string `json:"tags"`
 }
 
+// ProjectTypeDefinition defines a project type declared in
+// .sow/types/<name>.cue instead of Go code.
+//
+// The definition describes the type's phases, its state machine, and the
+// prompt templates shown in each state. Guards are built from a small
+// vocabulary of predicates over project state (see #GuardDefinition).
+//
+// Example:
+//
+//	name:          "review"
+//	description:   "Review an existing change"
+//	branch_prefix: "review/"
+//	initial_state: "Reviewing"
+//	phases: review: {
+//		start_state: "Reviewing"
+//		end_state:   "Reviewing"
+//		outputs: ["review"]
+//	}
+//	transitions: [{
+//		from:  "Reviewing"
+//		to:    "Done"
+//		event: "complete_review"
+//		guards: [{predicate: "output_approved", phase: "review", type: "review"}]
+//	}]
+type ProjectTypeDefinition struct {
+	// Project type name stored in project state.
+	// Must match the file name (<name>.cue).
+	Name string `json:"name"`
+
+	// Short description shown when choosing a project type
+	Description string `json:"description,omitempty"`
+
+	// Branch name prefix for new projects of this type
+	// Default: "feat/"
+	Branch_prefix string `json:"branch_prefix,omitempty"`
+
+	// State new projects start in. Must be the start state of a phase,
+	// which becomes the active phase of a new project.
+	Initial_state string `json:"initial_state"`
+
+	// Orchestrator prompt template, relative to .sow/types/
+	Orchestrator_prompt string `json:"orchestrator_prompt,omitempty"`
+
+	// Phases keyed by phase name
+	Phases map[string]PhaseDefinition `json:"phases"`
+
+	// Per-state settings keyed by state name
+	States map[string]StateDefinition `json:"states,omitempty"`
+
+	// State machine transitions
+	Transitions []TransitionDefinition `json:"transitions"`
+}
+
+// PhaseDefinition declares one phase of a project type.
+type PhaseDefinition struct {
+	// State in which the phase begins
+	Start_state string `json:"start_state"`
+
+	// State in which the phase ends (may equal start_state)
+	End_state string `json:"end_state"`
+
+	// Allowed input artifact types (empty allows any)
+	Inputs []string `json:"inputs,omitempty"`
+
+	// Allowed output artifact types (empty allows any)
+	Outputs []string `json:"outputs,omitempty"`
+
+	// Whether the phase supports tasks
+	Tasks bool `json:"tasks,omitempty"`
+
+	// CUE schema for the phase metadata, e.g. "{ approved?: bool }"
+	Metadata_schema string `json:"metadata_schema,omitempty"`
+}
+
+// StateDefinition holds settings for one state of a project type.
+type StateDefinition struct {
+	// Prompt template for the state, relative to .sow/types/
+	Prompt string `json:"prompt,omitempty"`
+
+	// Agent 'sow run' spawns in the state when it has no tasks to run
+	Agent string `json:"agent,omitempty"`
+}
+
+// TransitionDefinition declares a state machine transition.
+type TransitionDefinition struct {
+	// Source state
+	From string `json:"from"`
+
+	// Target state
+	To string `json:"to"`
+
+	// Event that triggers the transition
+	Event string `json:"event"`
+
+	// Human-readable description of the transition
+	Description string `json:"description,omitempty"`
+
+	// Conditions that must all hold for the transition to fire
+	Guards []GuardDefinition `json:"guards,omitempty"`
+
+	// Phase to mark "failed" instead of "completed" when this transition
+	// leaves the phase's end state
+	Failed_phase string `json:"failed_phase,omitempty"`
+}
+
+// GuardDefinition is a transition condition built from a predicate:
+//
+//	output_approved     an output of the given type is approved in phase
+//	all_tasks_complete  phase has tasks and all are completed or abandoned
+//	metadata_bool       phase metadata key is the boolean value (default true)
+type GuardDefinition struct {
+	// Predicate to evaluate
+	Predicate string `json:"predicate"`
+
+	// Phase the predicate examines
+	Phase string `json:"phase"`
+
+	// Output artifact type (required for output_approved)
+	Type string `json:"type,omitempty"`
+
+	// Metadata key (required for metadata_bool)
+	Key string `json:"key,omitempty"`
+
+	// Expected metadata value (metadata_bool)
+	// Default: true
+	Value *bool `json:"value,omitempty"`
+
+	// Description shown when the guard fails
+	// Default: generated from the predicate
+	Description string `json:"description,omitempty"`
+}
+
 // RefsCacheIndex defines the schema for ~/.cache/sow/index.json
 //
 // This is the cache index containing transient metadata about cached
@@ -234,41 +465,100 @@ type RefsLocalIndex struct {
 // This allows users to configure which AI CLI executors handle which agent roles.
 type UserConfig struct {
 	// Agent configuration
-	Agents *struct {
-		// Executor definitions
-		// Keys are executor names (e.g., "claude-code", "cursor")
-		Executors map[string]struct {
-			// Type of executor
-			Type string `json:"type"`
+	Agents *AgentsConfig `json:"agents,omitempty"`
+}
 
-			// Executor settings
-			Settings *struct {
-				// Skip permission prompts
-				Yolo_mode *bool `json:"yolo_mode,omitempty"`
+// AgentsConfig defines the executors and which agents they run.
+type AgentsConfig struct {
+	// Executor definitions
+	// Keys are executor names (e.g., "claude-code", "cursor")
+	Executors map[string]ExecutorConfig `json:"executors,omitempty"`
 
-				// AI model to use (only meaningful for claude type)
-				Model *string `json:"model,omitempty"`
-			} `json:"settings,omitempty"`
+	// Bindings from agent names to executor names. Keys are standard agent
+	// roles (e.g., "implementer") or custom agents from .sow/agents/.
+	Bindings map[string]string `json:"bindings,omitempty"`
 
-			// Additional CLI arguments
-			Custom_args []string `json:"custom_args,omitempty"`
-		} `json:"executors,omitempty"`
+	// Execution policies for agent roles, overriding the policy of the
+	// executor the role is bound to field by field
+	Policies map[string]ExecutionPolicy `json:"policies,omitempty"`
+}
+
+// ExecutorConfig defines one executor: an agent CLI and how sow invokes it.
+type ExecutorConfig struct {
+	// Type of executor
+	Type string `json:"type"`
 
-		// Bindings from agent roles to executor names
-		Bindings *struct {
-			Orchestrator *string `json:"orchestrator,omitempty"`
+	// Executor settings
+	Settings *struct {
+		// Skip permission prompts
+		Yolo_mode *bool `json:"yolo_mode,omitempty"`
 
-			Implementer *string `json:"implementer,omitempty"`
+		// AI model to use (claude type, or {{.Model}} for command type)
+		Model *string `json:"model,omitempty"`
+	} `json:"settings,omitempty"`
 
-			Architect *string `json:"architect,omitempty"`
+	// Additional CLI arguments
+	Custom_args []string `json:"custom_args,omitempty"`
 
-			Reviewer *string `json:"reviewer,omitempty"`
+	// How to invoke the program (required for command type)
+	Command *CommandExecutorConfig `json:"command,omitempty"`
 
-			Planner *string `json:"planner,omitempty"`
+	// YAML script of the steps each agent performs (required for scripted
+	// type). A stand-in for a real agent in end-to-end tests; relative
+	// paths are resolved from the directory sow runs in.
+	Script string `json:"script,omitempty"`
 
-			Researcher *string `json:"researcher,omitempty"`
+	// Timeout and retry policy for this executor's runs
+	Policy *ExecutionPolicy `json:"policy,omitempty"`
+}
+
+// CommandExecutorConfig describes an arbitrary agent CLI. Arguments are Go
+// templates with the fields .Prompt (agent prompt plus task prompt when
+// spawning), .TaskPrompt, .SessionID, .Model, and .AgentPromptPath (a file
+// holding the agent prompt, only set when spawning).
+type CommandExecutorConfig struct {
+	// Program to run, looked up on PATH
+	Program string `json:"program"`
+
+	// Arguments for starting a new session
+	// Example: ["--message", "{{.Prompt}}", "--model", "{{.Model}}"]
+	Spawn_args []string `json:"spawn_args"`
+
+	// Arguments for resuming a session. Without them the executor does not
+	// support resumption.
+	Resume_args []string `json:"resume_args,omitempty"`
+
+	// How the prompt is passed: on stdin, or in the arguments via {{.Prompt}}
+	// Default: "stdin"
+	Prompt *string `json:"prompt,omitempty"`
+
+	// Format of the program's stdout: "stream-json" (Claude Code events,
+	// formatted into the session log) or "text" (logged as is)
+	// Default: "text"
+	Output *string `json:"output,omitempty"`
+}
 
-			Decomposer *string `json:"decomposer,omitempty"`
-		} `json:"bindings,omitempty"`
-	} `json:"agents,omitempty"`
+// ExecutionPolicy bounds how long an agent may run and whether failed runs
+// are retried. Each attempt is recorded in the task's metadata.
+type ExecutionPolicy struct {
+	// Maximum wall time of one attempt, as a Go duration (e.g. "30m")
+	// Default: no limit
+	Timeout *string `json:"timeout,omitempty"`
+
+	// How many times a failed attempt is retried
+	// Default: 0
+	Max_retries *int64 `json:"max_retries,omitempty"`
+
+	// Delay before the first retry, doubled for each further retry
+	// Default: "10s"
+	Backoff *string `json:"backoff,omitempty"`
+
+	// Upper bound of the delay between retries
+	// Default: "5m"
+	Max_backoff *string `json:"max_backoff,omitempty"`
+
+	// Failures that are retried: "timeout", "error" (any failure), or
+	// "exit:<code>" for a specific exit code
+	// Default: ["timeout", "error"]
+	Retry_on []string `json:"retry_on,omitempty"`
 }
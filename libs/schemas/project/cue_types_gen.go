@@ -141,6 +141,12 @@ type TaskState struct {
 	// Used by executors that support session resumption (claude, cursor).
 	Session_id string `json:"session_id,omitempty"`
 
+	// depends_on lists the IDs of tasks in the same phase that must be
+	// completed (or abandoned) before this task is ready to start.
+	// Every ID must name an existing task, and dependencies may not form a
+	// cycle. Example: ["010", "020"]
+	Depends_on []string `json:"depends_on,omitempty"`
+
 	// inputs is the list of artifacts that this task consumes.
 	// These provide context and requirements for completing the task.
 	// Examples: design documents, specifications, feedback files.
@@ -198,6 +204,11 @@ type ProjectState struct {
 	// without an associated task. Keys are agent names, values are session UUIDs.
 	// Example: {"planner": "550e8400-e29b-41d4-a716-446655440000"}
 	Agent_sessions map[string]string `json:"agent_sessions,omitempty"`
+
+	// revision is the optimistic concurrency counter for this state.
+	// Backends increment it on every successful save and reject a save whose
+	// revision does not match the stored value. Zero for unsaved state.
+	Revision int64 `json:"revision,omitempty"`
 }
 
 // StatechartState represents the current position in a project's state machine.
@@ -43,6 +43,11 @@ import "time"
 	// without an associated task. Keys are agent names, values are session UUIDs.
 	// Example: {"planner": "550e8400-e29b-41d4-a716-446655440000"}
 	agent_sessions?: [string]: string
+
+	// revision is the optimistic concurrency counter for this state.
+	// Backends increment it on every successful save and reject a save whose
+	// revision does not match the stored value. Zero for unsaved state.
+	revision?: int & >=0
 }
 
 // StatechartState represents the current position in a project's state machine.
@@ -1316,3 +1316,47 @@ func TestValidTaskState_PausedStatusWithSessionID(t *testing.T) {
 		t.Errorf("task with paused status and session_id should pass validation: %v", err)
 	}
 }
+
+func TestValidTaskState_WithDependsOn(t *testing.T) {
+	now := time.Now().Format(time.RFC3339)
+	data := map[string]any{
+		"id":             "030",
+		"name":           "Wire middleware",
+		"phase":          "implementation",
+		"status":         "pending",
+		"created_at":     now,
+		"updated_at":     now,
+		"iteration":      1,
+		"assigned_agent": "implementer",
+		"depends_on":     []any{"010", "020"},
+		"inputs":         []any{},
+		"outputs":        []any{},
+	}
+
+	err := validateSchema(t, "#TaskState", data)
+	if err != nil {
+		t.Errorf("task with depends_on should pass validation: %v", err)
+	}
+}
+
+func TestInvalidTaskState_DependsOnBadID(t *testing.T) {
+	now := time.Now().Format(time.RFC3339)
+	data := map[string]any{
+		"id":             "030",
+		"name":           "Wire middleware",
+		"phase":          "implementation",
+		"status":         "pending",
+		"created_at":     now,
+		"updated_at":     now,
+		"iteration":      1,
+		"assigned_agent": "implementer",
+		"depends_on":     []any{"task-10"},
+		"inputs":         []any{},
+		"outputs":        []any{},
+	}
+
+	err := validateSchema(t, "#TaskState", data)
+	if err == nil {
+		t.Error("task depending on a malformed ID should fail validation")
+	}
+}
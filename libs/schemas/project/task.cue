@@ -58,6 +58,12 @@ import "time"
 	// Used by executors that support session resumption (claude, cursor).
 	session_id?: string
 
+	// depends_on lists the IDs of tasks in the same phase that must be
+	// completed (or abandoned) before this task is ready to start.
+	// Every ID must name an existing task, and dependencies may not form a
+	// cycle. Example: ["010", "020"]
+	depends_on?: [...string & =~"^[0-9]{3}$"]
+
 	// inputs is the list of artifacts that this task consumes.
 	// These provide context and requirements for completing the task.
 	// Examples: design documents, specifications, feedback files.
@@ -0,0 +1,133 @@
+package schemas
+
+// ProjectTypeDefinition defines a project type declared in
+// .sow/types/<name>.cue instead of Go code.
+//
+// The definition describes the type's phases, its state machine, and the
+// prompt templates shown in each state. Guards are built from a small
+// vocabulary of predicates over project state (see #GuardDefinition).
+//
+// Example:
+//
+//	name:          "review"
+//	description:   "Review an existing change"
+//	branch_prefix: "review/"
+//	initial_state: "Reviewing"
+//	phases: review: {
+//		start_state: "Reviewing"
+//		end_state:   "Reviewing"
+//		outputs: ["review"]
+//	}
+//	transitions: [{
+//		from:  "Reviewing"
+//		to:    "Done"
+//		event: "complete_review"
+//		guards: [{predicate: "output_approved", phase: "review", type: "review"}]
+//	}]
+#ProjectTypeDefinition: {
+	// Project type name stored in project state.
+	// Must match the file name (<name>.cue).
+	name: string & =~"^[a-z][a-z0-9-]*$"
+
+	// Short description shown when choosing a project type
+	description?: string
+
+	// Branch name prefix for new projects of this type
+	// Default: "feat/"
+	branch_prefix?: string
+
+	// State new projects start in. Must be the start state of a phase,
+	// which becomes the active phase of a new project.
+	initial_state: string & !=""
+
+	// Orchestrator prompt template, relative to .sow/types/
+	orchestrator_prompt?: string
+
+	// Phases keyed by phase name
+	phases: {[string]: #PhaseDefinition}
+
+	// Per-state settings keyed by state name
+	states?: {[string]: #StateDefinition}
+
+	// State machine transitions
+	transitions: [...#TransitionDefinition]
+}
+
+// PhaseDefinition declares one phase of a project type.
+#PhaseDefinition: {
+	// State in which the phase begins
+	start_state: string & !=""
+
+	// State in which the phase ends (may equal start_state)
+	end_state: string & !=""
+
+	// Allowed input artifact types (empty allows any)
+	inputs?: [...string]
+
+	// Allowed output artifact types (empty allows any)
+	outputs?: [...string]
+
+	// Whether the phase supports tasks
+	tasks?: bool
+
+	// CUE schema for the phase metadata, e.g. "{ approved?: bool }"
+	metadata_schema?: string
+}
+
+// StateDefinition holds settings for one state of a project type.
+#StateDefinition: {
+	// Prompt template for the state, relative to .sow/types/
+	prompt?: string
+
+	// Agent 'sow run' spawns in the state when it has no tasks to run
+	agent?: string & !=""
+}
+
+// TransitionDefinition declares a state machine transition.
+#TransitionDefinition: {
+	// Source state
+	from: string & !=""
+
+	// Target state
+	to: string & !=""
+
+	// Event that triggers the transition
+	event: string & !=""
+
+	// Human-readable description of the transition
+	description?: string
+
+	// Conditions that must all hold for the transition to fire
+	guards?: [...#GuardDefinition]
+
+	// Phase to mark "failed" instead of "completed" when this transition
+	// leaves the phase's end state
+	failed_phase?: string
+}
+
+// GuardDefinition is a transition condition built from a predicate:
+//
+//	output_approved     an output of the given type is approved in phase
+//	all_tasks_complete  phase has tasks and all are completed or abandoned
+//	metadata_bool       phase metadata key is the boolean value (default true)
+#GuardDefinition: {
+	// Predicate to evaluate
+	predicate: "output_approved" | "all_tasks_complete" | "metadata_bool"
+
+	// Phase the predicate examines
+	phase: string & !=""
+
+	// Output artifact type (required for output_approved)
+	type?: string
+
+	// Metadata key (required for metadata_bool)
+	key?: string
+
+	// Expected metadata value (metadata_bool)
+	// Default: true
+	value?: bool @go(,optional=nillable)
+
+	// Description shown when the guard fails
+	// Default: generated from the predicate
+	description?: string
+}
@@ -6,34 +6,99 @@ package schemas
 // This allows users to configure which AI CLI executors handle which agent roles.
 #UserConfig: {
 	// Agent configuration
-	agents?: {
-		// Executor definitions
-		// Keys are executor names (e.g., "claude-code", "cursor")
-		executors?: [string]: {
-			// Type of executor
-			type: "claude" | "cursor"
-
-			// Executor settings
-			settings?: {
-				// Skip permission prompts
-				yolo_mode?: bool
-				// AI model to use (only meaningful for claude type)
-				model?: string
-			} @go(,optional=nillable)
-
-			// Additional CLI arguments
-			custom_args?: [...string]
-		}
-
-		// Bindings from agent roles to executor names
-		bindings?: {
-			orchestrator?: string
-			implementer?: string
-			architect?: string
-			reviewer?: string
-			planner?: string
-			researcher?: string
-			decomposer?: string
-		} @go(,optional=nillable)
+	agents?: #AgentsConfig @go(,optional=nillable)
+}
+
+// AgentsConfig defines the executors and which agents they run.
+#AgentsConfig: {
+	// Executor definitions
+	// Keys are executor names (e.g., "claude-code", "cursor")
+	executors?: [string]: #ExecutorConfig
+
+	// Bindings from agent names to executor names. Keys are standard agent
+	// roles (e.g., "implementer") or custom agents from .sow/agents/.
+	bindings?: [string]: string
+
+	// Execution policies for agent roles, overriding the policy of the
+	// executor the role is bound to field by field
+	policies?: [string]: #ExecutionPolicy
+}
+
+// ExecutorConfig defines one executor: an agent CLI and how sow invokes it.
+#ExecutorConfig: {
+	// Type of executor
+	type: "claude" | "cursor" | "command" | "scripted"
+
+	// Executor settings
+	settings?: {
+		// Skip permission prompts
+		yolo_mode?: bool
+		// AI model to use (claude type, or {{.Model}} for command type)
+		model?: string
 	} @go(,optional=nillable)
+
+	// Additional CLI arguments
+	custom_args?: [...string]
+
+	// How to invoke the program (required for command type)
+	command?: #CommandExecutorConfig @go(,optional=nillable)
+
+	// YAML script of the steps each agent performs (required for scripted
+	// type). A stand-in for a real agent in end-to-end tests; relative
+	// paths are resolved from the directory sow runs in.
+	script?: string
+
+	// Timeout and retry policy for this executor's runs
+	policy?: #ExecutionPolicy @go(,optional=nillable)
+}
+
+// ExecutionPolicy bounds how long an agent may run and whether failed runs
+// are retried. Each attempt is recorded in the task's metadata.
+#ExecutionPolicy: {
+	// Maximum wall time of one attempt, as a Go duration (e.g. "30m")
+	// Default: no limit
+	timeout?: string @go(,optional=nillable)
+
+	// How many times a failed attempt is retried
+	// Default: 0
+	max_retries?: int & >=0 @go(,optional=nillable)
+
+	// Delay before the first retry, doubled for each further retry
+	// Default: "10s"
+	backoff?: string @go(,optional=nillable)
+
+	// Upper bound of the delay between retries
+	// Default: "5m"
+	max_backoff?: string @go(,optional=nillable)
+
+	// Failures that are retried: "timeout", "error" (any failure), or
+	// "exit:<code>" for a specific exit code
+	// Default: ["timeout", "error"]
+	retry_on?: [..."timeout" | "error" | =~"^exit:[0-9]+$"]
+}
+
+// CommandExecutorConfig describes an arbitrary agent CLI. Arguments are Go
+// templates with the fields .Prompt (agent prompt plus task prompt when
+// spawning), .TaskPrompt, .SessionID, .Model, and .AgentPromptPath (a file
+// holding the agent prompt, only set when spawning).
+#CommandExecutorConfig: {
+	// Program to run, looked up on PATH
+	program: string
+
+	// Arguments for starting a new session
+	// Example: ["--message", "{{.Prompt}}", "--model", "{{.Model}}"]
+	spawn_args: [...string]
+
+	// Arguments for resuming a session. Without them the executor does not
+	// support resumption.
+	resume_args?: [...string]
+
+	// How the prompt is passed: on stdin, or in the arguments via {{.Prompt}}
+	// Default: "stdin"
+	prompt?: "stdin" | "argv" @go(,optional=nillable)
+
+	// Format of the program's stdout: "stream-json" (Claude Code events,
+	// formatted into the session log) or "text" (logged as is)
+	// Default: "text"
+	output?: "stream-json" | "text" @go(,optional=nillable)
 }